@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"sigs.k8s.io/yaml"
+
+	"pod-visualizer/pkg/alerts"
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/web"
+)
+
+// configSettle is how long the -config file must go unchanged before it is
+// reloaded, so a save that truncates and then writes is read once, whole
+const configSettle = 250 * time.Millisecond
+
+// configFile is the -config file. Settings it leaves out keep the value
+// of their flag.
+type configFile struct {
+	RefreshInterval    string   `json:"refreshInterval,omitempty"`
+	PriorityNamespaces []string `json:"priorityNamespaces,omitempty"`
+	AlertRules         string   `json:"alertRules,omitempty"`
+
+	CustomResources []k8s.CustomResourceType `json:"customResources,omitempty"`
+}
+
+// loadConfig reads the -config file at path over the flag settings in base
+func loadConfig(path string, base web.Settings) (web.Settings, []byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return web.Settings{}, nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var file configFile
+	if err := yaml.UnmarshalStrict(raw, &file); err != nil {
+		return web.Settings{}, nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	settings := base
+	if file.RefreshInterval != "" {
+		if settings.RefreshInterval, err = time.ParseDuration(file.RefreshInterval); err != nil {
+			return web.Settings{}, nil, fmt.Errorf("invalid refreshInterval: %w", err)
+		}
+	}
+	if file.PriorityNamespaces != nil {
+		settings.PriorityNamespaces = file.PriorityNamespaces
+	}
+	if file.AlertRules != "" {
+		if settings.AlertRules, err = alerts.ParseRules(file.AlertRules); err != nil {
+			return web.Settings{}, nil, fmt.Errorf("invalid alertRules: %w", err)
+		}
+	}
+	if file.CustomResources != nil {
+		for _, t := range file.CustomResources {
+			if err := t.Validate(); err != nil {
+				return web.Settings{}, nil, fmt.Errorf("invalid customResources: %w", err)
+			}
+		}
+		settings.CustomResources = file.CustomResources
+	}
+	return settings, raw, nil
+}
+
+// watchConfig applies the -config file at startup and again whenever it
+// changes. The directory is watched rather than the file, so that editors
+// that save by renaming and ConfigMap volumes, which swap a symlink, are
+// both noticed. A file that fails to load is logged and the running
+// settings are kept.
+func watchConfig(server *web.Server, path string, base web.Settings) {
+	settings, applied, err := loadConfig(path, base)
+	if err != nil {
+		logging.Fatal("Error loading config", "error", err)
+	}
+	server.Reconfigure(settings)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		logging.Fatal("Error watching config", "error", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		logging.Fatal("Error watching config", "path", path, "error", err)
+	}
+	slog.Info("Watching config for changes", "path", path)
+
+	go func() {
+		defer watcher.Close()
+
+		settle := time.NewTimer(configSettle)
+		settle.Stop()
+		for {
+			select {
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				settle.Reset(configSettle)
+
+			case <-settle.C:
+				settings, raw, err := loadConfig(path, base)
+				if err != nil {
+					slog.Warn("Ignoring config change", "path", path, "error", err)
+					continue
+				}
+				if bytes.Equal(raw, applied) {
+					continue
+				}
+				applied = raw
+				slog.Info("Config changed, reloading", "path", path)
+				server.Reconfigure(settings)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("Config watch failed", "path", path, "error", err)
+			}
+		}
+	}()
+}
@@ -3,13 +3,22 @@ package main
 import (
 	"context"
 	"flag"
-	"log"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"pod-visualizer/pkg/alerts"
+	"pod-visualizer/pkg/history"
 	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/k8s/fake"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/manifests"
+	"pod-visualizer/pkg/status"
 	"pod-visualizer/pkg/web"
 
 	"k8s.io/client-go/util/homedir"
@@ -23,44 +32,368 @@ func main() {
 		kubeconfig = flag.String("kubeconfig", "", "(optional) absolute path to the kubeconfig file - not needed when running in cluster")
 	}
 
+	fromFile := flag.String("from-file", os.Getenv("FROM_FILE"), "serve the cluster described by the YAML/JSON manifests in this file or directory instead of connecting to one")
+	record := flag.String("record", os.Getenv("RECORD_FILE"), "record every watch event to this newline-delimited JSON file, for -replay")
+	replayFile := flag.String("replay", os.Getenv("REPLAY_FILE"), "serve a cluster replayed from a -record file instead of connecting to one")
+	demo := flag.Bool("demo", envBool("DEMO"), "serve a synthetic cluster whose pods crash and deployments scale over time, instead of connecting to one")
+	replaySpeed := flag.Float64("replay-speed", 1, "playback speed for -replay, e.g. 10 for ten times faster (0 = jump to the end)")
+	kubeQPS := flag.Float64("kube-qps", envFloat("KUBE_QPS", 0), "sustained requests per second to the Kubernetes API (0 = client-go default of 5)")
+	kubeBurst := flag.Int("kube-burst", envInt("KUBE_BURST", 0), "requests allowed in a burst above -kube-qps (0 = client-go default of 10)")
+	kubeTimeout := flag.Duration("kube-timeout", envDuration("KUBE_TIMEOUT", 0), "timeout for each Kubernetes API request; watches restart when it ends them (0 = none)")
+	leaderElect := flag.Bool("leader-elect", envBool("LEADER_ELECT"), "with several replicas, elect one to watch the cluster while the others poll every -refresh-interval and serve reads")
+	leaderNamespace := flag.String("leader-elect-namespace", os.Getenv("POD_NAMESPACE"), "namespace of the leader election Lease (default $POD_NAMESPACE)")
+	leaderLease := flag.String("leader-elect-lease", envOr("LEADER_ELECT_LEASE", "pod-visualizer"), "name of the leader election Lease")
 	port := flag.Int("port", 8080, "port for the web server")
+	bind := flag.String("bind", os.Getenv("BIND_ADDRESS"), "address to listen on, e.g. 127.0.0.1 (default all interfaces); ignored for systemd socket-activated sockets")
+	tlsCert := flag.String("tls-cert", os.Getenv("TLS_CERT"), "PEM certificate file; with -tls-key serves HTTPS and gRPC over TLS")
+	tlsKey := flag.String("tls-key", os.Getenv("TLS_KEY"), "PEM private key file for -tls-cert")
+	tlsSelfSigned := flag.Bool("tls-self-signed", envBool("TLS_SELF_SIGNED"), "serve HTTPS with a generated self-signed certificate for localhost (development only)")
+	grpcPort := flag.Int("grpc-port", envInt("GRPC_PORT", 0), "port for the gRPC ClusterService API (0 disables it)")
+	wsMaxClients := flag.Int("ws-max-clients", envInt("WS_MAX_CLIENTS", 500), "maximum concurrent WebSocket clients (0 = unlimited)")
+	wsMaxClientsPerIP := flag.Int("ws-max-clients-per-ip", envInt("WS_MAX_CLIENTS_PER_IP", 0), "maximum concurrent WebSocket clients per client address (0 = unlimited; leave off behind a proxy)")
+	listPageSize := flag.Int("list-page-size", envInt("LIST_PAGE_SIZE", k8s.DefaultListPageSize), "objects requested per Kubernetes list call (0 = all in one call)")
+	maxPageSize := flag.Int("api-max-page-size", envInt("API_MAX_PAGE_SIZE", 1000), "maximum pods per paginated /api/cluster response (0 = unlimited)")
+	statusSymbols := flag.String("status-symbols", "", "comma-separated Status=Symbol overrides, e.g. Running=OK,Failed=X")
+	resources := flag.String("resources", os.Getenv("WATCH_RESOURCES"), "comma-separated resource kinds to fetch and watch (default all): "+strings.Join(k8s.AllKinds, ","))
+	priorityNamespaces := flag.String("priority-namespaces", os.Getenv("PRIORITY_NAMESPACES"), "comma-separated namespaces that get real-time updates; others are polled every minute (default all real-time)")
+	refreshInterval := flag.Duration("refresh-interval", envDuration("REFRESH_INTERVAL", 10*time.Second), "how often to broadcast a full refresh without watch events")
+	cacheTTL := flag.Duration("cache-ttl", envDuration("CACHE_TTL", 5*time.Second), "how long filtered /api/cluster responses are served from memory (0 = always fetch)")
+	debounce := flag.Duration("debounce", envDuration("REFRESH_DEBOUNCE", 500*time.Millisecond), "window for coalescing bursts of watch events into one broadcast")
+	authMode := flag.String("auth-mode", envOr("AUTH_MODE", web.AuthNone), "dashboard authentication: none, token, basic or oidc")
+	oidcIssuerURL := flag.String("oidc-issuer-url", os.Getenv("OIDC_ISSUER_URL"), "OIDC issuer whose ID tokens are accepted in oidc auth mode")
+	oidcClientID := flag.String("oidc-client-id", os.Getenv("OIDC_CLIENT_ID"), "OIDC client ID (token audience) accepted in oidc auth mode")
+	excludeSidecars := flag.Bool("exclude-sidecars", envBool("EXCLUDE_SIDECARS"), "leave sidecars (native sidecars and proxies such as istio-proxy) out of pod readiness")
+	kubeletStats := flag.Bool("kubelet-stats", envBool("KUBELET_STATS"), "query each node's kubelet summary API for per-pod ephemeral storage usage (needs nodes/proxy access)")
+	idleAfter := flag.Duration("idle-after", envDuration("IDLE_AFTER", 7*24*time.Hour), "how long a deployment must stay at near-zero CPU to be listed at /api/idle")
+	historyBackend := flag.String("history", envOr("HISTORY_BACKEND", "memory"), "readiness history store for /api/history: none, memory or sqlite")
+	historyPath := flag.String("history-path", envOr("HISTORY_PATH", "pod-visualizer-history.db"), "SQLite database file for the sqlite history store")
+	historyRetention := flag.Duration("history-retention", envDuration("HISTORY_RETENTION", history.DefaultRetention), "how long the sqlite history store keeps samples")
+	enableActions := flag.Bool("enable-actions", envBool("ENABLE_ACTIONS"), "enable audited write actions (exec, port-forward, pod delete, rollout restart, scale); requires -auth-mode and matching RBAC")
+	alertRules := flag.String("alert-rules", envOr("ALERT_RULES", alerts.DefaultRules), "comma-separated Condition=Duration alert rules, sent to ALERT_SLACK_WEBHOOK_URL and ALERT_WEBHOOK_URL when set; conditions: "+strings.Join(alerts.Conditions, ","))
+	excludeNamespaces := flag.String("exclude-namespaces", os.Getenv("EXCLUDE_NAMESPACES"), "comma-separated namespaces to hide when viewing all namespaces, e.g. kube-system,istio-system; ?namespace= still shows them")
+	corsOrigins := flag.String("cors-origins", os.Getenv("CORS_ORIGINS"), "comma-separated origins allowed to call /api/* from the browser, e.g. https://grafana.example.com (* = any, without credentials)")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML file of refreshInterval, priorityNamespaces, alertRules and customResources, applied over their flags at startup and again whenever it changes")
+	profileName := flag.String("profile", os.Getenv("PROFILE"), "quickstart preset of defaults: "+profileNames())
+	logFormat := flag.String("log-format", envOr("LOG_FORMAT", logging.FormatText), "log output format: text or json")
+	logLevel := flag.String("log-level", envOr("LOG_LEVEL", "info"), "minimum log level: debug, info, warn or error")
+	printManifests := flag.Bool("print-manifests", false, "print Namespace, RBAC, Deployment and Service YAML for installing the dashboard in-cluster, then exit")
+	manifestNamespace := flag.String("manifest-namespace", "pod-visualizer", "namespace for -print-manifests")
+	manifestImage := flag.String("manifest-image", "pod-visualizer:latest", "container image for -print-manifests")
+	manifestReplicas := flag.Int("manifest-replicas", 1, "Deployment replicas for -print-manifests")
 	flag.Parse()
 
-	// Create Kubernetes client
-	client, err := k8s.NewClient(*kubeconfig)
+	if err := logging.Setup(*logFormat, *logLevel); err != nil {
+		logging.Fatal("Error configuring logging", "error", err)
+	}
+
+	var preset profile
+	if *profileName != "" {
+		p, err := applyProfile(*profileName)
+		if err != nil {
+			logging.Fatal("Error applying profile", "error", err)
+		}
+		preset = p
+		slog.Info("Using profile", "profile", *profileName, "description", preset.description)
+	}
+	if preset.requireAuth && *authMode == web.AuthNone {
+		logging.Fatal("Profile requires authentication; set -auth-mode", "profile", *profileName)
+	}
+
+	symbols, err := status.ParseMapping(*statusSymbols)
+	if err != nil {
+		logging.Fatal("Error parsing status symbols", "error", err)
+	}
+
+	kinds, err := k8s.ParseKinds(*resources)
 	if err != nil {
-		log.Fatalf("Error creating Kubernetes client: %v", err)
+		logging.Fatal("Error parsing resource kinds", "error", err)
 	}
 
+	// Print install manifests, e.g. for "pod-visualizer-web -print-manifests | kubectl apply -f -"
+	if *printManifests {
+		err := manifests.Write(os.Stdout, manifests.Options{
+			Name:         "pod-visualizer",
+			Namespace:    *manifestNamespace,
+			Image:        *manifestImage,
+			Replicas:     int32(*manifestReplicas),
+			Port:         *port,
+			Kinds:        kinds,
+			KubeletStats: *kubeletStats,
+			LeaderElect:  *leaderElect,
+		})
+		if err != nil {
+			logging.Fatal("Error printing manifests", "error", err)
+		}
+		return
+	}
+
+	// Create Kubernetes client
+	var client *k8s.Client
+	var replay *fake.Replay
+	var demoCluster *fake.Demo
+	switch {
+	case *fromFile != "" && *replayFile != "":
+		logging.Fatal("Cannot combine -from-file with -replay")
+	case *demo && (*fromFile != "" || *replayFile != ""):
+		logging.Fatal("Cannot combine -demo with -from-file or -replay")
+	case *demo:
+		client, demoCluster = fake.NewDemoClient(fake.DefaultDemoInterval)
+		slog.Info("Serving synthetic demo cluster")
+	case *replayFile != "":
+		client, replay, err = fake.NewReplayClient(*replayFile, *replaySpeed)
+		if err != nil {
+			logging.Fatal("Error loading recording", "error", err)
+		}
+		slog.Info("Serving cluster from recording", "path", *replayFile, "duration", replay.Duration().String())
+	case *fromFile != "":
+		var skipped []fake.Skipped
+		client, skipped, err = fake.NewClientFromManifests(*fromFile)
+		if err != nil {
+			logging.Fatal("Error loading manifests", "error", err)
+		}
+		for _, document := range skipped {
+			slog.Warn("Skipped manifest document", "file", document.File, "kind", document.Kind, "name", document.Name, "reason", document.Reason)
+		}
+		slog.Info("Serving cluster from manifests", "path", *fromFile)
+	default:
+		client, err = k8s.NewClient(*kubeconfig, k8s.RESTOptions{QPS: float32(*kubeQPS), Burst: *kubeBurst, Timeout: *kubeTimeout})
+		if err != nil {
+			logging.Fatal("Error creating Kubernetes client", "error", err)
+		}
+	}
+	client.SetListPageSize(int64(*listPageSize))
+	client.SetExcludedNamespaces(splitList(*excludeNamespaces))
+	client.SetExcludeSidecars(*excludeSidecars)
+
 	// Create and start web server
 	server := web.NewServer(client, *port)
+	server.SetStatusSymbols(symbols)
+	server.SetResourceKinds(kinds)
+	server.SetRefreshInterval(*refreshInterval)
+	server.SetDebounce(*debounce)
+	server.SetCacheTTL(*cacheTTL)
+	server.SetKubeletStats(*kubeletStats)
+	server.SetIdleWindow(*idleAfter)
+	server.SetGRPCPort(*grpcPort)
+	server.SetWebSocketLimits(*wsMaxClients, *wsMaxClientsPerIP)
+	server.SetMaxPageSize(*maxPageSize)
+	server.SetBindAddress(*bind)
+	server.SetCORSOrigins(splitList(*corsOrigins))
+
+	switch {
+	case *tlsCert != "" || *tlsKey != "":
+		if *tlsCert == "" || *tlsKey == "" {
+			logging.Fatal("-tls-cert and -tls-key must be set together")
+		}
+		if err := server.SetTLS(*tlsCert, *tlsKey); err != nil {
+			logging.Fatal("Error configuring TLS", "error", err)
+		}
+	case *tlsSelfSigned:
+		if err := server.SetSelfSignedTLS(); err != nil {
+			logging.Fatal("Error configuring TLS", "error", err)
+		}
+		slog.Warn("Serving a self-signed certificate; use -tls-cert/-tls-key outside development")
+	}
+
+	switch *historyBackend {
+	case "none":
+	case "memory":
+		server.SetHistory(history.NewRing(history.DefaultRingSize), 0)
+	case "sqlite":
+		store, err := history.OpenSQLite(*historyPath, *historyRetention)
+		if err != nil {
+			logging.Fatal("Error opening history", "error", err)
+		}
+		server.SetHistory(store, 0)
+	default:
+		logging.Fatal("Unknown history store: expected none, memory or sqlite", "store", *historyBackend)
+	}
+	if *priorityNamespaces != "" {
+		server.SetPriorityNamespaces(splitList(*priorityNamespaces))
+	}
+
+	// Secrets are read from the environment only, so they never show up in
+	// the process list
+	err = server.SetAuth(context.Background(), web.AuthConfig{
+		Mode:          *authMode,
+		Token:         os.Getenv("AUTH_TOKEN"),
+		Username:      os.Getenv("AUTH_USERNAME"),
+		Password:      os.Getenv("AUTH_PASSWORD"),
+		OIDCIssuerURL: *oidcIssuerURL,
+		OIDCClientID:  *oidcClientID,
+	})
+	if err != nil {
+		logging.Fatal("Error configuring authentication", "error", err)
+	}
+	slog.Info("Dashboard authentication", "mode", *authMode)
+	if err := server.SetActions(*enableActions); err != nil {
+		logging.Fatal("Error enabling actions", "error", err)
+	}
+	if *enableActions {
+		slog.Warn("Write-mode actions are enabled")
+	}
+
+	if *leaderElect {
+		if *leaderNamespace == "" {
+			logging.Fatal("-leader-elect needs -leader-elect-namespace or POD_NAMESPACE")
+		}
+		// In a pod the hostname is the pod name, unique among replicas
+		identity, err := os.Hostname()
+		if err != nil {
+			logging.Fatal("Error getting leader election identity", "error", err)
+		}
+		server.SetLeaderElection(web.LeaderElection{Namespace: *leaderNamespace, Name: *leaderLease, Identity: identity})
+		slog.Info("Leader election enabled", "lease", *leaderNamespace+"/"+*leaderLease, "identity", identity)
+	}
+
+	// Webhook URLs carry their credentials, so they are environment-only too
+	var notifiers []alerts.Notifier
+	for _, url := range splitList(os.Getenv("ALERT_SLACK_WEBHOOK_URL")) {
+		notifiers = append(notifiers, alerts.Slack{URL: url})
+	}
+	for _, url := range splitList(os.Getenv("ALERT_WEBHOOK_URL")) {
+		notifiers = append(notifiers, alerts.Webhook{URL: url})
+	}
+	var rules []alerts.Rule
+	if len(notifiers) > 0 {
+		rules, err = alerts.ParseRules(*alertRules)
+		if err != nil {
+			logging.Fatal("Error parsing alert rules", "error", err)
+		}
+		server.SetAlerts(alerts.NewEvaluator(rules, notifiers...))
+		slog.Info("Alerting enabled", "rules", *alertRules, "notifiers", len(notifiers))
+	}
+
+	if *configPath != "" {
+		base := web.Settings{RefreshInterval: *refreshInterval, AlertRules: rules}
+		if *priorityNamespaces != "" {
+			base.PriorityNamespaces = splitList(*priorityNamespaces)
+		}
+		watchConfig(server, *configPath, base)
+	}
 
 	// Handle graceful shutdown
+	shutdownDone := make(chan struct{})
 	go func() {
+		defer close(shutdownDone)
+
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 
-		log.Println("Shutting down server...")
-		os.Exit(0)
+		slog.Info("Shutting down server")
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		if err := server.Stop(ctx); err != nil {
+			slog.Error("Error during shutdown", "error", err)
+		}
 	}()
 
 	// Start the server
-	log.Printf("Pod Visualizer Web Server")
-	log.Printf("========================")
-	log.Printf("Connecting to Kubernetes cluster...")
+	slog.Info("Pod Visualizer Web Server")
+	slog.Info("Connecting to Kubernetes cluster")
 
 	// Test connection
 	ctx := context.Background()
 	_, err = client.GetPods(ctx, "")
 	if err != nil {
-		log.Fatalf("Failed to connect to Kubernetes cluster: %v", err)
+		logging.Fatal("Failed to connect to Kubernetes cluster", "error", err)
 	}
 
-	log.Printf("✅ Connected to Kubernetes cluster successfully")
-	log.Printf("Starting web server on port %d...", *port)
+	slog.Info("Connected to Kubernetes cluster")
+	if replay != nil {
+		go playRecording(replay)
+	}
+	if demoCluster != nil {
+		go demoCluster.Run(context.Background())
+	}
+	if *record != "" {
+		startRecording(client, *record)
+	}
+	slog.Info("Watching resource kinds", "kinds", kinds.String())
+	slog.Info("Starting web server", "port", *port)
+
+	if err := server.Start(ctx); err != nil {
+		logging.Fatal("Server failed", "error", err)
+	}
+
+	// Start returns once shutdown begins; wait for in-flight requests to drain
+	<-shutdownDone
+	slog.Info("Server stopped")
+}
+
+// envDuration reads a duration from the environment, falling back to def
+func envDuration(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logging.Fatal("Invalid environment variable", "key", key, "value", value, "error", err)
+	}
+	return d
+}
+
+// envInt reads an integer from the environment, falling back to def
+func envInt(key string, def int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		logging.Fatal("Invalid environment variable", "key", key, "value", value, "error", err)
+	}
+	return n
+}
+
+// envFloat reads a number from the environment, falling back to def
+func envFloat(key string, def float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		logging.Fatal("Invalid environment variable", "key", key, "value", value, "error", err)
+	}
+	return f
+}
+
+// envBool reports whether a boolean environment variable is set to true
+func envBool(key string) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		logging.Fatal("Invalid environment variable", "key", key, "value", value, "error", err)
+	}
+	return b
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
 
-	if err := server.Start(); err != nil {
-		log.Fatalf("Server failed: %v", err)
+// envOr reads a value from the environment, falling back to def
+func envOr(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return def
 }
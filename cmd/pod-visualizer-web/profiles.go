@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// profile is a quickstart preset of flag values for a kind of user. It
+// sets which kinds are fetched, whose panels the dashboard then hides when
+// empty, how often they refresh, and whether authentication is required;
+// the server has no grouping setting for a profile to choose.
+type profile struct {
+	description string
+	flags       map[string]string
+	// requireAuth refuses to start with -auth-mode=none
+	requireAuth bool
+}
+
+// profiles are selected with -profile. Their values only fill in flags
+// that were not set on the command line or through the environment.
+var profiles = map[string]profile{
+	"wallboard": {
+		description: "unattended status screen: calm refresh, only pods, deployments, nodes and metrics fetched",
+		flags: map[string]string{
+			"resources":        "pods,deployments,nodes,metrics",
+			"refresh-interval": "30s",
+			"debounce":         "2s",
+		},
+	},
+	"operator": {
+		description: "cluster operators: every kind fetched, fast updates, authentication required",
+		flags: map[string]string{
+			"resources":        "",
+			"refresh-interval": "10s",
+			"debounce":         "500ms",
+		},
+		requireAuth: true,
+	},
+	"developer": {
+		description: "local development: application kinds fetched, near-instant updates",
+		flags: map[string]string{
+			"resources":        "pods,deployments,hpas,jobs,cronjobs,services,ingresses,pvcs",
+			"refresh-interval": "5s",
+			"debounce":         "200ms",
+		},
+	},
+}
+
+// profileEnv maps the flags a profile may set to the environment variables
+// that also provide their defaults
+var profileEnv = map[string]string{
+	"resources":        "WATCH_RESOURCES",
+	"refresh-interval": "REFRESH_INTERVAL",
+	"debounce":         "REFRESH_DEBOUNCE",
+}
+
+// profileNames returns the available profiles for help text
+func profileNames() string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// applyProfile sets the named profile's values on flags that were left at
+// their defaults, and returns the profile so its checks can run later
+func applyProfile(name string) (profile, error) {
+	p, ok := profiles[name]
+	if !ok {
+		return profile{}, fmt.Errorf("unknown profile %q (valid: %s)", name, profileNames())
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	for name, value := range p.flags {
+		if explicit[name] || os.Getenv(profileEnv[name]) != "" {
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return profile{}, fmt.Errorf("profile sets invalid %s: %v", name, err)
+		}
+	}
+
+	return p, nil
+}
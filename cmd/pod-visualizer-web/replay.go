@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/k8s/fake"
+	"pod-visualizer/pkg/logging"
+)
+
+// startRecording writes the cluster's watch events to path in the
+// background for as long as the server runs
+func startRecording(client *k8s.Client, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		logging.Fatal("Error creating recording", "error", err)
+	}
+
+	slog.Info("Recording watch events", "path", path)
+	go func() {
+		defer f.Close()
+		if err := client.RecordEvents(context.Background(), "", f); err != nil {
+			slog.Error("Recording stopped", "path", path, "error", err)
+		}
+	}()
+}
+
+// playRecording replays a recording into the served cluster
+func playRecording(replay *fake.Replay) {
+	if err := replay.Run(context.Background()); err != nil {
+		slog.Error("Replay stopped", "error", err)
+		return
+	}
+	slog.Info("Replay finished; serving the final recorded state")
+}
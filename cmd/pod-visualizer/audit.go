@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/visualizer"
+)
+
+// runAudit implements `pod-visualizer audit [flags]`, listing pods that
+// reference missing ConfigMaps or Secrets. It exits with status 1 when any
+// are found so it can gate deploys in CI.
+func runAudit(args []string) {
+	flags := flag.NewFlagSet("audit", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s audit [flags]\n", commandName())
+		flags.PrintDefaults()
+	}
+
+	// Without -namespace every namespace is audited
+	configFlags := addKubeFlags(flags)
+	themeName := flags.String("theme", visualizer.UnicodeTheme.Name, "output theme: "+strings.Join(visualizer.ThemeNames(), ", "))
+	noColor := flags.Bool("no-color", false, "disable ANSI colors (also off when NO_COLOR is set or output is not a terminal)")
+	flags.Parse(args)
+
+	client := newClient(configFlags)
+	audits, err := client.AuditReferences(context.Background(), *configFlags.Namespace)
+	if err != nil {
+		logging.Fatal("Error auditing references", "error", err)
+	}
+
+	exitOnWriteError(newVisualizer(*themeName, "", *noColor).DisplayReferenceAudit(audits))
+	if len(audits) > 0 {
+		os.Exit(1)
+	}
+}
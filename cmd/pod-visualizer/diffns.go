@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/visualizer"
+)
+
+// runDiffNamespaces implements `pod-visualizer diff-ns <from> <to> [flags]`,
+// reporting drift between same-named deployments in two namespaces. It
+// exits with status 1 when drift is found so it can gate promotions in CI.
+func runDiffNamespaces(args []string) {
+	flags := flag.NewFlagSet("diff-ns", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s diff-ns <from-namespace> <to-namespace> [flags]\n", commandName())
+		flags.PrintDefaults()
+	}
+
+	configFlags := addKubeFlags(flags)
+	selector := flags.String("selector", "", "label selector limiting the compared workloads, e.g. app=web")
+	flags.StringVar(selector, "l", "", "shorthand for -selector")
+	themeName := flags.String("theme", visualizer.UnicodeTheme.Name, "output theme: "+strings.Join(visualizer.ThemeNames(), ", "))
+
+	// Allow the namespaces before or after the flags, like kubectl
+	var namespaces []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		namespaces, args = append(namespaces, args[0]), args[1:]
+	}
+	flags.Parse(args)
+	namespaces = append(namespaces, flags.Args()...)
+	if len(namespaces) != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	from, to := namespaces[0], namespaces[1]
+
+	client := newClient(configFlags)
+
+	ctx := context.Background()
+	fromSpecs, err := client.GetWorkloadSpecs(ctx, from, *selector)
+	if err != nil {
+		logging.Fatal("Error getting workloads", "error", err)
+	}
+	toSpecs, err := client.GetWorkloadSpecs(ctx, to, *selector)
+	if err != nil {
+		logging.Fatal("Error getting workloads", "error", err)
+	}
+
+	// Count each workload name once, whichever namespace it is in
+	names := make(map[string]bool)
+	for _, spec := range append(fromSpecs, toSpecs...) {
+		names[spec.Name] = true
+	}
+
+	drifts := k8s.DiffWorkloads(from, fromSpecs, to, toSpecs)
+	exitOnWriteError(newVisualizer(*themeName, "", true).DisplayNamespaceDiff(from, to, len(names), drifts))
+	if len(drifts) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"pod-visualizer/pkg/grafana"
+	"pod-visualizer/pkg/logging"
+)
+
+// runExport implements `pod-visualizer export <format> [flags]`
+func runExport(args []string) {
+	if len(args) == 0 || args[0] != "grafana-dashboard" {
+		fmt.Fprintf(os.Stderr, "Usage: %s export grafana-dashboard [flags]\n", commandName())
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("export grafana-dashboard", flag.ExitOnError)
+	title := flags.String("title", "Pod Visualizer", "dashboard title")
+	datasource := flags.String("datasource-uid", "", "UID of the Prometheus data source scraping /metrics (default: chosen on import)")
+	namespaces := flags.String("namespaces", os.Getenv("PRIORITY_NAMESPACES"), "comma-separated namespaces offered by the namespace variable (default all namespaces in the metrics)")
+	flags.Parse(args[1:])
+
+	opts := grafana.Options{Title: *title, DatasourceUID: *datasource}
+	for _, namespace := range strings.Split(*namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			opts.Namespaces = append(opts.Namespaces, namespace)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(grafana.NewDashboard(opts)); err != nil {
+		logging.Fatal("Error encoding dashboard", "error", err)
+	}
+}
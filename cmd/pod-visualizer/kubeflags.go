@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/k8s/fake"
+	"pod-visualizer/pkg/logging"
+)
+
+// pluginPrefix is how kubectl names the binaries it runs as plugins; the
+// CLI installed as kubectl-visualize is invoked as `kubectl visualize`
+const pluginPrefix = "kubectl-"
+
+// commandName returns how the user invoked the CLI, for usage messages
+func commandName() string {
+	if name := filepath.Base(os.Args[0]); strings.HasPrefix(name, pluginPrefix) {
+		return "kubectl " + strings.TrimPrefix(name, pluginPrefix)
+	}
+	return "pod-visualizer"
+}
+
+// addKubeFlags registers kubectl's standard connection flags (--kubeconfig,
+// --context, --namespace/-n, --cluster, --user, --as, --token, ...) on a
+// flag set. They are defined by genericclioptions for pflag and bridged onto
+// the standard library flag set, so both -flag and --flag spellings work.
+// -kube-qps and -kube-burst tune client-side rate limiting on top; they and
+// --request-timeout default to KUBE_QPS, KUBE_BURST and KUBE_TIMEOUT.
+func addKubeFlags(flags *flag.FlagSet) *genericclioptions.ConfigFlags {
+	configFlags := genericclioptions.NewConfigFlags(true)
+
+	kubectlFlags := pflag.NewFlagSet(flags.Name(), pflag.ContinueOnError)
+	configFlags.AddFlags(kubectlFlags)
+	kubectlFlags.VisitAll(func(f *pflag.Flag) {
+		flags.Var(f.Value, f.Name, f.Usage)
+		if f.Shorthand != "" {
+			flags.Var(f.Value, f.Shorthand, "shorthand for -"+f.Name)
+		}
+	})
+
+	qps := flags.Float64("kube-qps", 0, "sustained requests per second to the Kubernetes API (0 = client-go default of 5)")
+	burst := flags.Int("kube-burst", 0, "requests allowed in a burst above -kube-qps (0 = client-go default of 10)")
+	for name, key := range map[string]string{"kube-qps": "KUBE_QPS", "kube-burst": "KUBE_BURST", "request-timeout": "KUBE_TIMEOUT"} {
+		if value := os.Getenv(key); value != "" {
+			if err := flags.Set(name, value); err != nil {
+				logging.Fatal("Invalid environment variable", "key", key, "value", value, "error", err)
+			}
+		}
+	}
+	configFlags.WrapConfigFn = func(config *rest.Config) *rest.Config {
+		k8s.RESTOptions{QPS: float32(*qps), Burst: *burst}.Apply(config)
+		return config
+	}
+
+	return configFlags
+}
+
+// newClient creates the Kubernetes client the way kubectl resolves its
+// config: explicit flags, then KUBECONFIG, then ~/.kube/config, then the
+// in-cluster service account. It exits on failure.
+func newClient(configFlags *genericclioptions.ConfigFlags) *k8s.Client {
+	config, err := configFlags.ToRESTConfig()
+	if err != nil {
+		logging.Fatal("Error loading Kubernetes config", "error", err)
+	}
+
+	client, err := k8s.NewClientForConfig(config)
+	if err != nil {
+		logging.Fatal("Error creating Kubernetes client", "error", err)
+	}
+	return client
+}
+
+// newManifestClient serves the objects in the manifests at path as if they
+// were running, warning about documents it cannot load
+func newManifestClient(path string) *k8s.Client {
+	client, skipped, err := fake.NewClientFromManifests(path)
+	if err != nil {
+		logging.Fatal("Error loading manifests", "error", err)
+	}
+	for _, document := range skipped {
+		slog.Warn("Skipped manifest document", "file", document.File, "kind", document.Kind, "name", document.Name, "reason", document.Reason)
+	}
+	return client
+}
+
+// contextNamespace returns the --namespace flag, or the current context's
+// namespace ("default" when it sets none), as kubectl does
+func contextNamespace(configFlags *genericclioptions.ConfigFlags) string {
+	namespace, _, err := configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		logging.Fatal("Error loading Kubernetes config", "error", err)
+	}
+	return namespace
+}
@@ -4,50 +4,452 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
-	"path/filepath"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/metrics"
+	"pod-visualizer/pkg/status"
+	"pod-visualizer/pkg/topology"
 	"pod-visualizer/pkg/visualizer"
-
-	"k8s.io/client-go/util/homedir"
+	"pod-visualizer/pkg/web"
 )
 
 func main() {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file - not needed when running in cluster")
-	} else {
-		kubeconfig = flag.String("kubeconfig", "", "(optional) absolute path to the kubeconfig file - not needed when running in cluster")
+	if len(os.Args) > 1 && os.Args[1] == "wait" {
+		runWait(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff-ns" {
+		runDiffNamespaces(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		runAudit(os.Args[2:])
+		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExport(os.Args[2:])
+		return
+	}
+
+	// Without -namespace every namespace is shown, not just the context's.
+	// -namespace a,b shows several, listing each on its own.
+	configFlags := addKubeFlags(flag.CommandLine)
+	namespace := configFlags.Namespace
 
-	namespace := flag.String("namespace", "", "namespace to filter pods (empty for all namespaces)")
+	excludeNamespaces := flag.String("exclude-namespaces", "", "comma-separated namespaces to hide when no -n is given, e.g. kube-system,istio-system")
+	node := flag.String("node", "", "node name to filter pods (empty for all nodes)")
+	showMetrics := flag.Bool("metrics", false, "show live CPU/memory usage from metrics-server")
+	excludeSidecars := flag.Bool("exclude-sidecars", false, "leave sidecars (native sidecars and proxies such as istio-proxy) out of pod readiness")
+	kubeletStats := flag.Bool("kubelet-stats", false, "flag pods near their ephemeral-storage limit using each node's kubelet summary API")
+	statusSymbols := flag.String("status-symbols", "", "comma-separated Status=Symbol overrides, e.g. Running=OK,Failed=X")
+	snapshot := flag.String("snapshot", "", "write the complete cluster state as a timestamped JSON file into this directory and exit")
+	images := flag.Bool("images", false, "list the container images running, with counts, versions and namespaces, instead of the overview")
+	tree := flag.Bool("tree", false, "show Deployment/StatefulSet/DaemonSet/CronJob ownership trees instead of the overview")
+	spread := flag.String("spread", "", "show how this deployment's pods spread across nodes and zones against its topology spread constraints and anti-affinity, instead of the overview; namespace/name, or name with -n")
+	resources := flag.String("resources", "", "comma-separated resource kinds to show (default all): "+strings.Join(k8s.AllKinds, ","))
+	sortBy := flag.String("sort-by", "", "order pods by "+strings.Join(k8s.PodSortOrders, "|")+", most interesting first (default namespace)")
+	reverse := flag.Bool("reverse", false, "reverse the pod order")
+	groupBy := flag.String("group-by", k8s.GroupByNone, "show pods in sections with subtotals: "+strings.Join(k8s.PodGroupings, ", "))
+	minAge := flag.Duration("min-age", 0, "show only pods at least this old, e.g. 24h (0 = no minimum)")
+	maxAge := flag.Duration("max-age", 0, "show only pods at most this old, e.g. 10m to spot fresh restarts (0 = no maximum)")
+	themeName := flag.String("theme", visualizer.UnicodeTheme.Name, "output theme: "+strings.Join(visualizer.ThemeNames(), ", "))
+	noColor := flag.Bool("no-color", false, "disable ANSI colors (also off when NO_COLOR is set or output is not a terminal)")
+	hideCompleted := flag.Bool("hide-completed", false, "hide Completed pods, such as finished Job pods")
+	problemsOnly := flag.Bool("problems-only", false, "show only pods not fully ready, deployments below desired replicas and NotReady nodes")
+	watchPods := flag.Bool("watch", false, "print pod status changes as they happen instead of the overview, until interrupted")
+	notify := flag.Bool("notify", false, "with -watch, send a desktop notification (or ring the terminal bell) when a pod becomes Failed or CrashLoopBackOff")
+	selector := flag.String("selector", "", "with -watch, only watch pods matching this label selector, e.g. app=web")
+	flag.StringVar(selector, "l", "", "shorthand for -selector")
+	containers := flag.Bool("containers", false, "list each pod's containers below it with image, state, readiness and restarts")
+	flag.BoolVar(containers, "verbose", false, "alias for -containers")
+	qos := flag.String("qos", "", "show only pods in these comma-separated QoS classes: "+strings.Join(k8s.QOSClasses, ", "))
+	priorityClass := flag.String("priority-class", "", "show only pods with these comma-separated priority class names")
+	nameFilter := flag.String("name-filter", "", "show only pods and deployments whose names match this glob, e.g. web-*, or /regexp/, e.g. /^(web|api)-/")
+	fromFile := flag.String("from-file", "", "render the cluster described by the YAML/JSON manifests in this file or directory instead of connecting to one")
 	flag.Parse()
 
-	// Create Kubernetes client
-	client, err := k8s.NewClient(*kubeconfig)
+	symbols, err := status.ParseMapping(*statusSymbols)
 	if err != nil {
-		log.Fatalf("Error creating Kubernetes client: %v", err)
+		logging.Fatal("Error parsing status symbols", "error", err)
 	}
 
-	// Get pod information
-	ctx := context.Background()
-	pods, err := client.GetPods(ctx, *namespace)
+	kinds, err := k8s.ParseKinds(*resources)
 	if err != nil {
-		log.Fatalf("Error getting pods: %v", err)
+		logging.Fatal("Error parsing resource kinds", "error", err)
 	}
 
-	// Get deployment information
-	deployments, err := client.GetDeployments(ctx, *namespace)
+	order, err := k8s.ParsePodOrder(*sortBy, *reverse)
 	if err != nil {
-		log.Fatalf("Error getting deployments: %v", err)
+		logging.Fatal("Error parsing sort order", "error", err)
+	}
+
+	grouping, err := k8s.ParsePodGrouping(*groupBy)
+	if err != nil {
+		logging.Fatal("Error parsing pod grouping", "error", err)
+	}
+
+	names, err := k8s.ParseNameFilter(*nameFilter)
+	if err != nil {
+		logging.Fatal("Error parsing name filter", "error", err)
+	}
+
+	qosClasses, err := k8s.ParseQOSClasses(*qos)
+	if err != nil {
+		logging.Fatal("Error parsing QoS classes", "error", err)
+	}
+	var priorityClasses []string
+	if *priorityClass != "" {
+		priorityClasses = strings.Split(*priorityClass, ",")
+	}
+
+	// Create Kubernetes client
+	var client *k8s.Client
+	if *fromFile != "" {
+		client = newManifestClient(*fromFile)
+	} else {
+		client = newClient(configFlags)
+	}
+	client.SetExcludedNamespaces(strings.Split(*excludeNamespaces, ","))
+	client.SetExcludeSidecars(*excludeSidecars)
+
+	ctx := context.Background()
+	if *snapshot != "" {
+		server := web.NewServer(client, 0)
+		server.SetStatusSymbols(symbols)
+		server.SetResourceKinds(kinds)
+		server.SetKubeletStats(*kubeletStats)
+
+		data, err := server.Snapshot(ctx, *namespace, *node)
+		if err != nil {
+			logging.Fatal("Error getting cluster data", "error", err)
+		}
+		path, err := web.WriteSnapshotFile(*snapshot, data)
+		if err != nil {
+			logging.Fatal("Error writing snapshot", "error", err)
+		}
+		fmt.Printf("Snapshot written to %s\n", path)
+		return
+	}
+
+	if *tree {
+		roots, err := topology.Build(ctx, client, *namespace, kinds)
+		if err != nil {
+			logging.Fatal("Error building ownership tree", "error", err)
+		}
+
+		exitOnWriteError(newVisualizer(*themeName, *statusSymbols, *noColor).DisplayTree(roots))
+		return
+	}
+
+	if *spread != "" {
+		spreadNamespace, spreadName, found := strings.Cut(*spread, "/")
+		if !found {
+			spreadNamespace, spreadName = *namespace, *spread
+		}
+		if spreadNamespace == "" || strings.Contains(spreadNamespace, ",") {
+			logging.Fatal("Error parsing -spread", "error", fmt.Errorf("expected namespace/name, or a name with a single -n namespace, got %q", *spread))
+		}
+
+		detail, err := client.GetDeploymentDetail(ctx, spreadNamespace, spreadName)
+		if err != nil {
+			logging.Fatal("Error getting deployment", "error", err)
+		}
+		exitOnWriteError(newVisualizer(*themeName, *statusSymbols, *noColor).DisplaySpread(detail))
+		return
+	}
+
+	if *watchPods {
+		runWatchMode(client, newVisualizer(*themeName, *statusSymbols, *noColor), *namespace, *selector, names, *notify)
+		return
+	}
+
+	if *images {
+		pods, err := client.GetPodsOnNode(ctx, *namespace, *node)
+		if err != nil {
+			logging.Fatal("Error getting pods", "error", err)
+		}
+
+		exitOnWriteError(newVisualizer(*themeName, *statusSymbols, *noColor).DisplayImages(k8s.ImageInventory(pods)))
+		return
+	}
+
+	var (
+		pods        []k8s.PodInfo
+		deployments []k8s.DeploymentInfo
+		hpas        []k8s.HPAInfo
+		pdbs        []k8s.PDBInfo
+		jobs        []k8s.JobInfo
+		cronJobs    []k8s.CronJobInfo
+		services    []k8s.ServiceInfo
+		ingresses   []k8s.IngressInfo
+		rollouts    []k8s.ArgoRolloutInfo
+		pvcs        []k8s.PVCInfo
+		nodes       []k8s.NodeInfo
+
+		// allPods are the pods before the display filters, which health
+		// scores and rollout counts are computed from, as the web server does
+		allPods []k8s.PodInfo
+	)
+
+	// Get pod information
+	if kinds.Enabled(k8s.KindPods) {
+		pods, err = client.GetPodsOnNode(ctx, *namespace, *node)
+		if err != nil {
+			logging.Fatal("Error getting pods", "error", err)
+		}
+
+		policies, err := client.GetRuntimeClassPolicies(ctx)
+		if err != nil {
+			slog.Warn("Runtime class policies unavailable", "error", err)
+		}
+		k8s.ApplyRuntimeClassPolicies(pods, policies)
+		allPods = pods
+		pods = k8s.FilterPodsByAge(pods, *minAge, *maxAge, time.Now())
+		pods = k8s.FilterByName(pods, names)
+		pods = k8s.FilterByQOS(pods, qosClasses, priorityClasses)
+		if *hideCompleted {
+			pods = k8s.HideCompleted(pods)
+		}
+		k8s.SortPods(pods, order)
+	}
+
+	// Get deployment information
+	if kinds.Enabled(k8s.KindDeployments) {
+		deployments, err = client.GetDeployments(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting deployments", "error", err)
+		}
+		deployments = k8s.FilterByName(deployments, names)
+
+		// Score health with the pods' restarts and recent warnings,
+		// worst first
+		warnings, err := client.CountDeploymentWarnings(ctx, *namespace, allPods)
+		if err != nil {
+			slog.Warn("Deployment warning events unavailable", "error", err)
+		}
+		k8s.ApplyDeploymentHealth(deployments, allPods, warnings)
+		k8s.SortByHealth(deployments)
+
+		// Annotate workloads deployed by Argo CD or Flux with their sync status
+		gitOps, err := client.GetGitOpsStatus(ctx)
+		if err != nil {
+			slog.Warn("GitOps sync status unavailable", "error", err)
+		}
+		k8s.ApplyGitOpsStatus(deployments, pods, gitOps)
+
+		// Argo Rollouts, where installed, are shown beside deployments
+		rollouts, err = client.GetArgoRollouts(ctx, *namespace)
+		if err != nil {
+			slog.Warn("Argo Rollouts unavailable", "error", err)
+		}
+		rollouts = k8s.FilterByName(rollouts, names)
+		k8s.ApplyArgoRolloutPods(rollouts, allPods)
+	}
+
+	// Get autoscaler information, marking deployments pinned at maxReplicas
+	if kinds.Enabled(k8s.KindHPAs) {
+		hpas, err = client.GetHPAs(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting horizontalpodautoscalers", "error", err)
+		}
+		k8s.ApplyHPAs(deployments, hpas)
+	}
+
+	// Get disruption budget information
+	if kinds.Enabled(k8s.KindPDBs) {
+		pdbs, err = client.GetPDBs(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting poddisruptionbudgets", "error", err)
+		}
+	}
+
+	// Get job information
+	if kinds.Enabled(k8s.KindJobs) {
+		jobs, err = client.GetJobs(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting jobs", "error", err)
+		}
+	}
+
+	if kinds.Enabled(k8s.KindCronJobs) {
+		cronJobs, err = client.GetCronJobs(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting cronjobs", "error", err)
+		}
+	}
+
+	// Get service information
+	if kinds.Enabled(k8s.KindServices) {
+		services, err = client.GetServices(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting services", "error", err)
+		}
+	}
+
+	// Get ingress and route information
+	if kinds.Enabled(k8s.KindIngresses) {
+		ingresses, err = client.GetIngresses(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting ingresses", "error", err)
+		}
+	}
+
+	// Get persistentvolumeclaim information
+	if kinds.Enabled(k8s.KindPVCs) {
+		pvcs, err = client.GetPVCs(ctx, *namespace)
+		if err != nil {
+			logging.Fatal("Error getting persistentvolumeclaims", "error", err)
+		}
+	}
+
+	// Flag pods on nodes under memory, disk or PID pressure, and Pending
+	// pods kept off every node by taints
+	if kinds.Enabled(k8s.KindNodes) {
+		nodes, err = client.GetNodes(ctx)
+		if err != nil {
+			slog.Warn("Node pressure unavailable", "error", err)
+		}
+		k8s.ApplyNodePressure(pods, nodes)
+		k8s.ApplyTaintBlocks(pods, nodes)
+		k8s.ApplyZones(pods, nodes)
+	}
+
+	// Annotate with live usage from metrics-server
+	showNodes := *showMetrics && kinds.Enabled(k8s.KindMetrics) && kinds.Enabled(k8s.KindNodes)
+	if *showMetrics && kinds.Enabled(k8s.KindMetrics) {
+		metricsClient := metrics.NewClient(client)
+		if err := metricsClient.AnnotatePods(ctx, *namespace, pods); err != nil {
+			slog.Warn("Pod usage unavailable", "error", err)
+		}
+
+		if showNodes {
+			if err := metricsClient.AnnotateNodes(ctx, nodes); err != nil {
+				slog.Warn("Node usage unavailable", "error", err)
+			}
+		}
+	}
+
+	// Annotate with storage usage from the kubelets
+	if *kubeletStats && kinds.Enabled(k8s.KindPods) {
+		if err := metrics.NewClient(client).AnnotateStorage(ctx, pods); err != nil {
+			slog.Warn("Storage usage unavailable", "error", err)
+		}
 	}
 
 	// Create and display visualization
-	viz := visualizer.New()
+	viz := newVisualizer(*themeName, *statusSymbols, *noColor, visualizer.WithContainers(*containers))
 	fmt.Println("Pod Visualizer - Kubernetes Container Overview")
 	fmt.Println("============================================")
-	viz.DisplayPods(pods)
-	fmt.Println()
-	viz.DisplayDeployments(deployments)
+	if *problemsOnly {
+		displayProblems(viz, kinds, grouping, pods, deployments, nodes)
+		return
+	}
+	if kinds.Enabled(k8s.KindPods) {
+		exitOnWriteError(displayPods(viz, grouping, pods))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindDeployments) {
+		exitOnWriteError(viz.DisplayDeployments(deployments))
+		fmt.Println()
+	}
+	if len(rollouts) > 0 {
+		exitOnWriteError(viz.DisplayArgoRollouts(rollouts))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindHPAs) {
+		exitOnWriteError(viz.DisplayHPAs(hpas))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindPDBs) {
+		exitOnWriteError(viz.DisplayPDBs(pdbs))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindJobs) || kinds.Enabled(k8s.KindCronJobs) {
+		exitOnWriteError(viz.DisplayJobs(jobs, cronJobs))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindServices) {
+		exitOnWriteError(viz.DisplayServices(services))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindIngresses) {
+		exitOnWriteError(viz.DisplayIngresses(ingresses))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindPVCs) {
+		exitOnWriteError(viz.DisplayPVCs(pvcs))
+		fmt.Println()
+	}
+	if grouping == k8s.GroupByZone && kinds.Enabled(k8s.KindNodes) {
+		exitOnWriteError(viz.DisplayZones(k8s.GroupZones(nodes, pods)))
+		fmt.Println()
+	}
+	if showNodes {
+		exitOnWriteError(viz.DisplayNodes(nodes))
+	}
+}
+
+// displayProblems shows only the pods, deployments and nodes that need
+// attention, or a single all-clear line when nothing does
+func displayProblems(viz *visualizer.Visualizer, kinds k8s.Kinds, grouping string, pods []k8s.PodInfo, deployments []k8s.DeploymentInfo, nodes []k8s.NodeInfo) {
+	pods, deployments, nodes = k8s.Problems(pods), k8s.Problems(deployments), k8s.Problems(nodes)
+	if len(pods) == 0 && len(deployments) == 0 && len(nodes) == 0 {
+		fmt.Println(viz.Theme().OK + " Nothing needs attention")
+		return
+	}
+
+	if kinds.Enabled(k8s.KindPods) && len(pods) > 0 {
+		exitOnWriteError(displayPods(viz, grouping, pods))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindDeployments) && len(deployments) > 0 {
+		exitOnWriteError(viz.DisplayDeployments(deployments))
+		fmt.Println()
+	}
+	if kinds.Enabled(k8s.KindNodes) && len(nodes) > 0 {
+		exitOnWriteError(viz.DisplayNodes(nodes))
+	}
+}
+
+// displayPods shows pods as one list, or in sections for a grouping
+func displayPods(viz *visualizer.Visualizer, grouping string, pods []k8s.PodInfo) error {
+	if grouping == k8s.GroupByNone {
+		return viz.DisplayPods(pods)
+	}
+	return viz.DisplayPodGroups(grouping, k8s.GroupPods(pods, grouping))
+}
+
+// exitOnWriteError exits when output could not be written, e.g. to a
+// closed pipe
+func exitOnWriteError(err error) {
+	if err != nil {
+		logging.Fatal("Error writing output", "error", err)
+	}
+}
+
+// newVisualizer creates the terminal visualizer for a theme, status symbol
+// overrides on top of the theme's glyphs and the -no-color flag, sized to
+// the terminal (or unlimited when stdout is piped), exiting on invalid
+// settings. opts are applied last.
+func newVisualizer(themeName, statusSymbols string, noColor bool, opts ...visualizer.Option) *visualizer.Visualizer {
+	theme, err := visualizer.LookupTheme(themeName)
+	if err != nil {
+		logging.Fatal("Error selecting theme", "error", err)
+	}
+	symbols, err := status.ParseMappingFrom(theme.Symbols, statusSymbols)
+	if err != nil {
+		logging.Fatal("Error parsing status symbols", "error", err)
+	}
+
+	return visualizer.New(append([]visualizer.Option{
+		visualizer.WithTheme(theme),
+		visualizer.WithStatusSymbols(symbols),
+		visualizer.WithColor(!noColor && visualizer.ColorSupported(os.Stdout)),
+		visualizer.WithWidth(visualizer.TerminalWidth(os.Stdout)),
+	}, opts...)...)
 }
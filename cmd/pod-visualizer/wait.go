@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/visualizer"
+)
+
+// waitPollInterval is how often the waited-on resource is re-read
+const waitPollInterval = time.Second
+
+// runWait implements `pod-visualizer wait kind/name [flags]`, blocking
+// until the resource meets the condition and rendering its progress
+func runWait(args []string) {
+	flags := flag.NewFlagSet("wait", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s wait <deployment|pod|job>/<name> [flags]\n", commandName())
+		flags.PrintDefaults()
+	}
+
+	configFlags := addKubeFlags(flags)
+	condition := flags.String("for", "", "condition to wait for: available (deployment), ready (pod), complete or failed (job)")
+	timeout := flags.Duration("timeout", 30*time.Second, "how long to wait before giving up")
+	themeName := flags.String("theme", visualizer.UnicodeTheme.Name, "output theme: "+strings.Join(visualizer.ThemeNames(), ", "))
+	noColor := flags.Bool("no-color", false, "disable ANSI colors (also off when NO_COLOR is set or output is not a terminal)")
+
+	// Allow the resource before or after the flags, like kubectl
+	var ref string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ref, args = args[0], args[1:]
+	}
+	flags.Parse(args)
+	if ref == "" && flags.NArg() > 0 {
+		ref = flags.Arg(0)
+	}
+	if ref == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	target, err := k8s.ParseWaitTarget(ref, contextNamespace(configFlags), *condition)
+	if err != nil {
+		logging.Fatal("Error parsing wait target", "error", err)
+	}
+
+	client := newClient(configFlags)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	viz := newVisualizer(*themeName, "", *noColor)
+	start := time.Now()
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		state, err := client.CheckWait(ctx, target)
+		if err != nil && ctx.Err() == nil {
+			// The resource may not exist yet; keep waiting like kubectl does
+			fmt.Printf("\r%s %s/%s: %v\033[K", viz.Theme().Pending, target.Namespace, target, err)
+		} else if err == nil {
+			exitOnWriteError(viz.DisplayWaitProgress(target, state, time.Since(start)))
+		}
+
+		switch {
+		case err == nil && state.Met:
+			fmt.Println()
+			fmt.Printf("%s condition met\n", target)
+			return
+		case err == nil && state.Failed:
+			fmt.Println()
+			logging.Fatal("Condition can no longer be met", "target", target.String(), "condition", target.Condition, "message", state.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			logging.Fatal("Timed out waiting for condition", "target", target.String(), "condition", target.Condition, "timeout", *timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/logging"
+	"pod-visualizer/pkg/status"
+	"pod-visualizer/pkg/visualizer"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// notifyStatuses are the statuses a pod entering triggers a notification
+var notifyStatuses = map[string]bool{
+	string(corev1.PodFailed): true,
+	status.CrashLoopBackOff:  true,
+}
+
+// runWatchMode prints each status change of pods whose names match names
+// until interrupted and, with notify, raises a desktop notification when a
+// pod fails or starts crash-looping
+func runWatchMode(client k8s.Interface, viz *visualizer.Visualizer, namespace, selector string, names *k8s.NameFilter, notify bool) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	scope := "all namespaces"
+	if namespaces := k8s.SplitNamespaces(namespace); len(namespaces) > 1 {
+		scope = "namespaces " + strings.Join(namespaces, ", ")
+	} else if namespace != "" {
+		scope = "namespace " + namespace
+	}
+	if selector != "" {
+		scope += ", pods matching " + selector
+	}
+	if names != nil {
+		scope += ", pods named " + names.String()
+	}
+	fmt.Printf("Watching pod status changes in %s (Ctrl-C to stop)\n", scope)
+
+	err := client.WatchPodTransitions(ctx, namespace, selector, func(transition k8s.PodTransition) {
+		if !names.Matches(transition.Pod.Name) {
+			return
+		}
+		exitOnWriteError(viz.DisplayPodTransition(transition, time.Now()))
+		if notify && notifyStatuses[transition.Pod.Status] {
+			pod := transition.Pod
+			desktopNotify("Pod "+pod.Status, fmt.Sprintf("%s/%s (%d restarts)", pod.Namespace, pod.Name, pod.Restarts))
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		logging.Fatal("Error watching pods", "error", err)
+	}
+}
+
+// desktopNotify shows an OS notification with notify-send on Linux and
+// BSDs or osascript on macOS, ringing the terminal bell instead where
+// neither is available
+func desktopNotify(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", "--app-name=pod-visualizer", title, message)
+	}
+
+	if cmd == nil || cmd.Run() != nil {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+}
@@ -0,0 +1,336 @@
+// Package alerts evaluates rules such as "pod not ready for 5 minutes"
+// against cluster snapshots and notifies Slack or generic webhooks when an
+// alert starts firing and when it resolves.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Conditions a rule can watch for
+const (
+	PodNotReady            = "pod-not-ready"
+	DeploymentBelowDesired = "deployment-below-desired"
+	NodeNotReady           = "node-not-ready"
+)
+
+// Conditions lists every condition, in the order rules are evaluated
+var Conditions = []string{PodNotReady, DeploymentBelowDesired, NodeNotReady}
+
+// DefaultRules is the rule spec used when none is configured
+const DefaultRules = "pod-not-ready=5m,deployment-below-desired=5m,node-not-ready=2m"
+
+// Alert states
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+const (
+	// evaluationInterval is how often the latest snapshot is evaluated
+	evaluationInterval = 15 * time.Second
+	// notifyTimeout bounds each notifier call
+	notifyTimeout = 10 * time.Second
+)
+
+// Rule fires for a subject once its condition has held for For
+type Rule struct {
+	Condition string
+	For       time.Duration
+}
+
+// ParseRules parses a spec of the form "pod-not-ready=5m,node-not-ready=1m".
+// A condition without a duration fires as soon as it is observed.
+func ParseRules(spec string) ([]Rule, error) {
+	var rules []Rule
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		condition, value, hasDuration := strings.Cut(entry, "=")
+		condition = strings.TrimSpace(condition)
+		if !validCondition(condition) {
+			return nil, fmt.Errorf("unknown alert condition %q, expected one of %s", condition, strings.Join(Conditions, ", "))
+		}
+		if seen[condition] {
+			return nil, fmt.Errorf("alert condition %q given more than once", condition)
+		}
+		seen[condition] = true
+
+		rule := Rule{Condition: condition}
+		if hasDuration {
+			duration, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil || duration < 0 {
+				return nil, fmt.Errorf("invalid duration %q for alert condition %s", value, condition)
+			}
+			rule.For = duration
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// validCondition reports whether condition is one of Conditions
+func validCondition(condition string) bool {
+	for _, c := range Conditions {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot is the cluster state rules are evaluated against
+type Snapshot struct {
+	Pods        []Pod
+	Deployments []Deployment
+	Nodes       []Node
+}
+
+// Pod is a pod as seen by the rules
+type Pod struct {
+	Namespace string
+	Name      string
+	Status    string
+	Ready     bool
+}
+
+// Deployment is a deployment as seen by the rules
+type Deployment struct {
+	Namespace     string
+	Name          string
+	Replicas      int32
+	ReadyReplicas int32
+}
+
+// Node is a node as seen by the rules
+type Node struct {
+	Name  string
+	Ready bool
+}
+
+// Alert is a change in a rule's state for one subject
+type Alert struct {
+	Condition string    `json:"condition"`
+	State     string    `json:"state"`
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Since     time.Time `json:"since"`
+	Time      time.Time `json:"time"`
+}
+
+// Subject names what the alert is about, e.g. "Pod shop/web-1"
+func (a Alert) Subject() string {
+	if a.Namespace != "" {
+		return a.Kind + " " + a.Namespace + "/" + a.Name
+	}
+	return a.Kind + " " + a.Name
+}
+
+// violation is a subject currently matching a rule's condition
+type violation struct {
+	kind, namespace, name, detail string
+}
+
+// key identifies the violation across snapshots
+func (v violation) key(condition string) string {
+	return condition + "/" + v.kind + "/" + v.namespace + "/" + v.name
+}
+
+// Evaluator tracks how long each subject has matched each rule and sends an
+// alert when a rule starts firing and when it resolves. Snapshots are
+// offered from the broadcast path and evaluated on a separate goroutine,
+// so slow webhooks never delay broadcasts.
+type Evaluator struct {
+	notifiers []Notifier
+
+	mu     sync.Mutex
+	rules  []Rule
+	latest *Snapshot
+
+	// Only touched by Evaluate
+	since  map[string]time.Time
+	firing map[string]Alert
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewEvaluator creates an evaluator sending alerts to every notifier
+func NewEvaluator(rules []Rule, notifiers ...Notifier) *Evaluator {
+	return &Evaluator{
+		rules:     rules,
+		notifiers: notifiers,
+		since:     make(map[string]time.Time),
+		firing:    make(map[string]Alert),
+		done:      make(chan struct{}),
+		stopped:   make(chan struct{}),
+	}
+}
+
+// Offer records snapshot as the latest cluster state; it never blocks on
+// notifiers
+func (e *Evaluator) Offer(snapshot Snapshot) {
+	e.mu.Lock()
+	e.latest = &snapshot
+	e.mu.Unlock()
+}
+
+// SetRules replaces the rules, e.g. on a configuration reload. Alerts
+// firing for a rule that is dropped resolve on the next evaluation.
+func (e *Evaluator) SetRules(rules []Rule) {
+	e.mu.Lock()
+	e.rules = rules
+	e.mu.Unlock()
+}
+
+// Run evaluates the latest snapshot every evaluationInterval until Stop is
+// called. The same snapshot is evaluated again while no newer one arrives,
+// so rules keep maturing on a quiet cluster.
+func (e *Evaluator) Run() {
+	defer close(e.stopped)
+
+	ticker := time.NewTicker(evaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.mu.Lock()
+			latest := e.latest
+			e.mu.Unlock()
+			if latest == nil {
+				continue
+			}
+
+			if alerts := e.Evaluate(*latest, time.Now()); len(alerts) > 0 {
+				e.notify(alerts)
+			}
+
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// Stop waits for Run to exit
+func (e *Evaluator) Stop() {
+	e.once.Do(func() { close(e.done) })
+	<-e.stopped
+}
+
+// Evaluate compares snapshot, taken at now, with the state of earlier
+// evaluations and returns the alerts that started firing or resolved.
+// Subjects that disappear from the snapshot resolve.
+func (e *Evaluator) Evaluate(snapshot Snapshot, now time.Time) []Alert {
+	var alerts []Alert
+	current := make(map[string]bool)
+
+	e.mu.Lock()
+	rules := e.rules
+	e.mu.Unlock()
+	for _, rule := range rules {
+		for _, v := range violations(rule.Condition, snapshot) {
+			key := v.key(rule.Condition)
+			current[key] = true
+
+			since, ok := e.since[key]
+			if !ok {
+				since = now
+				e.since[key] = since
+			}
+			if _, firing := e.firing[key]; firing || now.Sub(since) < rule.For {
+				continue
+			}
+
+			alert := Alert{
+				Condition: rule.Condition,
+				State:     StateFiring,
+				Kind:      v.kind,
+				Namespace: v.namespace,
+				Name:      v.name,
+				Message:   fmt.Sprintf("%s for %s", v.detail, now.Sub(since).Round(time.Second)),
+				Since:     since,
+				Time:      now,
+			}
+			e.firing[key] = alert
+			alerts = append(alerts, alert)
+		}
+	}
+
+	for key := range e.since {
+		if current[key] {
+			continue
+		}
+		delete(e.since, key)
+		if alert, firing := e.firing[key]; firing {
+			delete(e.firing, key)
+			alert.State = StateResolved
+			alert.Message = fmt.Sprintf("%s recovered after %s", alert.Subject(), now.Sub(alert.Since).Round(time.Second))
+			alert.Time = now
+			alerts = append(alerts, alert)
+		}
+	}
+
+	// Map iteration is random; keep notifications in a stable order
+	sort.SliceStable(alerts, func(i, j int) bool {
+		if alerts[i].State != alerts[j].State {
+			return alerts[i].State == StateFiring
+		}
+		return alerts[i].Subject() < alerts[j].Subject()
+	})
+
+	return alerts
+}
+
+// violations lists the subjects in snapshot matching condition
+func violations(condition string, snapshot Snapshot) []violation {
+	var matches []violation
+	switch condition {
+	case PodNotReady:
+		for _, pod := range snapshot.Pods {
+			if !pod.Ready {
+				matches = append(matches, violation{"Pod", pod.Namespace, pod.Name,
+					fmt.Sprintf("Pod %s/%s not ready (%s)", pod.Namespace, pod.Name, pod.Status)})
+			}
+		}
+	case DeploymentBelowDesired:
+		for _, deployment := range snapshot.Deployments {
+			if deployment.ReadyReplicas < deployment.Replicas {
+				matches = append(matches, violation{"Deployment", deployment.Namespace, deployment.Name,
+					fmt.Sprintf("Deployment %s/%s at %d/%d ready replicas", deployment.Namespace, deployment.Name, deployment.ReadyReplicas, deployment.Replicas)})
+			}
+		}
+	case NodeNotReady:
+		for _, node := range snapshot.Nodes {
+			if !node.Ready {
+				matches = append(matches, violation{"Node", "", node.Name,
+					fmt.Sprintf("Node %s NotReady", node.Name)})
+			}
+		}
+	}
+	return matches
+}
+
+// notify sends alerts to every notifier, logging failures
+func (e *Evaluator) notify(alerts []Alert) {
+	for _, notifier := range e.notifiers {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		if err := notifier.Notify(ctx, alerts); err != nil {
+			slog.Error("Failed to send alerts", "notifier", notifier.Name(), "alerts", len(alerts), "error", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,154 @@
+package alerts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name: "default rules",
+			spec: DefaultRules,
+			want: []Rule{
+				{Condition: PodNotReady, For: 5 * time.Minute},
+				{Condition: DeploymentBelowDesired, For: 5 * time.Minute},
+				{Condition: NodeNotReady, For: 2 * time.Minute},
+			},
+		},
+		{
+			name: "without a duration fires at once",
+			spec: " node-not-ready , ",
+			want: []Rule{{Condition: NodeNotReady}},
+		},
+		{name: "empty", spec: ""},
+		{name: "unknown condition", spec: "pod-crashing=1m", wantErr: true},
+		{name: "duplicate condition", spec: "pod-not-ready=1m,pod-not-ready=2m", wantErr: true},
+		{name: "invalid duration", spec: "pod-not-ready=soon", wantErr: true},
+		{name: "negative duration", spec: "pod-not-ready=-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRules(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRules(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRules(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+// states summarises alerts as "state subject" lines
+func states(alerts []Alert) []string {
+	var got []string
+	for _, alert := range alerts {
+		got = append(got, alert.State+" "+alert.Subject())
+	}
+	return got
+}
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEvaluator([]Rule{
+		{Condition: PodNotReady, For: 5 * time.Minute},
+		{Condition: NodeNotReady},
+	})
+
+	unhealthy := Snapshot{
+		Pods:  []Pod{{Namespace: "shop", Name: "web-1", Status: "Pending"}, {Namespace: "shop", Name: "web-2", Ready: true}},
+		Nodes: []Node{{Name: "node-a"}},
+	}
+	steps := []struct {
+		name     string
+		snapshot Snapshot
+		after    time.Duration
+		want     []string
+	}{
+		{name: "node fires at once", snapshot: unhealthy, want: []string{"firing Node node-a"}},
+		{name: "pod not ready long enough", snapshot: unhealthy, after: 4 * time.Minute},
+		{name: "pod fires after its duration", snapshot: unhealthy, after: 5 * time.Minute, want: []string{"firing Pod shop/web-1"}},
+		{name: "firing alerts are not repeated", snapshot: unhealthy, after: 6 * time.Minute},
+		{name: "recovered subjects resolve", snapshot: Snapshot{}, after: 7 * time.Minute, want: []string{"resolved Node node-a", "resolved Pod shop/web-1"}},
+		{name: "nothing left to resolve", snapshot: Snapshot{}, after: 8 * time.Minute},
+	}
+
+	for _, step := range steps {
+		alerts := e.Evaluate(step.snapshot, start.Add(step.after))
+		if got := states(alerts); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: Evaluate() = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestEvaluateMessages(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEvaluator([]Rule{{Condition: DeploymentBelowDesired, For: time.Minute}})
+	snapshot := Snapshot{Deployments: []Deployment{
+		{Namespace: "shop", Name: "web", Replicas: 3, ReadyReplicas: 1},
+		{Namespace: "shop", Name: "api", Replicas: 2, ReadyReplicas: 2},
+	}}
+
+	e.Evaluate(snapshot, start)
+	alerts := e.Evaluate(snapshot, start.Add(90*time.Second))
+	if len(alerts) != 1 {
+		t.Fatalf("Evaluate() = %v, want one firing alert", states(alerts))
+	}
+	want := Alert{
+		Condition: DeploymentBelowDesired,
+		State:     StateFiring,
+		Kind:      "Deployment",
+		Namespace: "shop",
+		Name:      "web",
+		Message:   "Deployment shop/web at 1/3 ready replicas for 1m30s",
+		Since:     start,
+		Time:      start.Add(90 * time.Second),
+	}
+	if alerts[0] != want {
+		t.Errorf("Evaluate() = %+v, want %+v", alerts[0], want)
+	}
+
+	alerts = e.Evaluate(Snapshot{}, start.Add(2*time.Minute))
+	if len(alerts) != 1 || alerts[0].Message != "Deployment shop/web recovered after 2m0s" {
+		t.Errorf("Evaluate() = %+v, want the alert resolved after 2m0s", alerts)
+	}
+}
+
+func TestSetRulesResolvesDroppedRules(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEvaluator([]Rule{{Condition: NodeNotReady}})
+	snapshot := Snapshot{Nodes: []Node{{Name: "node-a"}}}
+
+	if got := states(e.Evaluate(snapshot, now)); !reflect.DeepEqual(got, []string{"firing Node node-a"}) {
+		t.Fatalf("Evaluate() = %v, want node-a firing", got)
+	}
+	e.SetRules(nil)
+	if got := states(e.Evaluate(snapshot, now.Add(time.Minute))); !reflect.DeepEqual(got, []string{"resolved Node node-a"}) {
+		t.Errorf("Evaluate() after SetRules(nil) = %v, want node-a resolved", got)
+	}
+}
+
+func TestStop(t *testing.T) {
+	e := NewEvaluator(nil)
+	go e.Run()
+
+	stopped := make(chan struct{})
+	go func() {
+		e.Stop()
+		e.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() did not return")
+	}
+}
@@ -0,0 +1,100 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+)
+
+// Notifier delivers alerts somewhere
+type Notifier interface {
+	// Name identifies the notifier in logs
+	Name() string
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// Webhook posts alerts as JSON, {"alerts": [...]}, to a URL
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name identifies the webhook in logs without its URL, which may embed a
+// secret token
+func (w Webhook) Name() string {
+	return "webhook"
+}
+
+// Notify posts the alerts in one request
+func (w Webhook) Notify(ctx context.Context, alerts []Alert) error {
+	return post(ctx, w.Client, w.URL, map[string][]Alert{"alerts": alerts})
+}
+
+// Slack posts alerts to a Slack incoming webhook as one message
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name identifies the Slack webhook in logs
+func (s Slack) Name() string {
+	return "slack"
+}
+
+// Notify posts the alerts as a single message, one line per alert
+func (s Slack) Notify(ctx context.Context, alerts []Alert) error {
+	lines := make([]string, len(alerts))
+	for i, alert := range alerts {
+		lines[i] = slackLine(alert)
+	}
+	return post(ctx, s.Client, s.URL, map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+// slackLine formats one alert, e.g.
+// ":rotating_light: *FIRING* pod-not-ready: Pod shop/web-1 not ready (Pending) for 5m0s"
+func slackLine(alert Alert) string {
+	icon := ":rotating_light:"
+	if alert.State == StateResolved {
+		icon = ":white_check_mark:"
+	}
+	return fmt.Sprintf("%s *%s* %s: %s", icon, strings.ToUpper(alert.State), alert.Condition, alert.Message)
+}
+
+// post sends body as JSON and treats any non-2xx response as an error
+func post(ctx context.Context, client *http.Client, url string, body any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL may embed a secret token, so keep it out of the error
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
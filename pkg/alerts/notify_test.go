@@ -0,0 +1,87 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAlerts is one firing and one resolved alert
+func testAlerts() []Alert {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return []Alert{
+		{Condition: PodNotReady, State: StateFiring, Kind: "Pod", Namespace: "shop", Name: "web-1", Message: "Pod shop/web-1 not ready (Pending) for 5m0s", Since: since, Time: since.Add(5 * time.Minute)},
+		{Condition: NodeNotReady, State: StateResolved, Kind: "Node", Name: "node-a", Message: "Node node-a recovered after 3m0s", Since: since, Time: since.Add(3 * time.Minute)},
+	}
+}
+
+// recordBody starts a server answering status and storing each request body
+func recordBody(t *testing.T, status int, body *[]byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		*body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		io.WriteString(w, "no_text\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhookNotify(t *testing.T) {
+	var body []byte
+	server := recordBody(t, http.StatusOK, &body)
+
+	if err := (Webhook{URL: server.URL}).Notify(context.Background(), testAlerts()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	var got map[string][]Alert
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid body %s: %v", body, err)
+	}
+	if len(got["alerts"]) != 2 || got["alerts"][0].Subject() != "Pod shop/web-1" || got["alerts"][1].State != StateResolved {
+		t.Errorf("posted %s, want both alerts", body)
+	}
+}
+
+func TestSlackNotify(t *testing.T) {
+	var body []byte
+	server := recordBody(t, http.StatusOK, &body)
+
+	if err := (Slack{URL: server.URL}).Notify(context.Background(), testAlerts()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("invalid body %s: %v", body, err)
+	}
+	want := ":rotating_light: *FIRING* pod-not-ready: Pod shop/web-1 not ready (Pending) for 5m0s\n" +
+		":white_check_mark: *RESOLVED* node-not-ready: Node node-a recovered after 3m0s"
+	if got["text"] != want {
+		t.Errorf("text = %q, want %q", got["text"], want)
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	var body []byte
+	server := recordBody(t, http.StatusBadRequest, &body)
+
+	err := (Slack{URL: server.URL}).Notify(context.Background(), testAlerts())
+	if err == nil || !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "no_text") {
+		t.Errorf("Notify() error = %v, want the status and response body", err)
+	}
+
+	// Connection errors leave the URL, and any token in it, out
+	server.Close()
+	err = (Webhook{URL: server.URL + "/hooks/s3cret"}).Notify(context.Background(), testAlerts())
+	if err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("Notify() error = %v, want an error without the URL", err)
+	}
+}
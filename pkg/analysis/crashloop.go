@@ -0,0 +1,90 @@
+// Package analysis diagnoses pods from their container statuses, beyond
+// what the pod phase tells
+package analysis
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"pod-visualizer/pkg/status"
+)
+
+// The kubelet waits initialBackoff before restarting a crashed container
+// and doubles the wait with each crash, up to maxBackoff
+const (
+	initialBackoff = 10 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// CrashLoop describes a container in CrashLoopBackOff: how its last run
+// ended and how long the kubelet waits before starting it again
+type CrashLoop struct {
+	Container string
+	Restarts  int32
+	// ExitCode and Reason, e.g. Error or OOMKilled, are those of the last
+	// run, which ended at FinishedAt
+	ExitCode   int32
+	Reason     string
+	FinishedAt time.Time
+	Backoff    time.Duration
+}
+
+// NextRestart returns when the kubelet restarts the container
+func (c CrashLoop) NextRestart() time.Time {
+	return c.FinishedAt.Add(c.Backoff)
+}
+
+// CrashLoops returns the pod's init and regular containers that are in
+// CrashLoopBackOff, the most restarted first
+func CrashLoops(pod *corev1.Pod) []CrashLoop {
+	var loops []CrashLoop
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, containerStatus := range statuses {
+			waiting := containerStatus.State.Waiting
+			if waiting == nil || waiting.Reason != status.CrashLoopBackOff {
+				continue
+			}
+
+			loop := CrashLoop{
+				Container: containerStatus.Name,
+				Restarts:  containerStatus.RestartCount,
+				Backoff:   backoff(waiting.Message, containerStatus.RestartCount),
+			}
+			if last := containerStatus.LastTerminationState.Terminated; last != nil {
+				loop.ExitCode = last.ExitCode
+				loop.Reason = last.Reason
+				loop.FinishedAt = last.FinishedAt.Time
+			}
+			loops = append(loops, loop)
+		}
+	}
+
+	sort.SliceStable(loops, func(i, j int) bool { return loops[i].Restarts > loops[j].Restarts })
+	return loops
+}
+
+// backoff returns the kubelet's restart delay, read from its waiting
+// message such as "back-off 40s restarting failed container=web ..." or,
+// failing that, estimated from the restart count
+func backoff(message string, restarts int32) time.Duration {
+	if _, rest, found := strings.Cut(message, "back-off "); found {
+		delay, _, _ := strings.Cut(rest, " ")
+		if d, err := time.ParseDuration(delay); err == nil {
+			return d
+		}
+	}
+	return Backoff(restarts)
+}
+
+// Backoff estimates the kubelet's restart delay after restarts restarts:
+// 10s after the first crash, doubling up to 5m
+func Backoff(restarts int32) time.Duration {
+	delay := initialBackoff
+	for i := int32(1); i < restarts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
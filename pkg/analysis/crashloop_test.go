@@ -0,0 +1,77 @@
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// crashing returns the status of a container in CrashLoopBackOff after
+// restarts restarts, its last run ending at finished with exit code 1
+func crashing(name string, restarts int32, message string, finished time.Time) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         name,
+		RestartCount: restarts,
+		State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: message}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1, Reason: "Error", FinishedAt: metav1.NewTime(finished),
+		}},
+	}
+}
+
+func TestCrashLoops(t *testing.T) {
+	finished := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{Status: corev1.PodStatus{
+		InitContainerStatuses: []corev1.ContainerStatus{
+			crashing("migrate", 2, "", finished),
+		},
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			crashing("web", 4, "back-off 40s restarting failed container=web pod=web-1", finished),
+			{Name: "pulling", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+		},
+	}}
+
+	loops := CrashLoops(pod)
+	if len(loops) != 2 {
+		t.Fatalf("CrashLoops() = %+v, want web and migrate", loops)
+	}
+
+	web := loops[0]
+	if web.Container != "web" || web.Restarts != 4 || web.ExitCode != 1 || web.Reason != "Error" {
+		t.Errorf("CrashLoops()[0] = %+v, want web, the most restarted, first", web)
+	}
+	if web.Backoff != 40*time.Second {
+		t.Errorf("web backoff = %v, want the kubelet's 40s", web.Backoff)
+	}
+	if want := finished.Add(40 * time.Second); !web.NextRestart().Equal(want) {
+		t.Errorf("NextRestart() = %v, want %v", web.NextRestart(), want)
+	}
+
+	// Without a message the backoff is estimated from the restarts
+	if migrate := loops[1]; migrate.Container != "migrate" || migrate.Backoff != 20*time.Second {
+		t.Errorf("CrashLoops()[1] = %+v, want migrate with a 20s backoff", migrate)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		restarts int32
+		want     time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{5, 160 * time.Second},
+		{6, 5 * time.Minute},
+		{100, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.restarts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.restarts, got, tt.want)
+		}
+	}
+}
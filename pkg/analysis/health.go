@@ -0,0 +1,54 @@
+package analysis
+
+// Health score weights, adding up to 100. Readiness weighs most; a stuck
+// rollout costs as much as restarts at their worst.
+const (
+	readinessWeight = 50
+	restartsWeight  = 20
+	warningsWeight  = 10
+	rolloutWeight   = 20
+
+	// restartsPerReplica is how many restarts per replica cost all of
+	// restartsWeight
+	restartsPerReplica = 5
+)
+
+// Health score bands: at or above HealthyScore a deployment is healthy,
+// below DegradedScore it is failing, and degraded in between
+const (
+	HealthyScore  = 90
+	DegradedScore = 60
+)
+
+// DeploymentHealth is what a deployment's health score is computed from
+type DeploymentHealth struct {
+	Replicas      int32
+	ReadyReplicas int32
+	// Restarts are those of the deployment's pods, WarningEvents the
+	// recent Warning events about it, its ReplicaSets and pods
+	Restarts      int32
+	WarningEvents int
+	// RolloutStuck is set once the rollout exceeded its progress deadline
+	RolloutStuck bool
+}
+
+// HealthScore rates a deployment from 0 to 100, 100 being healthy, so
+// that how bad it is comes down to a single number. Missing replicas
+// cost up to 50 points, pod restarts up to 20 (reached at 5 restarts per
+// replica), recent warning events a point each up to 10, and a stuck
+// rollout 20.
+func HealthScore(health DeploymentHealth) int {
+	score := 100.0
+	if health.Replicas > 0 {
+		missing := max(health.Replicas-health.ReadyReplicas, 0)
+		score -= readinessWeight * float64(missing) / float64(health.Replicas)
+	}
+
+	restarts := float64(health.Restarts) / float64(max(health.Replicas, 1))
+	score -= min(restartsWeight, restartsWeight*restarts/restartsPerReplica)
+	score -= float64(min(warningsWeight, health.WarningEvents))
+	if health.RolloutStuck {
+		score -= rolloutWeight
+	}
+	return max(int(score), 0)
+}
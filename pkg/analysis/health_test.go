@@ -0,0 +1,34 @@
+package analysis
+
+import "testing"
+
+func TestHealthScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		health DeploymentHealth
+		want   int
+	}{
+		{name: "healthy", health: DeploymentHealth{Replicas: 3, ReadyReplicas: 3}, want: 100},
+		{name: "scaled to zero", health: DeploymentHealth{}, want: 100},
+		{name: "one of two ready", health: DeploymentHealth{Replicas: 2, ReadyReplicas: 1}, want: 75},
+		{name: "none ready", health: DeploymentHealth{Replicas: 2}, want: 50},
+		{name: "more ready than desired", health: DeploymentHealth{Replicas: 2, ReadyReplicas: 3}, want: 100},
+		{name: "restarts per replica", health: DeploymentHealth{Replicas: 2, ReadyReplicas: 2, Restarts: 5}, want: 90},
+		{name: "restarts are capped", health: DeploymentHealth{Replicas: 1, ReadyReplicas: 1, Restarts: 50}, want: 80},
+		{name: "warnings are capped", health: DeploymentHealth{Replicas: 1, ReadyReplicas: 1, WarningEvents: 25}, want: 90},
+		{name: "stuck rollout", health: DeploymentHealth{Replicas: 1, ReadyReplicas: 1, RolloutStuck: true}, want: 80},
+		{
+			name:   "everything wrong",
+			health: DeploymentHealth{Replicas: 4, Restarts: 100, WarningEvents: 10, RolloutStuck: true},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HealthScore(tt.health); got != tt.want {
+				t.Errorf("HealthScore(%+v) = %d, want %d", tt.health, got, tt.want)
+			}
+		})
+	}
+}
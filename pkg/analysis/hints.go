@@ -0,0 +1,243 @@
+package analysis
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Causes a Hint can name
+const (
+	CauseInsufficientResources = "InsufficientResources"
+	CauseTaints                = "Taints"
+	CauseImagePullAuth         = "ImagePullAuth"
+	CauseImageNotFound         = "ImageNotFound"
+	CauseImagePull             = "ImagePull"
+	CauseOOMKilled             = "OOMKilled"
+	CauseReadinessProbe        = "ReadinessProbe"
+)
+
+// Hint is a likely cause of a pod's trouble, with what to look at
+type Hint struct {
+	Cause string
+	// Container is empty for causes about the whole pod
+	Container string
+	Message   string
+}
+
+// Event is the part of a Kubernetes event about a pod the rules read
+type Event struct {
+	Reason  string
+	Message string
+}
+
+// rule derives hints from a pod and its events, which may be nil
+type rule func(pod *corev1.Pod, events []Event) []Hint
+
+// rules run in order, so the likeliest causes come first: a pod that is
+// not scheduled has no containers to diagnose
+var rules = []rule{unschedulable, imagePull, oomKilled, readinessProbe}
+
+// Hints runs the rules over a pod and, when known, its events, returning
+// the likely causes of its trouble, likeliest first. Healthy pods get
+// none.
+func Hints(pod *corev1.Pod, events []Event) []Hint {
+	var hints []Hint
+	for _, rule := range rules {
+		hints = append(hints, rule(pod, events)...)
+	}
+	return hints
+}
+
+var (
+	insufficientPattern = regexp.MustCompile(`Insufficient ([\w./-]+)`)
+	taintPattern        = regexp.MustCompile(`(\d+) node\(s\) had (?:untolerated )?taints? (\{[^}]*\})`)
+)
+
+// unschedulable explains the scheduler's message for a pod it cannot
+// place, e.g. "0/3 nodes are available: 1 node(s) had untolerated taint
+// {dedicated: gpu}, 2 Insufficient memory."
+func unschedulable(pod *corev1.Pod, events []Event) []Hint {
+	message := ""
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			message = condition.Message
+		}
+	}
+	if message == "" {
+		for _, event := range events {
+			if event.Reason == "FailedScheduling" {
+				message = event.Message
+				break
+			}
+		}
+	}
+
+	var hints []Hint
+	var resources []string
+	for _, match := range insufficientPattern.FindAllStringSubmatch(message, -1) {
+		// Resource names may hold dots, as in nvidia.com/gpu, but not end in one
+		name := strings.TrimRight(match[1], ".")
+		if !slices.Contains(resources, name) {
+			resources = append(resources, name)
+		}
+	}
+	if len(resources) > 0 {
+		hints = append(hints, Hint{
+			Cause:   CauseInsufficientResources,
+			Message: fmt.Sprintf("no node has enough free %s for the pod's requests: lower them or add capacity", strings.Join(resources, " or ")),
+		})
+	}
+	for _, match := range taintPattern.FindAllStringSubmatch(message, -1) {
+		hints = append(hints, Hint{
+			Cause:   CauseTaints,
+			Message: fmt.Sprintf("%s node(s) have the taint %s, which the pod does not tolerate", match[1], match[2]),
+		})
+	}
+	return hints
+}
+
+// Fragments of registry errors refusing access, and of those not finding
+// the image
+var (
+	authErrors     = []string{"unauthorized", "authentication required", "authorization failed", "access denied", "no basic auth credentials", "403 forbidden"}
+	notFoundErrors = []string{"not found", "manifest unknown"}
+)
+
+// imagePull tells image pull auth failures from missing images, reading
+// ErrImagePull messages and Failed events
+func imagePull(pod *corev1.Pod, events []Event) []Hint {
+	var hints []Hint
+	for _, containerStatus := range allStatuses(pod) {
+		waiting := containerStatus.State.Waiting
+		if waiting == nil || (waiting.Reason != "ErrImagePull" && waiting.Reason != "ImagePullBackOff") {
+			continue
+		}
+
+		reasons := []string{strings.ToLower(waiting.Message)}
+		for _, event := range events {
+			if event.Reason == "Failed" && strings.Contains(event.Message, containerStatus.Image) {
+				reasons = append(reasons, strings.ToLower(event.Message))
+			}
+		}
+		hint := Hint{Cause: CauseImagePull, Container: containerStatus.Name,
+			Message: fmt.Sprintf("image %s cannot be pulled: check its name, tag and registry", containerStatus.Image)}
+
+		switch {
+		case mentionsAny(reasons, authErrors):
+			secrets := "the pod has no imagePullSecrets"
+			if len(pod.Spec.ImagePullSecrets) > 0 {
+				names := make([]string, len(pod.Spec.ImagePullSecrets))
+				for i, secret := range pod.Spec.ImagePullSecrets {
+					names[i] = secret.Name
+				}
+				secrets = "check the imagePullSecrets " + strings.Join(names, ", ")
+			}
+			hint.Cause = CauseImagePullAuth
+			hint.Message = fmt.Sprintf("the registry refused to serve %s: %s", containerStatus.Image, secrets)
+		case mentionsAny(reasons, notFoundErrors):
+			hint.Cause = CauseImageNotFound
+			hint.Message = fmt.Sprintf("image %s does not exist: check its name and tag", containerStatus.Image)
+		}
+		hints = append(hints, hint)
+	}
+	return hints
+}
+
+// oomKilled flags containers the kernel killed for exceeding their
+// memory limit, now or on their last run
+func oomKilled(pod *corev1.Pod, events []Event) []Hint {
+	limits := make(map[string]string)
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			limits[container.Name] = limit.String()
+		}
+	}
+
+	var hints []Hint
+	for _, containerStatus := range allStatuses(pod) {
+		current, last := containerStatus.State.Terminated, containerStatus.LastTerminationState.Terminated
+		if (current == nil || current.Reason != "OOMKilled") && (last == nil || last.Reason != "OOMKilled") {
+			continue
+		}
+
+		message := fmt.Sprintf("container %s was OOMKilled at its %s memory limit: raise the limit or find what uses the memory", containerStatus.Name, limits[containerStatus.Name])
+		if limits[containerStatus.Name] == "" {
+			message = fmt.Sprintf("container %s was OOMKilled without a memory limit, so its node ran out of memory: set a limit", containerStatus.Name)
+		}
+		hints = append(hints, Hint{Cause: CauseOOMKilled, Container: containerStatus.Name, Message: message})
+	}
+	return hints
+}
+
+// readinessProbe flags running containers that are not ready although
+// they have started, quoting the probe's last failure from the events
+func readinessProbe(pod *corev1.Pod, events []Event) []Hint {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return nil
+	}
+
+	probes := make(map[string]*corev1.Probe)
+	for _, container := range pod.Spec.Containers {
+		probes[container.Name] = container.ReadinessProbe
+	}
+	failure := ""
+	for _, event := range events {
+		if event.Reason == "Unhealthy" && strings.HasPrefix(event.Message, "Readiness probe failed:") {
+			failure = strings.TrimSpace(strings.TrimPrefix(event.Message, "Readiness probe failed:"))
+			break
+		}
+	}
+
+	var hints []Hint
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		probe := probes[containerStatus.Name]
+		if probe == nil || containerStatus.Ready || containerStatus.State.Running == nil {
+			continue
+		}
+
+		message := fmt.Sprintf("container %s is running but its readiness probe (%s) fails", containerStatus.Name, describeProbe(probe))
+		if failure != "" {
+			message += ": " + failure
+		}
+		hints = append(hints, Hint{Cause: CauseReadinessProbe, Container: containerStatus.Name, Message: message})
+	}
+	return hints
+}
+
+// describeProbe names what a probe checks, e.g. "GET /healthz on 8080"
+func describeProbe(probe *corev1.Probe) string {
+	switch {
+	case probe.HTTPGet != nil:
+		return fmt.Sprintf("GET %s on %s", probe.HTTPGet.Path, probe.HTTPGet.Port.String())
+	case probe.TCPSocket != nil:
+		return "TCP " + probe.TCPSocket.Port.String()
+	case probe.GRPC != nil:
+		return fmt.Sprintf("gRPC on %d", probe.GRPC.Port)
+	case probe.Exec != nil:
+		return strings.Join(probe.Exec.Command, " ")
+	default:
+		return "unknown"
+	}
+}
+
+// allStatuses returns the pod's init and regular container statuses
+func allStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	return append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+}
+
+// mentionsAny reports whether any of texts contains any of fragments
+func mentionsAny(texts, fragments []string) bool {
+	for _, text := range texts {
+		for _, fragment := range fragments {
+			if strings.Contains(text, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
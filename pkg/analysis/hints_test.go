@@ -0,0 +1,146 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// waitingPod is a pod whose one container waits with reason and message
+func waitingPod(reason, message string) *corev1.Pod {
+	return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "web",
+		Image: "registry.example.com/shop/web:1.2",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+	}}}}
+}
+
+func TestHints(t *testing.T) {
+	unschedulable := &corev1.Pod{Status: corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+			Message: "0/3 nodes are available: 1 node(s) had untolerated taint {dedicated: gpu}, 2 Insufficient memory, 2 Insufficient nvidia.com/gpu.",
+		}},
+	}}
+
+	privateImage := waitingPod("ImagePullBackOff", "Back-off pulling image")
+	privateImage.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+
+	oomKilled := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      "web",
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")}},
+		}}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:                 "web",
+			LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+		}}},
+	}
+
+	notReady := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "web",
+			ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)},
+			}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "web",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		pod    *corev1.Pod
+		events []Event
+		want   []Hint
+	}{
+		{
+			name: "healthy",
+			pod:  &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+		},
+		{
+			name: "insufficient resources and taints",
+			pod:  unschedulable,
+			want: []Hint{
+				{Cause: CauseInsufficientResources, Message: "no node has enough free memory or nvidia.com/gpu for the pod's requests: lower them or add capacity"},
+				{Cause: CauseTaints, Message: "1 node(s) have the taint {dedicated: gpu}, which the pod does not tolerate"},
+			},
+		},
+		{
+			name:   "scheduling failure from events",
+			pod:    &corev1.Pod{},
+			events: []Event{{Reason: "FailedScheduling", Message: "0/1 nodes are available: 1 Insufficient cpu."}},
+			want: []Hint{
+				{Cause: CauseInsufficientResources, Message: "no node has enough free cpu for the pod's requests: lower them or add capacity"},
+			},
+		},
+		{
+			name: "image pull without imagePullSecrets",
+			pod:  waitingPod("ErrImagePull", "pull access denied, repository does not exist or may require authorization: 401 unauthorized"),
+			want: []Hint{{Cause: CauseImagePullAuth, Container: "web",
+				Message: "the registry refused to serve registry.example.com/shop/web:1.2: the pod has no imagePullSecrets"}},
+		},
+		{
+			name:   "image pull auth from events",
+			pod:    privateImage,
+			events: []Event{{Reason: "Failed", Message: `Failed to pull image "registry.example.com/shop/web:1.2": 403 Forbidden`}},
+			want: []Hint{{Cause: CauseImagePullAuth, Container: "web",
+				Message: "the registry refused to serve registry.example.com/shop/web:1.2: check the imagePullSecrets registry"}},
+		},
+		{
+			name: "image not found",
+			pod:  waitingPod("ErrImagePull", "manifest unknown: manifest tagged by \"1.2\" is not found"),
+			want: []Hint{{Cause: CauseImageNotFound, Container: "web",
+				Message: "image registry.example.com/shop/web:1.2 does not exist: check its name and tag"}},
+		},
+		{
+			name: "other image pull failures",
+			pod:  waitingPod("ErrImagePull", "dial tcp: i/o timeout"),
+			want: []Hint{{Cause: CauseImagePull, Container: "web",
+				Message: "image registry.example.com/shop/web:1.2 cannot be pulled: check its name, tag and registry"}},
+		},
+		{
+			name: "oom killed at its limit",
+			pod:  oomKilled,
+			want: []Hint{{Cause: CauseOOMKilled, Container: "web",
+				Message: "container web was OOMKilled at its 256Mi memory limit: raise the limit or find what uses the memory"}},
+		},
+		{
+			name:   "failing readiness probe",
+			pod:    notReady,
+			events: []Event{{Reason: "Unhealthy", Message: "Readiness probe failed: HTTP probe failed with statuscode: 503"}},
+			want: []Hint{{Cause: CauseReadinessProbe, Container: "web",
+				Message: "container web is running but its readiness probe (GET /healthz on 8080) fails: HTTP probe failed with statuscode: 503"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Hints(tt.pod, tt.events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Hints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOOMKilledWithoutLimit(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+		Name:  "worker",
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}}}}
+
+	want := []Hint{{Cause: CauseOOMKilled, Container: "worker",
+		Message: "container worker was OOMKilled without a memory limit, so its node ran out of memory: set a limit"}}
+	if got := Hints(pod, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Hints() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Rules a Violation can break
+const (
+	RuleTopologySpread  = "TopologySpread"
+	RulePodAntiAffinity = "PodAntiAffinity"
+)
+
+// Spread is how a workload's pods are placed across the topology domains
+// of the nodes, such as nodes and zones, and the rules of its pod
+// template they break
+type Spread struct {
+	Topologies []Topology
+	Violations []Violation
+}
+
+// Topology counts a workload's pods in each domain of a topology key, the
+// domains without pods included
+type Topology struct {
+	Key     string
+	Domains []Domain
+	// Skew is how many more pods the fullest domain has than the emptiest
+	Skew int
+}
+
+// Domain is a value of a topology key and how many pods run there
+type Domain struct {
+	Name string
+	Pods int
+}
+
+// Violation is a topology spread constraint or anti-affinity term that the
+// current placement breaks. Required ones are DoNotSchedule constraints and
+// requiredDuringScheduling terms, which only hold for pods as they are
+// scheduled, the others preferences the scheduler may have had to drop.
+type Violation struct {
+	Rule     string
+	Key      string
+	Required bool
+	Message  string
+}
+
+// PodSpread places pods, the running pods of a workload, on nodes along
+// the hostname, the zone when nodes carry zone labels, and every topology
+// key spec's topologySpreadConstraints and podAntiAffinity terms name, and
+// checks those rules against the placement. As for the scheduler, only
+// the nodes spec's nodeSelector allows count. Pods not bound to one of
+// them are left out.
+func PodSpread(spec *corev1.PodSpec, pods []corev1.Pod, nodes []Node) Spread {
+	eligible := make(map[string]bool, len(nodes))
+	var candidates []Node
+	for _, node := range nodes {
+		if selects(spec.NodeSelector, node.Labels) {
+			eligible[node.Name] = true
+			candidates = append(candidates, node)
+		}
+	}
+	nodes = candidates
+
+	var placed []corev1.Pod
+	for _, pod := range pods {
+		if eligible[pod.Spec.NodeName] {
+			placed = append(placed, pod)
+		}
+	}
+
+	var spread Spread
+	for _, key := range topologyKeys(spec, nodes) {
+		domains := countDomains(key, placed, nodes, labels.Everything())
+		spread.Topologies = append(spread.Topologies, Topology{Key: key, Domains: domains, Skew: skew(domains)})
+	}
+
+	for _, constraint := range spec.TopologySpreadConstraints {
+		selector := selectorOf(constraint.LabelSelector)
+		domains := countDomains(constraint.TopologyKey, placed, nodes, selector)
+		if have := skew(domains); have > int(constraint.MaxSkew) {
+			spread.Violations = append(spread.Violations, Violation{
+				Rule:     RuleTopologySpread,
+				Key:      constraint.TopologyKey,
+				Required: constraint.WhenUnsatisfiable == corev1.DoNotSchedule,
+				Message:  fmt.Sprintf("skew %d across %s exceeds maxSkew %d", have, constraint.TopologyKey, constraint.MaxSkew),
+			})
+		}
+	}
+
+	if affinity := spec.Affinity; affinity != nil && affinity.PodAntiAffinity != nil {
+		antiAffinity := affinity.PodAntiAffinity
+		for _, term := range antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			spread.Violations = append(spread.Violations, antiAffinityViolations(term, true, placed, nodes)...)
+		}
+		for _, weighted := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			spread.Violations = append(spread.Violations, antiAffinityViolations(weighted.PodAffinityTerm, false, placed, nodes)...)
+		}
+	}
+
+	return spread
+}
+
+// topologyKeys returns the hostname, the zone when a node has one, and the
+// keys named by spec's rules, each once
+func topologyKeys(spec *corev1.PodSpec, nodes []Node) []string {
+	keys := []string{corev1.LabelHostname}
+	for _, node := range nodes {
+		if _, ok := node.Labels[corev1.LabelTopologyZone]; ok {
+			keys = append(keys, corev1.LabelTopologyZone)
+			break
+		}
+	}
+
+	add := func(key string) {
+		if key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	for _, constraint := range spec.TopologySpreadConstraints {
+		add(constraint.TopologyKey)
+	}
+	if affinity := spec.Affinity; affinity != nil && affinity.PodAntiAffinity != nil {
+		for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			add(term.TopologyKey)
+		}
+		for _, weighted := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			add(weighted.PodAffinityTerm.TopologyKey)
+		}
+	}
+	return keys
+}
+
+// antiAffinityViolations reports the domains of term's topology key where
+// more than one of the pods term selects run together
+func antiAffinityViolations(term corev1.PodAffinityTerm, required bool, pods []corev1.Pod, nodes []Node) []Violation {
+	var violations []Violation
+	for _, domain := range countDomains(term.TopologyKey, pods, nodes, selectorOf(term.LabelSelector)) {
+		if domain.Pods > 1 {
+			violations = append(violations, Violation{
+				Rule:     RulePodAntiAffinity,
+				Key:      term.TopologyKey,
+				Required: required,
+				Message:  fmt.Sprintf("%d pods share %s=%s", domain.Pods, term.TopologyKey, domain.Name),
+			})
+		}
+	}
+	return violations
+}
+
+// countDomains counts the pods matching selector in each domain of key,
+// sorted by domain. Every node with the key is a domain, the hostname
+// falling back to the node name.
+func countDomains(key string, pods []corev1.Pod, nodes []Node, selector labels.Selector) []Domain {
+	domainOf := make(map[string]string)
+	counts := make(map[string]int)
+	for _, node := range nodes {
+		domain, ok := node.Labels[key]
+		if !ok && key == corev1.LabelHostname {
+			domain, ok = node.Name, true
+		}
+		if ok {
+			domainOf[node.Name] = domain
+			counts[domain] = 0
+		}
+	}
+
+	for _, pod := range pods {
+		if domain, ok := domainOf[pod.Spec.NodeName]; ok && selector.Matches(labels.Set(pod.Labels)) {
+			counts[domain]++
+		}
+	}
+
+	domains := make([]Domain, 0, len(counts))
+	for name, count := range counts {
+		domains = append(domains, Domain{Name: name, Pods: count})
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Name < domains[j].Name
+	})
+	return domains
+}
+
+// skew is the difference between the most and the fewest pods in domains
+func skew(domains []Domain) int {
+	if len(domains) == 0 {
+		return 0
+	}
+	most, fewest := domains[0].Pods, domains[0].Pods
+	for _, domain := range domains[1:] {
+		most = max(most, domain.Pods)
+		fewest = min(fewest, domain.Pods)
+	}
+	return most - fewest
+}
+
+// selectorOf converts a rule's label selector; as in the scheduler, a
+// missing or invalid one selects no pods
+func selectorOf(selector *metav1.LabelSelector) labels.Selector {
+	if selector == nil {
+		return labels.Nothing()
+	}
+	converted, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return labels.Nothing()
+	}
+	return converted
+}
@@ -0,0 +1,91 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// spreadNodes are two nodes in zone-a, one in zone-b and one outside the
+// web pool
+func spreadNodes() []Node {
+	zone := func(name, zone, pool string) Node {
+		return Node{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone, "pool": pool}}
+	}
+	return []Node{zone("node-a", "zone-a", "web"), zone("node-b", "zone-a", "web"), zone("node-c", "zone-b", "web"), zone("batch-a", "zone-b", "batch")}
+}
+
+// webPod is a pod labelled app=web bound to node
+func webPod(name, node string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": "web"}},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+}
+
+func TestPodSpread(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	spec := &corev1.PodSpec{
+		NodeSelector: map[string]string{"pool": "web"},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+			MaxSkew: 1, TopologyKey: corev1.LabelTopologyZone, WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: selector,
+		}},
+		Affinity: &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+				Weight:          100,
+				PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: corev1.LabelHostname, LabelSelector: selector},
+			}},
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+				TopologyKey: corev1.LabelTopologyZone, LabelSelector: selector,
+			}},
+		}},
+	}
+	pods := []corev1.Pod{webPod("web-1", "node-a"), webPod("web-2", "node-b"), webPod("web-3", "batch-a"), webPod("web-4", "")}
+
+	got := PodSpread(spec, pods, spreadNodes())
+
+	// batch-a is outside the nodeSelector, so neither it nor web-3 count
+	wantTopologies := []Topology{
+		{Key: corev1.LabelHostname, Domains: []Domain{{"node-a", 1}, {"node-b", 1}, {"node-c", 0}}, Skew: 1},
+		{Key: corev1.LabelTopologyZone, Domains: []Domain{{"zone-a", 2}, {"zone-b", 0}}, Skew: 2},
+	}
+	if !reflect.DeepEqual(got.Topologies, wantTopologies) {
+		t.Errorf("Topologies = %+v, want %+v", got.Topologies, wantTopologies)
+	}
+
+	wantViolations := []Violation{
+		{Rule: RuleTopologySpread, Key: corev1.LabelTopologyZone, Required: true, Message: "skew 2 across topology.kubernetes.io/zone exceeds maxSkew 1"},
+		{Rule: RulePodAntiAffinity, Key: corev1.LabelTopologyZone, Required: true, Message: "2 pods share topology.kubernetes.io/zone=zone-a"},
+	}
+	if !reflect.DeepEqual(got.Violations, wantViolations) {
+		t.Errorf("Violations = %+v, want %+v", got.Violations, wantViolations)
+	}
+}
+
+func TestPodSpreadWithoutRules(t *testing.T) {
+	nodes := []Node{{Name: "node-a"}, {Name: "node-b"}}
+	pods := []corev1.Pod{webPod("web-1", "node-a"), webPod("web-2", "node-a")}
+
+	got := PodSpread(&corev1.PodSpec{}, pods, nodes)
+	want := Spread{Topologies: []Topology{
+		{Key: corev1.LabelHostname, Domains: []Domain{{"node-a", 2}, {"node-b", 0}}, Skew: 2},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PodSpread() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPodSpreadMissingSelector(t *testing.T) {
+	// Like the scheduler, a rule without a label selector selects no pods
+	spec := &corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+		MaxSkew: 1, TopologyKey: corev1.LabelHostname, WhenUnsatisfiable: corev1.ScheduleAnyway,
+	}}}
+	nodes := []Node{{Name: "node-a"}, {Name: "node-b"}}
+	pods := []corev1.Pod{webPod("web-1", "node-a"), webPod("web-2", "node-a")}
+
+	if got := PodSpread(spec, pods, nodes).Violations; len(got) != 0 {
+		t.Errorf("Violations = %+v, want none", got)
+	}
+}
@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Node is what BlockingTaints reads of a node
+type Node struct {
+	Name   string
+	Labels map[string]string
+	Taints []corev1.Taint
+}
+
+// TaintBlock is a taint a pod does not tolerate, as key=value:Effect, and
+// the nodes it keeps the pod off
+type TaintBlock struct {
+	Taint string
+	Nodes []string
+}
+
+// BlockingTaints cross-references a Pending pod's tolerations against the
+// taints of the nodes its nodeSelector allows. For each node it takes the
+// first NoSchedule or NoExecute taint the pod does not tolerate, and
+// groups the nodes by that taint, most nodes first. It returns nil when a
+// node is left that the pod tolerates, since taints then do not explain
+// why it is Pending, and when the nodeSelector allows no node at all.
+func BlockingTaints(tolerations []corev1.Toleration, nodeSelector map[string]string, nodes []Node) []TaintBlock {
+	blocked := make(map[string][]string)
+	candidates := 0
+	for _, node := range nodes {
+		if !selects(nodeSelector, node.Labels) {
+			continue
+		}
+		candidates++
+
+		taint := untolerated(tolerations, node.Taints)
+		if taint == nil {
+			return nil
+		}
+		blocked[taint.ToString()] = append(blocked[taint.ToString()], node.Name)
+	}
+	if candidates == 0 {
+		return nil
+	}
+
+	blocks := make([]TaintBlock, 0, len(blocked))
+	for taint, names := range blocked {
+		sort.Strings(names)
+		blocks = append(blocks, TaintBlock{Taint: taint, Nodes: names})
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if len(blocks[i].Nodes) != len(blocks[j].Nodes) {
+			return len(blocks[i].Nodes) > len(blocks[j].Nodes)
+		}
+		return blocks[i].Taint < blocks[j].Taint
+	})
+	return blocks
+}
+
+// untolerated returns the first taint that keeps pods off the node and
+// none of tolerations tolerates, or nil
+func untolerated(tolerations []corev1.Toleration, taints []corev1.Taint) *corev1.Taint {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint
+		}
+	}
+	return nil
+}
+
+// selects reports whether labels satisfy every term of a nodeSelector
+func selects(nodeSelector, labels map[string]string) bool {
+	for key, value := range nodeSelector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestBlockingTaints(t *testing.T) {
+	gpu := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	draining := corev1.Taint{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule}
+	preferred := corev1.Taint{Key: "spot", Value: "true", Effect: corev1.TaintEffectPreferNoSchedule}
+	nodes := []Node{
+		{Name: "gpu-b", Labels: map[string]string{"pool": "gpu"}, Taints: []corev1.Taint{gpu}},
+		{Name: "gpu-a", Labels: map[string]string{"pool": "gpu"}, Taints: []corev1.Taint{gpu}},
+		{Name: "web-a", Labels: map[string]string{"pool": "web"}, Taints: []corev1.Taint{draining}},
+		{Name: "spot-a", Labels: map[string]string{"pool": "spot"}, Taints: []corev1.Taint{preferred}},
+	}
+	toleratesGPU := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+
+	tests := []struct {
+		name         string
+		tolerations  []corev1.Toleration
+		nodeSelector map[string]string
+		nodes        []Node
+		want         []TaintBlock
+	}{
+		{
+			name:         "grouped by taint, most nodes first",
+			nodeSelector: map[string]string{"pool": "gpu"},
+			nodes:        append(nodes, Node{Name: "web-b", Labels: map[string]string{"pool": "gpu"}, Taints: []corev1.Taint{draining}}),
+			want: []TaintBlock{
+				{Taint: "dedicated=gpu:NoSchedule", Nodes: []string{"gpu-a", "gpu-b"}},
+				{Taint: "node.kubernetes.io/unschedulable:NoSchedule", Nodes: []string{"web-b"}},
+			},
+		},
+		{
+			name:         "tolerated taints do not block",
+			tolerations:  toleratesGPU,
+			nodeSelector: map[string]string{"pool": "gpu"},
+			nodes:        nodes,
+		},
+		{
+			name:  "a schedulable node explains nothing",
+			nodes: nodes,
+		},
+		{
+			name:         "PreferNoSchedule does not block",
+			nodeSelector: map[string]string{"pool": "spot"},
+			nodes:        nodes,
+		},
+		{
+			name:         "no node matches the nodeSelector",
+			nodeSelector: map[string]string{"pool": "batch"},
+			nodes:        nodes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BlockingTaints(tt.tolerations, tt.nodeSelector, tt.nodes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BlockingTaints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,1165 @@
+// Code generated by go run ./gen. DO NOT EDIT.
+
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// APIVersions is the APIVersions schema of the API
+type APIVersions struct {
+	PreferredVersion string   `json:"preferredVersion"`
+	SchemaVersion    int      `json:"schemaVersion"`
+	Versions         []string `json:"versions"`
+}
+
+// ArgoRolloutData is the ArgoRolloutData schema of the API
+type ArgoRolloutData struct {
+	Aborted         bool                      `json:"aborted,omitempty"`
+	ActiveHash      string                    `json:"activeHash,omitempty"`
+	CreatedAt       time.Time                 `json:"createdAt"`
+	CurrentHash     string                    `json:"currentHash,omitempty"`
+	Message         string                    `json:"message,omitempty"`
+	Name            string                    `json:"name"`
+	Namespace       string                    `json:"namespace"`
+	Paused          bool                      `json:"paused,omitempty"`
+	Phase           string                    `json:"phase"`
+	PreviewHash     string                    `json:"previewHash,omitempty"`
+	ReadyReplicas   int32                     `json:"readyReplicas"`
+	Replicas        int32                     `json:"replicas"`
+	Revisions       []ArgoRolloutRevisionData `json:"revisions"`
+	StableHash      string                    `json:"stableHash,omitempty"`
+	Step            int32                     `json:"step"`
+	StepName        string                    `json:"stepName,omitempty"`
+	Steps           int32                     `json:"steps"`
+	Strategy        string                    `json:"strategy"`
+	UID             string                    `json:"uid"`
+	UpdatedReplicas int32                     `json:"updatedReplicas"`
+	Weight          int32                     `json:"weight"`
+}
+
+// ArgoRolloutRevisionData is the ArgoRolloutRevisionData schema of the API
+type ArgoRolloutRevisionData struct {
+	Hash      string `json:"hash"`
+	Pods      int    `json:"pods"`
+	ReadyPods int    `json:"readyPods"`
+	Role      string `json:"role,omitempty"`
+}
+
+// BatchDescribeRequest is the BatchDescribeRequest schema of the API
+type BatchDescribeRequest struct {
+	Refs []ResourceRef `json:"refs"`
+}
+
+// BatchDescribeResponse is the BatchDescribeResponse schema of the API
+type BatchDescribeResponse struct {
+	Results []BatchDescribeResult `json:"results"`
+}
+
+// BatchDescribeResult is the BatchDescribeResult schema of the API
+type BatchDescribeResult struct {
+	Error string      `json:"error,omitempty"`
+	Pod   *PodData    `json:"pod,omitempty"`
+	Ref   ResourceRef `json:"ref"`
+}
+
+// BlockingTaintData is the BlockingTaintData schema of the API
+type BlockingTaintData struct {
+	Pods  []string `json:"pods"`
+	Taint string   `json:"taint"`
+}
+
+// ClusterData is the ClusterData schema of the API
+type ClusterData struct {
+	ArgoRollouts        []ArgoRolloutData    `json:"argoRollouts,omitempty"`
+	Checksum            string               `json:"checksum"`
+	ContainerPercentage float64              `json:"containerPercentage"`
+	CronJobs            []CronJobData        `json:"cronJobs"`
+	CustomResources     []CustomResourceData `json:"customResources,omitempty"`
+	Deployments         []DeploymentData     `json:"deployments"`
+	HPAs                []HPAData            `json:"hpas"`
+	Ingresses           []IngressData        `json:"ingresses"`
+	Jobs                []JobData            `json:"jobs"`
+	LastUpdated         time.Time            `json:"lastUpdated"`
+	NextCursor          string               `json:"nextCursor,omitempty"`
+	Nodes               []NodeData           `json:"nodes,omitempty"`
+	PDBs                []PDBData            `json:"pdbs"`
+	Pods                []PodData            `json:"pods"`
+	PVCs                []PVCData            `json:"pvcs"`
+	ReadyContainers     int                  `json:"readyContainers"`
+	ReadyReplicas       int32                `json:"readyReplicas"`
+	ReplicaPercentage   float64              `json:"replicaPercentage"`
+	SchemaVersion       int                  `json:"schemaVersion"`
+	Services            []ServiceData        `json:"services"`
+	TotalContainers     int                  `json:"totalContainers"`
+	TotalPods           int                  `json:"totalPods,omitempty"`
+	TotalReplicas       int32                `json:"totalReplicas"`
+	Unavailable         []string             `json:"unavailable,omitempty"`
+}
+
+// ConditionData is the ConditionData schema of the API
+type ConditionData struct {
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	Reason             string     `json:"reason,omitempty"`
+	Status             string     `json:"status"`
+	Type               string     `json:"type"`
+}
+
+// ContainerDetailData is the ContainerDetailData schema of the API
+type ContainerDetailData struct {
+	Image        string     `json:"image"`
+	Message      string     `json:"message,omitempty"`
+	Name         string     `json:"name"`
+	Ready        bool       `json:"ready"`
+	Reason       string     `json:"reason,omitempty"`
+	RestartCount int32      `json:"restartCount"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	State        string     `json:"state"`
+}
+
+// CronJobData is the CronJobData schema of the API
+type CronJobData struct {
+	Active             int        `json:"active"`
+	LastScheduleTime   *time.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time `json:"lastSuccessfulTime,omitempty"`
+	Name               string     `json:"name"`
+	Namespace          string     `json:"namespace"`
+	Schedule           string     `json:"schedule"`
+	Suspended          bool       `json:"suspended"`
+	UID                string     `json:"uid"`
+}
+
+// CustomResourceData is the CustomResourceData schema of the API
+type CustomResourceData struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Desired   int32     `json:"desired"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Ready     int32     `json:"ready"`
+	UID       string    `json:"uid"`
+	Unknown   bool      `json:"unknown,omitempty"`
+}
+
+// DeploymentData is the DeploymentData schema of the API
+type DeploymentData struct {
+	AvailableReplicas int32     `json:"availableReplicas"`
+	CreatedAt         time.Time `json:"createdAt"`
+	GitOpsHealth      string    `json:"gitOpsHealth,omitempty"`
+	GitOpsMessage     string    `json:"gitOpsMessage,omitempty"`
+	GitOpsSource      string    `json:"gitOpsSource,omitempty"`
+	GitOpsSync        string    `json:"gitOpsSync,omitempty"`
+	GitOpsTool        string    `json:"gitOpsTool,omitempty"`
+	HealthScore       int       `json:"healthScore"`
+	Name              string    `json:"name"`
+	Namespace         string    `json:"namespace"`
+	PinnedAtMax       bool      `json:"pinnedAtMax,omitempty"`
+	ReadyReplicas     int32     `json:"readyReplicas"`
+	Replicas          int32     `json:"replicas"`
+	Restarts          int32     `json:"restarts,omitempty"`
+	RolloutStuck      bool      `json:"rolloutStuck,omitempty"`
+	UID               string    `json:"uid"`
+	WarningEvents     int       `json:"warningEvents,omitempty"`
+}
+
+// DeploymentDetailData is the DeploymentDetailData schema of the API
+type DeploymentDetailData struct {
+	AvailableReplicas   int32           `json:"availableReplicas"`
+	Conditions          []ConditionData `json:"conditions"`
+	Events              []EventData     `json:"events"`
+	MaxSurge            string          `json:"maxSurge,omitempty"`
+	MaxUnavailable      string          `json:"maxUnavailable,omitempty"`
+	Name                string          `json:"name"`
+	Namespace           string          `json:"namespace"`
+	Paused              bool            `json:"paused"`
+	ReadyReplicas       int32           `json:"readyReplicas"`
+	Replicas            int32           `json:"replicas"`
+	Revision            int64           `json:"revision"`
+	Revisions           []RevisionData  `json:"revisions"`
+	Rollout             RolloutData     `json:"rollout"`
+	Spread              *SpreadData     `json:"spread,omitempty"`
+	Strategy            string          `json:"strategy"`
+	UID                 string          `json:"uid"`
+	UnavailableReplicas int32           `json:"unavailableReplicas"`
+	UpdatedReplicas     int32           `json:"updatedReplicas"`
+}
+
+// DeploymentStartupData is the DeploymentStartupData schema of the API
+type DeploymentStartupData struct {
+	Deployment string  `json:"deployment"`
+	MaxSeconds float64 `json:"maxSeconds"`
+	Namespace  string  `json:"namespace"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	Pods       int     `json:"pods"`
+}
+
+// DomainData is the DomainData schema of the API
+type DomainData struct {
+	Name string `json:"name"`
+	Pods int    `json:"pods"`
+}
+
+// EventData is the EventData schema of the API
+type EventData struct {
+	Count    int32      `json:"count"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+	Message  string     `json:"message"`
+	Reason   string     `json:"reason"`
+	Type     string     `json:"type"`
+}
+
+// HPAData is the HPAData schema of the API
+type HPAData struct {
+	AtMaxReplicas   bool            `json:"atMaxReplicas"`
+	CurrentReplicas int32           `json:"currentReplicas"`
+	DesiredReplicas int32           `json:"desiredReplicas"`
+	MaxReplicas     int32           `json:"maxReplicas"`
+	Metrics         []HPAMetricData `json:"metrics"`
+	MinReplicas     int32           `json:"minReplicas"`
+	Name            string          `json:"name"`
+	Namespace       string          `json:"namespace"`
+	TargetKind      string          `json:"targetKind"`
+	TargetName      string          `json:"targetName"`
+	UID             string          `json:"uid"`
+}
+
+// HPAMetricData is the HPAMetricData schema of the API
+type HPAMetricData struct {
+	Current string `json:"current"`
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+}
+
+// HintData is the HintData schema of the API
+type HintData struct {
+	Cause     string `json:"cause"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// HistoryResponse is the HistoryResponse schema of the API
+type HistoryResponse struct {
+	Hours   int                 `json:"hours"`
+	Samples []HistorySampleData `json:"samples"`
+}
+
+// HistorySampleData is the HistorySampleData schema of the API
+type HistorySampleData struct {
+	ContainerPercentage float64   `json:"containerPercentage"`
+	Pods                int       `json:"pods"`
+	ReadyContainers     int       `json:"readyContainers"`
+	ReadyReplicas       int32     `json:"readyReplicas"`
+	ReplicaPercentage   float64   `json:"replicaPercentage"`
+	Time                time.Time `json:"time"`
+	TotalContainers     int       `json:"totalContainers"`
+	TotalReplicas       int32     `json:"totalReplicas"`
+}
+
+// IdleResponse is the IdleResponse schema of the API
+type IdleResponse struct {
+	Candidates    []IdleWorkloadData `json:"candidates"`
+	Total         Savings            `json:"total"`
+	TrackingSince time.Time          `json:"trackingSince"`
+	Window        string             `json:"window"`
+}
+
+// IdleWorkloadData is the IdleWorkloadData schema of the API
+type IdleWorkloadData struct {
+	CPUUsageMilli int64     `json:"cpuUsageMilli"`
+	IdleSince     time.Time `json:"idleSince"`
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Replicas      int32     `json:"replicas"`
+	Savings       Savings   `json:"savings"`
+}
+
+// ImageData is the ImageData schema of the API
+type ImageData struct {
+	Containers       int                `json:"containers"`
+	MultipleVersions bool               `json:"multipleVersions"`
+	Namespaces       []string           `json:"namespaces"`
+	Repository       string             `json:"repository"`
+	UsesLatest       bool               `json:"usesLatest"`
+	Versions         []ImageVersionData `json:"versions"`
+}
+
+// ImageVersionData is the ImageVersionData schema of the API
+type ImageVersionData struct {
+	Containers int      `json:"containers"`
+	Namespaces []string `json:"namespaces"`
+	Version    string   `json:"version"`
+}
+
+// ImagesResponse is the ImagesResponse schema of the API
+type ImagesResponse struct {
+	Images           []ImageData `json:"images"`
+	LatestTagged     int         `json:"latestTagged"`
+	MultipleVersions int         `json:"multipleVersions"`
+}
+
+// IngressData is the IngressData schema of the API
+type IngressData struct {
+	Class     string      `json:"class,omitempty"`
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Routes    []RouteData `json:"routes"`
+	UID       string      `json:"uid"`
+}
+
+// JobData is the JobData schema of the API
+type JobData struct {
+	Active         int32      `json:"active"`
+	Complete       bool       `json:"complete"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	Completions    int32      `json:"completions"`
+	Failed         int32      `json:"failed"`
+	JobFailed      bool       `json:"jobFailed"`
+	Name           string     `json:"name"`
+	Namespace      string     `json:"namespace"`
+	Owner          string     `json:"owner,omitempty"`
+	StartTime      *time.Time `json:"startTime,omitempty"`
+	Succeeded      int32      `json:"succeeded"`
+	UID            string     `json:"uid"`
+}
+
+// LaggingDeploymentData is the LaggingDeploymentData schema of the API
+type LaggingDeploymentData struct {
+	DeploymentData  DeploymentData `json:"DeploymentData"`
+	MissingReplicas int32          `json:"missingReplicas"`
+}
+
+// NamespaceData is the NamespaceData schema of the API
+type NamespaceData struct {
+	FailingPods int    `json:"failingPods"`
+	Name        string `json:"name"`
+	Phase       string `json:"phase"`
+	PodCount    int    `json:"podCount"`
+	RunningPods int    `json:"runningPods"`
+}
+
+// NamespaceSummary is the NamespaceSummary schema of the API
+type NamespaceSummary struct {
+	Deployments        int            `json:"deployments"`
+	HealthyDeployments int            `json:"healthyDeployments"`
+	Namespace          string         `json:"namespace"`
+	Pods               int            `json:"pods"`
+	PodsByPhase        map[string]int `json:"podsByPhase"`
+	PodsByQOS          map[string]int `json:"podsByQOS"`
+	ReadyContainers    int            `json:"readyContainers"`
+	ReadyPercentage    float64        `json:"readyPercentage"`
+	TotalContainers    int            `json:"totalContainers"`
+	WarningEvents      int            `json:"warningEvents"`
+}
+
+// NamespacesResponse is the NamespacesResponse schema of the API
+type NamespacesResponse struct {
+	Namespaces []NamespaceData `json:"namespaces"`
+}
+
+// NodeData is the NodeData schema of the API
+type NodeData struct {
+	CPUAllocatableMilli    int64    `json:"cpuAllocatableMilli"`
+	CPUUsageMilli          int64    `json:"cpuUsageMilli"`
+	MemoryAllocatableBytes int64    `json:"memoryAllocatableBytes"`
+	MemoryUsageBytes       int64    `json:"memoryUsageBytes"`
+	Name                   string   `json:"name"`
+	Pressure               []string `json:"pressure,omitempty"`
+	Ready                  bool     `json:"ready"`
+	Zone                   string   `json:"zone,omitempty"`
+}
+
+// NodeFitData is the NodeFitData schema of the API
+type NodeFitData struct {
+	Fits   int32  `json:"fits"`
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NodePodCount is the NodePodCount schema of the API
+type NodePodCount struct {
+	Node string `json:"node"`
+	Pods int    `json:"pods"`
+}
+
+// OverviewResponse is the OverviewResponse schema of the API
+type OverviewResponse struct {
+	CPUAllocatableMilli    int64          `json:"cpuAllocatableMilli"`
+	CPURequestedMilli      int64          `json:"cpuRequestedMilli"`
+	KubernetesVersion      string         `json:"kubernetesVersion,omitempty"`
+	MaxPodsPerNode         int            `json:"maxPodsPerNode"`
+	MemoryAllocatableBytes int64          `json:"memoryAllocatableBytes"`
+	MemoryRequestedBytes   int64          `json:"memoryRequestedBytes"`
+	MinPodsPerNode         int            `json:"minPodsPerNode"`
+	Nodes                  int            `json:"nodes"`
+	Pods                   int            `json:"pods"`
+	PodsPerNode            []NodePodCount `json:"podsPerNode"`
+	ReadyNodes             int            `json:"readyNodes"`
+	UnscheduledPods        int            `json:"unscheduledPods"`
+}
+
+// OwnerData is the OwnerData schema of the API
+type OwnerData struct {
+	Controller bool   `json:"controller"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+}
+
+// PDBData is the PDBData schema of the API
+type PDBData struct {
+	BlockedNodes       []string `json:"blockedNodes"`
+	CurrentHealthy     int32    `json:"currentHealthy"`
+	DesiredHealthy     int32    `json:"desiredHealthy"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	ExpectedPods       int32    `json:"expectedPods"`
+	MaxUnavailable     string   `json:"maxUnavailable,omitempty"`
+	MinAvailable       string   `json:"minAvailable,omitempty"`
+	Name               string   `json:"name"`
+	Namespace          string   `json:"namespace"`
+	UID                string   `json:"uid"`
+	Workloads          []string `json:"workloads"`
+}
+
+// PVCData is the PVCData schema of the API
+type PVCData struct {
+	AccessModes  []string  `json:"accessModes"`
+	Capacity     string    `json:"capacity"`
+	CreatedAt    time.Time `json:"createdAt"`
+	MountedBy    []string  `json:"mountedBy"`
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Phase        string    `json:"phase"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	UID          string    `json:"uid"`
+	VolumeName   string    `json:"volumeName,omitempty"`
+}
+
+// PendingPodData is the PendingPodData schema of the API
+type PendingPodData struct {
+	PodData        PodData `json:"PodData"`
+	PendingSeconds int64   `json:"pendingSeconds"`
+}
+
+// PodData is the PodData schema of the API
+type PodData struct {
+	BackoffSeconds             float64   `json:"backoffSeconds,omitempty"`
+	BlockingTaints             string    `json:"blockingTaints,omitempty"`
+	ContainerCount             int       `json:"containerCount"`
+	CPURequestMilli            int64     `json:"cpuRequestMilli"`
+	CPUUsageMilli              int64     `json:"cpuUsageMilli"`
+	CrashLoopContainer         string    `json:"crashLoopContainer,omitempty"`
+	CreatedAt                  time.Time `json:"createdAt"`
+	Deployment                 string    `json:"deployment,omitempty"`
+	EphemeralContainerCount    int       `json:"ephemeralContainerCount,omitempty"`
+	EphemeralStorageLimitBytes int64     `json:"ephemeralStorageLimitBytes,omitempty"`
+	EphemeralStorageNearLimit  bool      `json:"ephemeralStorageNearLimit"`
+	EphemeralStorageUsedBytes  int64     `json:"ephemeralStorageUsedBytes,omitempty"`
+	EvictionMessage            string    `json:"evictionMessage,omitempty"`
+	ExpectedRuntimeClass       string    `json:"expectedRuntimeClass,omitempty"`
+	GitOpsHealth               string    `json:"gitOpsHealth,omitempty"`
+	GitOpsMessage              string    `json:"gitOpsMessage,omitempty"`
+	GitOpsSource               string    `json:"gitOpsSource,omitempty"`
+	GitOpsSync                 string    `json:"gitOpsSync,omitempty"`
+	GitOpsTool                 string    `json:"gitOpsTool,omitempty"`
+	Hint                       string    `json:"hint,omitempty"`
+	HintCause                  string    `json:"hintCause,omitempty"`
+	InitContainerCount         int       `json:"initContainerCount,omitempty"`
+	InitContainersDone         int       `json:"initContainersDone,omitempty"`
+	LastExitReason             string    `json:"lastExitReason,omitempty"`
+	LastRestartAt              string    `json:"lastRestartAt,omitempty"`
+	MemoryRequestBytes         int64     `json:"memoryRequestBytes"`
+	MemoryUsageBytes           int64     `json:"memoryUsageBytes"`
+	Name                       string    `json:"name"`
+	Namespace                  string    `json:"namespace"`
+	NodeName                   string    `json:"nodeName"`
+	NodePressure               string    `json:"nodePressure,omitempty"`
+	PriorityClassName          string    `json:"priorityClassName,omitempty"`
+	QOSClass                   string    `json:"qosClass,omitempty"`
+	ReadyContainers            int       `json:"readyContainers"`
+	ReadySidecars              int       `json:"readySidecars,omitempty"`
+	Restarts                   int32     `json:"restarts"`
+	RootfsUsedBytes            int64     `json:"rootfsUsedBytes,omitempty"`
+	RuntimeClass               string    `json:"runtimeClass,omitempty"`
+	RuntimeClassMismatch       bool      `json:"runtimeClassMismatch"`
+	SidecarCount               int       `json:"sidecarCount,omitempty"`
+	SidecarsExcluded           bool      `json:"sidecarsExcluded,omitempty"`
+	StartupSeconds             float64   `json:"startupSeconds,omitempty"`
+	Status                     string    `json:"status"`
+	StatusSymbol               string    `json:"statusSymbol"`
+	UID                        string    `json:"uid"`
+	Unschedulable              string    `json:"unschedulable,omitempty"`
+	Zone                       string    `json:"zone,omitempty"`
+}
+
+// PodDetailData is the PodDetailData schema of the API
+type PodDetailData struct {
+	Conditions          []ConditionData       `json:"conditions"`
+	Containers          []ContainerDetailData `json:"containers"`
+	EphemeralContainers []ContainerDetailData `json:"ephemeralContainers,omitempty"`
+	Events              []EventData           `json:"events"`
+	Hints               []HintData            `json:"hints,omitempty"`
+	InitContainers      []ContainerDetailData `json:"initContainers,omitempty"`
+	Name                string                `json:"name"`
+	Namespace           string                `json:"namespace"`
+	NodeName            string                `json:"nodeName"`
+	Owners              []OwnerData           `json:"owners"`
+	Phase               string                `json:"phase"`
+	PodIP               string                `json:"podIP"`
+	QOSClass            string                `json:"qosClass"`
+	ReadinessGates      []ConditionData       `json:"readinessGates,omitempty"`
+	RuntimeClass        string                `json:"runtimeClass,omitempty"`
+	StartTime           *time.Time            `json:"startTime,omitempty"`
+	Status              string                `json:"status"`
+	StatusSymbol        string                `json:"statusSymbol"`
+	Tolerations         []TolerationData      `json:"tolerations"`
+	UID                 string                `json:"uid"`
+	Unschedulable       string                `json:"unschedulable,omitempty"`
+}
+
+// PortForwardData is the PortForwardData schema of the API
+type PortForwardData struct {
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	ID        string    `json:"id"`
+	LocalPort int       `json:"localPort"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	PodPort   int       `json:"podPort"`
+}
+
+// PortForwardRequest is the PortForwardRequest schema of the API
+type PortForwardRequest struct {
+	Port int `json:"port"`
+}
+
+// PreviewData is the PreviewData schema of the API
+type PreviewData struct {
+	Allowed                 bool             `json:"allowed"`
+	Name                    string           `json:"name"`
+	Namespace               string           `json:"namespace"`
+	Nodes                   []NodeFitData    `json:"nodes"`
+	PodCPURequestMilli      int64            `json:"podCpuRequestMilli"`
+	PodMemoryRequestBytes   int64            `json:"podMemoryRequestBytes"`
+	Quotas                  []QuotaCheckData `json:"quotas"`
+	Reasons                 []string         `json:"reasons,omitempty"`
+	Replicas                int32            `json:"replicas"`
+	SchedulableReplicas     int32            `json:"schedulableReplicas"`
+	TotalCPURequestMilli    int64            `json:"totalCpuRequestMilli"`
+	TotalMemoryRequestBytes int64            `json:"totalMemoryRequestBytes"`
+}
+
+// ProblemsResponse is the ProblemsResponse schema of the API
+type ProblemsResponse struct {
+	BlockingTaints      []BlockingTaintData     `json:"blockingTaints"`
+	FurthestFromDesired []LaggingDeploymentData `json:"furthestFromDesired"`
+	LongestPending      []PendingPodData        `json:"longestPending"`
+	MostRestarts        []PodData               `json:"mostRestarts"`
+}
+
+// QuotaCheckData is the QuotaCheckData schema of the API
+type QuotaCheckData struct {
+	Exceeds   bool   `json:"exceeds"`
+	Hard      int64  `json:"hard"`
+	Quota     string `json:"quota"`
+	Requested int64  `json:"requested"`
+	Resource  string `json:"resource"`
+	Used      int64  `json:"used"`
+}
+
+// ResourceRef is the ResourceRef schema of the API
+type ResourceRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// RevisionData is the RevisionData schema of the API
+type RevisionData struct {
+	ChangeCause   string     `json:"changeCause,omitempty"`
+	Created       *time.Time `json:"created,omitempty"`
+	Images        []string   `json:"images"`
+	ReadyReplicas int32      `json:"readyReplicas"`
+	ReplicaSet    string     `json:"replicaSet"`
+	Replicas      int32      `json:"replicas"`
+	Revision      int64      `json:"revision"`
+}
+
+// RolloutData is the RolloutData schema of the API
+type RolloutData struct {
+	Complete bool   `json:"complete"`
+	Message  string `json:"message,omitempty"`
+	Stuck    bool   `json:"stuck"`
+}
+
+// RouteData is the RouteData schema of the API
+type RouteData struct {
+	Host              string `json:"host"`
+	Namespace         string `json:"namespace"`
+	NotReadyEndpoints int    `json:"notReadyEndpoints"`
+	Path              string `json:"path"`
+	Port              string `json:"port,omitempty"`
+	ReadyEndpoints    int    `json:"readyEndpoints"`
+	Service           string `json:"service"`
+	ServiceFound      bool   `json:"serviceFound"`
+}
+
+// Savings is the Savings schema of the API
+type Savings struct {
+	CPURequestMilli    int64 `json:"cpuRequestMilli"`
+	MemoryRequestBytes int64 `json:"memoryRequestBytes"`
+}
+
+// ScaleData is the ScaleData schema of the API
+type ScaleData struct {
+	PreviousReplicas int32 `json:"previousReplicas"`
+	Replicas         int32 `json:"replicas"`
+}
+
+// ScaleRequest is the ScaleRequest schema of the API
+type ScaleRequest struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+// ServiceData is the ServiceData schema of the API
+type ServiceData struct {
+	ClusterIP         string `json:"clusterIP"`
+	HasSelector       bool   `json:"hasSelector"`
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	NotReadyEndpoints int    `json:"notReadyEndpoints"`
+	ReadyEndpoints    int    `json:"readyEndpoints"`
+	Type              string `json:"type"`
+	UID               string `json:"uid"`
+}
+
+// SpreadData is the SpreadData schema of the API
+type SpreadData struct {
+	Topologies []TopologyData  `json:"topologies"`
+	Violations []ViolationData `json:"violations"`
+}
+
+// StartupResponse is the StartupResponse schema of the API
+type StartupResponse struct {
+	Deployments []DeploymentStartupData `json:"deployments"`
+	Hours       int                     `json:"hours"`
+}
+
+// SummaryResponse is the SummaryResponse schema of the API
+type SummaryResponse struct {
+	Namespaces             []NamespaceSummary `json:"namespaces"`
+	WarningEventsAvailable bool               `json:"warningEventsAvailable"`
+}
+
+// TolerationData is the TolerationData schema of the API
+type TolerationData struct {
+	Effect   string `json:"effect,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+}
+
+// TopologyData is the TopologyData schema of the API
+type TopologyData struct {
+	Domains []DomainData `json:"domains"`
+	Key     string       `json:"key"`
+	Skew    int          `json:"skew"`
+}
+
+// TopologyNodeData is the TopologyNodeData schema of the API
+type TopologyNodeData struct {
+	Children  []TopologyNodeData `json:"children,omitempty"`
+	Kind      string             `json:"kind"`
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace"`
+	Status    string             `json:"status"`
+	UID       string             `json:"uid"`
+}
+
+// UIConfig is the UIConfig schema of the API
+type UIConfig struct {
+	Actions          bool            `json:"actions"`
+	AuthMode         string          `json:"authMode"`
+	DefaultNamespace string          `json:"defaultNamespace"`
+	Features         map[string]bool `json:"features"`
+	Title            string          `json:"title"`
+	Version          string          `json:"version"`
+}
+
+// ViolationData is the ViolationData schema of the API
+type ViolationData struct {
+	Key      string `json:"key"`
+	Message  string `json:"message"`
+	Required bool   `json:"required"`
+	Rule     string `json:"rule"`
+}
+
+// WorkloadZoneData is the WorkloadZoneData schema of the API
+type WorkloadZoneData struct {
+	ConcentratedIn string         `json:"concentratedIn,omitempty"`
+	Name           string         `json:"name"`
+	Namespace      string         `json:"namespace"`
+	Pods           int            `json:"pods"`
+	PodsByZone     map[string]int `json:"podsByZone"`
+}
+
+// ZoneData is the ZoneData schema of the API
+type ZoneData struct {
+	Nodes      []string `json:"nodes"`
+	Pods       int      `json:"pods"`
+	ReadyNodes int      `json:"readyNodes"`
+	Zone       string   `json:"zone"`
+}
+
+// ZonesResponse is the ZonesResponse schema of the API
+type ZonesResponse struct {
+	Workloads []WorkloadZoneData `json:"workloads"`
+	Zones     []ZoneData         `json:"zones"`
+}
+
+// BatchDescribe describes many pods in one call
+func (c *Client) BatchDescribe(ctx context.Context, body BatchDescribeRequest) (*BatchDescribeResponse, error) {
+	var out BatchDescribeResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/batch/describe", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ClosePortForwardParams holds the query parameters of ClosePortForward
+type ClosePortForwardParams struct {
+	// Port-forward ID
+	Id string
+}
+
+// ClosePortForward closes a port-forward to a pod
+func (c *Client) ClosePortForward(ctx context.Context, namespace string, name string, params ClosePortForwardParams) error {
+	query := url.Values{}
+	if params.Id != "" {
+		query.Set("id", params.Id)
+	}
+	return c.do(ctx, http.MethodDelete, "/api/v1/pods/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/portforward", query, nil, nil)
+}
+
+// DeletePod deletes a pod, leaving its controller, if any, to replace it
+func (c *Client) DeletePod(ctx context.Context, namespace string, name string) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/pods/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/delete", nil, nil, nil)
+}
+
+// GetAPIVersions lists the API versions the server serves
+func (c *Client) GetAPIVersions(ctx context.Context) (*APIVersions, error) {
+	var out APIVersions
+	if err := c.do(ctx, http.MethodGet, "/api", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetClusterParams holds the query parameters of GetCluster
+type GetClusterParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+	// Only pods on this node
+	Node string
+	// Only pods, deployments and rollouts whose names match this glob, such as web-*, or /regexp/
+	NameFilter string
+	// Only the pods, deployments, rollouts, nodes and other resources that need attention
+	ProblemsOnly bool
+	// Leave out Completed pods, those whose containers all exited successfully
+	HideCompleted bool
+	// Only pods in these comma-separated QoS classes: Guaranteed, Burstable or BestEffort
+	Qos string
+	// Only pods with these comma-separated priority class names; an empty name matches pods without one
+	PriorityClass string
+	// Pod sort order
+	SortBy string
+	// Reverse the pod sort order
+	Reverse bool
+	// Page size; pages cannot be combined with sortBy or reverse
+	Limit int
+	// The nextCursor of the previous page
+	Cursor string
+}
+
+// GetCluster returns the pods, deployments and nodes of the cluster
+func (c *Client) GetCluster(ctx context.Context, params GetClusterParams) (*ClusterData, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	if params.Node != "" {
+		query.Set("node", params.Node)
+	}
+	if params.NameFilter != "" {
+		query.Set("nameFilter", params.NameFilter)
+	}
+	if params.ProblemsOnly {
+		query.Set("problemsOnly", "true")
+	}
+	if params.HideCompleted {
+		query.Set("hideCompleted", "true")
+	}
+	if params.Qos != "" {
+		query.Set("qos", params.Qos)
+	}
+	if params.PriorityClass != "" {
+		query.Set("priorityClass", params.PriorityClass)
+	}
+	if params.SortBy != "" {
+		query.Set("sortBy", params.SortBy)
+	}
+	if params.Reverse {
+		query.Set("reverse", "true")
+	}
+	if params.Limit != 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+	var out ClusterData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/cluster", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDeployment describes a deployment
+func (c *Client) GetDeployment(ctx context.Context, namespace string, name string) (*DeploymentDetailData, error) {
+	var out DeploymentDetailData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/deployments/"+url.PathEscape(namespace)+"/"+url.PathEscape(name), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHealth reports that the server is up
+func (c *Client) GetHealth(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodGet, "/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetHistoryParams holds the query parameters of GetHistory
+type GetHistoryParams struct {
+	// How many hours back, default 24
+	Hours int
+}
+
+// GetHistory returns the readiness samples of the last hours
+func (c *Client) GetHistory(ctx context.Context, params GetHistoryParams) (*HistoryResponse, error) {
+	query := url.Values{}
+	if params.Hours != 0 {
+		query.Set("hours", strconv.Itoa(params.Hours))
+	}
+	var out HistoryResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/history", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetIdleParams holds the query parameters of GetIdle
+type GetIdleParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+}
+
+// GetIdle lists deployments quiet for the idle window as scale-down candidates
+func (c *Client) GetIdle(ctx context.Context, params GetIdleParams) (*IdleResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	var out IdleResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/idle", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetImagesParams holds the query parameters of GetImages
+type GetImagesParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+}
+
+// GetImages lists the running container images by repository
+func (c *Client) GetImages(ctx context.Context, params GetImagesParams) (*ImagesResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	var out ImagesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/images", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOverview returns the node count, allocatable and requested CPU and memory, pods per node and Kubernetes version
+func (c *Client) GetOverview(ctx context.Context) (*OverviewResponse, error) {
+	var out OverviewResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/overview", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPod describes a pod
+func (c *Client) GetPod(ctx context.Context, namespace string, name string) (*PodDetailData, error) {
+	var out PodDetailData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/pods/"+url.PathEscape(namespace)+"/"+url.PathEscape(name), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetProblemsParams holds the query parameters of GetProblems
+type GetProblemsParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+	// Cap on each list
+	Limit int
+}
+
+// GetProblems ranks the most broken resources for triage
+func (c *Client) GetProblems(ctx context.Context, params GetProblemsParams) (*ProblemsResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	if params.Limit != 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	var out ProblemsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/problems", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReady reports whether the server can reach the Kubernetes API
+func (c *Client) GetReady(ctx context.Context) (map[string]string, error) {
+	var out map[string]string
+	if err := c.do(ctx, http.MethodGet, "/ready", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSnapshotParams holds the query parameters of GetSnapshot
+type GetSnapshotParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+	// Only pods on this node
+	Node string
+}
+
+// GetSnapshot returns a freshly fetched snapshot of the cluster
+func (c *Client) GetSnapshot(ctx context.Context, params GetSnapshotParams) (*ClusterData, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	if params.Node != "" {
+		query.Set("node", params.Node)
+	}
+	var out ClusterData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/snapshot", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStartupParams holds the query parameters of GetStartup
+type GetStartupParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+	// Pods Ready in how many hours back, default 24
+	Hours int
+}
+
+// GetStartup returns per-deployment percentiles of how long pods took from creation to Ready
+func (c *Client) GetStartup(ctx context.Context, params GetStartupParams) (*StartupResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	if params.Hours != 0 {
+		query.Set("hours", strconv.Itoa(params.Hours))
+	}
+	var out StartupResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/startup", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSummaryParams holds the query parameters of GetSummary
+type GetSummaryParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+}
+
+// GetSummary returns per-namespace aggregates of the cluster
+func (c *Client) GetSummary(ctx context.Context, params GetSummaryParams) (*SummaryResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	var out SummaryResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/summary", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTopologyParams holds the query parameters of GetTopology
+type GetTopologyParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+}
+
+// GetTopology returns the ownership trees of the cluster's workloads
+func (c *Client) GetTopology(ctx context.Context, params GetTopologyParams) ([]TopologyNodeData, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	var out []TopologyNodeData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/topology", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUIConfig returns the bootstrap configuration of the dashboard
+func (c *Client) GetUIConfig(ctx context.Context) (*UIConfig, error) {
+	var out UIConfig
+	if err := c.do(ctx, http.MethodGet, "/api/v1/ui-config", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetZonesParams holds the query parameters of GetZones
+type GetZonesParams struct {
+	// Only this namespace
+	Namespace string
+	// Only these comma-separated namespaces, in place of namespace
+	Namespaces string
+}
+
+// GetZones groups the nodes and pods by topology zone, flagging deployments concentrated in one zone
+func (c *Client) GetZones(ctx context.Context, params GetZonesParams) (*ZonesResponse, error) {
+	query := url.Values{}
+	if params.Namespace != "" {
+		query.Set("namespace", params.Namespace)
+	}
+	if params.Namespaces != "" {
+		query.Set("namespaces", params.Namespaces)
+	}
+	var out ZonesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/topology/zones", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNamespaces lists namespaces with their pod counts
+func (c *Client) ListNamespaces(ctx context.Context) (*NamespacesResponse, error) {
+	var out NamespacesResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/namespaces", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPortForwards lists the open port-forwards to a pod
+func (c *Client) ListPortForwards(ctx context.Context, namespace string, name string) ([]PortForwardData, error) {
+	var out []PortForwardData
+	if err := c.do(ctx, http.MethodGet, "/api/v1/pods/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/portforward", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OpenPortForward opens a port-forward to a pod on the server's loopback interface
+func (c *Client) OpenPortForward(ctx context.Context, namespace string, name string, body PortForwardRequest) (*PortForwardData, error) {
+	var out PortForwardData
+	if err := c.do(ctx, http.MethodPost, "/api/v1/pods/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/portforward", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewDeployment reports how a Deployment manifest would render before it is applied
+func (c *Client) PreviewDeployment(ctx context.Context, manifest []byte) (*PreviewData, error) {
+	var out PreviewData
+	if err := c.do(ctx, http.MethodPost, "/api/v1/preview", nil, manifest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestartDeployment rolls every pod of a deployment, like kubectl rollout restart
+func (c *Client) RestartDeployment(ctx context.Context, namespace string, name string) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/deployments/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/restart", nil, nil, nil)
+}
+
+// ScaleDeployment sets the desired replicas of a deployment
+func (c *Client) ScaleDeployment(ctx context.Context, namespace string, name string, body ScaleRequest) (*ScaleData, error) {
+	var out ScaleData
+	if err := c.do(ctx, http.MethodPost, "/api/v1/deployments/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/scale", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
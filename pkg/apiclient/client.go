@@ -0,0 +1,123 @@
+// Package apiclient is a typed client for the pod-visualizer web API. The
+// types and methods in api.gen.go are generated from the server's OpenAPI
+// document; run go generate ./pkg/apiclient after changing the API.
+package apiclient
+
+//go:generate go run ./gen -o api.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxErrorBody caps how much of an error response is kept as its message
+const maxErrorBody = 4096
+
+// Client calls the API of one pod-visualizer server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	authorize  func(r *http.Request)
+}
+
+// New creates a client for the server at baseURL, such as
+// http://localhost:8080
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+}
+
+// SetHTTPClient replaces http.DefaultClient, for custom TLS or timeouts
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetToken authenticates with a bearer token, for -auth-mode token or oidc
+func (c *Client) SetToken(token string) {
+	c.authorize = func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// SetBasicAuth authenticates with a username and password, for
+// -auth-mode basic
+func (c *Client) SetBasicAuth(username, password string) {
+	c.authorize = func(r *http.Request) {
+		r.SetBasicAuth(username, password)
+	}
+}
+
+// Error is a response with a status other than 2xx
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("pod-visualizer: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("pod-visualizer: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// do sends a request and decodes a JSON response into out, unless out is
+// nil. A []byte body is sent as a YAML manifest, any other non-nil body as
+// JSON.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	contentType := ""
+	switch body := body.(type) {
+	case nil:
+	case []byte:
+		reader, contentType = bytes.NewReader(body), "application/yaml"
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader, contentType = bytes.NewReader(encoded), "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorize != nil {
+		c.authorize(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+// Command gen writes the types and methods of package apiclient from the
+// web server's OpenAPI document. Run it through go generate ./pkg/apiclient.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"pod-visualizer/pkg/web"
+)
+
+// document is the part of an OpenAPI 3.0 document the generator reads
+type document struct {
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Schemas map[string]schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Parameters  []parameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]mediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]mediaType `json:"content"`
+	} `json:"responses"`
+}
+
+type parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description"`
+	Schema      schema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string            `json:"$ref"`
+	Type                 string            `json:"type"`
+	Format               string            `json:"format"`
+	Nullable             bool              `json:"nullable"`
+	Items                *schema           `json:"items"`
+	AdditionalProperties *schema           `json:"additionalProperties"`
+	Properties           map[string]schema `json:"properties"`
+	Required             []string          `json:"required"`
+	AllOf                []schema          `json:"allOf"`
+	GoName               string            `json:"x-go-name"`
+}
+
+func main() {
+	output := flag.String("o", "api.gen.go", "file to write")
+	flag.Parse()
+
+	spec, err := web.OpenAPI("")
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+	var doc document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		log.Fatalf("Failed to parse OpenAPI document: %v", err)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		writeType(&buf, name, doc.Components.Schemas[name])
+	}
+
+	var operations []operation
+	paths := map[string]string{}
+	methods := map[string]string{}
+	for path, byMethod := range doc.Paths {
+		for method, op := range byMethod {
+			operations = append(operations, op)
+			paths[op.OperationID] = path
+			methods[op.OperationID] = strings.ToUpper(method)
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].OperationID < operations[j].OperationID
+	})
+	for _, op := range operations {
+		writeOperation(&buf, methods[op.OperationID], paths[op.OperationID], op)
+	}
+
+	// Import only the packages the generated code refers to
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "// Code generated by go run ./gen. DO NOT EDIT.\n\npackage apiclient\n\nimport (\n")
+	for _, pkg := range []string{"context", "net/http", "net/url", "strconv", "time"} {
+		if bytes.Contains(buf.Bytes(), []byte(pkg[strings.LastIndex(pkg, "/")+1:]+".")) {
+			fmt.Fprintf(&header, "%q\n", pkg)
+		}
+	}
+	fmt.Fprintf(&header, ")\n\n")
+
+	source, err := format.Source(append(header.Bytes(), buf.Bytes()...))
+	if err != nil {
+		log.Fatalf("Failed to format generated code: %v", err)
+	}
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+}
+
+// writeType writes the struct for a component schema
+func writeType(buf *bytes.Buffer, name string, s schema) {
+	required := map[string]bool{}
+	for _, property := range s.Required {
+		required[property] = true
+	}
+
+	fmt.Fprintf(buf, "// %s is the %s schema of the API\ntype %s struct {\n", name, name, name)
+	for _, property := range sortedKeys(s.Properties) {
+		field := s.Properties[property]
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "%s %s `json:%q`\n", goName(property, field), goType(field), tag)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// writeOperation writes the client method for an operation. Path
+// parameters become arguments, query parameters a parameter struct.
+func writeOperation(buf *bytes.Buffer, method, path string, op operation) {
+	name := exported(op.OperationID)
+
+	var args, query []parameter
+	for _, param := range op.Parameters {
+		if param.In == "path" {
+			args = append(args, param)
+		} else {
+			query = append(query, param)
+		}
+	}
+
+	if len(query) > 0 {
+		fmt.Fprintf(buf, "// %sParams holds the query parameters of %s\ntype %sParams struct {\n", name, name, name)
+		for _, param := range query {
+			fmt.Fprintf(buf, "// %s\n%s %s\n", param.Description, exported(param.Name), goType(param.Schema))
+		}
+		fmt.Fprintf(buf, "}\n\n")
+	}
+
+	signature := []string{"ctx context.Context"}
+	for _, param := range args {
+		signature = append(signature, param.Name+" string")
+	}
+	if len(query) > 0 {
+		signature = append(signature, "params "+name+"Params")
+	}
+	body := "nil"
+	if op.RequestBody != nil {
+		if _, ok := op.RequestBody.Content["application/yaml"]; ok {
+			signature = append(signature, "manifest []byte")
+			body = "manifest"
+		} else {
+			signature = append(signature, "body "+goType(op.RequestBody.Content["application/json"].Schema))
+			body = "body"
+		}
+	}
+
+	result := ""
+	for status, response := range op.Responses {
+		if strings.HasPrefix(status, "2") {
+			if content, ok := response.Content["application/json"]; ok {
+				result = goType(content.Schema)
+			}
+		}
+	}
+
+	fmt.Fprintf(buf, "// %s %s\n", name, lowerFirst(op.Summary))
+	if result == "" {
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) error {\n", name, strings.Join(signature, ", "))
+	} else if isStruct(result) {
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) (*%s, error) {\n", name, strings.Join(signature, ", "), result)
+	} else {
+		fmt.Fprintf(buf, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(signature, ", "), result)
+	}
+
+	queryArg := "nil"
+	if len(query) > 0 {
+		queryArg = "query"
+		fmt.Fprintf(buf, "query := url.Values{}\n")
+		for _, param := range query {
+			field := "params." + exported(param.Name)
+			switch param.Schema.Type {
+			case "integer":
+				fmt.Fprintf(buf, "if %s != 0 {\nquery.Set(%q, strconv.Itoa(%s))\n}\n", field, param.Name, field)
+			case "boolean":
+				fmt.Fprintf(buf, "if %s {\nquery.Set(%q, \"true\")\n}\n", field, param.Name)
+			default:
+				fmt.Fprintf(buf, "if %s != \"\" {\nquery.Set(%q, %s)\n}\n", field, param.Name, field)
+			}
+		}
+	}
+
+	target := pathExpression(path)
+	switch {
+	case result == "":
+		fmt.Fprintf(buf, "return c.do(ctx, http.Method%s, %s, %s, %s, nil)\n}\n\n", methodName(method), target, queryArg, body)
+	case isStruct(result):
+		fmt.Fprintf(buf, "var out %s\nif err := c.do(ctx, http.Method%s, %s, %s, %s, &out); err != nil {\nreturn nil, err\n}\nreturn &out, nil\n}\n\n",
+			result, methodName(method), target, queryArg, body)
+	default:
+		fmt.Fprintf(buf, "var out %s\nif err := c.do(ctx, http.Method%s, %s, %s, %s, &out); err != nil {\nreturn nil, err\n}\nreturn out, nil\n}\n\n",
+			result, methodName(method), target, queryArg, body)
+	}
+}
+
+// pathExpression returns a Go expression building path, with each
+// {param} replaced by the escaped argument of that name
+func pathExpression(path string) string {
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		end := strings.Index(path, "}")
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		parts = append(parts, "url.PathEscape("+path[start+1:end]+")")
+		path = path[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goType returns the Go type of a schema
+func goType(s schema) string {
+	pointer := ""
+	if s.Nullable {
+		pointer = "*"
+	}
+	if len(s.AllOf) == 1 {
+		s = s.AllOf[0]
+	}
+
+	switch {
+	case s.Ref != "":
+		return pointer + strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	case s.Type == "string" && s.Format == "date-time":
+		return pointer + "time.Time"
+	case s.Type == "string" && s.Format == "byte":
+		return "[]byte"
+	case s.Type == "string":
+		return pointer + "string"
+	case s.Type == "boolean":
+		return pointer + "bool"
+	case s.Type == "integer" && (s.Format == "int32" || s.Format == "int64"):
+		return pointer + s.Format
+	case s.Type == "integer":
+		return pointer + "int"
+	case s.Type == "number":
+		return pointer + "float64"
+	case s.Type == "array":
+		return "[]" + goType(*s.Items)
+	case s.AdditionalProperties != nil:
+		return "map[string]" + goType(*s.AdditionalProperties)
+	}
+	return "any"
+}
+
+// isStruct reports whether a Go type generated by goType is a component
+func isStruct(goType string) bool {
+	return goType != "" && unicode.IsUpper(rune(goType[0]))
+}
+
+// goName returns the field name of a property, as named by the server
+func goName(property string, s schema) string {
+	if s.GoName != "" {
+		return s.GoName
+	}
+	return exported(property)
+}
+
+// methodName turns GET into Get, as in http.MethodGet
+func methodName(method string) string {
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func lowerFirst(text string) string {
+	return strings.ToLower(text[:1]) + text[1:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,221 @@
+package grafana
+
+import (
+	"fmt"
+	"strings"
+
+	"pod-visualizer/pkg/web"
+)
+
+// Dashboard is the subset of the Grafana dashboard JSON model the export uses
+type Dashboard struct {
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Tags          []string   `json:"tags"`
+	Timezone      string     `json:"timezone"`
+	Refresh       string     `json:"refresh"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Time          TimeRange  `json:"time"`
+	Templating    Templating `json:"templating"`
+	Panels        []Panel    `json:"panels"`
+}
+
+// TimeRange is the dashboard's default time range
+type TimeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Templating holds the dashboard variables
+type Templating struct {
+	List []Variable `json:"list"`
+}
+
+// Variable is a dashboard template variable
+type Variable struct {
+	Name       string      `json:"name"`
+	Label      string      `json:"label"`
+	Type       string      `json:"type"`
+	Query      string      `json:"query"`
+	Datasource *Datasource `json:"datasource,omitempty"`
+	Multi      bool        `json:"multi"`
+	IncludeAll bool        `json:"includeAll"`
+	AllValue   string      `json:"allValue,omitempty"`
+	Refresh    int         `json:"refresh,omitempty"`
+}
+
+// Datasource references a Grafana data source by UID
+type Datasource struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+// Panel is a single dashboard panel
+type Panel struct {
+	ID          int          `json:"id"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	GridPos     GridPos      `json:"gridPos"`
+	Datasource  *Datasource  `json:"datasource"`
+	Targets     []Target     `json:"targets"`
+	FieldConfig *FieldConfig `json:"fieldConfig,omitempty"`
+}
+
+// GridPos places a panel on the dashboard's 24-column grid
+type GridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Target is a panel query
+type Target struct {
+	RefID        string `json:"refId"`
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat,omitempty"`
+}
+
+// FieldConfig sets the unit and bounds of a panel's values
+type FieldConfig struct {
+	Defaults FieldDefaults `json:"defaults"`
+}
+
+// FieldDefaults are the default field options
+type FieldDefaults struct {
+	Unit string   `json:"unit,omitempty"`
+	Min  *float64 `json:"min,omitempty"`
+	Max  *float64 `json:"max,omitempty"`
+}
+
+// Options configure the generated dashboard
+type Options struct {
+	Title string
+	// DatasourceUID is the Prometheus data source scraping /metrics;
+	// empty uses the "${datasource}" variable so it can be picked on import
+	DatasourceUID string
+	// Namespaces limits the namespace variable to these values; empty
+	// offers every namespace seen in the metrics
+	Namespaces []string
+}
+
+// NewDashboard builds a dashboard charting the series the web server
+// exposes on /metrics
+func NewDashboard(opts Options) Dashboard {
+	if opts.Title == "" {
+		opts.Title = "Pod Visualizer"
+	}
+
+	datasource := &Datasource{Type: "prometheus", UID: opts.DatasourceUID}
+	variables := []Variable{}
+	if opts.DatasourceUID == "" {
+		datasource.UID = "${datasource}"
+		variables = append(variables, Variable{
+			Name:  "datasource",
+			Label: "Data source",
+			Type:  "datasource",
+			Query: "prometheus",
+		})
+	}
+
+	namespaceVariable := Variable{
+		Name:       "namespace",
+		Label:      "Namespace",
+		Multi:      true,
+		IncludeAll: true,
+		AllValue:   ".*",
+	}
+	if len(opts.Namespaces) > 0 {
+		namespaceVariable.Type = "custom"
+		namespaceVariable.Query = strings.Join(opts.Namespaces, ",")
+	} else {
+		namespaceVariable.Type = "query"
+		namespaceVariable.Datasource = datasource
+		namespaceVariable.Query = fmt.Sprintf("label_values(%s, namespace)", web.MetricPods)
+		namespaceVariable.Refresh = 2 // on time range change
+	}
+	variables = append(variables, namespaceVariable)
+
+	selector := `{namespace=~"$namespace"}`
+	zero, hundred := 0.0, 100.0
+
+	panels := []Panel{
+		{
+			Type:  "stat",
+			Title: "Ready containers",
+			Targets: []Target{{
+				Expr: fmt.Sprintf("100 * sum(%s%s) / sum(%s%s)", web.MetricContainersReady, selector, web.MetricContainers, selector),
+			}},
+			FieldConfig: &FieldConfig{Defaults: FieldDefaults{Unit: "percent", Min: &zero, Max: &hundred}},
+		},
+		{
+			Type:  "stat",
+			Title: "WebSocket clients",
+			Targets: []Target{{
+				Expr: web.MetricWebSocketClients,
+			}},
+		},
+		{
+			Type:  "timeseries",
+			Title: "Pods by status",
+			Targets: []Target{{
+				Expr:         fmt.Sprintf("sum by (status) (%s%s)", web.MetricPods, selector),
+				LegendFormat: "{{status}}",
+			}},
+		},
+		{
+			Type:  "timeseries",
+			Title: "Ready containers by namespace",
+			Targets: []Target{{
+				Expr:         fmt.Sprintf("100 * sum by (namespace) (%s%s) / sum by (namespace) (%s%s)", web.MetricContainersReady, selector, web.MetricContainers, selector),
+				LegendFormat: "{{namespace}}",
+			}},
+			FieldConfig: &FieldConfig{Defaults: FieldDefaults{Unit: "percent", Min: &zero, Max: &hundred}},
+		},
+		{
+			Type:  "timeseries",
+			Title: "Unready replicas by deployment",
+			Targets: []Target{{
+				Expr:         fmt.Sprintf("%s%s - %s%s > 0", web.MetricDeploymentReplicas, selector, web.MetricDeploymentReady, selector),
+				LegendFormat: "{{namespace}}/{{deployment}}",
+			}},
+		},
+		{
+			Type:  "timeseries",
+			Title: "Container restarts (1h increase)",
+			Targets: []Target{{
+				Expr:         fmt.Sprintf("sum by (namespace) (delta(%s%s[1h]))", web.MetricRestarts, selector),
+				LegendFormat: "{{namespace}}",
+			}},
+		},
+	}
+
+	// Two stats across the top, then the charts in a two-column grid
+	for i := range panels {
+		panels[i].ID = i + 1
+		panels[i].Datasource = datasource
+		for j := range panels[i].Targets {
+			panels[i].Targets[j].RefID = string(rune('A' + j))
+		}
+
+		switch {
+		case i < 2:
+			panels[i].GridPos = GridPos{X: i * 12, Y: 0, W: 12, H: 4}
+		default:
+			chart := i - 2
+			panels[i].GridPos = GridPos{X: (chart % 2) * 12, Y: 4 + (chart/2)*8, W: 12, H: 8}
+		}
+	}
+
+	return Dashboard{
+		UID:           "pod-visualizer",
+		Title:         opts.Title,
+		Tags:          []string{"kubernetes", "pod-visualizer"},
+		Timezone:      "browser",
+		Refresh:       "30s",
+		SchemaVersion: 38,
+		Time:          TimeRange{From: "now-6h", To: "now"},
+		Templating:    Templating{List: variables},
+		Panels:        panels,
+	}
+}
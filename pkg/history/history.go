@@ -0,0 +1,44 @@
+// Package history stores cluster readiness samples and pod startup times
+// over time so that dashboards can chart trends. Backends are pluggable:
+// Ring keeps recent samples in memory, SQLite persists them across
+// restarts.
+package history
+
+import "time"
+
+// Sample is the cluster-wide readiness at one point in time
+type Sample struct {
+	Time                time.Time
+	Pods                int
+	TotalContainers     int
+	ReadyContainers     int
+	ContainerPercentage float64
+	TotalReplicas       int32
+	ReadyReplicas       int32
+	ReplicaPercentage   float64
+}
+
+// Startup is how long one pod of a deployment took from creation to Ready
+type Startup struct {
+	UID        string
+	Namespace  string
+	Deployment string
+	Pod        string
+	ReadyAt    time.Time
+	Duration   time.Duration
+}
+
+// Backend stores samples and startups and returns them in time order
+type Backend interface {
+	// Append stores samples, which are given oldest first
+	Append(samples []Sample) error
+	// Since returns the samples taken at or after since, oldest first
+	Since(since time.Time) ([]Sample, error)
+	// AppendStartups stores startups, keeping the first one recorded for
+	// a pod UID
+	AppendStartups(startups []Startup) error
+	// StartupsSince returns the startups of pods Ready at or after since,
+	// oldest first
+	StartupsSince(since time.Time) ([]Startup, error)
+	Close() error
+}
@@ -0,0 +1,105 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// backends returns each Backend, empty, for tests every backend must pass
+func backends(t *testing.T) map[string]Backend {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "history.db"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return map[string]Backend{"ring": NewRing(10), "sqlite": db}
+}
+
+// sampleTimes returns the times of samples
+func sampleTimes(samples []Sample) []time.Time {
+	times := make([]time.Time, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.Time
+	}
+	return times
+}
+
+// sameTimes reports whether got and want hold equal instants
+func sameTimes(got, want []time.Time) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBackendSince(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	for name, backend := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			samples := []Sample{
+				{Time: now.Add(-2 * time.Minute), Pods: 3, TotalContainers: 4, ReadyContainers: 2, ContainerPercentage: 50, TotalReplicas: 3, ReadyReplicas: 1, ReplicaPercentage: 100.0 / 3},
+				{Time: now.Add(-time.Minute), Pods: 3},
+				{Time: now, Pods: 4},
+			}
+			if err := backend.Append(samples); err != nil {
+				t.Fatalf("Append() error = %v", err)
+			}
+
+			got, err := backend.Since(now.Add(-time.Minute))
+			if err != nil {
+				t.Fatalf("Since() error = %v", err)
+			}
+			if want := []time.Time{now.Add(-time.Minute), now}; !sameTimes(sampleTimes(got), want) {
+				t.Errorf("Since() times = %v, want %v", sampleTimes(got), want)
+			}
+
+			all, err := backend.Since(time.Time{})
+			if err != nil {
+				t.Fatalf("Since() error = %v", err)
+			}
+			first := all[0]
+			first.Time = samples[0].Time
+			if len(all) != 3 || first != samples[0] {
+				t.Errorf("Since() = %+v, want every sample stored in full", all)
+			}
+		})
+	}
+}
+
+func TestBackendStartups(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	for name, backend := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			err := backend.AppendStartups([]Startup{
+				{UID: "b", Namespace: "shop", Deployment: "web", Pod: "web-b", ReadyAt: now, Duration: 12 * time.Second},
+				{UID: "a", Namespace: "shop", Deployment: "web", Pod: "web-a", ReadyAt: now.Add(-time.Minute), Duration: 8 * time.Second},
+				{UID: "old", Namespace: "shop", Deployment: "web", Pod: "web-old", ReadyAt: now.Add(-time.Hour), Duration: time.Second},
+			})
+			if err != nil {
+				t.Fatalf("AppendStartups() error = %v", err)
+			}
+			// A pod keeps the first startup recorded for it
+			if err := backend.AppendStartups([]Startup{{UID: "a", Pod: "web-a", ReadyAt: now, Duration: time.Hour}}); err != nil {
+				t.Fatalf("AppendStartups() error = %v", err)
+			}
+
+			got, err := backend.StartupsSince(now.Add(-time.Minute))
+			if err != nil {
+				t.Fatalf("StartupsSince() error = %v", err)
+			}
+			if len(got) != 2 || got[0].UID != "a" || got[1].UID != "b" {
+				t.Fatalf("StartupsSince() = %+v, want a then b", got)
+			}
+			if got[0].Duration != 8*time.Second || !got[0].ReadyAt.Equal(now.Add(-time.Minute)) || got[1].Deployment != "web" {
+				t.Errorf("StartupsSince() = %+v, want the first startups recorded", got)
+			}
+		})
+	}
+}
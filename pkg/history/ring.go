@@ -0,0 +1,117 @@
+package history
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRingSize holds a day of samples at the recorder's default 30s interval
+const DefaultRingSize = 2880
+
+// Ring is an in-memory Backend keeping the most recent samples and as
+// many startups. It is lost when the process exits.
+type Ring struct {
+	mu      sync.RWMutex
+	samples []Sample
+	next    int
+	full    bool
+
+	startups     []Startup
+	nextStartup  int
+	startupsFull bool
+	// recorded holds the UIDs of the startups in the ring
+	recorded map[string]bool
+}
+
+// NewRing creates a ring buffer holding up to size samples
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &Ring{
+		samples:  make([]Sample, size),
+		startups: make([]Startup, size),
+		recorded: make(map[string]bool),
+	}
+}
+
+// Append stores samples, overwriting the oldest once the ring is full
+func (r *Ring) Append(samples []Sample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, sample := range samples {
+		r.samples[r.next] = sample
+		r.next = (r.next + 1) % len(r.samples)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+	return nil
+}
+
+// Since returns the samples taken at or after since, oldest first
+func (r *Ring) Since(since time.Time) ([]Sample, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ordered := r.samples[:r.next]
+	if r.full {
+		ordered = append(append([]Sample{}, r.samples[r.next:]...), r.samples[:r.next]...)
+	}
+
+	start := sort.Search(len(ordered), func(i int) bool {
+		return !ordered[i].Time.Before(since)
+	})
+	return append([]Sample{}, ordered[start:]...), nil
+}
+
+// AppendStartups stores startups not recorded yet, overwriting the
+// oldest once the ring is full
+func (r *Ring) AppendStartups(startups []Startup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, startup := range startups {
+		if r.recorded[startup.UID] {
+			continue
+		}
+		if r.startupsFull {
+			delete(r.recorded, r.startups[r.nextStartup].UID)
+		}
+		r.startups[r.nextStartup] = startup
+		r.recorded[startup.UID] = true
+		r.nextStartup = (r.nextStartup + 1) % len(r.startups)
+		if r.nextStartup == 0 {
+			r.startupsFull = true
+		}
+	}
+	return nil
+}
+
+// StartupsSince returns the startups of pods Ready at or after since,
+// oldest first
+func (r *Ring) StartupsSince(since time.Time) ([]Startup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ordered := r.startups[:r.nextStartup]
+	if r.startupsFull {
+		ordered = append(append([]Startup{}, r.startups[r.nextStartup:]...), r.startups[:r.nextStartup]...)
+	}
+
+	var startups []Startup
+	for _, startup := range ordered {
+		if !startup.ReadyAt.Before(since) {
+			startups = append(startups, startup)
+		}
+	}
+	sort.SliceStable(startups, func(i, j int) bool { return startups[i].ReadyAt.Before(startups[j].ReadyAt) })
+	return startups, nil
+}
+
+// Close is a no-op for the in-memory ring
+func (r *Ring) Close() error {
+	return nil
+}
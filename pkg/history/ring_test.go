@@ -0,0 +1,63 @@
+package history
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRingOverwritesOldest(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ring := NewRing(3)
+	for i := 0; i < 5; i++ {
+		if err := ring.Append([]Sample{{Time: start.Add(time.Duration(i) * time.Minute), Pods: i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ring.Since(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pods []int
+	for _, sample := range got {
+		pods = append(pods, sample.Pods)
+	}
+	if len(pods) != 3 || pods[0] != 2 || pods[1] != 3 || pods[2] != 4 {
+		t.Errorf("Since() pods = %v, want the 3 newest, [2 3 4]", pods)
+	}
+
+	if got, _ := ring.Since(start.Add(3 * time.Minute)); len(got) != 2 || got[0].Pods != 3 {
+		t.Errorf("Since(3m) = %+v, want the last two samples", got)
+	}
+}
+
+func TestRingOverwritesOldestStartups(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ring := NewRing(2)
+	for i := 0; i < 3; i++ {
+		uid := strconv.Itoa(i)
+		if err := ring.AppendStartups([]Startup{{UID: uid, ReadyAt: start.Add(time.Duration(i) * time.Minute)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, _ := ring.StartupsSince(time.Time{})
+	if len(got) != 2 || got[0].UID != "1" || got[1].UID != "2" {
+		t.Fatalf("StartupsSince() = %+v, want the 2 newest", got)
+	}
+
+	// The overwritten pod is forgotten, so it can be recorded again
+	if err := ring.AppendStartups([]Startup{{UID: "0", ReadyAt: start.Add(5 * time.Minute)}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ring.StartupsSince(time.Time{}); len(got) != 2 || got[1].UID != "0" {
+		t.Errorf("StartupsSince() = %+v, want 0 recorded again", got)
+	}
+}
+
+func TestNewRingDefaultSize(t *testing.T) {
+	if got := len(NewRing(0).samples); got != DefaultRingSize {
+		t.Errorf("NewRing(0) holds %d samples, want %d", got, DefaultRingSize)
+	}
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	// Pure Go driver, so the binary still builds with CGO_ENABLED=0
+	_ "modernc.org/sqlite"
+)
+
+// DefaultRetention is how long the SQLite backend keeps samples
+const DefaultRetention = 7 * 24 * time.Hour
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS samples (
+	time                 INTEGER NOT NULL,
+	pods                 INTEGER NOT NULL,
+	total_containers     INTEGER NOT NULL,
+	ready_containers     INTEGER NOT NULL,
+	container_percentage REAL NOT NULL,
+	total_replicas       INTEGER NOT NULL,
+	ready_replicas       INTEGER NOT NULL,
+	replica_percentage   REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS samples_time ON samples (time);
+CREATE TABLE IF NOT EXISTS startups (
+	uid        TEXT PRIMARY KEY,
+	namespace  TEXT NOT NULL,
+	deployment TEXT NOT NULL,
+	pod        TEXT NOT NULL,
+	ready_at   INTEGER NOT NULL,
+	duration   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS startups_ready_at ON startups (ready_at);
+`
+
+// SQLite is a Backend persisting samples and startups to a SQLite
+// database file. Entries older than the retention are pruned on every
+// append.
+type SQLite struct {
+	db        *sql.DB
+	retention time.Duration
+}
+
+// OpenSQLite opens or creates the database at path
+func OpenSQLite(path string, retention time.Duration) (*SQLite, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %v", err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create history schema: %v", err)
+	}
+
+	return &SQLite{db: db, retention: retention}, nil
+}
+
+// Append stores samples in one transaction and prunes expired ones
+func (s *SQLite) Append(samples []Sample) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin history transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(`INSERT INTO samples VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare history insert: %v", err)
+	}
+	defer insert.Close()
+
+	for _, sample := range samples {
+		_, err := insert.Exec(sample.Time.UnixMilli(), sample.Pods,
+			sample.TotalContainers, sample.ReadyContainers, sample.ContainerPercentage,
+			sample.TotalReplicas, sample.ReadyReplicas, sample.ReplicaPercentage)
+		if err != nil {
+			return fmt.Errorf("failed to insert history sample: %v", err)
+		}
+	}
+
+	cutoff := time.Now().Add(-s.retention).UnixMilli()
+	if _, err := tx.Exec(`DELETE FROM samples WHERE time < ?`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune history: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// Since returns the samples taken at or after since, oldest first
+func (s *SQLite) Since(since time.Time) ([]Sample, error) {
+	rows, err := s.db.Query(`SELECT * FROM samples WHERE time >= ? ORDER BY time`, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %v", err)
+	}
+	defer rows.Close()
+
+	var samples []Sample
+	for rows.Next() {
+		var sample Sample
+		var millis int64
+		err := rows.Scan(&millis, &sample.Pods,
+			&sample.TotalContainers, &sample.ReadyContainers, &sample.ContainerPercentage,
+			&sample.TotalReplicas, &sample.ReadyReplicas, &sample.ReplicaPercentage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history sample: %v", err)
+		}
+		sample.Time = time.UnixMilli(millis)
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
+
+// AppendStartups stores startups in one transaction, ignoring pods
+// already recorded, and prunes expired ones
+func (s *SQLite) AppendStartups(startups []Startup) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin history transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	insert, err := tx.Prepare(`INSERT OR IGNORE INTO startups VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare startup insert: %v", err)
+	}
+	defer insert.Close()
+
+	for _, startup := range startups {
+		_, err := insert.Exec(startup.UID, startup.Namespace, startup.Deployment, startup.Pod,
+			startup.ReadyAt.UnixMilli(), startup.Duration.Milliseconds())
+		if err != nil {
+			return fmt.Errorf("failed to insert startup: %v", err)
+		}
+	}
+
+	cutoff := time.Now().Add(-s.retention).UnixMilli()
+	if _, err := tx.Exec(`DELETE FROM startups WHERE ready_at < ?`, cutoff); err != nil {
+		return fmt.Errorf("failed to prune startups: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// StartupsSince returns the startups of pods Ready at or after since,
+// oldest first
+func (s *SQLite) StartupsSince(since time.Time) ([]Startup, error) {
+	rows, err := s.db.Query(`SELECT * FROM startups WHERE ready_at >= ? ORDER BY ready_at`, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query startups: %v", err)
+	}
+	defer rows.Close()
+
+	var startups []Startup
+	for rows.Next() {
+		var startup Startup
+		var readyAt, duration int64
+		err := rows.Scan(&startup.UID, &startup.Namespace, &startup.Deployment, &startup.Pod, &readyAt, &duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read startup: %v", err)
+		}
+		startup.ReadyAt = time.UnixMilli(readyAt)
+		startup.Duration = time.Duration(duration) * time.Millisecond
+		startups = append(startups, startup)
+	}
+
+	return startups, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,58 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLitePrunesExpired(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "history.db"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Append([]Sample{{Time: now.Add(-2 * time.Hour)}, {Time: now}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := db.Since(time.Time{}); len(got) != 1 || !got[0].Time.Equal(now) {
+		t.Errorf("Since() = %+v, want only the sample within the retention", got)
+	}
+
+	if err := db.AppendStartups([]Startup{{UID: "old", ReadyAt: now.Add(-2 * time.Hour)}, {UID: "new", ReadyAt: now}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := db.StartupsSince(time.Time{}); len(got) != 1 || got[0].UID != "new" {
+		t.Errorf("StartupsSince() = %+v, want only the startup within the retention", got)
+	}
+}
+
+func TestSQLitePersists(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenSQLite(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Append([]Sample{{Time: now, Pods: 7}}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db, err = OpenSQLite(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if got, _ := db.Since(time.Time{}); len(got) != 1 || got[0].Pods != 7 {
+		t.Errorf("Since() after reopening = %+v, want the stored sample", got)
+	}
+}
+
+func TestOpenSQLiteError(t *testing.T) {
+	if _, err := OpenSQLite(filepath.Join(t.TempDir(), "missing", "history.db"), 0); err == nil {
+		t.Error("OpenSQLite() error = nil for a missing directory")
+	}
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kindResource is an API resource the visualizer must be able to list
+type kindResource struct {
+	group    string
+	resource string
+}
+
+// kindResources lists the resources each kind reads; a kind is only
+// accessible when every one of them can be listed
+var kindResources = map[string][]kindResource{
+	KindPods:        {{"", "pods"}},
+	KindDeployments: {{"apps", "deployments"}},
+	KindHPAs:        {{"autoscaling", "horizontalpodautoscalers"}},
+	KindPDBs:        {{"policy", "poddisruptionbudgets"}, {"", "pods"}},
+	KindJobs:        {{"batch", "jobs"}},
+	KindCronJobs:    {{"batch", "cronjobs"}},
+	KindServices:    {{"", "services"}, {"discovery.k8s.io", "endpointslices"}},
+	KindIngresses:   {{"networking.k8s.io", "ingresses"}, {"", "services"}, {"discovery.k8s.io", "endpointslices"}},
+	KindPVCs:        {{"", "persistentvolumeclaims"}, {"", "pods"}},
+	KindNodes:       {{"", "nodes"}},
+	KindMetrics:     {{"metrics.k8s.io", "pods"}},
+}
+
+// AccessibleKinds returns the subset of kinds the current identity may
+// list in namespace (empty for all namespaces), checked with
+// SelfSubjectAccessReviews, along with the kinds that were denied
+func (c *Client) AccessibleKinds(ctx context.Context, namespace string, kinds Kinds) (Kinds, []string, error) {
+	accessible := make(Kinds)
+	var denied []string
+
+	for _, kind := range AllKinds {
+		if !kinds.Enabled(kind) {
+			continue
+		}
+
+		allowed := true
+		for _, r := range kindResources[kind] {
+			ok, err := c.CanList(ctx, namespace, r.group, r.resource)
+			if err != nil {
+				return nil, nil, err
+			}
+			allowed = allowed && ok
+		}
+
+		if allowed {
+			accessible[kind] = true
+		} else {
+			denied = append(denied, kind)
+		}
+	}
+
+	return accessible, denied, nil
+}
+
+// CanList reports whether the current identity may list a resource
+func (c *Client) CanList(ctx context.Context, namespace, group, resource string) (bool, error) {
+	return c.reviewAccess(ctx, authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "list",
+		Group:     group,
+		Resource:  resource,
+	})
+}
+
+// CanI reports whether the current identity may perform verb on a
+// resource or subresource, e.g. create pods/exec, optionally for one
+// named object
+func (c *Client) CanI(ctx context.Context, namespace, verb, group, resource, subresource, name string) (bool, error) {
+	return c.reviewAccess(ctx, authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        verb,
+		Group:       group,
+		Resource:    resource,
+		Subresource: subresource,
+		Name:        name,
+	})
+}
+
+// reviewAccess asks the API server whether the current identity may
+// perform the described request
+func (c *Client) reviewAccess(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+		},
+	}
+
+	result, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review access to %s: %v", attributes.Resource, err)
+	}
+
+	return result.Status.Allowed, nil
+}
@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restartedAtAnnotation is the pod template annotation kubectl rollout
+// restart sets; changing it rolls every pod of the deployment
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// DeletePod deletes a pod with its default grace period
+func (c *Client) DeletePod(ctx context.Context, namespace, name string) error {
+	if err := c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete pod %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// RestartDeployment triggers a rolling restart of a deployment, like
+// kubectl rollout restart, by stamping its pod template with at
+func (c *Client) RestartDeployment(ctx context.Context, namespace, name string, at time.Time) error {
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"annotations": map[string]string{
+						restartedAtAnnotation: at.Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to restart deployment %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// ScaleDeployment sets a deployment's desired replicas through its scale
+// subresource, like kubectl scale, returning the replicas it had before
+func (c *Client) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32) (int32, error) {
+	deployments := c.clientset.AppsV1().Deployments(namespace)
+	scale, err := deployments.GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get scale of deployment %s/%s: %w", namespace, name, err)
+	}
+
+	previous := scale.Spec.Replicas
+	scale.Spec.Replicas = replicas
+	if _, err := deployments.UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
+		return 0, fmt.Errorf("failed to scale deployment %s/%s: %w", namespace, name, err)
+	}
+	return previous, nil
+}
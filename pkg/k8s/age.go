@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+)
+
+// Age returns how long ago the pod was created, as of now
+func (p PodInfo) Age(now time.Time) time.Duration {
+	return now.Sub(p.CreatedAt)
+}
+
+// Age returns how long ago the deployment was created, as of now
+func (d DeploymentInfo) Age(now time.Time) time.Duration {
+	return now.Sub(d.CreatedAt)
+}
+
+// FilterPodsByAge keeps pods at least minAge and at most maxAge old;
+// a zero bound is not applied
+func FilterPodsByAge(pods []PodInfo, minAge, maxAge time.Duration, now time.Time) []PodInfo {
+	if minAge == 0 && maxAge == 0 {
+		return pods
+	}
+
+	var filtered []PodInfo
+	for _, pod := range pods {
+		age := pod.Age(now)
+		if minAge > 0 && age < minAge {
+			continue
+		}
+		if maxAge > 0 && age > maxAge {
+			continue
+		}
+		filtered = append(filtered, pod)
+	}
+	return filtered
+}
+
+// FormatAge renders an age the way kubectl does: the largest one or two
+// units, such as 45s, 12m, 3h20m or 5d
+func FormatAge(age time.Duration) string {
+	switch {
+	case age < 0:
+		return "0s"
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < 10*time.Minute:
+		if s := int(age.Seconds()) % 60; s > 0 {
+			return fmt.Sprintf("%dm%ds", int(age.Minutes()), s)
+		}
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 8*time.Hour:
+		if m := int(age.Minutes()) % 60; m > 0 {
+			return fmt.Sprintf("%dh%dm", int(age.Hours()), m)
+		}
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 8*24*time.Hour:
+		if h := int(age.Hours()) % 24; h > 0 {
+			return fmt.Sprintf("%dd%dh", int(age.Hours())/24, h)
+		}
+		return fmt.Sprintf("%dd", int(age.Hours())/24)
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours())/24)
+	}
+}
@@ -0,0 +1,356 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Rollout strategies reported in ArgoRolloutInfo.Strategy
+const (
+	RolloutCanary    = "Canary"
+	RolloutBlueGreen = "BlueGreen"
+)
+
+// Rollout phases of ArgoRolloutInfo.Phase, as Argo Rollouts reports them
+const (
+	RolloutHealthy     = "Healthy"
+	RolloutProgressing = "Progressing"
+	RolloutPaused      = "Paused"
+	RolloutDegraded    = "Degraded"
+)
+
+// Roles of a ArgoRolloutRevision: the stable and canary revisions of a
+// canary, the active and preview revisions of a blue-green rollout.
+// Other revisions are older ones being scaled down and have no role.
+const (
+	RevisionStable  = "stable"
+	RevisionCanary  = "canary"
+	RevisionActive  = "active"
+	RevisionPreview = "preview"
+)
+
+// rolloutsPath lists Argo Rollouts; the API is a CRD and is skipped on
+// clusters that do not install it
+const rolloutsPath = "/apis/argoproj.io/v1alpha1"
+
+// rolloutHashLabel is the pod template hash Argo Rollouts labels its
+// ReplicaSets and pods with, in place of the Deployment's
+const rolloutHashLabel = "rollouts-pod-template-hash"
+
+// ArgoRolloutInfo describes an Argo Rollout and how far its progressive
+// delivery has come
+type ArgoRolloutInfo struct {
+	UID       string
+	Name      string
+	Namespace string
+	Strategy  string
+	Phase     string
+	Message   string
+
+	Replicas        int32
+	ReadyReplicas   int32
+	UpdatedReplicas int32
+
+	// Step is the index of the canary step being run out of Steps, equal
+	// to Steps once all have run; StepName describes it, e.g. "pause 10m"
+	Step     int32
+	Steps    int32
+	StepName string
+	// Weight is the share of traffic, in percent, sent to the canary
+	Weight int32
+
+	// Paused is set while a pause step or a manual pause holds the
+	// rollout; Aborted once it was aborted back to stable
+	Paused  bool
+	Aborted bool
+
+	// StableHash and CurrentHash are the pod template hashes of the
+	// stable revision and of the one being rolled out; ActiveHash and
+	// PreviewHash those behind a blue-green rollout's services
+	StableHash  string
+	CurrentHash string
+	ActiveHash  string
+	PreviewHash string
+
+	// Revisions count the rollout's pods per pod template hash, stable
+	// or active first, set by ApplyArgoRolloutPods
+	Revisions []ArgoRolloutRevision
+
+	CreatedAt time.Time
+}
+
+// ArgoRolloutRevision counts a rollout's pods of one pod template hash
+type ArgoRolloutRevision struct {
+	Hash      string
+	Role      string
+	Pods      int
+	ReadyPods int
+}
+
+// Promoted reports whether the rollout runs its latest revision only
+func (r ArgoRolloutInfo) Promoted() bool {
+	return r.CurrentHash != "" && r.CurrentHash == r.StableHash
+}
+
+// NeedsAttention reports whether the rollout is degraded or was aborted
+func (r ArgoRolloutInfo) NeedsAttention() bool {
+	return r.Phase == RolloutDegraded || r.Aborted
+}
+
+// GetArgoRollouts retrieves the Argo Rollouts in the selected namespaces
+// (empty for all). Nothing is returned when Argo Rollouts is not
+// installed, or its rollouts may not be listed.
+func (c *Client) GetArgoRollouts(ctx context.Context, namespace string) ([]ArgoRolloutInfo, error) {
+	rollouts, err := listCustomResources[argoRollout](ctx, c, namespace, rolloutsPath, "rollouts")
+	if customResourceMissing(err) || apierrors.IsForbidden(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rollouts: %w", err)
+	}
+
+	infos := make([]ArgoRolloutInfo, len(rollouts))
+	for i, rollout := range rollouts {
+		infos[i] = rollout.info()
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Namespace != infos[j].Namespace {
+			return infos[i].Namespace < infos[j].Namespace
+		}
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos, nil
+}
+
+// ApplyArgoRolloutPods sets Revisions on rollouts from the pods they own
+func ApplyArgoRolloutPods(rollouts []ArgoRolloutInfo, pods []PodInfo) {
+	byRollout := make(map[string]map[string]*ArgoRolloutRevision)
+	for _, pod := range pods {
+		if pod.ArgoRollout == "" {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.ArgoRollout
+		if byRollout[key] == nil {
+			byRollout[key] = make(map[string]*ArgoRolloutRevision)
+		}
+		revision := byRollout[key][pod.ArgoRolloutHash]
+		if revision == nil {
+			revision = &ArgoRolloutRevision{Hash: pod.ArgoRolloutHash}
+			byRollout[key][pod.ArgoRolloutHash] = revision
+		}
+		revision.Pods++
+		if !pod.NeedsAttention() {
+			revision.ReadyPods++
+		}
+	}
+
+	for i := range rollouts {
+		rollouts[i].Revisions = nil
+		for _, revision := range byRollout[rollouts[i].Namespace+"/"+rollouts[i].Name] {
+			revision.Role = rollouts[i].role(revision.Hash)
+			rollouts[i].Revisions = append(rollouts[i].Revisions, *revision)
+		}
+		sort.Slice(rollouts[i].Revisions, func(a, b int) bool {
+			ra, rb := rolePriority(rollouts[i].Revisions[a].Role), rolePriority(rollouts[i].Revisions[b].Role)
+			if ra != rb {
+				return ra < rb
+			}
+			return rollouts[i].Revisions[a].Hash < rollouts[i].Revisions[b].Hash
+		})
+	}
+}
+
+// role returns the role of the revision with the given pod template hash
+func (r ArgoRolloutInfo) role(hash string) string {
+	switch {
+	case r.Strategy == RolloutBlueGreen && hash == r.ActiveHash:
+		return RevisionActive
+	case r.Strategy == RolloutBlueGreen && hash == r.PreviewHash:
+		return RevisionPreview
+	case r.Strategy == RolloutCanary && hash == r.StableHash:
+		return RevisionStable
+	case r.Strategy == RolloutCanary && hash == r.CurrentHash:
+		return RevisionCanary
+	}
+	return ""
+}
+
+// rolePriority orders revisions stable or active first, then canary or
+// preview, then older ones
+func rolePriority(role string) int {
+	switch role {
+	case RevisionStable, RevisionActive:
+		return 0
+	case RevisionCanary, RevisionPreview:
+		return 1
+	}
+	return 2
+}
+
+// owningRollout returns the name of the Argo Rollout managing a pod and
+// the pod's template hash, or empty. Like a Deployment, a Rollout names
+// its ReplicaSets "<rollout>-<pod-template-hash>".
+func owningRollout(pod *corev1.Pod) (string, string) {
+	hash := pod.Labels[rolloutHashLabel]
+	if hash == "" {
+		return "", ""
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller || owner.Kind != "ReplicaSet" {
+			continue
+		}
+		if strings.HasSuffix(owner.Name, "-"+hash) {
+			return strings.TrimSuffix(owner.Name, "-"+hash), hash
+		}
+	}
+	return "", ""
+}
+
+// argoRollout mirrors the parts of an argoproj.io Rollout the overview
+// needs
+type argoRollout struct {
+	Metadata struct {
+		UID               string    `json:"uid"`
+		Name              string    `json:"name"`
+		Namespace         string    `json:"namespace"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		Paused   bool `json:"paused"`
+		Strategy struct {
+			Canary *struct {
+				Steps []rolloutStep `json:"steps"`
+			} `json:"canary"`
+			BlueGreen *struct{} `json:"blueGreen"`
+		} `json:"strategy"`
+	} `json:"spec"`
+	Status struct {
+		Phase            string `json:"phase"`
+		Message          string `json:"message"`
+		Abort            bool   `json:"abort"`
+		Replicas         int32  `json:"replicas"`
+		ReadyReplicas    int32  `json:"readyReplicas"`
+		UpdatedReplicas  int32  `json:"updatedReplicas"`
+		CurrentPodHash   string `json:"currentPodHash"`
+		StableRS         string `json:"stableRS"`
+		CurrentStepIndex *int32 `json:"currentStepIndex"`
+		PauseConditions  []struct {
+			Reason string `json:"reason"`
+		} `json:"pauseConditions"`
+		Canary struct {
+			Weights *struct {
+				Canary struct {
+					Weight int32 `json:"weight"`
+				} `json:"canary"`
+			} `json:"weights"`
+		} `json:"canary"`
+		BlueGreen struct {
+			ActiveSelector  string `json:"activeSelector"`
+			PreviewSelector string `json:"previewSelector"`
+		} `json:"blueGreen"`
+	} `json:"status"`
+}
+
+// rolloutStep mirrors a canary step; only one of its fields is set
+type rolloutStep struct {
+	SetWeight *int32 `json:"setWeight"`
+	Pause     *struct {
+		Duration *intstr.IntOrString `json:"duration"`
+	} `json:"pause"`
+	Analysis       *struct{} `json:"analysis"`
+	Experiment     *struct{} `json:"experiment"`
+	SetCanaryScale *struct{} `json:"setCanaryScale"`
+}
+
+// name describes the step, e.g. "setWeight 20" or "pause 10m"
+func (s rolloutStep) name() string {
+	switch {
+	case s.SetWeight != nil:
+		return fmt.Sprintf("setWeight %d", *s.SetWeight)
+	case s.Pause != nil && s.Pause.Duration != nil:
+		if s.Pause.Duration.Type == intstr.Int {
+			return fmt.Sprintf("pause %ds", s.Pause.Duration.IntVal)
+		}
+		return "pause " + s.Pause.Duration.StrVal
+	case s.Pause != nil:
+		return "pause until promoted"
+	case s.Analysis != nil:
+		return "analysis"
+	case s.Experiment != nil:
+		return "experiment"
+	case s.SetCanaryScale != nil:
+		return "setCanaryScale"
+	}
+	return "unknown step"
+}
+
+// info converts the Rollout. Without a traffic router, which reports the
+// canary weight itself, the weight is the last setWeight step run.
+func (r argoRollout) info() ArgoRolloutInfo {
+	info := ArgoRolloutInfo{
+		UID:             r.Metadata.UID,
+		Name:            r.Metadata.Name,
+		Namespace:       r.Metadata.Namespace,
+		Phase:           r.Status.Phase,
+		Message:         r.Status.Message,
+		Replicas:        r.Status.Replicas,
+		ReadyReplicas:   r.Status.ReadyReplicas,
+		UpdatedReplicas: r.Status.UpdatedReplicas,
+		Paused:          r.Spec.Paused || len(r.Status.PauseConditions) > 0,
+		Aborted:         r.Status.Abort,
+		StableHash:      r.Status.StableRS,
+		CurrentHash:     r.Status.CurrentPodHash,
+		ActiveHash:      r.Status.BlueGreen.ActiveSelector,
+		PreviewHash:     r.Status.BlueGreen.PreviewSelector,
+		CreatedAt:       r.Metadata.CreationTimestamp,
+	}
+
+	if r.Spec.Strategy.BlueGreen != nil {
+		info.Strategy = RolloutBlueGreen
+		return info
+	}
+
+	info.Strategy = RolloutCanary
+	var steps []rolloutStep
+	if r.Spec.Strategy.Canary != nil {
+		steps = r.Spec.Strategy.Canary.Steps
+	}
+	info.Steps = int32(len(steps))
+	info.Step = info.Steps
+	if r.Status.CurrentStepIndex != nil {
+		info.Step = min(*r.Status.CurrentStepIndex, info.Steps)
+	}
+	if info.Step < info.Steps {
+		info.StepName = steps[info.Step].name()
+	}
+
+	// A promoted rollout's canary is its stable revision, taking all traffic
+	switch {
+	case info.Aborted:
+		info.Weight = 0
+	case info.Promoted():
+		info.Weight = 100
+	case r.Status.Canary.Weights != nil:
+		info.Weight = r.Status.Canary.Weights.Canary.Weight
+	case info.Step == info.Steps:
+		info.Weight = 100
+	default:
+		for _, step := range steps[:info.Step] {
+			if step.SetWeight != nil {
+				info.Weight = *step.SetWeight
+			}
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,201 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HPAInfo contains relevant HorizontalPodAutoscaler information
+type HPAInfo struct {
+	UID             string
+	Name            string
+	Namespace       string
+	TargetKind      string
+	TargetName      string
+	MinReplicas     int32
+	MaxReplicas     int32
+	CurrentReplicas int32
+	DesiredReplicas int32
+	Metrics         []HPAMetric
+}
+
+// HPAMetric is one scaling metric with its target and current value,
+// formatted for display, e.g. "cpu" with "80%" and "93%"
+type HPAMetric struct {
+	Name    string
+	Target  string
+	Current string
+}
+
+// AtMaxReplicas reports whether the autoscaler is pinned at maxReplicas and
+// so cannot add capacity however high its metrics go
+func (h HPAInfo) AtMaxReplicas() bool {
+	return h.MaxReplicas > 0 && h.CurrentReplicas >= h.MaxReplicas
+}
+
+// NeedsAttention reports whether the autoscaler is pinned at maxReplicas
+func (h HPAInfo) NeedsAttention() bool {
+	return h.AtMaxReplicas()
+}
+
+// GetHPAs retrieves HorizontalPodAutoscalers from the cluster
+func (c *Client) GetHPAs(ctx context.Context, namespace string) ([]HPAInfo, error) {
+	hpas, err := c.listHPAs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers: %w", err)
+	}
+
+	var hpaInfos []HPAInfo
+	for i := range hpas {
+		hpaInfos = append(hpaInfos, newHPAInfo(&hpas[i]))
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(hpaInfos, func(i, j int) bool {
+		if hpaInfos[i].Namespace != hpaInfos[j].Namespace {
+			return hpaInfos[i].Namespace < hpaInfos[j].Namespace
+		}
+		return hpaInfos[i].Name < hpaInfos[j].Name
+	})
+
+	return hpaInfos, nil
+}
+
+// newHPAInfo converts an autoscaler, pairing each spec metric with the
+// status metric of the same source
+func newHPAInfo(hpa *autoscalingv2.HorizontalPodAutoscaler) HPAInfo {
+	info := HPAInfo{
+		UID:             string(hpa.UID),
+		Name:            hpa.Name,
+		Namespace:       hpa.Namespace,
+		TargetKind:      hpa.Spec.ScaleTargetRef.Kind,
+		TargetName:      hpa.Spec.ScaleTargetRef.Name,
+		MinReplicas:     1,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}
+	if hpa.Spec.MinReplicas != nil {
+		info.MinReplicas = *hpa.Spec.MinReplicas
+	}
+
+	current := make(map[string]string, len(hpa.Status.CurrentMetrics))
+	for _, status := range hpa.Status.CurrentMetrics {
+		name, value := metricStatus(status)
+		current[name] = value
+	}
+
+	for _, spec := range hpa.Spec.Metrics {
+		name, target := metricSpec(spec)
+		metric := HPAMetric{Name: name, Target: target, Current: current[name]}
+		if metric.Current == "" {
+			metric.Current = "<unknown>"
+		}
+		info.Metrics = append(info.Metrics, metric)
+	}
+
+	return info
+}
+
+// metricSpec names a spec metric by its source and formats its target
+func metricSpec(spec autoscalingv2.MetricSpec) (string, string) {
+	switch spec.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if spec.Resource != nil {
+			return string(spec.Resource.Name), metricTarget(spec.Resource.Target)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if spec.ContainerResource != nil {
+			return spec.ContainerResource.Container + "/" + string(spec.ContainerResource.Name), metricTarget(spec.ContainerResource.Target)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if spec.Pods != nil {
+			return spec.Pods.Metric.Name, metricTarget(spec.Pods.Target)
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if spec.Object != nil {
+			return spec.Object.Metric.Name, metricTarget(spec.Object.Target)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if spec.External != nil {
+			return spec.External.Metric.Name, metricTarget(spec.External.Target)
+		}
+	}
+	return string(spec.Type), ""
+}
+
+// metricStatus names a status metric the same way metricSpec does and
+// formats its current value
+func metricStatus(status autoscalingv2.MetricStatus) (string, string) {
+	switch status.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if status.Resource != nil {
+			return string(status.Resource.Name), metricValue(status.Resource.Current)
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if status.ContainerResource != nil {
+			return status.ContainerResource.Container + "/" + string(status.ContainerResource.Name), metricValue(status.ContainerResource.Current)
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if status.Pods != nil {
+			return status.Pods.Metric.Name, metricValue(status.Pods.Current)
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if status.Object != nil {
+			return status.Object.Metric.Name, metricValue(status.Object.Current)
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if status.External != nil {
+			return status.External.Metric.Name, metricValue(status.External.Current)
+		}
+	}
+	return string(status.Type), ""
+}
+
+// metricTarget formats a target as a utilization percentage or a quantity
+func metricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return strconv.Itoa(int(*target.AverageUtilization)) + "%"
+	case target.AverageValue != nil:
+		return target.AverageValue.String()
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return ""
+}
+
+// metricValue formats a current value the same way metricTarget does
+func metricValue(value autoscalingv2.MetricValueStatus) string {
+	switch {
+	case value.AverageUtilization != nil:
+		return strconv.Itoa(int(*value.AverageUtilization)) + "%"
+	case value.AverageValue != nil:
+		return value.AverageValue.String()
+	case value.Value != nil:
+		return value.Value.String()
+	}
+	return ""
+}
+
+// ApplyHPAs sets PinnedAtMax on deployments whose autoscaler is at its
+// maxReplicas, so a saturated deployment stands out in the overview
+func ApplyHPAs(deployments []DeploymentInfo, hpas []HPAInfo) {
+	pinned := make(map[string]int32)
+	for _, hpa := range hpas {
+		if hpa.TargetKind == "Deployment" && hpa.AtMaxReplicas() {
+			pinned[hpa.Namespace+"/"+hpa.TargetName] = hpa.MaxReplicas
+		}
+	}
+
+	for i := range deployments {
+		maxReplicas, ok := pinned[deployments[i].Namespace+"/"+deployments[i].Name]
+		deployments[i].PinnedAtMax = ok
+		deployments[i].MaxReplicas = maxReplicas
+	}
+}
@@ -3,41 +3,238 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"pod-visualizer/pkg/analysis"
+	"pod-visualizer/pkg/status"
 )
 
 // Client wraps the Kubernetes clientset
 type Client struct {
-	clientset *kubernetes.Clientset
+	clientset    kubernetes.Interface
+	listPageSize int64
+
+	// excludedNamespaces are left out of cluster-wide lists, see
+	// SetExcludedNamespaces
+	excludedNamespaces []string
+
+	// excludeSidecars leaves sidecars out of pod readiness, see
+	// SetExcludeSidecars
+	excludeSidecars bool
+
+	// config is nil for wrapped clientsets; exec and port-forward need it
+	config *rest.Config
 }
 
 // PodInfo contains relevant pod information for visualization
 type PodInfo struct {
+	UID             string
 	Name            string
 	Namespace       string
 	Status          string
+	Phase           string
 	ContainerCount  int
 	ReadyContainers int
+	Restarts        int32
+	NodeName        string
+	CreatedAt       time.Time
+
+	// ReadyAt is when the pod's Ready condition last became True, zero
+	// while it is not Ready
+	ReadyAt time.Time
+
+	// Init containers run to completion before the containers above
+	// start; sidecars among them count as done once started. Ephemeral
+	// containers are debug containers added with kubectl debug.
+	InitContainerCount      int
+	InitContainersDone      int
+	EphemeralContainerCount int
+
+	// Sidecars are native sidecars (init containers with restartPolicy
+	// Always) and well-known proxies among the containers, see
+	// KnownSidecars. They count toward ContainerCount and ReadyContainers,
+	// as in kubectl's READY column, unless SidecarsExcluded.
+	SidecarCount     int
+	ReadySidecars    int
+	SidecarsExcluded bool
+
+	// Deployment is the name of the Deployment managing the pod, if any
+	Deployment string
+
+	// ArgoRollout is the name of the Argo Rollout managing the pod, if
+	// any, and ArgoRolloutHash the pod's rollouts-pod-template-hash
+	ArgoRollout     string
+	ArgoRolloutHash string
+
+	// Images are the containers' images, in container order
+	Images []string
+
+	// Containers are the containers' states, followed by native sidecars
+	Containers []ContainerDetail
+
+	// RuntimeClass is the pod's runtimeClassName (empty for the default runtime);
+	// ExpectedRuntimeClass is set from the namespace policy, if any
+	RuntimeClass         string
+	ExpectedRuntimeClass string
+
+	// QOSClass is Guaranteed, Burstable or BestEffort, the order in which
+	// the kubelet protects pods under node pressure; PriorityClass is the
+	// pod's priorityClassName, empty for the default priority
+	QOSClass      string
+	PriorityClass string
+
+	// Unschedulable is the scheduler's message while it cannot place the
+	// pod, e.g. "0/3 nodes are available: 3 Insufficient cpu"
+	Unschedulable string
+
+	// Tolerations and NodeSelector are the pod's, for ApplyTaintBlocks to
+	// set BlockingTaints on Pending pods that no node's taints admit
+	Tolerations    []corev1.Toleration
+	NodeSelector   map[string]string
+	BlockingTaints []analysis.TaintBlock
+
+	// CrashLoops are the containers in CrashLoopBackOff, the most
+	// restarted first
+	CrashLoops []analysis.CrashLoop
+
+	// Hints are the likely causes of an unhealthy pod's trouble, derived
+	// from its status, likeliest first
+	Hints []analysis.Hint
+
+	// EvictionMessage is why the kubelet evicted the pod, e.g. "The node
+	// was low on resource: memory."
+	EvictionMessage string
+
+	// NodePressure lists the pressure conditions of the pod's node, set by
+	// ApplyNodePressure
+	NodePressure []string
+
+	// Zone is the topology zone of the pod's node, set by ApplyZones
+	Zone string
+
+	// GitOps is the sync status of the pod's Deployment, set by
+	// ApplyGitOpsStatus
+	GitOps GitOpsStatus
+
+	// Resource requests summed across containers
+	CPURequestMilli    int64
+	MemoryRequestBytes int64
+
+	// Live usage, populated by the metrics package when metrics-server is available
+	CPUUsageMilli    int64
+	MemoryUsageBytes int64
+
+	// Ephemeral-storage limit summed across containers (0 when any container
+	// is unlimited), and storage usage populated from the kubelet summary API
+	EphemeralStorageLimitBytes int64
+	EphemeralStorageUsedBytes  int64
+	RootfsUsedBytes            int64
+}
+
+// Initializing reports whether the pod is still running init containers
+func (p PodInfo) Initializing() bool {
+	return status.IsInit(p.Status)
+}
+
+// EphemeralStorageWarnRatio is the share of the ephemeral-storage limit at
+// which a pod is flagged; the kubelet evicts it once the limit is exceeded
+const EphemeralStorageWarnRatio = 0.8
+
+// EphemeralStorageNearLimit reports whether the pod's ephemeral-storage usage
+// is close to its limit
+func (p PodInfo) EphemeralStorageNearLimit() bool {
+	if p.EphemeralStorageLimitBytes == 0 {
+		return false
+	}
+	return float64(p.EphemeralStorageUsedBytes) >= EphemeralStorageWarnRatio*float64(p.EphemeralStorageLimitBytes)
 }
 
 // DeploymentInfo contains relevant deployment information
 type DeploymentInfo struct {
+	UID               string
 	Name              string
 	Namespace         string
 	Replicas          int32
 	ReadyReplicas     int32
 	AvailableReplicas int32
+	CreatedAt         time.Time
+
+	// PinnedAtMax is set by ApplyHPAs when the deployment's autoscaler is
+	// at its MaxReplicas
+	PinnedAtMax bool
+	MaxReplicas int32
+
+	// RolloutStuck is set once the rollout exceeded its progress deadline
+	RolloutStuck bool
+
+	// HealthScore rates the deployment from 0 to 100, see
+	// analysis.HealthScore. GetDeployments scores readiness and the
+	// rollout; ApplyDeploymentHealth adds Restarts and WarningEvents.
+	HealthScore   int
+	Restarts      int32
+	WarningEvents int
+
+	// GitOps is the sync status reported by the Argo CD Application or
+	// Flux Kustomization deploying it, set by ApplyGitOpsStatus
+	GitOps GitOpsStatus
+}
+
+// NodeInfo contains relevant node information
+type NodeInfo struct {
+	Name                   string
+	Ready                  bool
+	CPUAllocatableMilli    int64
+	MemoryAllocatableBytes int64
+
+	// Pressure lists active MemoryPressure, DiskPressure and PIDPressure conditions
+	Pressure []string
+
+	// Labels and Taints decide which pods the scheduler places on the node
+	Labels map[string]string
+	Taints []corev1.Taint
+
+	// Zone is the node's topology.kubernetes.io/zone label, empty without one
+	Zone string
+
+	// Live usage, populated by the metrics package when metrics-server is available
+	CPUUsageMilli    int64
+	MemoryUsageBytes int64
+}
+
+// RESTOptions tunes client-go's client-side rate limiting and request
+// timeout. Zero values keep the client-go defaults: 5 requests per second,
+// bursts of 10 and no timeout.
+type RESTOptions struct {
+	QPS   float32
+	Burst int
+	// Timeout bounds each request, watches included, which then restart
+	Timeout time.Duration
+}
+
+// Apply sets the non-zero options on config
+func (o RESTOptions) Apply(config *rest.Config) {
+	if o.QPS > 0 {
+		config.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		config.Burst = o.Burst
+	}
+	if o.Timeout > 0 {
+		config.Timeout = o.Timeout
+	}
 }
 
 // NewClient creates a new Kubernetes client
 // It prioritizes in-cluster configuration when running inside a pod
-func NewClient(kubeconfigPath string) (*Client, error) {
+func NewClient(kubeconfigPath string, options RESTOptions) (*Client, error) {
 	var config *rest.Config
 	var err error
 
@@ -55,82 +252,330 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 		}
 	}
 
+	options.Apply(config)
+	return NewClientForConfig(config)
+}
+
+// NewClientForConfig creates a client from an already resolved REST config,
+// such as one built from kubectl's standard flags
+func NewClientForConfig(config *rest.Config) (*Client, error) {
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %v", err)
 	}
 
-	return &Client{clientset: clientset}, nil
+	client := NewClientFromClientset(clientset)
+	client.config = config
+	return client, nil
+}
+
+// NewClientFromClientset wraps an existing clientset, such as the fake
+// clientset used by pkg/k8s/fake
+func NewClientFromClientset(clientset kubernetes.Interface) *Client {
+	return &Client{clientset: clientset, listPageSize: DefaultListPageSize}
 }
 
 // GetPods retrieves pods from the cluster
 func (c *Client) GetPods(ctx context.Context, namespace string) ([]PodInfo, error) {
-	var pods *corev1.PodList
-	var err error
+	return c.GetPodsOnNode(ctx, namespace, "")
+}
+
+// GetPodsOnNode retrieves pods scheduled on the given node (empty for all nodes)
+func (c *Client) GetPodsOnNode(ctx context.Context, namespace, nodeName string) ([]PodInfo, error) {
+	listOptions := metav1.ListOptions{}
+	if nodeName != "" {
+		listOptions.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", nodeName).String()
+	}
 
-	if namespace == "" {
-		pods, err = c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
-	} else {
-		pods, err = c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	// Pods are converted page by page; only the compact PodInfo is kept
+	podInfos, err := listNamespaces(c, namespace, listOptions, func(namespace string, opts metav1.ListOptions) ([]PodInfo, string, error) {
+		pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		infos := make([]PodInfo, len(pods.Items))
+		for i := range pods.Items {
+			infos[i] = c.podInfo(&pods.Items[i])
+		}
+		return infos, pods.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(podInfos, func(i, j int) bool {
+		if podInfos[i].Namespace != podInfos[j].Namespace {
+			return podInfos[i].Namespace < podInfos[j].Namespace
+		}
+		return podInfos[i].Name < podInfos[j].Name
+	})
+
+	return podInfos, nil
+}
+
+// GetPod retrieves a single pod by namespace and name
+func (c *Client) GetPod(ctx context.Context, namespace, name string) (PodInfo, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods: %v", err)
+		return PodInfo{}, fmt.Errorf("failed to get pod %s/%s: %v", namespace, name, err)
 	}
 
-	var podInfos []PodInfo
-	for _, pod := range pods.Items {
-		readyContainers := 0
-		for _, containerStatus := range pod.Status.ContainerStatuses {
-			if containerStatus.Ready {
-				readyContainers++
+	return c.podInfo(pod), nil
+}
+
+// podInfo converts a pod like newPodInfo, leaving sidecars out of its
+// readiness if the client excludes them
+func (c *Client) podInfo(pod *corev1.Pod) PodInfo {
+	info := newPodInfo(pod)
+	if c.excludeSidecars {
+		info.excludeSidecars()
+	}
+	return info
+}
+
+// newPodInfo converts a pod into its visualization summary
+func newPodInfo(pod *corev1.Pod) PodInfo {
+	readyContainers := 0
+	restarts := int32(0)
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Ready {
+			readyContainers++
+		}
+		restarts += containerStatus.RestartCount
+	}
+
+	sidecars := sidecarContainers(pod)
+	readySidecars := 0
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, containerStatus := range statuses {
+			if containerStatus.Ready && sidecars[containerStatus.Name] {
+				readySidecars++
 			}
 		}
+	}
+	// Native sidecars keep running beside the containers, so they count
+	// as containers too
+	var nativeSidecars []corev1.Container
+	for _, container := range pod.Spec.InitContainers {
+		if isNativeSidecar(container) {
+			nativeSidecars = append(nativeSidecars, container)
+		}
+	}
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		if containerStatus.Ready && sidecars[containerStatus.Name] {
+			readyContainers++
+		}
+	}
+
+	cpuRequest := int64(0)
+	memoryRequest := int64(0)
+	storageLimit := int64(0)
+	storageUnlimited := false
+	images := make([]string, len(pod.Spec.Containers))
+	for i, container := range pod.Spec.Containers {
+		images[i] = container.Image
+		cpuRequest += container.Resources.Requests.Cpu().MilliValue()
+		memoryRequest += container.Resources.Requests.Memory().Value()
 
-		podInfo := PodInfo{
-			Name:            pod.Name,
-			Namespace:       pod.Namespace,
-			Status:          string(pod.Status.Phase),
-			ContainerCount:  len(pod.Spec.Containers),
-			ReadyContainers: readyContainers,
+		if limit, ok := container.Resources.Limits[corev1.ResourceEphemeralStorage]; ok {
+			storageLimit += limit.Value()
+		} else {
+			storageUnlimited = true
 		}
-		podInfos = append(podInfos, podInfo)
+	}
+	// Without a limit on every container the pod as a whole is unbounded
+	if storageUnlimited {
+		storageLimit = 0
 	}
 
-	return podInfos, nil
+	initDone, initCount := initProgress(pod)
+	containers := append(containerDetails(pod.Spec.Containers, pod.Status.ContainerStatuses),
+		containerDetails(nativeSidecars, pod.Status.InitContainerStatuses)...)
+
+	runtimeClass := ""
+	if pod.Spec.RuntimeClassName != nil {
+		runtimeClass = *pod.Spec.RuntimeClassName
+	}
+
+	evictionMessage := ""
+	if pod.Status.Reason == status.Evicted {
+		evictionMessage = pod.Status.Message
+	}
+
+	info := PodInfo{
+		UID:             string(pod.UID),
+		Name:            pod.Name,
+		Namespace:       pod.Namespace,
+		Status:          PodStatus(pod),
+		Phase:           string(pod.Status.Phase),
+		ContainerCount:  len(pod.Spec.Containers) + len(nativeSidecars),
+		ReadyContainers: readyContainers,
+		Restarts:        restarts,
+		NodeName:        pod.Spec.NodeName,
+		CreatedAt:       pod.CreationTimestamp.Time,
+		ReadyAt:         readySince(pod),
+		Deployment:      owningDeployment(pod),
+		Images:          images,
+		Containers:      containers,
+		RuntimeClass:    runtimeClass,
+		QOSClass:        string(pod.Status.QOSClass),
+		PriorityClass:   pod.Spec.PriorityClassName,
+		Unschedulable:   unschedulableMessage(pod),
+		Tolerations:     pod.Spec.Tolerations,
+		NodeSelector:    pod.Spec.NodeSelector,
+		EvictionMessage: evictionMessage,
+		CrashLoops:      analysis.CrashLoops(pod),
+
+		InitContainerCount:      initCount,
+		InitContainersDone:      initDone,
+		EphemeralContainerCount: len(pod.Spec.EphemeralContainers),
+
+		SidecarCount:  len(sidecars),
+		ReadySidecars: readySidecars,
+
+		CPURequestMilli:    cpuRequest,
+		MemoryRequestBytes: memoryRequest,
+
+		EphemeralStorageLimitBytes: storageLimit,
+	}
+	info.ArgoRollout, info.ArgoRolloutHash = owningRollout(pod)
+
+	// Healthy pods keep no hints, such as one for an OOMKilled run the
+	// container has recovered from
+	if info.NeedsAttention() {
+		info.Hints = analysis.Hints(pod, nil)
+	}
+	return info
 }
 
-// GetDeployments retrieves deployments from the cluster
-func (c *Client) GetDeployments(ctx context.Context, namespace string) ([]DeploymentInfo, error) {
-	var deployments *appsv1.DeploymentList
-	var err error
+// PodStatus derives a display status from the pod phase, refining it for
+// pods that are terminating, evicted, crash-looping or still running init
+// containers
+func PodStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return status.Terminating
+	}
+	if pod.Status.Reason == status.Evicted {
+		return status.Evicted
+	}
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, containerStatus := range statuses {
+			if containerStatus.State.Waiting != nil && containerStatus.State.Waiting.Reason == status.CrashLoopBackOff {
+				return status.CrashLoopBackOff
+			}
+		}
+	}
+	if done, total := initProgress(pod); done < total && pod.Status.Phase == corev1.PodPending {
+		return status.InitProgress(done, total)
+	}
+	if pod.Status.Phase == corev1.PodSucceeded {
+		return status.Completed
+	}
+	return string(pod.Status.Phase)
+}
+
+// initProgress counts the pod's init containers and how many have
+// finished: exited successfully or, for sidecars, started
+func initProgress(pod *corev1.Pod) (done, total int) {
+	sidecars := make(map[string]bool, len(pod.Spec.InitContainers))
+	for _, container := range pod.Spec.InitContainers {
+		sidecars[container.Name] = isNativeSidecar(container)
+	}
 
-	if namespace == "" {
-		deployments, err = c.clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
-	} else {
-		deployments, err = c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		state := containerStatus.State
+		switch {
+		case sidecars[containerStatus.Name] && containerStatus.Started != nil && *containerStatus.Started:
+			done++
+		case state.Terminated != nil && state.Terminated.ExitCode == 0:
+			done++
+		}
 	}
+	return done, len(pod.Spec.InitContainers)
+}
 
+// GetDeployments retrieves deployments from the cluster
+func (c *Client) GetDeployments(ctx context.Context, namespace string) ([]DeploymentInfo, error) {
+	deployments, err := c.listDeployments(ctx, namespace, metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list deployments: %v", err)
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
 
 	var deploymentInfos []DeploymentInfo
-	for _, deployment := range deployments.Items {
+	for _, deployment := range deployments {
 		deploymentInfo := DeploymentInfo{
+			UID:               string(deployment.UID),
 			Name:              deployment.Name,
 			Namespace:         deployment.Namespace,
 			Replicas:          *deployment.Spec.Replicas,
 			ReadyReplicas:     deployment.Status.ReadyReplicas,
 			AvailableReplicas: deployment.Status.AvailableReplicas,
+			CreatedAt:         deployment.CreationTimestamp.Time,
+			RolloutStuck:      deploymentWaitState(&deployment).Failed,
 		}
+		deploymentInfo.HealthScore = analysis.HealthScore(deploymentInfo.health())
 		deploymentInfos = append(deploymentInfos, deploymentInfo)
 	}
 
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(deploymentInfos, func(i, j int) bool {
+		if deploymentInfos[i].Namespace != deploymentInfos[j].Namespace {
+			return deploymentInfos[i].Namespace < deploymentInfos[j].Namespace
+		}
+		return deploymentInfos[i].Name < deploymentInfos[j].Name
+	})
+
 	return deploymentInfos, nil
 }
 
+// GetNodes retrieves nodes from the cluster
+func (c *Client) GetNodes(ctx context.Context) ([]NodeInfo, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	var nodeInfos []NodeInfo
+	for _, node := range nodes.Items {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+
+		nodeInfo := NodeInfo{
+			Name:                   node.Name,
+			Ready:                  ready,
+			CPUAllocatableMilli:    node.Status.Allocatable.Cpu().MilliValue(),
+			MemoryAllocatableBytes: node.Status.Allocatable.Memory().Value(),
+			Pressure:               nodePressure(&node),
+			Labels:                 node.Labels,
+			Taints:                 node.Spec.Taints,
+			Zone:                   nodeZone(&node),
+		}
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+
+	sort.Slice(nodeInfos, func(i, j int) bool {
+		return nodeInfos[i].Name < nodeInfos[j].Name
+	})
+
+	return nodeInfos, nil
+}
+
+// ServerVersion returns the Kubernetes version of the API server, such as
+// v1.30.2
+func (c *Client) ServerVersion() (string, error) {
+	info, err := c.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+	return info.GitVersion, nil
+}
+
 // GetClientset returns the underlying Kubernetes clientset for advanced operations
-func (c *Client) GetClientset() *kubernetes.Clientset {
+func (c *Client) GetClientset() kubernetes.Interface {
 	return c.clientset
 }
@@ -0,0 +1,287 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"pod-visualizer/pkg/status"
+)
+
+// testPod returns a Running pod with one ready container
+func testPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "-" + name)},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx:1.27"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+		},
+	}
+}
+
+func TestPodStatus(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name   string
+		modify func(pod *corev1.Pod)
+		want   string
+	}{
+		{
+			name:   "running",
+			modify: func(pod *corev1.Pod) {},
+			want:   string(corev1.PodRunning),
+		},
+		{
+			name: "terminating",
+			modify: func(pod *corev1.Pod) {
+				pod.DeletionTimestamp = &now
+			},
+			want: status.Terminating,
+		},
+		{
+			name: "evicted",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = status.Evicted
+			},
+			want: status.Evicted,
+		},
+		{
+			name: "crash looping",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses[0].Ready = false
+				pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: status.CrashLoopBackOff}
+			},
+			want: status.CrashLoopBackOff,
+		},
+		{
+			name: "crash looping init container",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodPending
+				pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+					Name:  "migrate",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: status.CrashLoopBackOff}},
+				}}
+			},
+			want: status.CrashLoopBackOff,
+		},
+		{
+			name: "init containers running",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodPending
+				pod.Spec.InitContainers = []corev1.Container{{Name: "fetch"}, {Name: "migrate"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+					{Name: "fetch", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+					{Name: "migrate", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}
+			},
+			want: status.InitProgress(1, 2),
+		},
+		{
+			name: "succeeded",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodSucceeded
+			},
+			want: status.Completed,
+		},
+		{
+			name: "pending",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodPending
+				pod.Status.ContainerStatuses = nil
+			},
+			want: string(corev1.PodPending),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod("default", "web")
+			tt.modify(pod)
+			if got := PodStatus(pod); got != tt.want {
+				t.Errorf("PodStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPodInfo(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	started := true
+	tests := []struct {
+		name   string
+		modify func(pod *corev1.Pod)
+		check  func(t *testing.T, info PodInfo)
+	}{
+		{
+			name:   "ready container",
+			modify: func(pod *corev1.Pod) {},
+			check: func(t *testing.T, info PodInfo) {
+				if info.ContainerCount != 1 || info.ReadyContainers != 1 {
+					t.Errorf("containers = %d/%d, want 1/1", info.ReadyContainers, info.ContainerCount)
+				}
+				if info.NeedsAttention() {
+					t.Error("NeedsAttention() = true for a ready pod")
+				}
+				if len(info.Images) != 1 || info.Images[0] != "nginx:1.27" {
+					t.Errorf("Images = %v, want [nginx:1.27]", info.Images)
+				}
+			},
+		},
+		{
+			name: "restarts summed over containers",
+			modify: func(pod *corev1.Pod) {
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "proxy"})
+				pod.Status.ContainerStatuses[0].RestartCount = 2
+				pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: "proxy", RestartCount: 3})
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.Restarts != 5 {
+					t.Errorf("Restarts = %d, want 5", info.Restarts)
+				}
+				if info.ContainerCount != 2 || info.ReadyContainers != 1 {
+					t.Errorf("containers = %d/%d, want 1/2", info.ReadyContainers, info.ContainerCount)
+				}
+			},
+		},
+		{
+			name: "native sidecar counts as a container",
+			modify: func(pod *corev1.Pod) {
+				pod.Spec.InitContainers = []corev1.Container{{Name: "mesh", RestartPolicy: &always}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "mesh", Ready: true, Started: &started}}
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.ContainerCount != 2 || info.ReadyContainers != 2 {
+					t.Errorf("containers = %d/%d, want 2/2", info.ReadyContainers, info.ContainerCount)
+				}
+				if info.SidecarCount != 1 || info.ReadySidecars != 1 {
+					t.Errorf("sidecars = %d/%d, want 1/1", info.ReadySidecars, info.SidecarCount)
+				}
+				if info.InitContainerCount != 1 || info.InitContainersDone != 1 {
+					t.Errorf("init containers = %d/%d, want 1/1", info.InitContainersDone, info.InitContainerCount)
+				}
+			},
+		},
+		{
+			name: "requests and storage limits",
+			modify: func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("250m"),
+						corev1.ResourceMemory: resource.MustParse("64Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+					},
+				}
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.CPURequestMilli != 250 {
+					t.Errorf("CPURequestMilli = %d, want 250", info.CPURequestMilli)
+				}
+				if info.MemoryRequestBytes != 64<<20 {
+					t.Errorf("MemoryRequestBytes = %d, want %d", info.MemoryRequestBytes, 64<<20)
+				}
+				if info.EphemeralStorageLimitBytes != 1<<30 {
+					t.Errorf("EphemeralStorageLimitBytes = %d, want %d", info.EphemeralStorageLimitBytes, 1<<30)
+				}
+			},
+		},
+		{
+			name: "storage unbounded without a limit on every container",
+			modify: func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")}
+				pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "proxy"})
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.EphemeralStorageLimitBytes != 0 {
+					t.Errorf("EphemeralStorageLimitBytes = %d, want 0", info.EphemeralStorageLimitBytes)
+				}
+			},
+		},
+		{
+			name: "evicted keeps its message",
+			modify: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.Reason = status.Evicted
+				pod.Status.Message = "The node was low on resource: memory."
+				pod.Status.ContainerStatuses[0].Ready = false
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.Status != status.Evicted {
+					t.Errorf("Status = %q, want %q", info.Status, status.Evicted)
+				}
+				if info.EvictionMessage != "The node was low on resource: memory." {
+					t.Errorf("EvictionMessage = %q", info.EvictionMessage)
+				}
+			},
+		},
+		{
+			name: "owning deployment",
+			modify: func(pod *corev1.Pod) {
+				controller := true
+				pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-6d4f9c8b7", Controller: &controller}}
+				pod.Labels = map[string]string{"pod-template-hash": "6d4f9c8b7"}
+			},
+			check: func(t *testing.T, info PodInfo) {
+				if info.Deployment != "web" {
+					t.Errorf("Deployment = %q, want web", info.Deployment)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod("default", "web")
+			tt.modify(pod)
+			tt.check(t, newPodInfo(pod))
+		})
+	}
+}
+
+func TestGetPods(t *testing.T) {
+	client := NewClientFromClientset(fake.NewSimpleClientset(
+		testPod("shop", "web"),
+		testPod("default", "worker"),
+		testPod("default", "api"),
+		testPod("kube-system", "dns"),
+	))
+
+	tests := []struct {
+		name      string
+		namespace string
+		want      []string
+	}{
+		{name: "all namespaces", namespace: "", want: []string{"default/api", "default/worker", "kube-system/dns", "shop/web"}},
+		{name: "one namespace", namespace: "default", want: []string{"default/api", "default/worker"}},
+		{name: "several namespaces", namespace: "shop, default", want: []string{"default/api", "default/worker", "shop/web"}},
+		{name: "unknown namespace", namespace: "missing", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := client.GetPods(context.Background(), tt.namespace)
+			if err != nil {
+				t.Fatalf("GetPods() error = %v", err)
+			}
+			var got []string
+			for _, pod := range pods {
+				got = append(got, pod.Namespace+"/"+pod.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetPods(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+		})
+	}
+}
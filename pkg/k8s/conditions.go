@@ -0,0 +1,75 @@
+package k8s
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// unschedulableMessage returns the scheduler's explanation for a pod it
+// could not place, such as "0/3 nodes are available: 3 Insufficient cpu",
+// or "" for pods that are scheduled or still waiting for their first try
+func unschedulableMessage(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodScheduled || condition.Status != corev1.ConditionFalse ||
+			condition.Reason != corev1.PodReasonUnschedulable {
+			continue
+		}
+		if condition.Message == "" {
+			return condition.Reason
+		}
+		return condition.Message
+	}
+	return ""
+}
+
+// readySince returns when the pod last became Ready, or the zero time
+// while it is not
+func readySince(pod *corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// StartupDuration returns how long the pod took from creation to Ready,
+// or 0 while it is not Ready. A pod that lost readiness and regained it
+// reports the later transition.
+func (p PodInfo) StartupDuration() time.Duration {
+	if p.ReadyAt.IsZero() || p.ReadyAt.Before(p.CreatedAt) {
+		return 0
+	}
+	return p.ReadyAt.Sub(p.CreatedAt)
+}
+
+// readinessGates returns the conditions named by the pod's readiness
+// gates, which an external controller such as a load balancer sets. A
+// gate without its condition yet has an empty Status; like a False one,
+// it keeps the pod from becoming Ready.
+func readinessGates(pod *corev1.Pod) []ConditionInfo {
+	var gates []ConditionInfo
+	for _, gate := range pod.Spec.ReadinessGates {
+		info := ConditionInfo{Type: string(gate.ConditionType)}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == gate.ConditionType {
+				info = conditionInfo(condition)
+				break
+			}
+		}
+		gates = append(gates, info)
+	}
+	return gates
+}
+
+// conditionInfo converts a pod condition
+func conditionInfo(condition corev1.PodCondition) ConditionInfo {
+	return ConditionInfo{
+		Type:               string(condition.Type),
+		Status:             string(condition.Status),
+		Reason:             condition.Reason,
+		Message:            condition.Message,
+		LastTransitionTime: condition.LastTransitionTime.Time,
+	}
+}
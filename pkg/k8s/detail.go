@@ -0,0 +1,270 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"pod-visualizer/pkg/analysis"
+)
+
+// maxDetailEvents is how many of the most recent events a detail view includes
+const maxDetailEvents = 20
+
+// PodDetail contains the full drill-down information for a single pod
+type PodDetail struct {
+	UID            string
+	Name           string
+	Namespace      string
+	Status         string
+	Phase          string
+	NodeName       string
+	PodIP          string
+	QOSClass       string
+	RuntimeClass   string
+	StartTime      time.Time
+	InitContainers []ContainerDetail
+	Containers     []ContainerDetail
+
+	// EphemeralContainers are debug containers added with kubectl debug
+	EphemeralContainers []ContainerDetail
+
+	// Unschedulable is the scheduler's message while it cannot place the
+	// pod, see PodInfo
+	Unschedulable string
+
+	// ReadinessGates are the conditions the pod's readiness gates wait on
+	ReadinessGates []ConditionInfo
+
+	// Hints are the likely causes of the pod's trouble, derived from its
+	// status and events
+	Hints []analysis.Hint
+
+	Conditions  []ConditionInfo
+	Owners      []OwnerInfo
+	Tolerations []TolerationInfo
+	Events      []EventInfo
+}
+
+// ContainerDetail contains the spec and status of one container
+type ContainerDetail struct {
+	Name         string
+	Image        string
+	Ready        bool
+	RestartCount int32
+	State        string // Waiting, Running or Terminated
+	Reason       string
+	Message      string
+	StartedAt    time.Time
+}
+
+// ConditionInfo is a resource status condition
+type ConditionInfo struct {
+	Type               string
+	Status             string
+	Reason             string
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// OwnerInfo is an owner reference
+type OwnerInfo struct {
+	Kind       string
+	Name       string
+	Controller bool
+}
+
+// TolerationInfo is a pod toleration
+type TolerationInfo struct {
+	Key      string
+	Operator string
+	Value    string
+	Effect   string
+}
+
+// EventInfo is a Kubernetes event about a resource
+type EventInfo struct {
+	Type     string
+	Reason   string
+	Message  string
+	Count    int32
+	LastSeen time.Time
+}
+
+// GetPodDetail retrieves a pod with its container states and recent events
+func (c *Client) GetPodDetail(ctx context.Context, namespace, name string) (PodDetail, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return PodDetail{}, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+	}
+
+	info := newPodInfo(pod)
+	detail := PodDetail{
+		UID:          info.UID,
+		Name:         pod.Name,
+		Namespace:    pod.Namespace,
+		Status:       info.Status,
+		Phase:        info.Phase,
+		NodeName:     pod.Spec.NodeName,
+		PodIP:        pod.Status.PodIP,
+		QOSClass:     string(pod.Status.QOSClass),
+		RuntimeClass: info.RuntimeClass,
+
+		Unschedulable: info.Unschedulable,
+	}
+	if pod.Status.StartTime != nil {
+		detail.StartTime = pod.Status.StartTime.Time
+	}
+
+	detail.InitContainers = containerDetails(pod.Spec.InitContainers, pod.Status.InitContainerStatuses)
+	detail.Containers = containerDetails(pod.Spec.Containers, pod.Status.ContainerStatuses)
+	ephemeral := make([]corev1.Container, len(pod.Spec.EphemeralContainers))
+	for i, container := range pod.Spec.EphemeralContainers {
+		ephemeral[i] = corev1.Container(container.EphemeralContainerCommon)
+	}
+	detail.EphemeralContainers = containerDetails(ephemeral, pod.Status.EphemeralContainerStatuses)
+
+	for _, condition := range pod.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, conditionInfo(condition))
+	}
+	detail.ReadinessGates = readinessGates(pod)
+
+	detail.Owners = ownerInfos(pod.OwnerReferences)
+
+	for _, toleration := range pod.Spec.Tolerations {
+		detail.Tolerations = append(detail.Tolerations, TolerationInfo{
+			Key:      toleration.Key,
+			Operator: string(toleration.Operator),
+			Value:    toleration.Value,
+			Effect:   string(toleration.Effect),
+		})
+	}
+
+	// Events are supplementary; a pod without readable events still has detail
+	detail.Events, _ = c.GetEvents(ctx, namespace, "Pod", name)
+
+	events := make([]analysis.Event, len(detail.Events))
+	for i, event := range detail.Events {
+		events[i] = analysis.Event{Reason: event.Reason, Message: event.Message}
+	}
+	detail.Hints = analysis.Hints(pod, events)
+
+	return detail, nil
+}
+
+// containerDetails merges container specs with their statuses by name
+func containerDetails(containers []corev1.Container, statuses []corev1.ContainerStatus) []ContainerDetail {
+	statusByName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, containerStatus := range statuses {
+		statusByName[containerStatus.Name] = containerStatus
+	}
+
+	var details []ContainerDetail
+	for _, container := range containers {
+		detail := ContainerDetail{Name: container.Name, Image: container.Image, State: "Waiting"}
+
+		if containerStatus, ok := statusByName[container.Name]; ok {
+			detail.Ready = containerStatus.Ready
+			detail.RestartCount = containerStatus.RestartCount
+
+			state := containerStatus.State
+			switch {
+			case state.Running != nil:
+				detail.State = "Running"
+				detail.StartedAt = state.Running.StartedAt.Time
+			case state.Terminated != nil:
+				detail.State = "Terminated"
+				detail.Reason = state.Terminated.Reason
+				detail.Message = state.Terminated.Message
+				detail.StartedAt = state.Terminated.StartedAt.Time
+			case state.Waiting != nil:
+				detail.Reason = state.Waiting.Reason
+				detail.Message = state.Waiting.Message
+			}
+		}
+
+		details = append(details, detail)
+	}
+
+	return details
+}
+
+// ownerInfos converts owner references
+func ownerInfos(references []metav1.OwnerReference) []OwnerInfo {
+	var owners []OwnerInfo
+	for _, owner := range references {
+		owners = append(owners, OwnerInfo{
+			Kind:       owner.Kind,
+			Name:       owner.Name,
+			Controller: owner.Controller != nil && *owner.Controller,
+		})
+	}
+	return owners
+}
+
+// GetEvents retrieves the most recent events about an object, newest first
+func (c *Client) GetEvents(ctx context.Context, namespace, kind, name string) ([]EventInfo, error) {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", kind),
+		fields.OneTermEqualSelector("involvedObject.name", name),
+	)
+
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var eventInfos []EventInfo
+	for _, event := range events.Items {
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+
+		eventInfos = append(eventInfos, EventInfo{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: lastSeen,
+		})
+	}
+
+	sort.Slice(eventInfos, func(i, j int) bool {
+		return eventInfos[i].LastSeen.After(eventInfos[j].LastSeen)
+	})
+	if len(eventInfos) > maxDetailEvents {
+		eventInfos = eventInfos[:maxDetailEvents]
+	}
+
+	return eventInfos, nil
+}
+
+// CountWarningEvents counts Warning events per namespace, including each
+// event's repeats, so noisy namespaces stand out in the summary
+func (c *Client) CountWarningEvents(ctx context.Context, namespace string) (map[string]int, error) {
+	selector := fields.OneTermEqualSelector("type", corev1.EventTypeWarning)
+	events, err := c.listEvents(ctx, namespace, metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, event := range events {
+		// The fake clientset ignores field selectors
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		count := int(event.Count)
+		if count < 1 {
+			count = 1
+		}
+		counts[event.Namespace] += count
+	}
+	return counts, nil
+}
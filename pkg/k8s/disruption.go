@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PDBInfo contains relevant PodDisruptionBudget information, the workloads
+// it covers and the nodes that could not be drained without violating it
+type PDBInfo struct {
+	UID            string
+	Name           string
+	Namespace      string
+	MinAvailable   string
+	MaxUnavailable string
+
+	CurrentHealthy     int32
+	DesiredHealthy     int32
+	ExpectedPods       int32
+	DisruptionsAllowed int32
+
+	// Workloads are the controllers of the covered pods, e.g. "Deployment/web"
+	Workloads []string
+
+	// BlockedNodes are nodes running more healthy covered pods than the
+	// budget allows to be disrupted, so draining one would be refused
+	BlockedNodes []string
+}
+
+// DrainBlocked reports whether draining some node would violate the budget
+func (p PDBInfo) DrainBlocked() bool {
+	return len(p.BlockedNodes) > 0
+}
+
+// NeedsAttention reports whether a node drain would violate the budget
+func (p PDBInfo) NeedsAttention() bool {
+	return p.DrainBlocked()
+}
+
+// GetPDBs retrieves PodDisruptionBudgets from the cluster, matching each to
+// the pods it selects to find their workloads and which nodes a drain would
+// be blocked on
+func (c *Client) GetPDBs(ctx context.Context, namespace string) ([]PDBInfo, error) {
+	budgets, err := c.listPDBs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list poddisruptionbudgets: %w", err)
+	}
+
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var pdbInfos []PDBInfo
+	for i := range budgets {
+		budget := &budgets[i]
+		info := PDBInfo{
+			UID:                string(budget.UID),
+			Name:               budget.Name,
+			Namespace:          budget.Namespace,
+			CurrentHealthy:     budget.Status.CurrentHealthy,
+			DesiredHealthy:     budget.Status.DesiredHealthy,
+			ExpectedPods:       budget.Status.ExpectedPods,
+			DisruptionsAllowed: budget.Status.DisruptionsAllowed,
+		}
+		if budget.Spec.MinAvailable != nil {
+			info.MinAvailable = budget.Spec.MinAvailable.String()
+		}
+		if budget.Spec.MaxUnavailable != nil {
+			info.MaxUnavailable = budget.Spec.MaxUnavailable.String()
+		}
+
+		// An invalid selector matches nothing, as in the disruption controller
+		selector, err := metav1.LabelSelectorAsSelector(budget.Spec.Selector)
+		if err != nil {
+			selector = labels.Nothing()
+		}
+
+		workloads := make(map[string]bool)
+		healthyByNode := make(map[string]int32)
+		for j := range pods {
+			pod := &pods[j]
+			if pod.Namespace != budget.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if workload := podWorkload(pod); workload != "" {
+				workloads[workload] = true
+			}
+			if pod.Spec.NodeName != "" && podIsReady(pod) {
+				healthyByNode[pod.Spec.NodeName]++
+			}
+		}
+
+		for workload := range workloads {
+			info.Workloads = append(info.Workloads, workload)
+		}
+		sort.Strings(info.Workloads)
+
+		for node, healthy := range healthyByNode {
+			if healthy > info.DisruptionsAllowed {
+				info.BlockedNodes = append(info.BlockedNodes, node)
+			}
+		}
+		sort.Strings(info.BlockedNodes)
+
+		pdbInfos = append(pdbInfos, info)
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(pdbInfos, func(i, j int) bool {
+		if pdbInfos[i].Namespace != pdbInfos[j].Namespace {
+			return pdbInfos[i].Namespace < pdbInfos[j].Namespace
+		}
+		return pdbInfos[i].Name < pdbInfos[j].Name
+	})
+
+	return pdbInfos, nil
+}
+
+// podWorkload names the pod's controller as "Kind/name", resolving
+// ReplicaSets to their Deployment
+func podWorkload(pod *corev1.Pod) string {
+	if deployment := owningDeployment(pod); deployment != "" {
+		return "Deployment/" + deployment
+	}
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+	return ""
+}
+
+// podIsReady reports whether the pod's Ready condition is true; only ready
+// pods count as healthy for a disruption budget
+func podIsReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// ErrStreamingUnavailable is returned by ExecPod and PortForwardPod when
+// the client was built from a clientset alone, as in tests, and so has no
+// REST config to open streams with
+var ErrStreamingUnavailable = errors.New("exec and port-forward need a client built from a REST config")
+
+// TerminalSize is a terminal's width and height in characters
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
+// ExecOptions describes a command to run in a pod's container
+type ExecOptions struct {
+	// Container defaults to the pod's only container
+	Container string
+	Command   []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	// Stderr is unused with TTY, where the terminal merges both streams
+	Stderr io.Writer
+	TTY    bool
+
+	// Resize delivers terminal size changes for TTY sessions
+	Resize <-chan TerminalSize
+}
+
+// ExecPod runs a command in a container, like kubectl exec, streaming
+// until the command exits or ctx is done
+func (c *Client) ExecPod(ctx context.Context, namespace, name string, opts ExecOptions) error {
+	if c.config == nil {
+		return ErrStreamingUnavailable
+	}
+
+	request := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil && !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.config, http.MethodPost, request.URL())
+	if err != nil {
+		return fmt.Errorf("failed to start exec: %w", err)
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Tty:    opts.TTY,
+	}
+	if !opts.TTY {
+		streamOptions.Stderr = opts.Stderr
+	}
+	if opts.Resize != nil {
+		streamOptions.TerminalSizeQueue = sizeQueue(opts.Resize)
+	}
+	return executor.StreamWithContext(ctx, streamOptions)
+}
+
+// sizeQueue adapts a channel of sizes to remotecommand.TerminalSizeQueue
+type sizeQueue <-chan TerminalSize
+
+// Next blocks for the next size; nil ends the queue
+func (q sizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+}
+
+// PortForward is an open port-forward from a local port to a pod
+type PortForward struct {
+	Address   string
+	LocalPort int
+	PodPort   int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Close stops forwarding and waits for open connections to be torn down
+func (p *PortForward) Close() {
+	select {
+	case <-p.stop:
+	default:
+		close(p.stop)
+	}
+	<-p.done
+}
+
+// Done is closed once forwarding stops, e.g. because the pod went away
+func (p *PortForward) Done() <-chan struct{} {
+	return p.done
+}
+
+// PortForwardPod forwards a random free port on address (e.g. 127.0.0.1)
+// to podPort, like kubectl port-forward, until Close is called
+func (c *Client) PortForwardPod(ctx context.Context, namespace, name string, podPort int, address string) (*PortForward, error) {
+	if c.config == nil {
+		return nil, ErrStreamingUnavailable
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start port-forward: %w", err)
+	}
+	url := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	forward := &PortForward{
+		Address: address,
+		PodPort: podPort,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	ready := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{address}, []string{fmt.Sprintf("0:%d", podPort)}, forward.stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start port-forward: %w", err)
+	}
+
+	failed := make(chan error, 1)
+	go func() {
+		defer close(forward.done)
+		failed <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-ready:
+	case err := <-failed:
+		return nil, fmt.Errorf("failed to start port-forward: %w", err)
+	case <-ctx.Done():
+		forward.Close()
+		return nil, ctx.Err()
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		forward.Close()
+		return nil, fmt.Errorf("failed to start port-forward: no local port bound")
+	}
+	forward.LocalPort = int(ports[0].Local)
+	return forward, nil
+}
@@ -0,0 +1,402 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// DefaultDemoInterval is how often the demo cluster changes
+const DefaultDemoInterval = 5 * time.Second
+
+// demoAge is how long ago the demo cluster's objects were created
+const demoAge = 3 * time.Hour
+
+// demoVersion is the Kubernetes version the demo cluster reports
+const demoVersion = "v1.30.0"
+
+// Demo drives a synthetic cluster through a loop of everyday trouble: a
+// pod crash-loops and recovers, a deployment scales out through Pending
+// pods and back in, and warning events accompany the failures
+type Demo struct {
+	clientset *fake.Clientset
+	interval  time.Duration
+	random    *rand.Rand
+	tick      int
+}
+
+// NewDemoClient returns a client serving a synthetic cluster of three
+// nodes and a handful of namespaces, and the Demo that evolves it
+func NewDemoClient(interval time.Duration) (*k8s.Client, *Demo) {
+	clientset := newClientset()
+	for _, object := range settle(demoObjects(), time.Now().Add(-demoAge)) {
+		// The objects are generated with unique names, so Add cannot fail
+		_ = clientset.Tracker().Add(object)
+	}
+	allowAccessReviews(clientset)
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: demoVersion}
+
+	demo := &Demo{clientset: clientset, interval: interval, random: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	return k8s.NewClientFromClientset(clientset), demo
+}
+
+// demoObjects returns the manifests of the demo cluster
+func demoObjects() []runtime.Object {
+	objects := []runtime.Object{
+		demoNode("node-a"), demoNode("node-b"), demoNode("node-c"),
+		demoDeployment("shop", "frontend", "nginx:1.25", 3, "100m", "64Mi"),
+		demoDeployment("shop", "checkout", "ghcr.io/example/checkout:2.4.1", 2, "250m", "256Mi"),
+		demoDeployment("shop", "catalog", "ghcr.io/example/catalog:1.9.0", 2, "200m", "128Mi"),
+		demoDeployment("payments", "api", "ghcr.io/example/payments-api:3.0.2", 2, "500m", "512Mi"),
+		demoDeployment("monitoring", "grafana", "grafana/grafana:10.2.0", 1, "100m", "128Mi"),
+		demoStatefulSet("payments", "postgres", "postgres:16", 2),
+		demoDaemonSet("monitoring", "node-exporter", "prom/node-exporter:v1.7.0"),
+	}
+	for _, name := range []string{"frontend", "checkout", "catalog"} {
+		objects = append(objects, demoService("shop", name))
+	}
+	return append(objects, demoService("payments", "api"), demoService("payments", "postgres"), demoService("monitoring", "grafana"))
+}
+
+// demoNode is a Ready node with room for the demo's pods
+func demoNode(name string) *corev1.Node {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"kubernetes.io/hostname": name}},
+		Status:     corev1.NodeStatus{Capacity: capacity, Allocatable: capacity},
+	}
+}
+
+// demoTemplate is a single-container pod template labeled app=name
+func demoTemplate(name, image, cpu, memory string) corev1.PodTemplateSpec {
+	requests := corev1.ResourceList{}
+	if cpu != "" {
+		requests[corev1.ResourceCPU] = resource.MustParse(cpu)
+		requests[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:      name,
+			Image:     image,
+			Resources: corev1.ResourceRequirements{Requests: requests},
+		}}},
+	}
+}
+
+// demoDeployment is a deployment of replicas pods labeled app=name
+func demoDeployment(namespace, name, image string, replicas int32, cpu, memory string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: demoTemplate(name, image, cpu, memory),
+		},
+	}
+}
+
+// demoStatefulSet is a statefulset of replicas pods labeled app=name
+func demoStatefulSet(namespace, name, image string, replicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: demoTemplate(name, image, "500m", "1Gi"),
+		},
+	}
+}
+
+// demoDaemonSet is a daemonset of pods labeled app=name
+func demoDaemonSet(namespace, name, image string) *appsv1.DaemonSet {
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: demoTemplate(name, image, "", ""),
+		},
+	}
+}
+
+// demoService selects the pods labeled app=name
+func demoService(namespace, name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports:    []corev1.ServicePort{{Port: 80}},
+		},
+	}
+}
+
+// Run changes the cluster every interval until ctx is done
+func (d *Demo) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.step(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("Demo step failed", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// step starts the Pending pods of the previous step, then moves the loop
+// on: crash a checkout pod, scale the frontend out, recover the crashed
+// pod, scale the frontend back in
+func (d *Demo) step(ctx context.Context) error {
+	now := time.Now()
+	if err := d.startPendingPods(ctx, now); err != nil {
+		return err
+	}
+
+	var err error
+	switch d.tick % 4 {
+	case 0:
+		err = d.crashPod(ctx, "shop", "checkout", now)
+	case 1:
+		err = d.scale(ctx, "shop", "frontend", 5, now)
+	case 2:
+		err = d.recoverPods(ctx, "shop", now)
+	case 3:
+		err = d.scale(ctx, "shop", "frontend", 3, now)
+	}
+	d.tick++
+	if err != nil {
+		return err
+	}
+	return d.syncDeployments(ctx)
+}
+
+// startPendingPods makes every Pending pod Running and ready
+func (d *Demo) startPendingPods(ctx context.Context, now time.Time) error {
+	pods, err := d.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		ip := pod.Status.PodIP
+		settlePod(pod, now)
+		pod.Status.PodIP = ip
+		if _, err := d.clientset.CoreV1().Pods(pod.Namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crashPod puts a random pod labeled app=name into CrashLoopBackOff
+func (d *Demo) crashPod(ctx context.Context, namespace, name string, now time.Time) error {
+	pods, err := d.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + name})
+	if err != nil || len(pods.Items) == 0 {
+		return err
+	}
+
+	pod := &pods.Items[d.random.Intn(len(pods.Items))]
+	for i := range pod.Status.ContainerStatuses {
+		container := &pod.Status.ContainerStatuses[i]
+		container.Ready = false
+		container.RestartCount += 3
+		container.LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1, Reason: "Error", StartedAt: metav1.NewTime(now.Add(-10 * time.Second)), FinishedAt: metav1.NewTime(now),
+		}}
+		container.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason: "CrashLoopBackOff", Message: "back-off 40s restarting failed container",
+		}}
+	}
+	setPodReady(pod, false, now)
+	if _, err := d.clientset.CoreV1().Pods(namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return d.warn(ctx, pod, "BackOff", "Back-off restarting failed container", now)
+}
+
+// recoverPods restarts the crash-looping pods of a namespace
+func (d *Demo) recoverPods(ctx context.Context, namespace string, now time.Time) error {
+	pods, err := d.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if k8s.PodStatus(pod) != "CrashLoopBackOff" {
+			continue
+		}
+		for j := range pod.Status.ContainerStatuses {
+			container := &pod.Status.ContainerStatuses[j]
+			container.Ready = true
+			container.State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now)}}
+		}
+		setPodReady(pod, true, now)
+		if _, err := d.clientset.CoreV1().Pods(namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scale sets a deployment's replicas, creating Pending pods or deleting
+// surplus ones to match
+func (d *Demo) scale(ctx context.Context, namespace, name string, replicas int32, now time.Time) error {
+	deployment, err := d.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	deployment.Spec.Replicas = &replicas
+	if _, err := d.clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	replicaSets, err := d.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + name})
+	if err != nil || len(replicaSets.Items) == 0 {
+		return err
+	}
+	replicaSet := &replicaSets.Items[0]
+	pods, err := d.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + name})
+	if err != nil {
+		return err
+	}
+
+	nodes := []string{"node-a", "node-b", "node-c"}
+	for i := int32(len(pods.Items)); i < replicas; i++ {
+		pod := templatePod(replicaSet.Name+"-"+utilrand.String(5), namespace, replicaSet.Spec.Template, controllerRef(replicaSet, "ReplicaSet"), nodes[d.random.Intn(len(nodes))], now)
+		stamp(pod, now)
+		pod.Status = corev1.PodStatus{
+			Phase:    corev1.PodPending,
+			PodIP:    fmt.Sprintf("10.244.9.%d", d.random.Intn(250)+2),
+			Reason:   "ContainerCreating",
+			QOSClass: qosClass(pod.Spec),
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(now)},
+			},
+		}
+		for _, container := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				Name:  container.Name,
+				Image: container.Image,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+			})
+		}
+		if _, err := d.clientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	for i := int(replicas); i < len(pods.Items); i++ {
+		if err := d.clientset.CoreV1().Pods(namespace).Delete(ctx, pods.Items[i].Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDeployments recomputes each deployment's status from its pods, as
+// the deployment controller would
+func (d *Demo) syncDeployments(ctx context.Context) error {
+	deployments, err := d.clientset.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		pods, err := d.clientset.CoreV1().Pods(deployment.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(deployment.Spec.Selector.MatchLabels).String(),
+		})
+		if err != nil {
+			return err
+		}
+
+		var ready int32
+		for j := range pods.Items {
+			if podReady(&pods.Items[j]) {
+				ready++
+			}
+		}
+		status := &deployment.Status
+		if status.Replicas == int32(len(pods.Items)) && status.ReadyReplicas == ready {
+			continue
+		}
+		status.Replicas, status.UpdatedReplicas = int32(len(pods.Items)), int32(len(pods.Items))
+		status.ReadyReplicas, status.AvailableReplicas = ready, ready
+		status.UnavailableReplicas = *deployment.Spec.Replicas - ready
+		if status.UnavailableReplicas < 0 {
+			status.UnavailableReplicas = 0
+		}
+		if _, err := d.clientset.AppsV1().Deployments(deployment.Namespace).UpdateStatus(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warn records a Warning event about pod
+func (d *Demo) warn(ctx context.Context, pod *corev1.Pod, reason, message string, now time.Time) error {
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name + "." + utilrand.String(8), Namespace: pod.Namespace},
+		InvolvedObject: corev1.ObjectReference{
+			Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID,
+		},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(now),
+		LastTimestamp:  metav1.NewTime(now),
+		Source:         corev1.EventSource{Component: "kubelet", Host: pod.Spec.NodeName},
+	}
+	_, err := d.clientset.CoreV1().Events(pod.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+// setPodReady sets the pod's Ready and ContainersReady conditions
+func setPodReady(pod *corev1.Pod, ready bool, now time.Time) {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	for i := range pod.Status.Conditions {
+		condition := &pod.Status.Conditions[i]
+		if condition.Type == corev1.PodReady || condition.Type == corev1.ContainersReady {
+			condition.Status = status
+			condition.LastTransitionTime = metav1.NewTime(now)
+		}
+	}
+}
+
+// podReady reports whether the pod's Ready condition is true
+func podReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Package fake provides a k8s.Client backed by client-go's fake clientset,
+// so code using the client can be exercised without a cluster.
+package fake
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// NewClient returns a client serving the given objects from memory. The
+// clientset is returned too, for adding objects, injecting errors through
+// reactors or inspecting the actions a test performed.
+func NewClient(objects ...runtime.Object) (*k8s.Client, *fake.Clientset) {
+	clientset := newClientset(objects...)
+	return k8s.NewClientFromClientset(clientset), clientset
+}
+
+// newClientset returns a fake clientset holding objects
+func newClientset(objects ...runtime.Object) *fake.Clientset {
+	clientset := fake.NewSimpleClientset(objects...)
+	filterNamespaceFields(clientset)
+	return clientset
+}
+
+// filterNamespaceFields applies the metadata.namespace terms of list field
+// selectors, which the fake clientset otherwise ignores, so excluded
+// namespaces stay out of cluster-wide lists as on a real API server
+func filterNamespaceFields(clientset *fake.Clientset) {
+	list := k8stesting.ObjectReaction(clientset.Tracker())
+	clientset.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.ListAction).GetListRestrictions().Fields
+		if selector == nil || selector.Empty() {
+			return false, nil, nil
+		}
+
+		handled, object, err := list(action)
+		if !handled || err != nil {
+			return handled, object, err
+		}
+		items, err := meta.ExtractList(object)
+		if err != nil {
+			return true, nil, err
+		}
+		kept := items[:0]
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				return true, nil, err
+			}
+			if namespaceMatches(selector.Requirements(), accessor.GetNamespace()) {
+				kept = append(kept, item)
+			}
+		}
+		return true, object, meta.SetList(object, kept)
+	})
+}
+
+// namespaceMatches reports whether namespace meets the metadata.namespace
+// requirements of a field selector; other fields are ignored
+func namespaceMatches(requirements []fields.Requirement, namespace string) bool {
+	for _, requirement := range requirements {
+		if requirement.Field != "metadata.namespace" {
+			continue
+		}
+		switch requirement.Operator {
+		case selection.Equals, selection.DoubleEquals:
+			if namespace != requirement.Value {
+				return false
+			}
+		case selection.NotEquals:
+			if namespace == requirement.Value {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,489 @@
+package fake
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	nodev1 "k8s.io/api/node/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// Skipped describes a manifest document that was not loaded, such as a
+// custom resource the built-in scheme does not know
+type Skipped struct {
+	File   string
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// NewClientFromManifests returns a client serving the Kubernetes objects in
+// the YAML or JSON manifests at path, a file or a directory searched
+// recursively, as a cluster would run them once they settled: every
+// Deployment, StatefulSet and DaemonSet gets ready pods, services get
+// endpoints for the pods they select, and every access review is allowed.
+// Documents of unknown kinds are skipped and returned.
+func NewClientFromManifests(path string) (*k8s.Client, []Skipped, error) {
+	objects, skipped, err := LoadManifests(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset := newClientset()
+	for _, object := range settle(objects, time.Now()) {
+		if err := clientset.Tracker().Add(object); err != nil {
+			accessor, _ := meta.Accessor(object)
+			return nil, nil, fmt.Errorf("failed to load %T %s/%s: %w", object, accessor.GetNamespace(), accessor.GetName(), err)
+		}
+	}
+	allowAccessReviews(clientset)
+
+	return k8s.NewClientFromClientset(clientset), skipped, nil
+}
+
+// allowAccessReviews answers every SelfSubjectAccessReview with allowed,
+// so no panel is hidden for lack of RBAC
+func allowAccessReviews(clientset *fake.Clientset) {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+}
+
+// LoadManifests decodes the objects in the manifests at path, a file or a
+// directory searched recursively for .yaml, .yml and .json files. Files may
+// hold several documents, and List documents are expanded to their items.
+func LoadManifests(path string) ([]runtime.Object, []Skipped, error) {
+	var files []string
+	err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				files = append(files, file)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no .yaml, .yml or .json manifests found in %s", path)
+	}
+
+	var objects []runtime.Object
+	var skipped []Skipped
+	for _, file := range files {
+		fileObjects, fileSkipped, err := decodeFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		objects = append(objects, fileObjects...)
+		skipped = append(skipped, fileSkipped...)
+	}
+	return objects, skipped, nil
+}
+
+// decodeFile decodes every document in one manifest file
+func decodeFile(file string) ([]runtime.Object, []Skipped, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var objects []runtime.Object
+	var skipped []Skipped
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		document, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		if strings.TrimSpace(string(document)) == "" {
+			continue
+		}
+
+		var typeMeta struct {
+			metav1.TypeMeta   `json:",inline"`
+			metav1.ObjectMeta `json:"metadata"`
+		}
+		if err := utilyaml.Unmarshal(document, &typeMeta); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if typeMeta.Kind == "" {
+			// Comment-only documents and fragments such as Kustomize patches
+			continue
+		}
+
+		data, err := utilyaml.ToJSON(document)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		object, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+		if err != nil {
+			skipped = append(skipped, Skipped{File: file, Kind: typeMeta.Kind, Name: typeMeta.Name, Reason: err.Error()})
+			continue
+		}
+
+		if list, ok := object.(*corev1.List); ok {
+			for _, item := range list.Items {
+				itemObject, _, err := scheme.Codecs.UniversalDeserializer().Decode(item.Raw, nil, nil)
+				if err != nil {
+					skipped = append(skipped, Skipped{File: file, Kind: "List item", Reason: err.Error()})
+					continue
+				}
+				objects = append(objects, itemObject)
+			}
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects, skipped, nil
+}
+
+// clusterScoped reports whether object is of a kind without a namespace
+func clusterScoped(object runtime.Object) bool {
+	switch object.(type) {
+	case *corev1.Namespace, *corev1.Node, *corev1.PersistentVolume,
+		*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding,
+		*storagev1.StorageClass, *nodev1.RuntimeClass, *schedulingv1.PriorityClass:
+		return true
+	}
+	return false
+}
+
+// settle fills in what the control plane would have added to objects by
+// now: namespaces, UIDs and creation times, workload pods and statuses, and
+// service endpoints
+func settle(objects []runtime.Object, now time.Time) []runtime.Object {
+	var nodes []string
+	namespaces := make(map[string]bool)
+	declaredNamespaces := make(map[string]bool)
+
+	for _, object := range objects {
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			continue
+		}
+		if clusterScoped(object) {
+			accessor.SetNamespace("")
+		} else if accessor.GetNamespace() == "" {
+			accessor.SetNamespace(metav1.NamespaceDefault)
+		}
+		stamp(accessor, now)
+
+		switch o := object.(type) {
+		case *corev1.Namespace:
+			declaredNamespaces[o.Name] = true
+		case *corev1.Node:
+			nodes = append(nodes, o.Name)
+			settleNode(o)
+		case *corev1.Service:
+			if o.Spec.Type == "" {
+				o.Spec.Type = corev1.ServiceTypeClusterIP
+			}
+		}
+		if ns := accessor.GetNamespace(); ns != "" {
+			namespaces[ns] = true
+		}
+	}
+
+	settled := append([]runtime.Object{}, objects...)
+	for namespace := range namespaces {
+		if !declaredNamespaces[namespace] {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+			stamp(ns, now)
+			settled = append(settled, ns)
+		}
+	}
+
+	var pods []*corev1.Pod
+	next := 0
+	// schedule spreads pods over the declared nodes, if any
+	schedule := func() string {
+		if len(nodes) == 0 {
+			return ""
+		}
+		node := nodes[next%len(nodes)]
+		next++
+		return node
+	}
+
+	for _, object := range objects {
+		switch o := object.(type) {
+		case *corev1.Pod:
+			if o.Spec.NodeName == "" {
+				o.Spec.NodeName = schedule()
+			}
+			if o.Status.Phase == "" {
+				settlePod(o, now)
+			}
+			pods = append(pods, o)
+
+		case *appsv1.Deployment:
+			replicas := desiredReplicas(o.Spec.Replicas)
+			o.Spec.Replicas = &replicas
+			hash := templateHash(o.Name)
+			replicaSet := &appsv1.ReplicaSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            o.Name + "-" + hash,
+					Namespace:       o.Namespace,
+					Labels:          withLabel(o.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey, hash),
+					Annotations:     map[string]string{"deployment.kubernetes.io/revision": "1"},
+					OwnerReferences: []metav1.OwnerReference{controllerRef(o, "Deployment")},
+				},
+				Spec: appsv1.ReplicaSetSpec{Replicas: &replicas, Selector: o.Spec.Selector, Template: o.Spec.Template},
+				Status: appsv1.ReplicaSetStatus{
+					Replicas: replicas, ReadyReplicas: replicas, AvailableReplicas: replicas, FullyLabeledReplicas: replicas,
+				},
+			}
+			stamp(replicaSet, now)
+			replicaSet.Spec.Template.Labels = replicaSet.Labels
+			settled = append(settled, replicaSet)
+
+			for i := int32(0); i < replicas; i++ {
+				pods = append(pods, templatePod(replicaSet.Name+"-"+utilrand.String(5), o.Namespace, replicaSet.Spec.Template, controllerRef(replicaSet, "ReplicaSet"), schedule(), now))
+			}
+			o.Status = appsv1.DeploymentStatus{
+				ObservedGeneration: o.Generation,
+				Replicas:           replicas, UpdatedReplicas: replicas, ReadyReplicas: replicas, AvailableReplicas: replicas,
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+				},
+			}
+
+		case *appsv1.StatefulSet:
+			replicas := desiredReplicas(o.Spec.Replicas)
+			o.Spec.Replicas = &replicas
+			for i := int32(0); i < replicas; i++ {
+				pods = append(pods, templatePod(fmt.Sprintf("%s-%d", o.Name, i), o.Namespace, o.Spec.Template, controllerRef(o, "StatefulSet"), schedule(), now))
+			}
+			o.Status = appsv1.StatefulSetStatus{
+				ObservedGeneration: o.Generation,
+				Replicas:           replicas, ReadyReplicas: replicas, CurrentReplicas: replicas, UpdatedReplicas: replicas, AvailableReplicas: replicas,
+			}
+
+		case *appsv1.DaemonSet:
+			for _, node := range nodes {
+				pods = append(pods, templatePod(o.Name+"-"+utilrand.String(5), o.Namespace, o.Spec.Template, controllerRef(o, "DaemonSet"), node, now))
+			}
+			count := int32(len(nodes))
+			o.Status = appsv1.DaemonSetStatus{
+				ObservedGeneration:     o.Generation,
+				DesiredNumberScheduled: count, CurrentNumberScheduled: count, NumberReady: count, NumberAvailable: count, UpdatedNumberScheduled: count,
+			}
+		}
+	}
+
+	for i, pod := range pods {
+		if pod.Status.PodIP == "" && pod.Status.Phase == corev1.PodRunning {
+			pod.Status.PodIP = fmt.Sprintf("10.244.%d.%d", i/250, i%250+2)
+			pod.Status.PodIPs = []corev1.PodIP{{IP: pod.Status.PodIP}}
+		}
+		// Declared pods were stamped, and are in settled, already
+		if pod.UID == "" {
+			stamp(pod, now)
+			settled = append(settled, pod)
+		}
+	}
+	for _, object := range objects {
+		if service, ok := object.(*corev1.Service); ok && len(service.Spec.Selector) > 0 {
+			settled = append(settled, endpointSlice(service, pods, now))
+		}
+	}
+
+	return settled
+}
+
+// stamp sets the UID and creation time the API server would have assigned
+func stamp(object metav1.Object, now time.Time) {
+	if object.GetUID() == "" {
+		object.SetUID(uuid.NewUUID())
+	}
+	if created := object.GetCreationTimestamp(); created.IsZero() {
+		object.SetCreationTimestamp(metav1.NewTime(now))
+	}
+}
+
+// desiredReplicas defaults a workload's replicas to 1, as the API server
+// does for manifests that leave them out
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// templateHash derives a stable pod-template-hash from a deployment name
+func templateHash(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// withLabel copies set and adds key=value
+func withLabel(set map[string]string, key, value string) map[string]string {
+	copied := make(map[string]string, len(set)+1)
+	for k, v := range set {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+// controllerRef makes owner the controller of an object it creates
+func controllerRef(owner metav1.Object, kind string) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: owner.GetName(), UID: owner.GetUID(), Controller: &controller}
+}
+
+// templatePod creates a running pod from a workload's pod template
+func templatePod(name, namespace string, template corev1.PodTemplateSpec, owner metav1.OwnerReference, node string, now time.Time) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          template.Labels,
+			Annotations:     template.Annotations,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	pod.Spec.NodeName = node
+	settlePod(pod, now)
+	return pod
+}
+
+// settlePod marks a pod Running with every container started and ready
+func settlePod(pod *corev1.Pod, now time.Time) {
+	started := metav1.NewTime(now)
+	ready := true
+	pod.Status = corev1.PodStatus{
+		Phase:     corev1.PodRunning,
+		StartTime: &started,
+		QOSClass:  qosClass(pod.Spec),
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: started},
+			{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: started},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: started},
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: started},
+		},
+	}
+	for _, container := range pod.Spec.InitContainers {
+		pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, corev1.ContainerStatus{
+			Name:  container.Name,
+			Image: container.Image,
+			Ready: true,
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed", StartedAt: started, FinishedAt: started}},
+		})
+	}
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    container.Name,
+			Image:   container.Image,
+			Ready:   true,
+			Started: &ready,
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: started}},
+		})
+	}
+}
+
+// qosClass approximates the QoS class the API server assigns: Guaranteed
+// when every container's CPU and memory limits equal its requests,
+// BestEffort when none sets any, Burstable otherwise
+func qosClass(spec corev1.PodSpec) corev1.PodQOSClass {
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	guaranteed, bestEffort := true, true
+	for _, container := range containers {
+		resources := container.Resources
+		if len(resources.Requests) > 0 || len(resources.Limits) > 0 {
+			bestEffort = false
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit, hasLimit := resources.Limits[name]
+			request, hasRequest := resources.Requests[name]
+			if !hasLimit || (hasRequest && request.Cmp(limit) != 0) {
+				guaranteed = false
+			}
+		}
+	}
+	switch {
+	case bestEffort:
+		return corev1.PodQOSBestEffort
+	case guaranteed:
+		return corev1.PodQOSGuaranteed
+	}
+	return corev1.PodQOSBurstable
+}
+
+// settleNode marks a node Ready unless its manifest sets conditions
+func settleNode(node *corev1.Node) {
+	if len(node.Status.Conditions) == 0 {
+		node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady"}}
+	}
+}
+
+// endpointSlice lists the ready pods a service selects
+func endpointSlice(service *corev1.Service, pods []*corev1.Pod, now time.Time) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service.Name + "-" + utilrand.String(5),
+			Namespace: service.Namespace,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service.Name},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	stamp(slice, now)
+
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	for _, pod := range pods {
+		if pod.Namespace != service.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		ready := pod.Status.Phase == corev1.PodRunning
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{pod.Status.PodIP},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID},
+		})
+	}
+	return slice
+}
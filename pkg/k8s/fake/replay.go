@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// maxRecordedLine bounds one line of a recording; large objects such as
+// pods with many containers run to tens of kilobytes
+const maxRecordedLine = 16 << 20
+
+// Replay plays a recording made by k8s.Client.RecordEvents back into the
+// in-memory cluster of the client returned with it
+type Replay struct {
+	clientset *fake.Clientset
+	events    []k8s.RecordedEvent
+	speed     float64
+}
+
+// NewReplayClient returns an empty in-memory client and the Replay that
+// fills it from the recording at path. speed scales the recorded pace: 2
+// plays twice as fast, and 0 or less applies every event at once.
+func NewReplayClient(path string, speed float64) (*k8s.Client, *Replay, error) {
+	events, err := readRecording(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clientset := newClientset()
+	allowAccessReviews(clientset)
+	replay := &Replay{clientset: clientset, events: events, speed: speed}
+	return k8s.NewClientFromClientset(clientset), replay, nil
+}
+
+// readRecording parses a newline-delimited recording
+func readRecording(path string) ([]k8s.RecordedEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+
+	var events []k8s.RecordedEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordedLine)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event k8s.RecordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", path, line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("recording %s holds no events", path)
+	}
+	return events, nil
+}
+
+// Duration is the recorded time from the first event to the last
+func (r *Replay) Duration() time.Duration {
+	return r.events[len(r.events)-1].Time.Sub(r.events[0].Time)
+}
+
+// Run applies the recorded events at their recorded pace until the last
+// one or until ctx is done. The cluster keeps its final state afterwards.
+func (r *Replay) Run(ctx context.Context) error {
+	start := time.Now()
+	first := r.events[0].Time
+
+	for i, event := range r.events {
+		if r.speed > 0 {
+			due := start.Add(time.Duration(float64(event.Time.Sub(first)) / r.speed))
+			timer := time.NewTimer(time.Until(due))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := r.apply(event); err != nil {
+			slog.Warn("Failed to replay event", "index", i, "type", event.Type, "error", err)
+		}
+	}
+	return nil
+}
+
+// apply makes one recorded change to the in-memory cluster. Watches that
+// restarted while recording repeat ADDED events, and changes to objects
+// the recording never added still apply, so ADDED and MODIFIED both
+// create or update.
+func (r *Replay) apply(event k8s.RecordedEvent) error {
+	object, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(event.Object, nil, nil)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(*gvk)
+	tracker := r.clientset.Tracker()
+
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		err := tracker.Update(gvr, object, accessor.GetNamespace())
+		if apierrors.IsNotFound(err) {
+			return tracker.Add(object)
+		}
+		return err
+	case watch.Deleted:
+		err := tracker.Delete(gvr, accessor.GetNamespace(), accessor.GetName())
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return fmt.Errorf("unknown event type %q", event.Type)
+}
@@ -0,0 +1,243 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitOps tools reported in GitOpsStatus.Tool
+const (
+	GitOpsArgoCD = "argocd"
+	GitOpsFlux   = "flux"
+)
+
+// Sync states of GitOpsStatus.Sync. Argo CD reports them as is; a Flux
+// Kustomization is Synced once Ready and Failed when its last
+// reconciliation failed.
+const (
+	SyncSynced    = "Synced"
+	SyncOutOfSync = "OutOfSync"
+	SyncFailed    = "Failed"
+	SyncUnknown   = "Unknown"
+)
+
+// Health states of GitOpsStatus.Health, following Argo CD's
+const (
+	GitOpsHealthy     = "Healthy"
+	GitOpsProgressing = "Progressing"
+	GitOpsDegraded    = "Degraded"
+	GitOpsMissing     = "Missing"
+)
+
+// Paths of the GitOps custom resources; each tool is skipped on
+// clusters that do not install it
+const (
+	argoApplicationsPath  = "/apis/argoproj.io/v1alpha1"
+	fluxKustomizationPath = "/apis/kustomize.toolkit.fluxcd.io/v1"
+)
+
+// GitOpsStatus is the sync and health an Argo CD Application or Flux
+// Kustomization reports for a workload it deploys. The zero value means
+// the workload is not managed by either.
+type GitOpsStatus struct {
+	Tool string
+	// Source is the namespace/name of the Application or Kustomization
+	Source  string
+	Sync    string
+	Health  string
+	Message string
+}
+
+// Managed reports whether a GitOps tool deploys the workload
+func (s GitOpsStatus) Managed() bool {
+	return s.Tool != ""
+}
+
+// Drifted reports whether the workload differs from what Git declares,
+// or the last sync of it failed
+func (s GitOpsStatus) Drifted() bool {
+	return s.Sync == SyncOutOfSync || s.Sync == SyncFailed
+}
+
+// NeedsAttention reports whether the workload drifted or its tool
+// considers it degraded or missing
+func (s GitOpsStatus) NeedsAttention() bool {
+	return s.Drifted() || s.Health == GitOpsDegraded || s.Health == GitOpsMissing
+}
+
+// GetGitOpsStatus reads Argo CD Applications and Flux Kustomizations in
+// every namespace, since they usually live apart from what they deploy,
+// and returns the status of each Deployment they manage keyed by its
+// namespace/name. A tool that is not installed, or whose resources may
+// not be listed, is skipped.
+func (c *Client) GetGitOpsStatus(ctx context.Context) (map[string]GitOpsStatus, error) {
+	statuses := make(map[string]GitOpsStatus)
+
+	applications, err := listCustomResources[argoApplication](ctx, c, "", argoApplicationsPath, "applications")
+	if err != nil && !customResourceMissing(err) && !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to list argo cd applications: %w", err)
+	}
+	for _, application := range applications {
+		application.addStatuses(statuses)
+	}
+
+	kustomizations, err := listCustomResources[fluxKustomization](ctx, c, "", fluxKustomizationPath, "kustomizations")
+	if err != nil && !customResourceMissing(err) && !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to list flux kustomizations: %w", err)
+	}
+	for _, kustomization := range kustomizations {
+		kustomization.addStatuses(statuses)
+	}
+
+	return statuses, nil
+}
+
+// ApplyGitOpsStatus sets GitOps on deployments and their pods from
+// statuses as GetGitOpsStatus returns them
+func ApplyGitOpsStatus(deployments []DeploymentInfo, pods []PodInfo, statuses map[string]GitOpsStatus) {
+	if len(statuses) == 0 {
+		return
+	}
+
+	for i := range deployments {
+		deployments[i].GitOps = statuses[deployments[i].Namespace+"/"+deployments[i].Name]
+	}
+	for i := range pods {
+		if pods[i].Deployment != "" {
+			pods[i].GitOps = statuses[pods[i].Namespace+"/"+pods[i].Deployment]
+		}
+	}
+}
+
+// argoApplication mirrors the parts of an argoproj.io Application the
+// sync status needs
+type argoApplication struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Destination struct {
+			Namespace string `json:"namespace"`
+		} `json:"destination"`
+	} `json:"spec"`
+	Status struct {
+		Sync struct {
+			Status string `json:"status"`
+		} `json:"sync"`
+		Health struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"health"`
+		OperationState *struct {
+			Phase   string `json:"phase"`
+			Message string `json:"message"`
+		} `json:"operationState"`
+		Resources []struct {
+			Group     string `json:"group"`
+			Kind      string `json:"kind"`
+			Namespace string `json:"namespace"`
+			Name      string `json:"name"`
+			Status    string `json:"status"`
+			Health    *struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			} `json:"health"`
+		} `json:"resources"`
+	} `json:"status"`
+}
+
+// addStatuses records the status of each Deployment the Application
+// manages. A failed sync operation marks all of them Failed, with the
+// operation's message.
+func (a argoApplication) addStatuses(statuses map[string]GitOpsStatus) {
+	operationFailed := false
+	if op := a.Status.OperationState; op != nil {
+		operationFailed = op.Phase == "Failed" || op.Phase == "Error"
+	}
+
+	for _, resource := range a.Status.Resources {
+		if resource.Group != "apps" || resource.Kind != "Deployment" {
+			continue
+		}
+		namespace := resource.Namespace
+		if namespace == "" {
+			namespace = a.Spec.Destination.Namespace
+		}
+
+		status := GitOpsStatus{
+			Tool:    GitOpsArgoCD,
+			Source:  a.Metadata.Namespace + "/" + a.Metadata.Name,
+			Sync:    resource.Status,
+			Health:  a.Status.Health.Status,
+			Message: a.Status.Health.Message,
+		}
+		if resource.Health != nil {
+			status.Health, status.Message = resource.Health.Status, resource.Health.Message
+		}
+		if status.Sync == "" {
+			status.Sync = SyncUnknown
+		}
+		if operationFailed {
+			status.Sync, status.Message = SyncFailed, a.Status.OperationState.Message
+		}
+		statuses[namespace+"/"+resource.Name] = status
+	}
+}
+
+// fluxKustomization mirrors the parts of a kustomize.toolkit.fluxcd.io
+// Kustomization the sync status needs
+type fluxKustomization struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []metav1.Condition `json:"conditions"`
+		Inventory  *struct {
+			Entries []struct {
+				// ID is namespace_name_group_kind
+				ID string `json:"id"`
+			} `json:"entries"`
+		} `json:"inventory"`
+	} `json:"status"`
+}
+
+// addStatuses records the Kustomization's Ready condition for each
+// Deployment in its inventory
+func (k fluxKustomization) addStatuses(statuses map[string]GitOpsStatus) {
+	if k.Status.Inventory == nil {
+		return
+	}
+
+	status := GitOpsStatus{
+		Tool:   GitOpsFlux,
+		Source: k.Metadata.Namespace + "/" + k.Metadata.Name,
+		Sync:   SyncUnknown,
+		Health: GitOpsProgressing,
+	}
+	for _, condition := range k.Status.Conditions {
+		if condition.Type != "Ready" {
+			continue
+		}
+		switch condition.Status {
+		case metav1.ConditionTrue:
+			status.Sync, status.Health = SyncSynced, GitOpsHealthy
+		case metav1.ConditionFalse:
+			status.Sync, status.Health = SyncFailed, GitOpsDegraded
+			status.Message = condition.Message
+		}
+	}
+
+	for _, entry := range k.Status.Inventory.Entries {
+		parts := strings.Split(entry.ID, "_")
+		if len(parts) != 4 || parts[2] != "apps" || parts[3] != "Deployment" {
+			continue
+		}
+		statuses[parts[0]+"/"+parts[1]] = status
+	}
+}
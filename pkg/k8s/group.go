@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod groupings for sectioned output
+const (
+	GroupByNamespace  = "namespace"
+	GroupByNode       = "node"
+	GroupByDeployment = "deployment"
+	GroupByZone       = "zone"
+	GroupByNone       = "none"
+)
+
+// PodGroupings lists every supported grouping
+var PodGroupings = []string{GroupByNamespace, GroupByNode, GroupByDeployment, GroupByZone, GroupByNone}
+
+// Group names for pods without a value for the grouping field
+const (
+	unscheduledGroup  = "(unscheduled)"
+	noDeploymentGroup = "(no deployment)"
+	noZoneGroup       = "(no zone)"
+)
+
+// PodGroup is a section of pods sharing a namespace, node, deployment or
+// zone
+type PodGroup struct {
+	Name string
+	Pods []PodInfo
+}
+
+// ReadyContainers returns the ready and total container counts of the
+// group, leaving out completed and evicted pods
+func (g PodGroup) ReadyContainers() (int, int) {
+	ready, total := 0, 0
+	for _, pod := range g.Pods {
+		podReady, podTotal := pod.Readiness()
+		ready += podReady
+		total += podTotal
+	}
+	return ready, total
+}
+
+// Ungrouped reports whether the group holds the pods without a node,
+// deployment or zone rather than pods sharing one
+func (g PodGroup) Ungrouped() bool {
+	return isUngrouped(g.Name)
+}
+
+// ParsePodGrouping validates a grouping name; empty means no grouping
+func ParsePodGrouping(name string) (string, error) {
+	grouping := strings.ToLower(strings.TrimSpace(name))
+	if grouping == "" {
+		return GroupByNone, nil
+	}
+	for _, supported := range PodGroupings {
+		if grouping == supported {
+			return grouping, nil
+		}
+	}
+	return "", fmt.Errorf("unknown grouping %q (supported: %s)", name, strings.Join(PodGroupings, ", "))
+}
+
+// GroupPods splits pods into groups sorted by name, keeping the pods' order
+// within each group. Pods without a node, deployment or zone are grouped
+// last.
+// GroupByNone returns a single unnamed group.
+func GroupPods(pods []PodInfo, grouping string) []PodGroup {
+	if grouping == GroupByNone {
+		return []PodGroup{{Pods: pods}}
+	}
+
+	index := make(map[string]int)
+	var groups []PodGroup
+	for _, pod := range pods {
+		name := podGroupName(pod, grouping)
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, PodGroup{Name: name})
+		}
+		groups[i].Pods = append(groups[i].Pods, pod)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Name, groups[j].Name
+		if ungroupedA, ungroupedB := isUngrouped(a), isUngrouped(b); ungroupedA != ungroupedB {
+			return ungroupedB
+		}
+		return a < b
+	})
+	return groups
+}
+
+// podGroupName returns the group a pod belongs to. Deployments are scoped by
+// namespace, since names only need to be unique within one.
+func podGroupName(pod PodInfo, grouping string) string {
+	switch grouping {
+	case GroupByNode:
+		if pod.NodeName == "" {
+			return unscheduledGroup
+		}
+		return pod.NodeName
+	case GroupByDeployment:
+		if pod.Deployment == "" {
+			return noDeploymentGroup
+		}
+		return pod.Namespace + "/" + pod.Deployment
+	case GroupByZone:
+		if pod.NodeName == "" {
+			return unscheduledGroup
+		}
+		return zoneGroupName(pod.Zone)
+	default:
+		return pod.Namespace
+	}
+}
+
+// isUngrouped reports whether a group name is a placeholder for pods
+// without a value for the grouping field
+func isUngrouped(name string) bool {
+	return name == unscheduledGroup || name == noDeploymentGroup || name == noZoneGroup
+}
+
+// owningDeployment returns the name of the Deployment managing a pod, or
+// empty. A Deployment names its ReplicaSets "<deployment>-<pod-template-hash>",
+// so the name is recovered without fetching the ReplicaSet.
+func owningDeployment(pod *corev1.Pod) string {
+	hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller || owner.Kind != "ReplicaSet" {
+			continue
+		}
+		if hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return ""
+}
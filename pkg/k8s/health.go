@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"pod-visualizer/pkg/analysis"
+)
+
+// RecentWarningWindow is how far back warning events count against a
+// deployment's health score
+const RecentWarningWindow = time.Hour
+
+// CountDeploymentWarnings counts the Warning events of the last
+// RecentWarningWindow about each deployment, its ReplicaSets and its
+// pods, including repeats, keyed by namespace/name. Pods are matched to
+// their deployment through pods; ReplicaSets by their name, the
+// deployment's followed by the pod template hash.
+func (c *Client) CountDeploymentWarnings(ctx context.Context, namespace string, pods []PodInfo) (map[string]int, error) {
+	selector := fields.OneTermEqualSelector("type", corev1.EventTypeWarning)
+	events, err := c.listEvents(ctx, namespace, metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	podDeployments := make(map[string]string)
+	for _, pod := range pods {
+		if pod.Deployment != "" {
+			podDeployments[pod.Namespace+"/"+pod.Name] = pod.Deployment
+		}
+	}
+
+	since := time.Now().Add(-RecentWarningWindow)
+	counts := make(map[string]int)
+	for _, event := range events {
+		// The fake clientset ignores field selectors
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		lastSeen := event.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = event.EventTime.Time
+		}
+		if lastSeen.Before(since) {
+			continue
+		}
+
+		object := event.InvolvedObject
+		var deployment string
+		switch object.Kind {
+		case "Deployment":
+			deployment = object.Name
+		case "ReplicaSet":
+			if i := strings.LastIndex(object.Name, "-"); i > 0 {
+				deployment = object.Name[:i]
+			}
+		case "Pod":
+			deployment = podDeployments[object.Namespace+"/"+object.Name]
+		}
+		if deployment == "" {
+			continue
+		}
+
+		counts[object.Namespace+"/"+deployment] += max(int(event.Count), 1)
+	}
+	return counts, nil
+}
+
+// ApplyDeploymentHealth sets the restarts of deployments from their
+// pods, their warning counts from warnings as CountDeploymentWarnings
+// returns them, and scores them again with both. warnings may be nil
+// when events could not be listed.
+func ApplyDeploymentHealth(deployments []DeploymentInfo, pods []PodInfo, warnings map[string]int) {
+	restarts := make(map[string]int32)
+	for _, pod := range pods {
+		if pod.Deployment != "" {
+			restarts[pod.Namespace+"/"+pod.Deployment] += pod.Restarts
+		}
+	}
+
+	for i := range deployments {
+		key := deployments[i].Namespace + "/" + deployments[i].Name
+		deployments[i].Restarts = restarts[key]
+		deployments[i].WarningEvents = warnings[key]
+		deployments[i].HealthScore = analysis.HealthScore(deployments[i].health())
+	}
+}
+
+// SortByHealth orders deployments worst health score first, keeping the
+// namespace and name order among equal scores
+func SortByHealth(deployments []DeploymentInfo) {
+	sort.SliceStable(deployments, func(i, j int) bool {
+		return deployments[i].HealthScore < deployments[j].HealthScore
+	})
+}
+
+// health returns what the deployment's health score is computed from
+func (d DeploymentInfo) health() analysis.DeploymentHealth {
+	return analysis.DeploymentHealth{
+		Replicas:      d.Replicas,
+		ReadyReplicas: d.ReadyReplicas,
+		Restarts:      d.Restarts,
+		WarningEvents: d.WarningEvents,
+		RolloutStuck:  d.RolloutStuck,
+	}
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// LastTrafficAnnotation is the deployment annotation an ingress controller
+// or service mesh integration can set to the RFC 3339 time it last routed a
+// request to the deployment, so that low-CPU but still used workloads are
+// not reported as idle
+const LastTrafficAnnotation = "pod-visualizer.io/last-traffic"
+
+// DeploymentActivity is a deployment's current usage summed over its pods
+type DeploymentActivity struct {
+	Namespace string
+	Name      string
+	Replicas  int32
+
+	CPURequestMilli    int64
+	MemoryRequestBytes int64
+	CPUUsageMilli      int64
+	Restarts           int32
+
+	// LastTraffic is parsed from LastTrafficAnnotation; zero when unset
+	LastTraffic time.Time
+}
+
+// GetDeploymentActivity aggregates the usage, requests and restarts of pods
+// onto the deployments selecting them. Pods must already carry live usage
+// from the metrics package.
+func (c *Client) GetDeploymentActivity(ctx context.Context, namespace string, pods []PodInfo) ([]DeploymentActivity, error) {
+	deployments, err := c.listDeployments(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+
+	// PodInfo carries no labels, so selectors are matched against the pod objects
+	podList, err := c.listPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	infoByPod := make(map[string]PodInfo, len(pods))
+	for _, pod := range pods {
+		infoByPod[pod.Namespace+"/"+pod.Name] = pod
+	}
+
+	var activities []DeploymentActivity
+	for _, deployment := range deployments {
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+
+		activity := DeploymentActivity{
+			Namespace: deployment.Namespace,
+			Name:      deployment.Name,
+		}
+		if deployment.Spec.Replicas != nil {
+			activity.Replicas = *deployment.Spec.Replicas
+		}
+		if value := deployment.Annotations[LastTrafficAnnotation]; value != "" {
+			activity.LastTraffic, _ = time.Parse(time.RFC3339, value)
+		}
+
+		for _, pod := range podList {
+			if pod.Namespace != deployment.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			info, ok := infoByPod[pod.Namespace+"/"+pod.Name]
+			if !ok {
+				continue
+			}
+			activity.CPURequestMilli += info.CPURequestMilli
+			activity.MemoryRequestBytes += info.MemoryRequestBytes
+			activity.CPUUsageMilli += info.CPUUsageMilli
+			activity.Restarts += info.Restarts
+		}
+
+		activities = append(activities, activity)
+	}
+
+	sort.Slice(activities, func(i, j int) bool {
+		if activities[i].Namespace != activities[j].Namespace {
+			return activities[i].Namespace < activities[j].Namespace
+		}
+		return activities[i].Name < activities[j].Name
+	})
+
+	return activities, nil
+}
@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+)
+
+// latestTag is the tag a container runtime pulls when an image has none
+const latestTag = "latest"
+
+// ImageInfo aggregates the containers running one image repository across
+// the pods given to ImageInventory
+type ImageInfo struct {
+	Repository string
+	Versions   []ImageVersion
+	Containers int
+	Namespaces []string
+}
+
+// ImageVersion is one tag or digest of a repository and where it runs
+type ImageVersion struct {
+	Version    string
+	Containers int
+	Namespaces []string
+}
+
+// UsesLatest reports whether any container runs the repository's :latest
+// tag, explicitly or by leaving the tag out, so what runs depends on when
+// each node pulled it
+func (i ImageInfo) UsesLatest() bool {
+	for _, version := range i.Versions {
+		if version.Version == latestTag {
+			return true
+		}
+	}
+	return false
+}
+
+// MultipleVersions reports whether more than one tag or digest of the
+// repository is running
+func (i ImageInfo) MultipleVersions() bool {
+	return len(i.Versions) > 1
+}
+
+// ParseImage splits an image reference into its repository and version,
+// the tag (latest when omitted) or, for references pinned only by digest,
+// the digest. Docker Hub references are shortened to their familiar form,
+// so "docker.io/library/nginx:1.25" and "nginx:1.25" count as one image.
+func ParseImage(image string) (string, string) {
+	repository, digest, _ := strings.Cut(image, "@")
+
+	tag := ""
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+
+	repository = strings.TrimPrefix(repository, "docker.io/")
+	repository = strings.TrimPrefix(repository, "index.docker.io/")
+	repository = strings.TrimPrefix(repository, "library/")
+
+	switch {
+	case tag != "":
+		return repository, tag
+	case digest != "":
+		return repository, digest
+	}
+	return repository, latestTag
+}
+
+// ImageInventory groups the pods' container images by repository, sorted
+// by repository with versions in name order
+func ImageInventory(pods []PodInfo) []ImageInfo {
+	type versionKey struct{ repository, version string }
+	versions := make(map[versionKey]*ImageVersion)
+	namespaces := make(map[versionKey]map[string]bool)
+
+	for _, pod := range pods {
+		for _, image := range pod.Images {
+			repository, version := ParseImage(image)
+			key := versionKey{repository, version}
+			if versions[key] == nil {
+				versions[key] = &ImageVersion{Version: version}
+				namespaces[key] = make(map[string]bool)
+			}
+			versions[key].Containers++
+			namespaces[key][pod.Namespace] = true
+		}
+	}
+
+	byRepository := make(map[string]*ImageInfo)
+	repositoryNamespaces := make(map[string]map[string]bool)
+	for key, version := range versions {
+		version.Namespaces = sortedKeys(namespaces[key])
+
+		image := byRepository[key.repository]
+		if image == nil {
+			image = &ImageInfo{Repository: key.repository}
+			byRepository[key.repository] = image
+			repositoryNamespaces[key.repository] = make(map[string]bool)
+		}
+		image.Versions = append(image.Versions, *version)
+		image.Containers += version.Containers
+		for namespace := range namespaces[key] {
+			repositoryNamespaces[key.repository][namespace] = true
+		}
+	}
+
+	images := make([]ImageInfo, 0, len(byRepository))
+	for repository, image := range byRepository {
+		sort.Slice(image.Versions, func(i, j int) bool {
+			return image.Versions[i].Version < image.Versions[j].Version
+		})
+		image.Namespaces = sortedKeys(repositoryNamespaces[repository])
+		images = append(images, *image)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Repository < images[j].Repository
+	})
+
+	return images
+}
+
+// sortedKeys returns a set's members in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
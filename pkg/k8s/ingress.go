@@ -0,0 +1,354 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Route kinds reported in IngressInfo.Kind
+const (
+	RouteKindIngress   = "Ingress"
+	RouteKindHTTPRoute = "HTTPRoute"
+)
+
+// httpRoutesPath lists Gateway API HTTPRoutes; the API is a CRD and is
+// skipped on clusters that do not install it
+const httpRoutesPath = "/apis/gateway.networking.k8s.io/v1"
+
+// errRESTUnavailable is returned when the clientset cannot send raw API
+// requests, as with client-go's fake clientset
+var errRESTUnavailable = errors.New("clientset has no REST client")
+
+// IngressInfo describes an Ingress or HTTPRoute and the chain from each of
+// its host/path rules to a Service and that Service's ready endpoints
+type IngressInfo struct {
+	UID       string
+	Name      string
+	Namespace string
+	Kind      string
+
+	// Class is the IngressClass, or the parent Gateways of an HTTPRoute
+	Class  string
+	Routes []RouteInfo
+}
+
+// RouteInfo is one host/path → Service:port hop
+type RouteInfo struct {
+	Host      string
+	Path      string
+	Service   string
+	Namespace string
+	Port      string
+
+	// ServiceFound is false when the backend Service does not exist
+	ServiceFound      bool
+	ReadyEndpoints    int
+	NotReadyEndpoints int
+}
+
+// RouteHealthy reports whether traffic on a route reaches a ready
+// endpoint: its backend Service exists and has at least one
+func RouteHealthy(serviceFound bool, readyEndpoints int) bool {
+	return serviceFound && readyEndpoints > 0
+}
+
+// Healthy reports whether traffic on this route reaches a ready endpoint
+func (r RouteInfo) Healthy() bool {
+	return RouteHealthy(r.ServiceFound, r.ReadyEndpoints)
+}
+
+// NeedsAttention reports whether any route has no ready backend
+func (i IngressInfo) NeedsAttention() bool {
+	for _, route := range i.Routes {
+		if !route.Healthy() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetIngresses retrieves Ingresses and, where the Gateway API is installed,
+// HTTPRoutes, resolving each rule's backend Service and its endpoint
+// readiness. HTTPRoutes are left out when the Gateway API is not installed
+// or not readable.
+func (c *Client) GetIngresses(ctx context.Context, namespace string) ([]IngressInfo, error) {
+	ingresses, err := c.listIngresses(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %w", err)
+	}
+
+	services, err := c.listServices(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	serviceExists := make(map[string]bool, len(services))
+	for _, service := range services {
+		serviceExists[service.Namespace+"/"+service.Name] = true
+	}
+
+	readinessByService, err := c.endpointReadiness(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolve fills in a route's backend Service and readiness
+	resolve := func(route RouteInfo) RouteInfo {
+		key := route.Namespace + "/" + route.Service
+		route.ServiceFound = serviceExists[key]
+		route.ReadyEndpoints = readinessByService[key].ready
+		route.NotReadyEndpoints = readinessByService[key].notReady
+		return route
+	}
+
+	var ingressInfos []IngressInfo
+	for i := range ingresses {
+		info := newIngressInfo(&ingresses[i])
+		for j, route := range info.Routes {
+			info.Routes[j] = resolve(route)
+		}
+		ingressInfos = append(ingressInfos, info)
+	}
+
+	routes, err := c.listHTTPRoutes(ctx, namespace)
+	if err != nil && !customResourceMissing(err) && !apierrors.IsForbidden(err) {
+		return nil, fmt.Errorf("failed to list httproutes: %w", err)
+	}
+	for _, route := range routes {
+		info := route.info()
+		for j, r := range info.Routes {
+			info.Routes[j] = resolve(r)
+		}
+		ingressInfos = append(ingressInfos, info)
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(ingressInfos, func(i, j int) bool {
+		if ingressInfos[i].Namespace != ingressInfos[j].Namespace {
+			return ingressInfos[i].Namespace < ingressInfos[j].Namespace
+		}
+		if ingressInfos[i].Name != ingressInfos[j].Name {
+			return ingressInfos[i].Name < ingressInfos[j].Name
+		}
+		return ingressInfos[i].Kind < ingressInfos[j].Kind
+	})
+
+	return ingressInfos, nil
+}
+
+// newIngressInfo lists an Ingress's host/path rules; the default backend
+// is reported as host "*" and path "/*"
+func newIngressInfo(ingress *networkingv1.Ingress) IngressInfo {
+	info := IngressInfo{
+		UID:       string(ingress.UID),
+		Name:      ingress.Name,
+		Namespace: ingress.Namespace,
+		Kind:      RouteKindIngress,
+	}
+	if ingress.Spec.IngressClassName != nil {
+		info.Class = *ingress.Spec.IngressClassName
+	}
+
+	addBackend := func(host, path string, backend networkingv1.IngressBackend) {
+		if backend.Service == nil {
+			return
+		}
+		port := backend.Service.Port.Name
+		if port == "" {
+			port = strconv.Itoa(int(backend.Service.Port.Number))
+		}
+		info.Routes = append(info.Routes, RouteInfo{
+			Host:      host,
+			Path:      path,
+			Service:   backend.Service.Name,
+			Namespace: ingress.Namespace,
+			Port:      port,
+		})
+	}
+
+	if ingress.Spec.DefaultBackend != nil {
+		addBackend("*", "/*", *ingress.Spec.DefaultBackend)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			p := path.Path
+			if p == "" {
+				p = "/"
+			}
+			addBackend(host, p, path.Backend)
+		}
+	}
+
+	return info
+}
+
+// httpRoute mirrors the parts of a gateway.networking.k8s.io HTTPRoute the
+// overview needs
+type httpRoute struct {
+	Metadata struct {
+		UID       string `json:"uid"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		ParentRefs []struct {
+			Name string `json:"name"`
+		} `json:"parentRefs"`
+		Hostnames []string `json:"hostnames"`
+		Rules     []struct {
+			Matches []struct {
+				Path *struct {
+					Value string `json:"value"`
+				} `json:"path"`
+			} `json:"matches"`
+			BackendRefs []struct {
+				Group     *string `json:"group"`
+				Kind      *string `json:"kind"`
+				Name      string  `json:"name"`
+				Namespace *string `json:"namespace"`
+				Port      *int32  `json:"port"`
+			} `json:"backendRefs"`
+		} `json:"rules"`
+	} `json:"spec"`
+}
+
+// info lists the route's hostname × path × Service backend combinations.
+// Backends other than core Services are skipped.
+func (r httpRoute) info() IngressInfo {
+	info := IngressInfo{
+		UID:       r.Metadata.UID,
+		Name:      r.Metadata.Name,
+		Namespace: r.Metadata.Namespace,
+		Kind:      RouteKindHTTPRoute,
+	}
+	for i, parent := range r.Spec.ParentRefs {
+		if i > 0 {
+			info.Class += ","
+		}
+		info.Class += parent.Name
+	}
+
+	hosts := r.Spec.Hostnames
+	if len(hosts) == 0 {
+		hosts = []string{"*"}
+	}
+
+	for _, rule := range r.Spec.Rules {
+		// A rule without matches matches every path
+		paths := []string{"/"}
+		if len(rule.Matches) > 0 {
+			paths = paths[:0]
+			for _, match := range rule.Matches {
+				if match.Path != nil && match.Path.Value != "" {
+					paths = append(paths, match.Path.Value)
+				} else {
+					paths = append(paths, "/")
+				}
+			}
+		}
+
+		for _, backend := range rule.BackendRefs {
+			if (backend.Group != nil && *backend.Group != "") || (backend.Kind != nil && *backend.Kind != "Service") {
+				continue
+			}
+			namespace := r.Metadata.Namespace
+			if backend.Namespace != nil {
+				namespace = *backend.Namespace
+			}
+			port := ""
+			if backend.Port != nil {
+				port = strconv.Itoa(int(*backend.Port))
+			}
+
+			for _, host := range hosts {
+				for _, path := range paths {
+					info.Routes = append(info.Routes, RouteInfo{
+						Host:      host,
+						Path:      path,
+						Service:   backend.Name,
+						Namespace: namespace,
+						Port:      port,
+					})
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// listHTTPRoutes lists HTTPRoutes in the selected namespaces (empty for
+// all); the Gateway API has no typed client in client-go
+func (c *Client) listHTTPRoutes(ctx context.Context, namespace string) ([]httpRoute, error) {
+	return listCustomResources[httpRoute](ctx, c, namespace, httpRoutesPath, "httproutes")
+}
+
+// customResourceList mirrors the list of a custom resource
+type customResourceList[T any] struct {
+	Metadata struct {
+		Continue string `json:"continue"`
+	} `json:"metadata"`
+	Items []T `json:"items"`
+}
+
+// listCustomResources lists a custom resource below an API group path
+// such as /apis/gateway.networking.k8s.io/v1 in the selected namespaces
+// (empty for all) page by page through the raw REST client, decoding the
+// items into T
+func listCustomResources[T any](ctx context.Context, c *Client, namespace, groupPath, resource string) ([]T, error) {
+	// Fake clientsets have no REST client to send raw requests with
+	restClient, ok := c.clientset.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return nil, errRESTUnavailable
+	}
+
+	return listNamespaces(c, namespace, metav1.ListOptions{}, func(namespace string, opts metav1.ListOptions) ([]T, string, error) {
+		path := groupPath + "/" + resource
+		if namespace != "" {
+			path = groupPath + "/namespaces/" + namespace + "/" + resource
+		}
+
+		request := restClient.Get().AbsPath(path)
+		if opts.Limit > 0 {
+			request = request.Param("limit", strconv.FormatInt(opts.Limit, 10))
+		}
+		if opts.Continue != "" {
+			request = request.Param("continue", opts.Continue)
+		}
+		if opts.FieldSelector != "" {
+			request = request.Param("fieldSelector", opts.FieldSelector)
+		}
+
+		body, err := request.DoRaw(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		var list customResourceList[T]
+		if err := json.Unmarshal(body, &list); err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Metadata.Continue, nil
+	})
+}
+
+// customResourceMissing reports whether err means the API of a custom
+// resource, such as the Gateway API, is not installed, as opposed to a
+// failed request
+func customResourceMissing(err error) bool {
+	return apierrors.IsNotFound(err) || errors.Is(err, errRESTUnavailable)
+}
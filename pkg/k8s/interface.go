@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodLister lists pods, their details and status changes
+type PodLister interface {
+	GetPods(ctx context.Context, namespace string) ([]PodInfo, error)
+	GetPodsOnNode(ctx context.Context, namespace, nodeName string) ([]PodInfo, error)
+	GetPod(ctx context.Context, namespace, name string) (PodInfo, error)
+	GetPodDetail(ctx context.Context, namespace, name string) (PodDetail, error)
+	WatchPodTransitions(ctx context.Context, namespace, selector string, onChange func(PodTransition)) error
+}
+
+// DeploymentLister lists deployments, their details and activity, and
+// Argo Rollouts
+type DeploymentLister interface {
+	GetDeployments(ctx context.Context, namespace string) ([]DeploymentInfo, error)
+	GetArgoRollouts(ctx context.Context, namespace string) ([]ArgoRolloutInfo, error)
+	GetDeploymentDetail(ctx context.Context, namespace, name string) (DeploymentDetail, error)
+	GetDeploymentActivity(ctx context.Context, namespace string, pods []PodInfo) ([]DeploymentActivity, error)
+}
+
+// HPALister lists HorizontalPodAutoscalers
+type HPALister interface {
+	GetHPAs(ctx context.Context, namespace string) ([]HPAInfo, error)
+}
+
+// PDBLister lists PodDisruptionBudgets with the drains they would block
+type PDBLister interface {
+	GetPDBs(ctx context.Context, namespace string) ([]PDBInfo, error)
+}
+
+// JobLister lists jobs and cronjobs
+type JobLister interface {
+	GetJobs(ctx context.Context, namespace string) ([]JobInfo, error)
+	GetCronJobs(ctx context.Context, namespace string) ([]CronJobInfo, error)
+}
+
+// ServiceLister lists services with their endpoint readiness
+type ServiceLister interface {
+	GetServices(ctx context.Context, namespace string) ([]ServiceInfo, error)
+}
+
+// IngressLister lists Ingresses and HTTPRoutes with their backend readiness
+type IngressLister interface {
+	GetIngresses(ctx context.Context, namespace string) ([]IngressInfo, error)
+}
+
+// PVCLister lists PersistentVolumeClaims and the pods mounting them
+type PVCLister interface {
+	GetPVCs(ctx context.Context, namespace string) ([]PVCInfo, error)
+}
+
+// NodeLister lists nodes
+type NodeLister interface {
+	GetNodes(ctx context.Context) ([]NodeInfo, error)
+}
+
+// NamespaceLister lists namespaces
+type NamespaceLister interface {
+	GetNamespaces(ctx context.Context) ([]NamespaceInfo, error)
+}
+
+// Interface is everything the visualizer asks of a cluster. *Client
+// implements it against a real API server; NewClientFromClientset also
+// accepts a fake clientset for tests.
+type Interface interface {
+	PodLister
+	DeploymentLister
+	HPALister
+	PDBLister
+	JobLister
+	ServiceLister
+	IngressLister
+	PVCLister
+	NodeLister
+	NamespaceLister
+
+	AccessibleKinds(ctx context.Context, namespace string, kinds Kinds) (Kinds, []string, error)
+	CanList(ctx context.Context, namespace, group, resource string) (bool, error)
+	CanI(ctx context.Context, namespace, verb, group, resource, subresource, name string) (bool, error)
+	ExecPod(ctx context.Context, namespace, name string, opts ExecOptions) error
+	PortForwardPod(ctx context.Context, namespace, name string, podPort int, address string) (*PortForward, error)
+	DeletePod(ctx context.Context, namespace, name string) error
+	RestartDeployment(ctx context.Context, namespace, name string, at time.Time) error
+	ScaleDeployment(ctx context.Context, namespace, name string, replicas int32) (int32, error)
+	GetEvents(ctx context.Context, namespace, kind, name string) ([]EventInfo, error)
+	CountWarningEvents(ctx context.Context, namespace string) (map[string]int, error)
+	CountDeploymentWarnings(ctx context.Context, namespace string, pods []PodInfo) (map[string]int, error)
+	GetRuntimeClassPolicies(ctx context.Context) (map[string]string, error)
+	GetWorkloadSpecs(ctx context.Context, namespace, selector string) ([]WorkloadSpec, error)
+	PreviewDeployment(ctx context.Context, deployment *appsv1.Deployment) (PreviewResult, error)
+	CheckWait(ctx context.Context, target WaitTarget) (WaitState, error)
+	AuditReferences(ctx context.Context, namespace string) ([]ReferenceAudit, error)
+	NamespaceFieldSelector(namespace string) string
+	ServerVersion() (string, error)
+	GetGitOpsStatus(ctx context.Context) (map[string]GitOpsStatus, error)
+	GetCustomResources(ctx context.Context, namespace string, types []CustomResourceType) ([]CustomResourceInfo, error)
+	GetOwnershipObjects(ctx context.Context, namespace string, kinds Kinds) (OwnershipObjects, error)
+
+	// GetClientset returns the underlying clientset, for watches and
+	// requests not covered above
+	GetClientset() kubernetes.Interface
+}
+
+// Client implements Interface
+var _ Interface = (*Client)(nil)
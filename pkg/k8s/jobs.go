@@ -0,0 +1,142 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JobInfo contains relevant job information
+type JobInfo struct {
+	UID            string
+	Name           string
+	Namespace      string
+	Owner          string // owning CronJob, if any
+	Completions    int32
+	Succeeded      int32
+	Failed         int32
+	Active         int32
+	Complete       bool
+	JobFailed      bool
+	StartTime      time.Time
+	CompletionTime time.Time
+}
+
+// CronJobInfo contains relevant cronjob information
+type CronJobInfo struct {
+	UID                string
+	Name               string
+	Namespace          string
+	Schedule           string
+	Suspended          bool
+	Active             int
+	LastScheduleTime   time.Time
+	LastSuccessfulTime time.Time
+}
+
+// GetJobs retrieves jobs from the cluster
+func (c *Client) GetJobs(ctx context.Context, namespace string) ([]JobInfo, error) {
+	jobs, err := c.listJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	var jobInfos []JobInfo
+	for _, job := range jobs {
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+
+		jobInfo := JobInfo{
+			UID:         string(job.UID),
+			Name:        job.Name,
+			Namespace:   job.Namespace,
+			Completions: completions,
+			Succeeded:   job.Status.Succeeded,
+			Failed:      job.Status.Failed,
+			Active:      job.Status.Active,
+		}
+
+		for _, owner := range job.OwnerReferences {
+			if owner.Kind == "CronJob" {
+				jobInfo.Owner = owner.Name
+			}
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				jobInfo.Complete = true
+			case batchv1.JobFailed:
+				jobInfo.JobFailed = true
+			}
+		}
+
+		if job.Status.StartTime != nil {
+			jobInfo.StartTime = job.Status.StartTime.Time
+		}
+		if job.Status.CompletionTime != nil {
+			jobInfo.CompletionTime = job.Status.CompletionTime.Time
+		}
+
+		jobInfos = append(jobInfos, jobInfo)
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(jobInfos, func(i, j int) bool {
+		if jobInfos[i].Namespace != jobInfos[j].Namespace {
+			return jobInfos[i].Namespace < jobInfos[j].Namespace
+		}
+		return jobInfos[i].Name < jobInfos[j].Name
+	})
+
+	return jobInfos, nil
+}
+
+// GetCronJobs retrieves cronjobs from the cluster
+func (c *Client) GetCronJobs(ctx context.Context, namespace string) ([]CronJobInfo, error) {
+	cronJobs, err := c.listCronJobs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+
+	var cronJobInfos []CronJobInfo
+	for _, cronJob := range cronJobs {
+		cronJobInfo := CronJobInfo{
+			UID:       string(cronJob.UID),
+			Name:      cronJob.Name,
+			Namespace: cronJob.Namespace,
+			Schedule:  cronJob.Spec.Schedule,
+			Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+			Active:    len(cronJob.Status.Active),
+		}
+
+		if cronJob.Status.LastScheduleTime != nil {
+			cronJobInfo.LastScheduleTime = cronJob.Status.LastScheduleTime.Time
+		}
+		if cronJob.Status.LastSuccessfulTime != nil {
+			cronJobInfo.LastSuccessfulTime = cronJob.Status.LastSuccessfulTime.Time
+		}
+
+		cronJobInfos = append(cronJobInfos, cronJobInfo)
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(cronJobInfos, func(i, j int) bool {
+		if cronJobInfos[i].Namespace != cronJobInfos[j].Namespace {
+			return cronJobInfos[i].Namespace < cronJobInfos[j].Namespace
+		}
+		return cronJobInfos[i].Name < cronJobInfos[j].Name
+	})
+
+	return cronJobInfos, nil
+}
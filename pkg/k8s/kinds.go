@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resource kinds that can be enabled or disabled
+const (
+	KindPods        = "pods"
+	KindDeployments = "deployments"
+	KindHPAs        = "hpas"
+	KindPDBs        = "pdbs"
+	KindJobs        = "jobs"
+	KindCronJobs    = "cronjobs"
+	KindServices    = "services"
+	KindIngresses   = "ingresses"
+	KindPVCs        = "pvcs"
+	KindNodes       = "nodes"
+	KindMetrics     = "metrics"
+)
+
+// AllKinds lists every resource kind the visualizer knows how to fetch
+var AllKinds = []string{KindPods, KindDeployments, KindHPAs, KindPDBs, KindJobs, KindCronJobs, KindServices, KindIngresses, KindPVCs, KindNodes, KindMetrics}
+
+// Kinds is the set of enabled resource kinds
+type Kinds map[string]bool
+
+// Enabled reports whether the given kind is enabled
+func (k Kinds) Enabled(kind string) bool {
+	return k[kind]
+}
+
+// String returns the enabled kinds as a sorted, comma-separated list
+func (k Kinds) String() string {
+	var kinds []string
+	for kind, enabled := range k {
+		if enabled {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	return strings.Join(kinds, ",")
+}
+
+// ParseKinds parses a comma-separated list of resource kinds.
+// An empty spec enables all kinds.
+func ParseKinds(spec string) (Kinds, error) {
+	kinds := make(Kinds)
+
+	if strings.TrimSpace(spec) == "" {
+		for _, kind := range AllKinds {
+			kinds[kind] = true
+		}
+		return kinds, nil
+	}
+
+	known := make(map[string]bool, len(AllKinds))
+	for _, kind := range AllKinds {
+		known[kind] = true
+	}
+
+	for _, kind := range strings.Split(spec, ",") {
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		if kind == "" {
+			continue
+		}
+		if !known[kind] {
+			return nil, fmt.Errorf("unknown resource kind %q (valid: %s)", kind, strings.Join(AllKinds, ","))
+		}
+		kinds[kind] = true
+	}
+
+	return kinds, nil
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// NameFilter matches pod, deployment and rollout names against a pattern:
+// a glob such as web-* or api-?-canary, or a regular expression between
+// slashes such as /^(web|api)-.*-canary$/. Globs match the whole name,
+// regular expressions any part of it. The nil filter matches every name.
+type NameFilter struct {
+	pattern string
+	regexp  *regexp.Regexp
+}
+
+// ParseNameFilter parses a name filter pattern. An empty pattern returns
+// the nil filter.
+func ParseNameFilter(pattern string) (*NameFilter, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid name regexp %s: %w", pattern, err)
+		}
+		return &NameFilter{pattern: pattern, regexp: re}, nil
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid name glob %q: %w", pattern, err)
+	}
+	return &NameFilter{pattern: pattern}, nil
+}
+
+// Matches reports whether name matches the filter
+func (f *NameFilter) Matches(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.regexp != nil {
+		return f.regexp.MatchString(name)
+	}
+	matched, _ := path.Match(f.pattern, name)
+	return matched
+}
+
+// String returns the pattern the filter was parsed from
+func (f *NameFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.pattern
+}
+
+// FilterByName keeps the elements whose name matches filter, preserving
+// order
+func FilterByName[T interface{ GetName() string }](items []T, filter *NameFilter) []T {
+	if filter == nil {
+		return items
+	}
+
+	var matched []T
+	for _, item := range items {
+		if filter.Matches(item.GetName()) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// GetName returns the pod's name
+func (p PodInfo) GetName() string {
+	return p.Name
+}
+
+// GetName returns the deployment's name
+func (d DeploymentInfo) GetName() string {
+	return d.Name
+}
+
+// GetName returns the rollout's name
+func (r ArgoRolloutInfo) GetName() string {
+	return r.Name
+}
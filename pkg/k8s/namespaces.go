@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	"pod-visualizer/pkg/status"
+)
+
+// NamespaceInfo contains a namespace and a summary of its pods
+type NamespaceInfo struct {
+	Name        string
+	Phase       string // Active or Terminating
+	PodCount    int
+	RunningPods int
+	FailingPods int // failed, evicted or crash-looping
+}
+
+// SplitNamespaces returns the namespaces of a selection: one namespace,
+// several separated by commas, or "" for all namespaces, which is returned
+// as the single entry "". The namespaces are sorted, so results gathered
+// from each in turn keep the namespace order of a cluster-wide list.
+func SplitNamespaces(selection string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(selection, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		return []string{""}
+	}
+	sort.Strings(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// InNamespaces reports whether a selection, as taken by SplitNamespaces,
+// includes namespace
+func InNamespaces(selection, namespace string) bool {
+	namespaces := SplitNamespaces(selection)
+	return namespaces[0] == "" || slices.Contains(namespaces, namespace)
+}
+
+// SetExcludedNamespaces leaves namespaces, such as kube-system, out of
+// cluster-wide lists. A selection naming an excluded namespace still
+// lists it.
+func (c *Client) SetExcludedNamespaces(namespaces []string) {
+	c.excludedNamespaces = nil
+	for _, namespace := range namespaces {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			c.excludedNamespaces = append(c.excludedNamespaces, namespace)
+		}
+	}
+}
+
+// NamespaceFieldSelector returns the field selector that keeps excluded
+// namespaces out of a list or watch of namespace, empty unless namespace
+// is "" (all namespaces) and some are excluded. The API server filters
+// them, so they cost nothing to transfer.
+func (c *Client) NamespaceFieldSelector(namespace string) string {
+	if namespace != "" || len(c.excludedNamespaces) == 0 {
+		return ""
+	}
+	selectors := make([]fields.Selector, len(c.excludedNamespaces))
+	for i, excluded := range c.excludedNamespaces {
+		selectors[i] = fields.OneTermNotEqualSelector("metadata.namespace", excluded)
+	}
+	return fields.AndSelectors(selectors...).String()
+}
+
+// withNamespaceFieldSelector adds NamespaceFieldSelector to opts
+func (c *Client) withNamespaceFieldSelector(namespace string, opts metav1.ListOptions) metav1.ListOptions {
+	selector := c.NamespaceFieldSelector(namespace)
+	switch {
+	case selector == "":
+	case opts.FieldSelector == "":
+		opts.FieldSelector = selector
+	default:
+		opts.FieldSelector += "," + selector
+	}
+	return opts
+}
+
+// GetNamespaces retrieves namespaces with pod counts. Excluded namespaces
+// are left out, as their pods are.
+func (c *Client) GetNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	pods, err := c.GetPods(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*NamespaceInfo, len(namespaces.Items))
+	namespaceInfos := make([]NamespaceInfo, 0, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		if slices.Contains(c.excludedNamespaces, namespace.Name) {
+			continue
+		}
+		namespaceInfos = append(namespaceInfos, NamespaceInfo{
+			Name:  namespace.Name,
+			Phase: string(namespace.Status.Phase),
+		})
+	}
+	for i := range namespaceInfos {
+		byName[namespaceInfos[i].Name] = &namespaceInfos[i]
+	}
+
+	for _, pod := range pods {
+		info, ok := byName[pod.Namespace]
+		if !ok {
+			continue
+		}
+		info.PodCount++
+		switch pod.Status {
+		case string(corev1.PodRunning):
+			info.RunningPods++
+		case string(corev1.PodFailed), status.Evicted, status.CrashLoopBackOff:
+			info.FailingPods++
+		}
+	}
+
+	sort.Slice(namespaceInfos, func(i, j int) bool {
+		return namespaceInfos[i].Name < namespaceInfos[j].Name
+	})
+
+	return namespaceInfos, nil
+}
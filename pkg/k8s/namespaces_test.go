@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection string
+		want      []string
+	}{
+		{name: "all namespaces", selection: "", want: []string{""}},
+		{name: "one namespace", selection: "shop", want: []string{"shop"}},
+		{name: "several sorted", selection: "shop,default,billing", want: []string{"billing", "default", "shop"}},
+		{name: "spaces trimmed", selection: " shop , default ", want: []string{"default", "shop"}},
+		{name: "duplicates dropped", selection: "shop,default,shop", want: []string{"default", "shop"}},
+		{name: "empty entries dropped", selection: "shop,,default,", want: []string{"default", "shop"}},
+		{name: "only separators is all namespaces", selection: " , ,", want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitNamespaces(tt.selection); !slices.Equal(got, tt.want) {
+				t.Errorf("SplitNamespaces(%q) = %q, want %q", tt.selection, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInNamespaces(t *testing.T) {
+	tests := []struct {
+		selection string
+		namespace string
+		want      bool
+	}{
+		{selection: "", namespace: "shop", want: true},
+		{selection: "shop", namespace: "shop", want: true},
+		{selection: "shop,default", namespace: "default", want: true},
+		{selection: "shop,default", namespace: "billing", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := InNamespaces(tt.selection, tt.namespace); got != tt.want {
+			t.Errorf("InNamespaces(%q, %q) = %v, want %v", tt.selection, tt.namespace, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OwnershipObjects are the workloads and pods ownership trees are built
+// from. Lists of disabled kinds, and of resources that may not be listed
+// or are not served, are left empty.
+type OwnershipObjects struct {
+	Deployments  []appsv1.Deployment
+	ReplicaSets  []appsv1.ReplicaSet
+	StatefulSets []appsv1.StatefulSet
+	DaemonSets   []appsv1.DaemonSet
+	CronJobs     []batchv1.CronJob
+	Jobs         []batchv1.Job
+	Pods         []corev1.Pod
+}
+
+// GetOwnershipObjects lists, page by page, the objects of the enabled
+// kinds in the selected namespaces (empty for all). Deployments bring
+// their ReplicaSets; StatefulSets and DaemonSets have no kind of their own
+// and are always listed.
+func (c *Client) GetOwnershipObjects(ctx context.Context, namespace string, kinds Kinds) (OwnershipObjects, error) {
+	var (
+		objects OwnershipObjects
+		err     error
+	)
+	opts := metav1.ListOptions{}
+
+	if kinds.Enabled(KindDeployments) {
+		if objects.Deployments, err = skipUnlistable(c.listDeployments(ctx, namespace, opts)); err != nil {
+			return OwnershipObjects{}, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		if objects.ReplicaSets, err = skipUnlistable(c.listReplicaSets(ctx, namespace, opts)); err != nil {
+			return OwnershipObjects{}, fmt.Errorf("failed to list replicasets: %w", err)
+		}
+	}
+	if objects.StatefulSets, err = skipUnlistable(c.listStatefulSets(ctx, namespace, opts)); err != nil {
+		return OwnershipObjects{}, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	if objects.DaemonSets, err = skipUnlistable(c.listDaemonSets(ctx, namespace, opts)); err != nil {
+		return OwnershipObjects{}, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	if kinds.Enabled(KindCronJobs) {
+		if objects.CronJobs, err = skipUnlistable(c.listCronJobs(ctx, namespace, opts)); err != nil {
+			return OwnershipObjects{}, fmt.Errorf("failed to list cronjobs: %w", err)
+		}
+	}
+	if kinds.Enabled(KindJobs) {
+		if objects.Jobs, err = skipUnlistable(c.listJobs(ctx, namespace, opts)); err != nil {
+			return OwnershipObjects{}, fmt.Errorf("failed to list jobs: %w", err)
+		}
+	}
+	if kinds.Enabled(KindPods) {
+		if objects.Pods, err = skipUnlistable(c.listPods(ctx, namespace, opts)); err != nil {
+			return OwnershipObjects{}, fmt.Errorf("failed to list pods: %w", err)
+		}
+	}
+
+	return objects, nil
+}
+
+// skipUnlistable passes a list's result through, except that a resource
+// the identity may not list, or the API server does not serve, is logged
+// and returns nothing instead of an error
+func skipUnlistable[T any](items []T, err error) ([]T, error) {
+	if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+		slog.Debug("Skipping unlistable resource", "error", err)
+		return nil, nil
+	}
+	return items, err
+}
@@ -0,0 +1,246 @@
+package k8s
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultListPageSize is how many objects are requested per list call.
+// Paging keeps each API response small enough to finish well within the
+// request timeout on clusters with tens of thousands of pods.
+const DefaultListPageSize = 500
+
+// SetListPageSize sets how many objects each list call requests
+// (0 = everything in one call)
+func (c *Client) SetListPageSize(size int64) {
+	c.listPageSize = size
+}
+
+// listAll fetches every page of a list, following continue tokens. page
+// lists one page and converts its items, so raw objects of earlier pages can
+// be freed while later ones are fetched. If the continue token expires
+// mid-way, listing starts over in a single unpaginated call, as kubectl does.
+func listAll[T any](pageSize int64, opts metav1.ListOptions, page func(opts metav1.ListOptions) ([]T, string, error)) ([]T, error) {
+	var items []T
+	opts.Limit = pageSize
+	for {
+		pageItems, next, err := page(opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			items, opts.Limit, opts.Continue = nil, 0, ""
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, pageItems...)
+		if next == "" {
+			return items, nil
+		}
+		opts.Continue = next
+	}
+}
+
+// listNamespaces lists every page in each namespace of a selection, see
+// SplitNamespaces, leaving excluded namespaces out of cluster-wide lists.
+// Listing only the selected namespaces keeps the work of the API server,
+// and the responses, proportional to the selection rather than to the
+// cluster.
+func listNamespaces[T any](c *Client, selection string, opts metav1.ListOptions, page func(namespace string, opts metav1.ListOptions) ([]T, string, error)) ([]T, error) {
+	var items []T
+	for _, namespace := range SplitNamespaces(selection) {
+		namespaceItems, err := listAll(c.listPageSize, c.withNamespaceFieldSelector(namespace, opts), func(opts metav1.ListOptions) ([]T, string, error) {
+			return page(namespace, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, namespaceItems...)
+	}
+	return items, nil
+}
+
+// listPods lists pods in the selected namespaces (empty for all) page by page
+func (c *Client) listPods(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Pod, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Pod, string, error) {
+		list, err := c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listDeployments lists deployments in the selected namespaces (empty for all) page by page
+func (c *Client) listDeployments(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]appsv1.Deployment, string, error) {
+		list, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listJobs lists jobs in the selected namespaces (empty for all) page by page
+func (c *Client) listJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.Job, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]batchv1.Job, string, error) {
+		list, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listCronJobs lists cronjobs in the selected namespaces (empty for all) page by page
+func (c *Client) listCronJobs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]batchv1.CronJob, string, error) {
+		list, err := c.clientset.BatchV1().CronJobs(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listServices lists services in the selected namespaces (empty for all) page by page
+func (c *Client) listServices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Service, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Service, string, error) {
+		list, err := c.clientset.CoreV1().Services(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listConfigMaps lists configmaps in the selected namespaces (empty for all) page by page
+func (c *Client) listConfigMaps(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, string, error) {
+		list, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listSecrets lists secrets in the selected namespaces (empty for all) page by page
+func (c *Client) listSecrets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Secret, string, error) {
+		list, err := c.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listPVCs lists persistentvolumeclaims in the selected namespaces (empty for all) page by page
+func (c *Client) listPVCs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.PersistentVolumeClaim, string, error) {
+		list, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listHPAs lists horizontalpodautoscalers in the selected namespaces (empty for all) page by page
+func (c *Client) listHPAs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]autoscalingv2.HorizontalPodAutoscaler, string, error) {
+		list, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listPDBs lists poddisruptionbudgets in the selected namespaces (empty for all) page by page
+func (c *Client) listPDBs(ctx context.Context, namespace string, opts metav1.ListOptions) ([]policyv1.PodDisruptionBudget, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]policyv1.PodDisruptionBudget, string, error) {
+		list, err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listIngresses lists ingresses in the selected namespaces (empty for all) page by page
+func (c *Client) listIngresses(ctx context.Context, namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]networkingv1.Ingress, string, error) {
+		list, err := c.clientset.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listEndpointSlices lists endpointslices in the selected namespaces (empty for all) page by page
+func (c *Client) listEndpointSlices(ctx context.Context, namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]discoveryv1.EndpointSlice, string, error) {
+		list, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listEvents lists events in the selected namespaces (empty for all) page by page
+func (c *Client) listEvents(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Event, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]corev1.Event, string, error) {
+		list, err := c.clientset.CoreV1().Events(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listReplicaSets lists replicasets in the selected namespaces (empty for all) page by page
+func (c *Client) listReplicaSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]appsv1.ReplicaSet, string, error) {
+		list, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listStatefulSets lists statefulsets in the selected namespaces (empty for all) page by page
+func (c *Client) listStatefulSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]appsv1.StatefulSet, string, error) {
+		list, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
+
+// listDaemonSets lists daemonsets in the selected namespaces (empty for all) page by page
+func (c *Client) listDaemonSets(ctx context.Context, namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, error) {
+	return listNamespaces(c, namespace, opts, func(namespace string, opts metav1.ListOptions) ([]appsv1.DaemonSet, string, error) {
+		list, err := c.clientset.AppsV1().DaemonSets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+}
@@ -0,0 +1,206 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestListAll(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name     string
+		pageSize int64
+		// expireAt fails the request with this continue token once, as an
+		// API server does when the token has expired
+		expireAt  string
+		want      []string
+		wantCalls int
+	}{
+		{name: "single page", pageSize: 0, want: items, wantCalls: 1},
+		{name: "exact pages", pageSize: 5, want: items, wantCalls: 1},
+		{name: "several pages", pageSize: 2, want: items, wantCalls: 3},
+		{name: "page per item", pageSize: 1, want: items, wantCalls: 5},
+		{name: "expired continue starts over unpaginated", pageSize: 2, expireAt: "2", want: items, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			expired := false
+			got, err := listAll(tt.pageSize, metav1.ListOptions{}, func(opts metav1.ListOptions) ([]string, string, error) {
+				calls++
+				if tt.expireAt != "" && opts.Continue == tt.expireAt && !expired {
+					expired = true
+					return nil, "", apierrors.NewResourceExpired("continue token expired")
+				}
+				return page(items, opts)
+			})
+			if err != nil {
+				t.Fatalf("listAll() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("listAll() = %v, want %v", got, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("listAll() made %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestListAllError(t *testing.T) {
+	// An expired first page is not retried, there is nothing to start over from
+	_, err := listAll(2, metav1.ListOptions{}, func(opts metav1.ListOptions) ([]string, string, error) {
+		return nil, "", apierrors.NewResourceExpired("expired")
+	})
+	if !apierrors.IsResourceExpired(err) {
+		t.Errorf("listAll() error = %v, want ResourceExpired", err)
+	}
+}
+
+// page returns the items of the page opts asks for, with the continue
+// token of the next, the offset of its first item
+func page(items []string, opts metav1.ListOptions) ([]string, string, error) {
+	start := 0
+	if opts.Continue != "" {
+		n, err := strconv.Atoi(opts.Continue)
+		if err != nil {
+			return nil, "", fmt.Errorf("bad continue token %q", opts.Continue)
+		}
+		start = n
+	}
+	end := len(items)
+	if opts.Limit > 0 {
+		end = min(start+int(opts.Limit), len(items))
+	}
+	next := ""
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+	return items[start:end], next, nil
+}
+
+func TestGetPodsPaged(t *testing.T) {
+	var objects []runtime.Object
+	var want []string
+	for i := 0; i < 7; i++ {
+		name := fmt.Sprintf("web-%d", i)
+		objects = append(objects, testPod("default", name))
+		want = append(want, "default/"+name)
+	}
+
+	tests := []struct {
+		name      string
+		pageSize  int64
+		expireAt  string
+		wantCalls []metav1.ListOptions
+	}{
+		{
+			name:      "unpaginated",
+			pageSize:  0,
+			wantCalls: []metav1.ListOptions{{}},
+		},
+		{
+			name:      "pages of three",
+			pageSize:  3,
+			wantCalls: []metav1.ListOptions{{Limit: 3}, {Limit: 3, Continue: "3"}, {Limit: 3, Continue: "6"}},
+		},
+		{
+			name:      "expired continue starts over unpaginated",
+			pageSize:  3,
+			expireAt:  "6",
+			wantCalls: []metav1.ListOptions{{Limit: 3}, {Limit: 3, Continue: "3"}, {Limit: 3, Continue: "6"}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := &pagedClientset{Clientset: fake.NewSimpleClientset(objects...), expireAt: tt.expireAt}
+			client := NewClientFromClientset(clientset)
+			client.SetListPageSize(tt.pageSize)
+
+			pods, err := client.GetPods(context.Background(), "default")
+			if err != nil {
+				t.Fatalf("GetPods() error = %v", err)
+			}
+			var got []string
+			for _, pod := range pods {
+				got = append(got, pod.Namespace+"/"+pod.Name)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("GetPods() = %v, want %v", got, want)
+			}
+			if !slices.Equal(clientset.calls, tt.wantCalls) {
+				t.Errorf("list calls = %+v, want %+v", clientset.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// pagedClientset pages pod lists by Limit and Continue, which the fake
+// clientset ignores, recording the options of each call. A list
+// continuing from expireAt fails once as expired.
+type pagedClientset struct {
+	*fake.Clientset
+	expireAt string
+	calls    []metav1.ListOptions
+}
+
+func (c *pagedClientset) CoreV1() corev1client.CoreV1Interface {
+	return pagedCoreV1{CoreV1Interface: c.Clientset.CoreV1(), clientset: c}
+}
+
+type pagedCoreV1 struct {
+	corev1client.CoreV1Interface
+	clientset *pagedClientset
+}
+
+func (c pagedCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return pagedPods{PodInterface: c.CoreV1Interface.Pods(namespace), clientset: c.clientset}
+}
+
+type pagedPods struct {
+	corev1client.PodInterface
+	clientset *pagedClientset
+}
+
+func (p pagedPods) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	p.clientset.calls = append(p.clientset.calls, opts)
+	if opts.Continue != "" && opts.Continue == p.clientset.expireAt {
+		p.clientset.expireAt = ""
+		return nil, apierrors.NewResourceExpired("continue token expired")
+	}
+
+	list, err := p.PodInterface.List(ctx, metav1.ListOptions{FieldSelector: opts.FieldSelector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	names := make([]string, len(list.Items))
+	for i, pod := range list.Items {
+		names[i] = pod.Name
+	}
+	pageNames, next, err := page(names, opts)
+	if err != nil {
+		return nil, err
+	}
+	start, _ := strconv.Atoi(opts.Continue)
+	list.Items = list.Items[start : start+len(pageNames)]
+	list.Continue = next
+	return list, nil
+}
@@ -0,0 +1,38 @@
+package k8s
+
+import corev1 "k8s.io/api/core/v1"
+
+// pressureConditions are the node conditions reporting resource pressure
+var pressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// nodePressure returns the pressure conditions a node currently reports
+func nodePressure(node *corev1.Node) []string {
+	var pressure []string
+	for _, conditionType := range pressureConditions {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == conditionType && condition.Status == corev1.ConditionTrue {
+				pressure = append(pressure, string(conditionType))
+			}
+		}
+	}
+	return pressure
+}
+
+// ApplyNodePressure sets NodePressure on pods scheduled onto nodes that
+// report resource pressure, so their symptoms can be attributed to the node
+func ApplyNodePressure(pods []PodInfo, nodes []NodeInfo) {
+	pressureByNode := make(map[string][]string)
+	for _, node := range nodes {
+		if len(node.Pressure) > 0 {
+			pressureByNode[node.Name] = node.Pressure
+		}
+	}
+
+	for i := range pods {
+		pods[i].NodePressure = pressureByNode[pods[i].NodeName]
+	}
+}
@@ -0,0 +1,275 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// PreviewResult describes how a deployment manifest would render if applied
+type PreviewResult struct {
+	Name      string
+	Namespace string
+	Replicas  int32
+
+	// Requests of a single pod and of all replicas together
+	PodCPURequestMilli      int64
+	PodMemoryRequestBytes   int64
+	TotalCPURequestMilli    int64
+	TotalMemoryRequestBytes int64
+
+	Quotas []QuotaCheck
+	Nodes  []NodeFit
+
+	// SchedulableReplicas is how many replicas fit on current free capacity
+	SchedulableReplicas int32
+
+	// Allowed is false when the manifest would exceed a quota or cannot be
+	// fully scheduled; Reasons explains why
+	Allowed bool
+	Reasons []string
+}
+
+// QuotaCheck compares the deployment's requests with one quota's headroom
+type QuotaCheck struct {
+	Quota     string
+	Resource  string
+	Hard      int64
+	Used      int64
+	Requested int64
+	Exceeds   bool
+}
+
+// NodeFit reports how many replicas a node could take, or why it takes none
+type NodeFit struct {
+	Name   string
+	Fits   int32
+	Reason string
+}
+
+// quotaResources are the quota resources a preview checks
+var quotaResources = []corev1.ResourceName{
+	corev1.ResourceRequestsCPU,
+	corev1.ResourceRequestsMemory,
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	corev1.ResourcePods,
+}
+
+// PreviewDeployment reports the replicas and resources a deployment would
+// request, how they compare with the namespace's ResourceQuotas and how
+// many replicas the nodes could currently fit. Nothing is created. Quota
+// usage already includes a deployment's existing pods, so previewing an
+// update to a running deployment is conservative.
+func (c *Client) PreviewDeployment(ctx context.Context, deployment *appsv1.Deployment) (PreviewResult, error) {
+	namespace := deployment.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	podSpec := deployment.Spec.Template.Spec
+	cpuRequest, memoryRequest := int64(0), int64(0)
+	for _, container := range podSpec.Containers {
+		cpuRequest += container.Resources.Requests.Cpu().MilliValue()
+		memoryRequest += container.Resources.Requests.Memory().Value()
+	}
+
+	result := PreviewResult{
+		Name:                    deployment.Name,
+		Namespace:               namespace,
+		Replicas:                replicas,
+		PodCPURequestMilli:      cpuRequest,
+		PodMemoryRequestBytes:   memoryRequest,
+		TotalCPURequestMilli:    cpuRequest * int64(replicas),
+		TotalMemoryRequestBytes: memoryRequest * int64(replicas),
+		Allowed:                 true,
+	}
+
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return PreviewResult{}, fmt.Errorf("failed to list resourcequotas: %w", err)
+	}
+
+	requested := map[corev1.ResourceName]int64{
+		corev1.ResourceRequestsCPU:    result.TotalCPURequestMilli,
+		corev1.ResourceRequestsMemory: result.TotalMemoryRequestBytes,
+		corev1.ResourceCPU:            result.TotalCPURequestMilli,
+		corev1.ResourceMemory:         result.TotalMemoryRequestBytes,
+		corev1.ResourcePods:           int64(replicas),
+	}
+
+	for _, quota := range quotas.Items {
+		for _, resource := range quotaResources {
+			hard, ok := quota.Status.Hard[resource]
+			if !ok {
+				continue
+			}
+			used := quota.Status.Used[resource]
+
+			check := QuotaCheck{
+				Quota:     quota.Name,
+				Resource:  string(resource),
+				Requested: requested[resource],
+			}
+			// CPU is compared in millicores, everything else in whole units
+			if resource == corev1.ResourceRequestsCPU || resource == corev1.ResourceCPU {
+				check.Hard, check.Used = hard.MilliValue(), used.MilliValue()
+			} else {
+				check.Hard, check.Used = hard.Value(), used.Value()
+			}
+			check.Exceeds = check.Used+check.Requested > check.Hard
+
+			if check.Exceeds {
+				result.Allowed = false
+				result.Reasons = append(result.Reasons, fmt.Sprintf("exceeds quota %s on %s", quota.Name, resource))
+			}
+			result.Quotas = append(result.Quotas, check)
+		}
+	}
+
+	result.Nodes, err = c.nodeFits(ctx, podSpec, cpuRequest, memoryRequest)
+	if err != nil {
+		return PreviewResult{}, err
+	}
+
+	capacity := int32(0)
+	for _, node := range result.Nodes {
+		capacity += node.Fits
+	}
+	result.SchedulableReplicas = capacity
+	if capacity > replicas {
+		result.SchedulableReplicas = replicas
+	}
+	if result.SchedulableReplicas < replicas {
+		result.Allowed = false
+		result.Reasons = append(result.Reasons, fmt.Sprintf("only %d of %d replicas fit on current node capacity", result.SchedulableReplicas, replicas))
+	}
+
+	return result, nil
+}
+
+// nodeFits estimates how many pods with the given spec and requests each
+// node could take, from its allocatable capacity minus the requests of the
+// pods already on it. Only nodeSelector and NoSchedule/NoExecute taints are
+// honoured; affinity and topology spread are not simulated.
+func (c *Client) nodeFits(ctx context.Context, podSpec corev1.PodSpec, cpuRequest, memoryRequest int64) ([]NodeFit, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	// Terminated pods no longer hold their requests
+	selector := fields.AndSelectors(
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(corev1.PodFailed)),
+	)
+	pods, err := c.listPods(ctx, "", metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	type usage struct{ cpu, memory, pods int64 }
+	usageByNode := make(map[string]usage)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		info := newPodInfo(pod)
+		u := usageByNode[pod.Spec.NodeName]
+		u.cpu += info.CPURequestMilli
+		u.memory += info.MemoryRequestBytes
+		u.pods++
+		usageByNode[pod.Spec.NodeName] = u
+	}
+
+	var fits []NodeFit
+	for _, node := range nodes.Items {
+		fit := NodeFit{Name: node.Name}
+		fit.Reason = unschedulableReason(node, podSpec)
+
+		if fit.Reason == "" {
+			u := usageByNode[node.Name]
+			free := []int64{node.Status.Allocatable.Pods().Value() - u.pods}
+			if cpuRequest > 0 {
+				free = append(free, (node.Status.Allocatable.Cpu().MilliValue()-u.cpu)/cpuRequest)
+			}
+			if memoryRequest > 0 {
+				free = append(free, (node.Status.Allocatable.Memory().Value()-u.memory)/memoryRequest)
+			}
+
+			count := free[0]
+			for _, f := range free[1:] {
+				if f < count {
+					count = f
+				}
+			}
+			if count > 0 {
+				fit.Fits = int32(count)
+			} else {
+				fit.Reason = "insufficient free capacity"
+			}
+		}
+
+		fits = append(fits, fit)
+	}
+
+	sort.Slice(fits, func(i, j int) bool {
+		return fits[i].Name < fits[j].Name
+	})
+
+	return fits, nil
+}
+
+// unschedulableReason explains why pods with this spec cannot land on the
+// node at all, or returns empty when they can
+func unschedulableReason(node corev1.Node, podSpec corev1.PodSpec) string {
+	if node.Spec.Unschedulable {
+		return "cordoned"
+	}
+
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+			ready = true
+		}
+	}
+	if !ready {
+		return "not ready"
+	}
+
+	for key, value := range podSpec.NodeSelector {
+		if node.Labels[key] != value {
+			return fmt.Sprintf("nodeSelector %s=%s does not match", key, value)
+		}
+	}
+
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+
+		tolerated := false
+		for j := range podSpec.Tolerations {
+			if podSpec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+			}
+		}
+		if !tolerated {
+			return fmt.Sprintf("untolerated taint %s", taint.ToString())
+		}
+	}
+
+	return ""
+}
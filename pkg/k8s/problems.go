@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"pod-visualizer/pkg/status"
+)
+
+// PodNeedsAttention reports whether a pod with this status and container
+// readiness is a problem: any pod not fully ready, except completed ones
+func PodNeedsAttention(podStatus string, readyContainers, containerCount int) bool {
+	if podStatus == status.Completed {
+		return false
+	}
+	return readyContainers < containerCount
+}
+
+// PodReadiness returns the ready and total containers a pod with this
+// status adds to readiness totals. Completed and evicted pods have
+// stopped for good and add none, so that a node's evicted pods, which
+// linger until deleted, don't drag down the share of ready containers.
+func PodReadiness(podStatus string, readyContainers, containerCount int) (int, int) {
+	if podStatus == status.Completed || podStatus == status.Evicted {
+		return 0, 0
+	}
+	return readyContainers, containerCount
+}
+
+// Readiness returns the pod's contribution to readiness totals, see
+// PodReadiness
+func (p PodInfo) Readiness() (int, int) {
+	return PodReadiness(p.Status, p.ReadyContainers, p.ContainerCount)
+}
+
+// HideCompleted drops completed pods, preserving order
+func HideCompleted[T interface{ GetStatus() string }](pods []T) []T {
+	var kept []T
+	for _, pod := range pods {
+		if pod.GetStatus() != status.Completed {
+			kept = append(kept, pod)
+		}
+	}
+	return kept
+}
+
+// GetStatus returns the pod's status
+func (p PodInfo) GetStatus() string {
+	return p.Status
+}
+
+// PodIsPending reports whether a pod with this status has not been
+// scheduled or has not started all its containers yet, including while
+// its init containers run
+func PodIsPending(podStatus string) bool {
+	return podStatus == string(corev1.PodPending) || status.IsInit(podStatus)
+}
+
+// DeploymentNeedsAttention reports whether a deployment is below its
+// desired replica count
+func DeploymentNeedsAttention(readyReplicas, replicas int32) bool {
+	return readyReplicas < replicas
+}
+
+// NeedsAttention reports whether the pod is not fully ready
+func (p PodInfo) NeedsAttention() bool {
+	return PodNeedsAttention(p.Status, p.ReadyContainers, p.ContainerCount)
+}
+
+// NeedsAttention reports whether the deployment is below desired replicas
+func (d DeploymentInfo) NeedsAttention() bool {
+	return DeploymentNeedsAttention(d.ReadyReplicas, d.Replicas)
+}
+
+// NeedsAttention reports whether the node is NotReady or under memory,
+// disk or PID pressure
+func (n NodeInfo) NeedsAttention() bool {
+	return !n.Ready || len(n.Pressure) > 0
+}
+
+// Problems keeps only the elements that need attention, preserving order
+func Problems[T interface{ NeedsAttention() bool }](items []T) []T {
+	var problems []T
+	for _, item := range items {
+		if item.NeedsAttention() {
+			problems = append(problems, item)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,200 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadSpec is the promotable part of a deployment's spec
+type WorkloadSpec struct {
+	Name       string
+	Replicas   int32
+	Containers []ContainerSpec
+}
+
+// ContainerSpec is the promotable part of a container's spec. Env values
+// are deliberately left out: they usually differ between environments and
+// may hold secrets, so only the variable names are compared.
+type ContainerSpec struct {
+	Name          string
+	Image         string
+	CPURequest    string
+	MemoryRequest string
+	CPULimit      string
+	MemoryLimit   string
+	EnvNames      []string
+}
+
+// WorkloadDrift lists the differences found for one workload. Missing is
+// set to the namespace the workload is absent from, if any.
+type WorkloadDrift struct {
+	Name        string
+	Missing     string
+	Differences []string
+}
+
+// GetWorkloadSpecs retrieves deployment specs matching a label selector
+func (c *Client) GetWorkloadSpecs(ctx context.Context, namespace, selector string) ([]WorkloadSpec, error) {
+	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in %s: %v", namespace, err)
+	}
+
+	var specs []WorkloadSpec
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		spec := WorkloadSpec{Name: deployment.Name, Replicas: replicas}
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			spec.Containers = append(spec.Containers, newContainerSpec(container))
+		}
+		specs = append(specs, spec)
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+
+	return specs, nil
+}
+
+// newContainerSpec extracts the compared fields of a container
+func newContainerSpec(container corev1.Container) ContainerSpec {
+	spec := ContainerSpec{
+		Name:          container.Name,
+		Image:         container.Image,
+		CPURequest:    quantityString(container.Resources.Requests, corev1.ResourceCPU),
+		MemoryRequest: quantityString(container.Resources.Requests, corev1.ResourceMemory),
+		CPULimit:      quantityString(container.Resources.Limits, corev1.ResourceCPU),
+		MemoryLimit:   quantityString(container.Resources.Limits, corev1.ResourceMemory),
+	}
+	for _, env := range container.Env {
+		spec.EnvNames = append(spec.EnvNames, env.Name)
+	}
+	sort.Strings(spec.EnvNames)
+	return spec
+}
+
+// quantityString formats a resource quantity, or "none" when unset
+func quantityString(resources corev1.ResourceList, name corev1.ResourceName) string {
+	if quantity, ok := resources[name]; ok {
+		return quantity.String()
+	}
+	return "none"
+}
+
+// DiffWorkloads compares workloads with the same name in two namespaces and
+// returns those that have drifted, in name order
+func DiffWorkloads(fromNamespace string, from []WorkloadSpec, toNamespace string, to []WorkloadSpec) []WorkloadDrift {
+	toSpecs := make(map[string]WorkloadSpec, len(to))
+	for _, spec := range to {
+		toSpecs[spec.Name] = spec
+	}
+
+	var drifts []WorkloadDrift
+	for _, fromSpec := range from {
+		toSpec, ok := toSpecs[fromSpec.Name]
+		delete(toSpecs, fromSpec.Name)
+		if !ok {
+			drifts = append(drifts, WorkloadDrift{Name: fromSpec.Name, Missing: toNamespace})
+			continue
+		}
+
+		if differences := diffWorkload(fromSpec, toSpec); len(differences) > 0 {
+			drifts = append(drifts, WorkloadDrift{Name: fromSpec.Name, Differences: differences})
+		}
+	}
+	for name := range toSpecs {
+		drifts = append(drifts, WorkloadDrift{Name: name, Missing: fromNamespace})
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return drifts[i].Name < drifts[j].Name
+	})
+
+	return drifts
+}
+
+// diffWorkload describes each difference between two specs as "field: from -> to"
+func diffWorkload(from, to WorkloadSpec) []string {
+	var differences []string
+	if from.Replicas != to.Replicas {
+		differences = append(differences, fmt.Sprintf("replicas: %d -> %d", from.Replicas, to.Replicas))
+	}
+
+	toContainers := make(map[string]ContainerSpec, len(to.Containers))
+	for _, container := range to.Containers {
+		toContainers[container.Name] = container
+	}
+
+	for _, fromContainer := range from.Containers {
+		toContainer, ok := toContainers[fromContainer.Name]
+		delete(toContainers, fromContainer.Name)
+		if !ok {
+			differences = append(differences, fmt.Sprintf("container %s: missing in target", fromContainer.Name))
+			continue
+		}
+
+		prefix := "container " + fromContainer.Name + " "
+		fields := []struct{ name, from, to string }{
+			{"image", fromContainer.Image, toContainer.Image},
+			{"cpu request", fromContainer.CPURequest, toContainer.CPURequest},
+			{"memory request", fromContainer.MemoryRequest, toContainer.MemoryRequest},
+			{"cpu limit", fromContainer.CPULimit, toContainer.CPULimit},
+			{"memory limit", fromContainer.MemoryLimit, toContainer.MemoryLimit},
+		}
+		for _, field := range fields {
+			if field.from != field.to {
+				differences = append(differences, fmt.Sprintf("%s%s: %s -> %s", prefix, field.name, field.from, field.to))
+			}
+		}
+
+		added, removed := diffNames(fromContainer.EnvNames, toContainer.EnvNames)
+		if len(added) > 0 {
+			differences = append(differences, fmt.Sprintf("%senv only in target: %s", prefix, strings.Join(added, ", ")))
+		}
+		if len(removed) > 0 {
+			differences = append(differences, fmt.Sprintf("%senv only in source: %s", prefix, strings.Join(removed, ", ")))
+		}
+	}
+
+	var extra []string
+	for name := range toContainers {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		differences = append(differences, fmt.Sprintf("container %s: missing in source", name))
+	}
+
+	return differences
+}
+
+// diffNames returns the names only in to (added) and only in from (removed)
+func diffNames(from, to []string) (added, removed []string) {
+	fromSet := make(map[string]bool, len(from))
+	for _, name := range from {
+		fromSet[name] = true
+	}
+	toSet := make(map[string]bool, len(to))
+	for _, name := range to {
+		toSet[name] = true
+		if !fromSet[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range from {
+		if !toSet[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,85 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// QOSClasses lists the pod QoS classes, from the first to the last the
+// kubelet evicts
+var QOSClasses = []string{
+	string(corev1.PodQOSGuaranteed),
+	string(corev1.PodQOSBurstable),
+	string(corev1.PodQOSBestEffort),
+}
+
+// ParseQOSClasses parses a comma-separated list of QoS classes, matching
+// case-insensitively. An empty spec selects every class, as nil.
+func ParseQOSClasses(spec string) ([]string, error) {
+	var classes []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		i := slices.IndexFunc(QOSClasses, func(class string) bool { return strings.EqualFold(class, name) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown QoS class %q: expected one of %s", name, strings.Join(QOSClasses, ", "))
+		}
+		classes = append(classes, QOSClasses[i])
+	}
+	return classes, nil
+}
+
+// scheduling is implemented by pods that report a QoS and priority class
+type scheduling interface {
+	GetQOSClass() string
+	GetPriorityClass() string
+}
+
+// FilterByQOS keeps the pods in one of the given QoS classes and with one
+// of the given priority classes ("" for pods without one), preserving
+// order. An empty list does not filter.
+func FilterByQOS[T scheduling](pods []T, qosClasses, priorityClasses []string) []T {
+	if len(qosClasses) == 0 && len(priorityClasses) == 0 {
+		return pods
+	}
+
+	var filtered []T
+	for _, pod := range pods {
+		if len(qosClasses) > 0 && !slices.Contains(qosClasses, pod.GetQOSClass()) {
+			continue
+		}
+		if len(priorityClasses) > 0 && !slices.Contains(priorityClasses, pod.GetPriorityClass()) {
+			continue
+		}
+		filtered = append(filtered, pod)
+	}
+	return filtered
+}
+
+// CountQOSClasses counts pods by QoS class, for seeing how much of a
+// cluster runs BestEffort. Pods the API server has not classified yet
+// are left out.
+func CountQOSClasses[T scheduling](pods []T) map[string]int {
+	counts := make(map[string]int, len(QOSClasses))
+	for _, pod := range pods {
+		if class := pod.GetQOSClass(); class != "" {
+			counts[class]++
+		}
+	}
+	return counts
+}
+
+// GetQOSClass returns the pod's QoS class
+func (p PodInfo) GetQOSClass() string {
+	return p.QOSClass
+}
+
+// GetPriorityClass returns the pod's priority class name
+func (p PodInfo) GetPriorityClass() string {
+	return p.PriorityClass
+}
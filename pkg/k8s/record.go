@@ -0,0 +1,167 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// RecordedEvent is one line of a recording made by RecordEvents: a watch
+// event with the full object, including its apiVersion and kind
+type RecordedEvent struct {
+	Time   time.Time       `json:"time"`
+	Type   watch.EventType `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// watchFunc starts a watch on one resource
+type watchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+// recordedResources returns a watch per resource the visualizer shows
+func (c *Client) recordedResources(namespace string) map[string]watchFunc {
+	core, apps := c.clientset.CoreV1(), c.clientset.AppsV1()
+	return map[string]watchFunc{
+		"namespaces":               core.Namespaces().Watch,
+		"nodes":                    core.Nodes().Watch,
+		"pods":                     core.Pods(namespace).Watch,
+		"services":                 core.Services(namespace).Watch,
+		"persistentvolumeclaims":   core.PersistentVolumeClaims(namespace).Watch,
+		"events":                   core.Events(namespace).Watch,
+		"deployments":              apps.Deployments(namespace).Watch,
+		"replicasets":              apps.ReplicaSets(namespace).Watch,
+		"statefulsets":             apps.StatefulSets(namespace).Watch,
+		"daemonsets":               apps.DaemonSets(namespace).Watch,
+		"jobs":                     c.clientset.BatchV1().Jobs(namespace).Watch,
+		"cronjobs":                 c.clientset.BatchV1().CronJobs(namespace).Watch,
+		"horizontalpodautoscalers": c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Watch,
+		"poddisruptionbudgets":     c.clientset.PolicyV1().PodDisruptionBudgets(namespace).Watch,
+		"endpointslices":           c.clientset.DiscoveryV1().EndpointSlices(namespace).Watch,
+		"ingresses":                c.clientset.NetworkingV1().Ingresses(namespace).Watch,
+	}
+}
+
+// RecordEvents writes the watch events of every resource the visualizer
+// shows in namespace (empty for all) to w as newline-delimited
+// RecordedEvents, until ctx is done or a write fails. Each watch starts
+// with an ADDED event per existing object, so a recording holds the
+// initial state followed by every change. Resources the identity may not
+// watch are left out.
+func (c *Client) RecordEvents(ctx context.Context, namespace string, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		encoder  = json.NewEncoder(w)
+		writeErr error
+		wg       sync.WaitGroup
+	)
+	record := func(event watch.Event) {
+		line, err := recordedEvent(event, time.Now())
+		if err != nil {
+			slog.Warn("Failed to record watch event", "error", err)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if writeErr == nil {
+			if writeErr = encoder.Encode(line); writeErr != nil {
+				cancel()
+			}
+		}
+	}
+
+	for resource, start := range c.recordedResources(namespace) {
+		wg.Add(1)
+		go func(resource string, start watchFunc) {
+			defer wg.Done()
+			recordWatch(ctx, resource, start, record)
+		}(resource, start)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write recording: %w", writeErr)
+	}
+	return nil
+}
+
+// recordWatch passes one resource's watch events to record until ctx is
+// done, restarting the watch when it drops
+func recordWatch(ctx context.Context, resource string, start watchFunc, record func(watch.Event)) {
+	for ctx.Err() == nil {
+		watcher, err := start(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsForbidden(err) || apierrors.IsNotFound(err) {
+				slog.Warn("Not recording resource", "resource", resource, "error", err)
+				return
+			}
+			if ctx.Err() == nil {
+				slog.Error("Failed to watch resource for recording", "resource", resource, "error", err)
+			}
+			sleepCtx(ctx, 5*time.Second)
+			continue
+		}
+
+		func() {
+			defer watcher.Stop()
+			for {
+				select {
+				case event, ok := <-watcher.ResultChan():
+					if !ok {
+						return
+					}
+					if event.Type == watch.Added || event.Type == watch.Modified || event.Type == watch.Deleted {
+						record(event)
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		sleepCtx(ctx, time.Second)
+	}
+}
+
+// recordedEvent encodes a watch event's object with its apiVersion and
+// kind, which typed watches leave empty, and without managed fields
+func recordedEvent(event watch.Event, at time.Time) (RecordedEvent, error) {
+	object := event.Object.DeepCopyObject()
+	kinds, _, err := scheme.Scheme.ObjectKinds(object)
+	if err != nil {
+		return RecordedEvent{}, err
+	}
+	object.GetObjectKind().SetGroupVersionKind(kinds[0])
+	if accessor, err := meta.Accessor(object); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return RecordedEvent{}, err
+	}
+	return RecordedEvent{Time: at.UTC(), Type: event.Type, Object: raw}, nil
+}
+
+// sleepCtx waits for d or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
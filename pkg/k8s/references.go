@@ -0,0 +1,219 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of object a pod can reference by name
+const (
+	RefKindConfigMap = "ConfigMap"
+	RefKindSecret    = "Secret"
+)
+
+// ReferenceAudit lists the ConfigMaps and Secrets one pod references that
+// do not exist or lack the referenced key
+type ReferenceAudit struct {
+	Namespace string
+	Pod       string
+	Status    string
+	Missing   []MissingReference
+}
+
+// MissingReference is one unresolved reference. Key is set when the object
+// exists but does not contain the key.
+type MissingReference struct {
+	Kind string
+	Name string
+	Key  string
+
+	// From says where the pod refers to the object, e.g. "volume config"
+	// or "container app env DB_URL"
+	From string
+}
+
+// String describes the reference, e.g. `Secret db (key "password")`
+func (r MissingReference) String() string {
+	if r.Key != "" {
+		return fmt.Sprintf("%s %s (key %q) from %s", r.Kind, r.Name, r.Key, r.From)
+	}
+	return fmt.Sprintf("%s %s from %s", r.Kind, r.Name, r.From)
+}
+
+// objectRef is a reference found in a pod spec, before it is resolved
+type objectRef struct {
+	MissingReference
+	optional bool
+}
+
+// AuditReferences cross-references the ConfigMaps and Secrets named in pod
+// specs (volumes, projected volumes, env, envFrom and imagePullSecrets)
+// against the objects that exist, returning the pods with unresolved
+// required references. These are what leave containers stuck in
+// CreateContainerConfigError. Completed pods are skipped.
+func (c *Client) AuditReferences(ctx context.Context, namespace string) ([]ReferenceAudit, error) {
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	configMaps, err := c.listConfigMaps(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %w", err)
+	}
+	secrets, err := c.listSecrets(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	// keys maps "Kind/namespace/name" to the object's keys
+	keys := make(map[string]map[string]bool)
+	for _, configMap := range configMaps {
+		objectKeys := make(map[string]bool, len(configMap.Data)+len(configMap.BinaryData))
+		for key := range configMap.Data {
+			objectKeys[key] = true
+		}
+		for key := range configMap.BinaryData {
+			objectKeys[key] = true
+		}
+		keys[RefKindConfigMap+"/"+configMap.Namespace+"/"+configMap.Name] = objectKeys
+	}
+	for _, secret := range secrets {
+		objectKeys := make(map[string]bool, len(secret.Data))
+		for key := range secret.Data {
+			objectKeys[key] = true
+		}
+		keys[RefKindSecret+"/"+secret.Namespace+"/"+secret.Name] = objectKeys
+	}
+
+	var audits []ReferenceAudit
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		var missing []MissingReference
+		for _, ref := range podReferences(&pod.Spec) {
+			objectKeys, exists := keys[ref.Kind+"/"+pod.Namespace+"/"+ref.Name]
+			switch {
+			case ref.optional:
+				continue
+			case !exists:
+				ref.Key = ""
+				missing = append(missing, ref.MissingReference)
+			case ref.Key != "" && !objectKeys[ref.Key]:
+				missing = append(missing, ref.MissingReference)
+			}
+		}
+
+		if len(missing) > 0 {
+			audits = append(audits, ReferenceAudit{
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Status:    newPodInfo(pod).Status,
+				Missing:   dedupeReferences(missing),
+			})
+		}
+	}
+
+	// Sort by namespace, then name, so output is stable between calls
+	sort.Slice(audits, func(i, j int) bool {
+		if audits[i].Namespace != audits[j].Namespace {
+			return audits[i].Namespace < audits[j].Namespace
+		}
+		return audits[i].Pod < audits[j].Pod
+	})
+
+	return audits, nil
+}
+
+// podReferences collects every ConfigMap and Secret a pod spec names
+func podReferences(spec *corev1.PodSpec) []objectRef {
+	var refs []objectRef
+	add := func(kind, name, key, from string, optional *bool) {
+		refs = append(refs, objectRef{
+			MissingReference: MissingReference{Kind: kind, Name: name, Key: key, From: from},
+			optional:         optional != nil && *optional,
+		})
+	}
+
+	for _, volume := range spec.Volumes {
+		from := "volume " + volume.Name
+		switch {
+		case volume.ConfigMap != nil:
+			add(RefKindConfigMap, volume.ConfigMap.Name, "", from, volume.ConfigMap.Optional)
+			for _, item := range volume.ConfigMap.Items {
+				add(RefKindConfigMap, volume.ConfigMap.Name, item.Key, from, volume.ConfigMap.Optional)
+			}
+		case volume.Secret != nil:
+			add(RefKindSecret, volume.Secret.SecretName, "", from, volume.Secret.Optional)
+			for _, item := range volume.Secret.Items {
+				add(RefKindSecret, volume.Secret.SecretName, item.Key, from, volume.Secret.Optional)
+			}
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					add(RefKindConfigMap, source.ConfigMap.Name, "", from, source.ConfigMap.Optional)
+					for _, item := range source.ConfigMap.Items {
+						add(RefKindConfigMap, source.ConfigMap.Name, item.Key, from, source.ConfigMap.Optional)
+					}
+				}
+				if source.Secret != nil {
+					add(RefKindSecret, source.Secret.Name, "", from, source.Secret.Optional)
+					for _, item := range source.Secret.Items {
+						add(RefKindSecret, source.Secret.Name, item.Key, from, source.Secret.Optional)
+					}
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		from := "container " + container.Name
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				add(RefKindConfigMap, envFrom.ConfigMapRef.Name, "", from+" envFrom", envFrom.ConfigMapRef.Optional)
+			}
+			if envFrom.SecretRef != nil {
+				add(RefKindSecret, envFrom.SecretRef.Name, "", from+" envFrom", envFrom.SecretRef.Optional)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add(RefKindConfigMap, ref.Name, ref.Key, from+" env "+env.Name, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				add(RefKindSecret, ref.Name, ref.Key, from+" env "+env.Name, ref.Optional)
+			}
+		}
+	}
+
+	for _, pullSecret := range spec.ImagePullSecrets {
+		add(RefKindSecret, pullSecret.Name, "", "imagePullSecrets", nil)
+	}
+
+	return refs
+}
+
+// dedupeReferences drops repeats, such as a missing object referenced
+// both as a whole and by key from the same place
+func dedupeReferences(refs []MissingReference) []MissingReference {
+	seen := make(map[MissingReference]bool, len(refs))
+	var unique []MissingReference
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+	return unique
+}
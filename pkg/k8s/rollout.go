@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"pod-visualizer/pkg/analysis"
+)
+
+// Annotations the deployment controller and kubectl set on ReplicaSets
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// DeploymentDetail contains the full drill-down information for a
+// deployment, including its rollout history
+type DeploymentDetail struct {
+	UID            string
+	Name           string
+	Namespace      string
+	Strategy       string
+	MaxSurge       string
+	MaxUnavailable string
+	Paused         bool
+
+	Replicas            int32
+	UpdatedReplicas     int32
+	ReadyReplicas       int32
+	AvailableReplicas   int32
+	UnavailableReplicas int32
+
+	// Rollout is the current rollout's progress; Revision is the
+	// revision being rolled out
+	Revision int64
+	Rollout  RolloutInfo
+
+	Conditions []ConditionInfo
+	Revisions  []RevisionInfo
+	Events     []EventInfo
+
+	// Spread is how the running pods are placed across nodes and zones
+	// against the template's topology spread constraints and pod
+	// anti-affinity; nil when pods or nodes cannot be listed
+	Spread *analysis.Spread
+}
+
+// RolloutInfo summarizes the progress of the current rollout
+type RolloutInfo struct {
+	Complete bool
+	Stuck    bool // the progress deadline was exceeded
+	Message  string
+}
+
+// RevisionInfo is one entry of a deployment's rollout history, backed by
+// the ReplicaSet created for that revision
+type RevisionInfo struct {
+	Revision      int64
+	ReplicaSet    string
+	Images        []string
+	ChangeCause   string
+	Replicas      int32
+	ReadyReplicas int32
+	Created       time.Time
+}
+
+// GetDeploymentDetail retrieves a deployment with its rollout history
+func (c *Client) GetDeploymentDetail(ctx context.Context, namespace, name string) (DeploymentDetail, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return DeploymentDetail{}, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+	}
+
+	state := deploymentWaitState(deployment)
+	detail := DeploymentDetail{
+		UID:                 string(deployment.UID),
+		Name:                deployment.Name,
+		Namespace:           deployment.Namespace,
+		Strategy:            string(deployment.Spec.Strategy.Type),
+		Paused:              deployment.Spec.Paused,
+		Replicas:            state.Total,
+		UpdatedReplicas:     deployment.Status.UpdatedReplicas,
+		ReadyReplicas:       deployment.Status.ReadyReplicas,
+		AvailableReplicas:   deployment.Status.AvailableReplicas,
+		UnavailableReplicas: deployment.Status.UnavailableReplicas,
+		Rollout: RolloutInfo{
+			Complete: state.Met,
+			Stuck:    state.Failed,
+			Message:  state.Message,
+		},
+	}
+	detail.Revision, _ = strconv.ParseInt(deployment.Annotations[revisionAnnotation], 10, 64)
+
+	if rollingUpdate := deployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+		if rollingUpdate.MaxSurge != nil {
+			detail.MaxSurge = rollingUpdate.MaxSurge.String()
+		}
+		if rollingUpdate.MaxUnavailable != nil {
+			detail.MaxUnavailable = rollingUpdate.MaxUnavailable.String()
+		}
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		detail.Conditions = append(detail.Conditions, ConditionInfo{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return DeploymentDetail{}, fmt.Errorf("invalid selector on deployment %s/%s: %v", namespace, name, err)
+	}
+
+	replicaSets, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return DeploymentDetail{}, fmt.Errorf("failed to list replicasets: %w", err)
+	}
+
+	for _, replicaSet := range replicaSets.Items {
+		owner := metav1.GetControllerOf(&replicaSet)
+		if owner == nil || owner.UID != deployment.UID {
+			continue
+		}
+
+		revision := RevisionInfo{
+			ReplicaSet:    replicaSet.Name,
+			ChangeCause:   replicaSet.Annotations[changeCauseAnnotation],
+			ReadyReplicas: replicaSet.Status.ReadyReplicas,
+			Created:       replicaSet.CreationTimestamp.Time,
+		}
+		revision.Revision, _ = strconv.ParseInt(replicaSet.Annotations[revisionAnnotation], 10, 64)
+		if replicaSet.Spec.Replicas != nil {
+			revision.Replicas = *replicaSet.Spec.Replicas
+		}
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			revision.Images = append(revision.Images, container.Image)
+		}
+
+		detail.Revisions = append(detail.Revisions, revision)
+	}
+
+	// Newest revision first
+	sort.Slice(detail.Revisions, func(i, j int) bool {
+		return detail.Revisions[i].Revision > detail.Revisions[j].Revision
+	})
+
+	// Events are supplementary; a deployment without readable events still has detail
+	detail.Events, _ = c.GetEvents(ctx, namespace, "Deployment", name)
+
+	// So is the spread, which needs the pods and every node
+	if spread, err := c.podSpread(ctx, namespace, selector.String(), &deployment.Spec.Template.Spec); err == nil {
+		detail.Spread = &spread
+	}
+
+	return detail, nil
+}
+
+// podSpread places the running pods matching selector across the nodes,
+// checking spec's placement rules, see analysis.PodSpread
+func (c *Client) podSpread(ctx context.Context, namespace, selector string, spec *corev1.PodSpec) (analysis.Spread, error) {
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return analysis.Spread{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	nodes, err := c.GetNodes(ctx)
+	if err != nil {
+		return analysis.Spread{}, err
+	}
+
+	// Terminating and finished pods are on their way out and no longer
+	// count toward the spread
+	running := pods[:0]
+	for _, pod := range pods {
+		finished := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+		if pod.DeletionTimestamp == nil && !finished {
+			running = append(running, pod)
+		}
+	}
+	return analysis.PodSpread(spec, running, analysisNodes(nodes)), nil
+}
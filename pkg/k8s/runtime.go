@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuntimeClassAnnotation is the namespace annotation declaring which
+// RuntimeClass (e.g. gvisor, kata, wasm) pods in that namespace must use
+const RuntimeClassAnnotation = "pod-visualizer.io/expected-runtime-class"
+
+// GetRuntimeClassPolicies returns the expected RuntimeClass per namespace,
+// for namespaces carrying the RuntimeClassAnnotation
+func (c *Client) GetRuntimeClassPolicies(ctx context.Context) (map[string]string, error) {
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %v", err)
+	}
+
+	policies := make(map[string]string)
+	for _, namespace := range namespaces.Items {
+		if expected := namespace.Annotations[RuntimeClassAnnotation]; expected != "" {
+			policies[namespace.Name] = expected
+		}
+	}
+
+	return policies, nil
+}
+
+// ApplyRuntimeClassPolicies sets ExpectedRuntimeClass on pods whose namespace has a policy
+func ApplyRuntimeClassPolicies(pods []PodInfo, policies map[string]string) {
+	for i := range pods {
+		pods[i].ExpectedRuntimeClass = policies[pods[i].Namespace]
+	}
+}
+
+// RuntimeClassMismatch reports whether the pod runs a different RuntimeClass
+// than its namespace policy expects
+func (p PodInfo) RuntimeClassMismatch() bool {
+	return p.ExpectedRuntimeClass != "" && p.RuntimeClass != p.ExpectedRuntimeClass
+}
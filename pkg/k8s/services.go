@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceInfo contains relevant service information and endpoint readiness
+type ServiceInfo struct {
+	UID               string
+	Name              string
+	Namespace         string
+	Type              string
+	ClusterIP         string
+	HasSelector       bool
+	ReadyEndpoints    int
+	NotReadyEndpoints int
+}
+
+// GetServices retrieves services from the cluster, counting ready and
+// not-ready endpoints from their EndpointSlices
+func (c *Client) GetServices(ctx context.Context, namespace string) ([]ServiceInfo, error) {
+	services, err := c.listServices(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	readinessByService, err := c.endpointReadiness(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var serviceInfos []ServiceInfo
+	for _, service := range services {
+		r := readinessByService[service.Namespace+"/"+service.Name]
+		serviceInfos = append(serviceInfos, ServiceInfo{
+			UID:               string(service.UID),
+			Name:              service.Name,
+			Namespace:         service.Namespace,
+			Type:              string(service.Spec.Type),
+			ClusterIP:         service.Spec.ClusterIP,
+			HasSelector:       len(service.Spec.Selector) > 0 && service.Spec.Type != corev1.ServiceTypeExternalName,
+			ReadyEndpoints:    r.ready,
+			NotReadyEndpoints: r.notReady,
+		})
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(serviceInfos, func(i, j int) bool {
+		if serviceInfos[i].Namespace != serviceInfos[j].Namespace {
+			return serviceInfos[i].Namespace < serviceInfos[j].Namespace
+		}
+		return serviceInfos[i].Name < serviceInfos[j].Name
+	})
+
+	return serviceInfos, nil
+}
+
+// readiness counts a service's ready and not-ready endpoints
+type readiness struct {
+	ready    int
+	notReady int
+}
+
+// endpointReadiness counts endpoints per "namespace/service" from the
+// EndpointSlices in a namespace (empty for all)
+func (c *Client) endpointReadiness(ctx context.Context, namespace string) (map[string]readiness, error) {
+	slices, err := c.listEndpointSlices(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpointslices: %w", err)
+	}
+
+	readinessByService := make(map[string]readiness)
+	for _, slice := range slices {
+		serviceName := slice.Labels[discoveryv1.LabelServiceName]
+		if serviceName == "" {
+			continue
+		}
+
+		key := slice.Namespace + "/" + serviceName
+		r := readinessByService[key]
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition means unknown and is treated as ready, per the API
+			if endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready {
+				r.ready++
+			} else {
+				r.notReady++
+			}
+		}
+		readinessByService[key] = r
+	}
+	return readinessByService, nil
+}
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// KnownSidecars are the names service meshes and agents give the
+// containers they inject, counted as sidecars even when they run as
+// ordinary containers
+var KnownSidecars = []string{
+	"istio-proxy",
+	"linkerd-proxy",
+	"vault-agent",
+	"cloud-sql-proxy",
+	"cloudsql-proxy",
+}
+
+// IsKnownSidecar reports whether a container name is in KnownSidecars
+func IsKnownSidecar(name string) bool {
+	return slices.Contains(KnownSidecars, name)
+}
+
+// isNativeSidecar reports whether an init container is a native sidecar,
+// one that keeps running beside the containers once started
+func isNativeSidecar(container corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// sidecarContainers returns the names of the pod's sidecars: native
+// sidecars and containers in KnownSidecars
+func sidecarContainers(pod *corev1.Pod) map[string]bool {
+	sidecars := make(map[string]bool)
+	for _, container := range pod.Spec.InitContainers {
+		if isNativeSidecar(container) {
+			sidecars[container.Name] = true
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if IsKnownSidecar(container.Name) {
+			sidecars[container.Name] = true
+		}
+	}
+	return sidecars
+}
+
+// SetExcludeSidecars leaves sidecars out of pod readiness, so a pod whose
+// application containers are ready reads as ready while its proxy still
+// starts, or 1/1 rather than 2/2 once it has
+func (c *Client) SetExcludeSidecars(exclude bool) {
+	c.excludeSidecars = exclude
+}
+
+// excludeSidecars takes the pod's sidecars out of its container counts
+func (p *PodInfo) excludeSidecars() {
+	p.ContainerCount -= p.SidecarCount
+	p.ReadyContainers -= p.ReadySidecars
+	p.SidecarsExcluded = true
+}
+
+// AppContainers returns the ready and total containers of the pod other
+// than its sidecars
+func (p PodInfo) AppContainers() (ready, total int) {
+	if p.SidecarsExcluded {
+		return p.ReadyContainers, p.ContainerCount
+	}
+	return p.ReadyContainers - p.ReadySidecars, p.ContainerCount - p.SidecarCount
+}
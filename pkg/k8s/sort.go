@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"pod-visualizer/pkg/status"
+)
+
+// Pod sort orders. Each sorts the most interesting pods first: unhealthy
+// statuses, the fewest ready containers, the most restarts and the
+// youngest pods. Ties fall back to namespace, then name.
+const (
+	SortByName      = "name"
+	SortByNamespace = "namespace"
+	SortByStatus    = "status"
+	SortByReady     = "ready"
+	SortByRestarts  = "restarts"
+	SortByAge       = "age"
+)
+
+// PodSortOrders lists every supported sort order
+var PodSortOrders = []string{SortByName, SortByNamespace, SortByStatus, SortByReady, SortByRestarts, SortByAge}
+
+// PodSortKey holds the fields pods can be ordered by, so pods in any
+// representation can share one ordering
+type PodSortKey struct {
+	Namespace       string
+	Name            string
+	Status          string
+	ContainerCount  int
+	ReadyContainers int
+	Restarts        int32
+	CreatedAt       time.Time
+}
+
+// SortKey returns the pod's sort fields
+func (p PodInfo) SortKey() PodSortKey {
+	return PodSortKey{
+		Namespace:       p.Namespace,
+		Name:            p.Name,
+		Status:          p.Status,
+		ContainerCount:  p.ContainerCount,
+		ReadyContainers: p.ReadyContainers,
+		Restarts:        p.Restarts,
+		CreatedAt:       p.CreatedAt,
+	}
+}
+
+// PodOrder reports whether a sorts before b
+type PodOrder func(a, b PodSortKey) bool
+
+// ParsePodOrder returns the ordering for a sort order name, flipped when
+// reverse is set. An empty name orders by namespace, then name.
+func ParsePodOrder(name string, reverse bool) (PodOrder, error) {
+	var compare func(a, b PodSortKey) int
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", SortByNamespace:
+		compare = func(a, b PodSortKey) int { return 0 }
+	case SortByName:
+		compare = func(a, b PodSortKey) int { return strings.Compare(a.Name, b.Name) }
+	case SortByStatus:
+		compare = func(a, b PodSortKey) int {
+			if ra, rb := statusRank(a.Status), statusRank(b.Status); ra != rb {
+				return ra - rb
+			}
+			return strings.Compare(a.Status, b.Status)
+		}
+	case SortByReady:
+		compare = func(a, b PodSortKey) int {
+			// Compare ready fractions without dividing by a zero count
+			return a.ReadyContainers*max(b.ContainerCount, 1) - b.ReadyContainers*max(a.ContainerCount, 1)
+		}
+	case SortByRestarts:
+		compare = func(a, b PodSortKey) int { return int(b.Restarts) - int(a.Restarts) }
+	case SortByAge:
+		compare = func(a, b PodSortKey) int { return b.CreatedAt.Compare(a.CreatedAt) }
+	default:
+		return nil, fmt.Errorf("unknown sort order %q: expected one of %s", name, strings.Join(PodSortOrders, ", "))
+	}
+
+	return func(a, b PodSortKey) bool {
+		c := compare(a, b)
+		if c == 0 {
+			c = strings.Compare(a.Namespace, b.Namespace)
+		}
+		if c == 0 {
+			c = strings.Compare(a.Name, b.Name)
+		}
+		if reverse {
+			return c > 0
+		}
+		return c < 0
+	}, nil
+}
+
+// SortPods orders pods in place
+func SortPods(pods []PodInfo, order PodOrder) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return order(pods[i].SortKey(), pods[j].SortKey())
+	})
+}
+
+// statusRank groups statuses from broken to finished
+func statusRank(podStatus string) int {
+	switch podStatus {
+	case status.CrashLoopBackOff, string(corev1.PodFailed), status.Evicted:
+		return 0
+	case string(corev1.PodRunning):
+		return 2
+	case status.Completed:
+		return 3
+	default:
+		// Pending, Terminating and anything unrecognised
+		return 1
+	}
+}
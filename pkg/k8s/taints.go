@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"pod-visualizer/pkg/analysis"
+)
+
+// ApplyTaintBlocks sets BlockingTaints on Pending pods not yet bound to a
+// node, naming the taints of nodes that their tolerations do not cover,
+// see analysis.BlockingTaints
+func ApplyTaintBlocks(pods []PodInfo, nodes []NodeInfo) {
+	if len(nodes) == 0 {
+		return
+	}
+
+	candidates := analysisNodes(nodes)
+	for i := range pods {
+		if pods[i].NodeName != "" || pods[i].Phase != string(corev1.PodPending) {
+			continue
+		}
+		pods[i].BlockingTaints = analysis.BlockingTaints(pods[i].Tolerations, pods[i].NodeSelector, candidates)
+	}
+}
+
+// analysisNodes returns the labels and taints of nodes for the analysis
+// package
+func analysisNodes(nodes []NodeInfo) []analysis.Node {
+	converted := make([]analysis.Node, len(nodes))
+	for i, node := range nodes {
+		converted[i] = analysis.Node{Name: node.Name, Labels: node.Labels, Taints: node.Taints}
+	}
+	return converted
+}
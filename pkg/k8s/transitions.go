@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// PodTransition is a change in a pod's display status. Previous is empty
+// for pods created while watching.
+type PodTransition struct {
+	Pod      PodInfo
+	Previous string
+}
+
+// WatchPodTransitions calls onChange whenever a pod's display status (see
+// PodStatus) changes, until ctx is done. Pods matching selector (empty for
+// all) in the selected namespaces (see SplitNamespaces, empty for all) are
+// listed first so that only changes are reported; expired or dropped
+// watches are resumed by listing again. Each namespace is watched on its
+// own, and onChange is never called concurrently.
+func (c *Client) WatchPodTransitions(ctx context.Context, namespace, selector string, onChange func(PodTransition)) error {
+	namespaces := SplitNamespaces(namespace)
+	if len(namespaces) == 1 {
+		return c.watchPodTransitions(ctx, namespaces[0], selector, onChange)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	report := func(transition PodTransition) {
+		mu.Lock()
+		defer mu.Unlock()
+		onChange(transition)
+	}
+	for _, namespace := range namespaces {
+		wg.Add(1)
+		go func(namespace string) {
+			defer wg.Done()
+			err := c.watchPodTransitions(ctx, namespace, selector, report)
+			mu.Lock()
+			defer mu.Unlock()
+			// The first to fail stops the others
+			if err != nil && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		}(namespace)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// watchPodTransitions reports the pod transitions of one namespace (empty
+// for all)
+func (c *Client) watchPodTransitions(ctx context.Context, namespace, selector string, onChange func(PodTransition)) error {
+	var statuses map[string]string
+	report := func(pod *corev1.Pod) {
+		info := c.podInfo(pod)
+		previous, known := statuses[info.UID]
+		statuses[info.UID] = info.Status
+		if !known || previous != info.Status {
+			onChange(PodTransition{Pod: info, Previous: previous})
+		}
+	}
+
+	for ctx.Err() == nil {
+		// Not paged: the watch resumes from the list's resourceVersion
+		list, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: selector,
+			FieldSelector: c.NamespaceFieldSelector(namespace),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("failed to list pods: %w", err)
+		}
+
+		if statuses == nil {
+			statuses = make(map[string]string, len(list.Items))
+			for i := range list.Items {
+				statuses[string(list.Items[i].UID)] = PodStatus(&list.Items[i])
+			}
+		} else {
+			// Catch up on changes missed while the watch was down
+			for i := range list.Items {
+				report(&list.Items[i])
+			}
+		}
+
+		watcher, err := c.clientset.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{
+			LabelSelector:   selector,
+			FieldSelector:   c.NamespaceFieldSelector(namespace),
+			ResourceVersion: list.ResourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return fmt.Errorf("failed to watch pods: %w", err)
+		}
+
+		followPodWatch(ctx, watcher, report, func(pod *corev1.Pod) {
+			delete(statuses, string(pod.UID))
+		})
+		watcher.Stop()
+	}
+
+	return ctx.Err()
+}
+
+// followPodWatch passes watched pods to update or remove until the watch
+// ends, fails (e.g. its resourceVersion expired) or ctx is done
+func followPodWatch(ctx context.Context, watcher watch.Interface, update, remove func(*corev1.Pod)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			pod, isPod := event.Object.(*corev1.Pod)
+			if !isPod {
+				// A watch error, such as an expired resourceVersion
+				return
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				update(pod)
+			case watch.Deleted:
+				remove(pod)
+			}
+		}
+	}
+}
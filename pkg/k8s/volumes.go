@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PVCInfo contains relevant PersistentVolumeClaim information and the pods
+// mounting the claim
+type PVCInfo struct {
+	UID          string
+	Name         string
+	Namespace    string
+	Phase        string
+	StorageClass string
+	VolumeName   string
+	AccessModes  []string
+
+	// Capacity is the bound volume's size, or the requested size while the
+	// claim is not bound yet
+	Capacity string
+
+	// MountedBy lists the names of pods in the claim's namespace that use it
+	MountedBy []string
+	CreatedAt time.Time
+}
+
+// PVCNeedsAttention reports whether a claim in this phase is a problem:
+// Pending claims keep the pods mounting them Pending, and Lost claims have
+// lost their volume
+func PVCNeedsAttention(phase string) bool {
+	return phase == string(corev1.ClaimPending) || phase == string(corev1.ClaimLost)
+}
+
+// NeedsAttention reports whether the claim is Pending or Lost
+func (p PVCInfo) NeedsAttention() bool {
+	return PVCNeedsAttention(p.Phase)
+}
+
+// GetPVCs retrieves PersistentVolumeClaims from the cluster along with the
+// pods that mount each one, directly or through a generic ephemeral volume
+func (c *Client) GetPVCs(ctx context.Context, namespace string) ([]PVCInfo, error) {
+	claims, err := c.listPVCs(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumeclaims: %w", err)
+	}
+
+	pods, err := c.listPods(ctx, namespace, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	mountedBy := make(map[string][]string)
+	for _, pod := range pods {
+		for _, volume := range pod.Spec.Volumes {
+			claimName := ""
+			switch {
+			case volume.PersistentVolumeClaim != nil:
+				claimName = volume.PersistentVolumeClaim.ClaimName
+			case volume.Ephemeral != nil:
+				// Ephemeral volumes are backed by a claim named <pod>-<volume>
+				claimName = pod.Name + "-" + volume.Name
+			default:
+				continue
+			}
+			key := pod.Namespace + "/" + claimName
+			mountedBy[key] = append(mountedBy[key], pod.Name)
+		}
+	}
+
+	var pvcInfos []PVCInfo
+	for _, claim := range claims {
+		capacity, ok := claim.Status.Capacity[corev1.ResourceStorage]
+		if !ok {
+			capacity = claim.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+
+		storageClass := ""
+		if claim.Spec.StorageClassName != nil {
+			storageClass = *claim.Spec.StorageClassName
+		}
+
+		accessModes := make([]string, len(claim.Spec.AccessModes))
+		for i, mode := range claim.Spec.AccessModes {
+			accessModes[i] = string(mode)
+		}
+
+		users := mountedBy[claim.Namespace+"/"+claim.Name]
+		sort.Strings(users)
+
+		pvcInfos = append(pvcInfos, PVCInfo{
+			UID:          string(claim.UID),
+			Name:         claim.Name,
+			Namespace:    claim.Namespace,
+			Phase:        string(claim.Status.Phase),
+			StorageClass: storageClass,
+			VolumeName:   claim.Spec.VolumeName,
+			AccessModes:  accessModes,
+			Capacity:     capacity.String(),
+			MountedBy:    users,
+			CreatedAt:    claim.CreationTimestamp.Time,
+		})
+	}
+
+	// Sort by namespace, then name, so responses are stable between calls
+	sort.Slice(pvcInfos, func(i, j int) bool {
+		if pvcInfos[i].Namespace != pvcInfos[j].Namespace {
+			return pvcInfos[i].Namespace < pvcInfos[j].Namespace
+		}
+		return pvcInfos[i].Name < pvcInfos[j].Name
+	})
+
+	return pvcInfos, nil
+}
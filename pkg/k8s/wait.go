@@ -0,0 +1,214 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Resource kinds accepted by wait
+const (
+	WaitDeployment = "deployment"
+	WaitPod        = "pod"
+	WaitJob        = "job"
+)
+
+// waitAliases maps the resource names kubectl accepts to wait kinds
+var waitAliases = map[string]string{
+	"deployment":  WaitDeployment,
+	"deployments": WaitDeployment,
+	"deploy":      WaitDeployment,
+	"pod":         WaitPod,
+	"pods":        WaitPod,
+	"po":          WaitPod,
+	"job":         WaitJob,
+	"jobs":        WaitJob,
+}
+
+// waitConditions lists the conditions each kind can be waited for; the
+// first entry is the default
+var waitConditions = map[string][]string{
+	WaitDeployment: {"available"},
+	WaitPod:        {"ready"},
+	WaitJob:        {"complete", "failed"},
+}
+
+// WaitTarget identifies a resource and the condition to wait for
+type WaitTarget struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Condition string
+}
+
+// WaitState is a snapshot of a resource's progress towards a condition.
+// Ready and Total count replicas, containers or completions depending on
+// the kind. Failed is set when the condition can no longer be met.
+type WaitState struct {
+	Ready   int32
+	Total   int32
+	Met     bool
+	Failed  bool
+	Message string
+}
+
+// ParseWaitTarget parses a "kind/name" reference and a condition such as
+// "available" or kubectl's "condition=Available". An empty condition
+// selects the kind's default.
+func ParseWaitTarget(ref, namespace, condition string) (WaitTarget, error) {
+	kindName, name, found := strings.Cut(ref, "/")
+	if !found || name == "" {
+		return WaitTarget{}, fmt.Errorf("invalid resource %q, expected kind/name", ref)
+	}
+
+	kind, ok := waitAliases[strings.ToLower(kindName)]
+	if !ok {
+		return WaitTarget{}, fmt.Errorf("unsupported resource kind %q, expected deployment, pod or job", kindName)
+	}
+
+	condition = strings.ToLower(strings.TrimPrefix(condition, "condition="))
+	if condition == "" {
+		condition = waitConditions[kind][0]
+	}
+
+	supported := false
+	for _, c := range waitConditions[kind] {
+		if c == condition {
+			supported = true
+		}
+	}
+	if !supported {
+		return WaitTarget{}, fmt.Errorf("unsupported condition %q for %s, expected one of: %s",
+			condition, kind, strings.Join(waitConditions[kind], ", "))
+	}
+
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return WaitTarget{Kind: kind, Namespace: namespace, Name: name, Condition: condition}, nil
+}
+
+// String returns the target in kind/name form
+func (t WaitTarget) String() string {
+	return fmt.Sprintf("%s/%s", t.Kind, t.Name)
+}
+
+// CheckWait fetches the target and reports its progress towards the condition
+func (c *Client) CheckWait(ctx context.Context, target WaitTarget) (WaitState, error) {
+	switch target.Kind {
+	case WaitDeployment:
+		deployment, err := c.clientset.AppsV1().Deployments(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return WaitState{}, fmt.Errorf("failed to get deployment %s/%s: %v", target.Namespace, target.Name, err)
+		}
+		return deploymentWaitState(deployment), nil
+
+	case WaitPod:
+		pod, err := c.clientset.CoreV1().Pods(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return WaitState{}, fmt.Errorf("failed to get pod %s/%s: %v", target.Namespace, target.Name, err)
+		}
+		return podWaitState(pod), nil
+
+	case WaitJob:
+		job, err := c.clientset.BatchV1().Jobs(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+		if err != nil {
+			return WaitState{}, fmt.Errorf("failed to get job %s/%s: %v", target.Namespace, target.Name, err)
+		}
+		return jobWaitState(job, target.Condition), nil
+	}
+
+	return WaitState{}, fmt.Errorf("unsupported resource kind %q", target.Kind)
+}
+
+// deploymentWaitState reports available replicas against the desired count.
+// The deployment only counts as available once the controller has observed
+// the latest spec, so a rollout that has not started yet is not mistaken
+// for a finished one.
+func deploymentWaitState(deployment *appsv1.Deployment) WaitState {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	state := WaitState{
+		Ready: deployment.Status.AvailableReplicas,
+		Total: replicas,
+	}
+
+	observed := deployment.Status.ObservedGeneration >= deployment.Generation
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			state.Message = condition.Message
+			state.Met = observed && condition.Status == corev1.ConditionTrue &&
+				deployment.Status.UpdatedReplicas == replicas &&
+				deployment.Status.AvailableReplicas >= replicas
+		}
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			state.Failed = true
+			state.Message = condition.Message
+		}
+	}
+
+	return state
+}
+
+// podWaitState reports ready containers against the container count
+func podWaitState(pod *corev1.Pod) WaitState {
+	info := newPodInfo(pod)
+	state := WaitState{
+		Ready:   int32(info.ReadyContainers),
+		Total:   int32(info.ContainerCount),
+		Message: info.Status,
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			state.Met = condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	// Terminal pods never become ready
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		state.Failed = !state.Met
+	}
+
+	return state
+}
+
+// jobWaitState reports succeeded pods against the requested completions
+func jobWaitState(job *batchv1.Job, condition string) WaitState {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+
+	state := WaitState{
+		Ready: job.Status.Succeeded,
+		Total: completions,
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			state.Met = condition == "complete"
+			state.Failed = condition == "failed"
+			state.Message = "complete"
+		case batchv1.JobFailed:
+			state.Met = condition == "failed"
+			state.Failed = condition == "complete"
+			state.Message = c.Message
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ZoneInfo is a topology zone, its nodes and how many pods they run
+type ZoneInfo struct {
+	Name  string
+	Nodes []NodeInfo
+	Pods  int
+}
+
+// WorkloadZones is how a deployment's pods spread over the zones
+type WorkloadZones struct {
+	Namespace  string
+	Name       string
+	Pods       int
+	PodsByZone map[string]int
+
+	// ConcentratedIn is the zone all of the pods run in although the
+	// cluster has several, so losing it takes the workload down; empty
+	// when the pods are spread
+	ConcentratedIn string
+}
+
+// Unzoned reports whether the zone holds the nodes without a zone label
+func (z ZoneInfo) Unzoned() bool {
+	return z.Name == noZoneGroup
+}
+
+// nodeZone returns the zone a node is labeled with, or empty
+func nodeZone(node *corev1.Node) string {
+	if zone := node.Labels[corev1.LabelTopologyZone]; zone != "" {
+		return zone
+	}
+	return node.Labels[corev1.LabelFailureDomainBetaZone]
+}
+
+// ApplyZones sets Zone on pods scheduled onto nodes with a zone label
+func ApplyZones(pods []PodInfo, nodes []NodeInfo) {
+	zoneByNode := make(map[string]string)
+	for _, node := range nodes {
+		zoneByNode[node.Name] = node.Zone
+	}
+
+	for i := range pods {
+		pods[i].Zone = zoneByNode[pods[i].NodeName]
+	}
+}
+
+// GroupZones groups nodes by zone, sorted by name with nodes without a
+// zone last, and counts the pods running in each. It also reports how
+// each deployment's pods spread over the zones, sorted by namespace and
+// name. Pods that finished hold no place in a zone and are left out, as
+// are pods not yet scheduled. pods must have had ApplyZones applied.
+func GroupZones(nodes []NodeInfo, pods []PodInfo) ([]ZoneInfo, []WorkloadZones) {
+	index := make(map[string]int)
+	var zones []ZoneInfo
+	zoneOf := func(name string) int {
+		i, ok := index[name]
+		if !ok {
+			i = len(zones)
+			index[name] = i
+			zones = append(zones, ZoneInfo{Name: name})
+		}
+		return i
+	}
+	for _, node := range nodes {
+		i := zoneOf(zoneGroupName(node.Zone))
+		zones[i].Nodes = append(zones[i].Nodes, node)
+	}
+
+	workloadIndex := make(map[string]int)
+	var workloads []WorkloadZones
+	for _, pod := range pods {
+		if pod.NodeName == "" || pod.Phase == string(corev1.PodSucceeded) || pod.Phase == string(corev1.PodFailed) {
+			continue
+		}
+		zone := zoneGroupName(pod.Zone)
+		zones[zoneOf(zone)].Pods++
+
+		if pod.Deployment == "" {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.Deployment
+		i, ok := workloadIndex[key]
+		if !ok {
+			i = len(workloads)
+			workloadIndex[key] = i
+			workloads = append(workloads, WorkloadZones{Namespace: pod.Namespace, Name: pod.Deployment, PodsByZone: make(map[string]int)})
+		}
+		workloads[i].Pods++
+		workloads[i].PodsByZone[zone]++
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		if unzonedA, unzonedB := zones[i].Unzoned(), zones[j].Unzoned(); unzonedA != unzonedB {
+			return unzonedB
+		}
+		return zones[i].Name < zones[j].Name
+	})
+
+	labeled := 0
+	for _, zone := range zones {
+		if !zone.Unzoned() && len(zone.Nodes) > 0 {
+			labeled++
+		}
+	}
+	for i := range workloads {
+		if labeled < 2 || workloads[i].Pods < 2 || len(workloads[i].PodsByZone) > 1 {
+			continue
+		}
+		for zone := range workloads[i].PodsByZone {
+			if zone != noZoneGroup {
+				workloads[i].ConcentratedIn = zone
+			}
+		}
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		if workloads[i].Namespace != workloads[j].Namespace {
+			return workloads[i].Namespace < workloads[j].Namespace
+		}
+		return workloads[i].Name < workloads[j].Name
+	})
+
+	return zones, workloads
+}
+
+// zoneGroupName returns the zone's name, or the group of nodes and pods
+// without one
+func zoneGroupName(zone string) string {
+	if zone == "" {
+		return noZoneGroup
+	}
+	return zone
+}
@@ -0,0 +1,52 @@
+// Package logging configures the process-wide structured logger
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Log output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup installs a slog handler writing to stderr in format (text or json)
+// at level (debug, info, warn or error) as the default logger. Output of
+// the standard log package is routed through it as well.
+func Setup(format, level string) error {
+	handler, err := NewHandler(os.Stderr, format, level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// NewHandler creates a text or JSON handler filtering below level
+func NewHandler(w io.Writer, format, level string) (slog.Handler, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", level)
+	}
+	options := &slog.HandlerOptions{Level: minLevel}
+
+	switch strings.ToLower(format) {
+	case FormatText:
+		return slog.NewTextHandler(w, options), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, options), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: expected %s or %s", format, FormatText, FormatJSON)
+	}
+}
+
+// Fatal logs msg with args at error level and exits with status 1
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
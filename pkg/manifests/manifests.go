@@ -0,0 +1,294 @@
+// Package manifests generates the Kubernetes objects that install the web
+// dashboard in-cluster, for pod-visualizer-web -print-manifests.
+package manifests
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// Options parameterizes the generated manifests
+type Options struct {
+	Name      string
+	Namespace string
+	Image     string
+	Replicas  int32
+	Port      int
+
+	// Kinds limits the RBAC rules to the resources the server watches
+	Kinds k8s.Kinds
+	// KubeletStats adds nodes/proxy access for kubelet summary queries
+	KubeletStats bool
+	// LeaderElect enables -leader-elect, with a Role for its Lease in
+	// the install namespace
+	LeaderElect bool
+}
+
+// runAsUser matches the non-root user of the container image
+const runAsUser = 1001
+
+// Write renders the Namespace, ServiceAccount, ClusterRole and binding,
+// the leader election Role and binding when enabled, Deployment and
+// Service as a multi-document YAML stream
+func Write(w io.Writer, opts Options) error {
+	objects := []any{
+		namespace(opts),
+		serviceAccount(opts),
+		clusterRole(opts),
+		clusterRoleBinding(opts),
+	}
+	if opts.LeaderElect {
+		objects = append(objects, leaderElectionRole(opts), leaderElectionRoleBinding(opts))
+	}
+	objects = append(objects, deployment(opts), service(opts))
+
+	for _, object := range objects {
+		body, err := yaml.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// labels are set on every object and select the dashboard's pods
+func labels(opts Options) map[string]string {
+	return map[string]string{"app.kubernetes.io/name": opts.Name}
+}
+
+// objectMeta names a namespaced object
+func objectMeta(opts Options) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: opts.Name, Namespace: opts.Namespace, Labels: labels(opts)}
+}
+
+func namespace(opts Options) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Namespace},
+	}
+}
+
+func serviceAccount(opts Options) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: objectMeta(opts),
+	}
+}
+
+// clusterRole grants read access to the watched kinds only. The server
+// lists across all namespaces, so the role is cluster-wide.
+func clusterRole(opts Options) *rbacv1.ClusterRole {
+	read := []string{"get", "list", "watch"}
+	var rules []rbacv1.PolicyRule
+	add := func(group string, verbs []string, resources ...string) {
+		rules = append(rules, rbacv1.PolicyRule{APIGroups: []string{group}, Resources: resources, Verbs: verbs})
+	}
+
+	if opts.Kinds.Enabled(k8s.KindPods) {
+		add("", read, "pods")
+		add("", []string{"list"}, "events")
+	}
+	if opts.Kinds.Enabled(k8s.KindDeployments) {
+		add("apps", read, "deployments")
+		add("apps", []string{"list"}, "replicasets")
+		add("", []string{"list"}, "resourcequotas")
+		// Argo CD Applications and Flux Kustomizations give the sync
+		// status of the deployments they manage, when installed
+		add("argoproj.io", []string{"list"}, "applications")
+		add("kustomize.toolkit.fluxcd.io", []string{"list"}, "kustomizations")
+		// Argo Rollouts are shown beside deployments when installed
+		add("argoproj.io", []string{"list"}, "rollouts")
+	}
+	if opts.Kinds.Enabled(k8s.KindHPAs) {
+		add("autoscaling", read, "horizontalpodautoscalers")
+	}
+	if opts.Kinds.Enabled(k8s.KindPDBs) {
+		add("policy", read, "poddisruptionbudgets")
+	}
+	if opts.Kinds.Enabled(k8s.KindJobs) || opts.Kinds.Enabled(k8s.KindCronJobs) {
+		add("batch", read, "jobs", "cronjobs")
+	}
+	if opts.Kinds.Enabled(k8s.KindServices) {
+		add("", read, "services")
+		add("discovery.k8s.io", read, "endpointslices")
+	}
+	if opts.Kinds.Enabled(k8s.KindIngresses) {
+		add("networking.k8s.io", read, "ingresses")
+		add("gateway.networking.k8s.io", read, "httproutes")
+		if !opts.Kinds.Enabled(k8s.KindServices) {
+			// Backends are resolved through their Services' endpoints
+			add("", []string{"list"}, "services")
+			add("discovery.k8s.io", []string{"list"}, "endpointslices")
+		}
+	}
+	if opts.Kinds.Enabled(k8s.KindPVCs) {
+		add("", read, "persistentvolumeclaims")
+	}
+	if !opts.Kinds.Enabled(k8s.KindPods) && (opts.Kinds.Enabled(k8s.KindPVCs) || opts.Kinds.Enabled(k8s.KindPDBs)) {
+		// Mounting and covered pods are found by listing pods
+		add("", []string{"list"}, "pods")
+	}
+	add("", []string{"get", "list"}, "nodes", "namespaces")
+	// Ownership trees (/api/topology) also show StatefulSets and DaemonSets
+	add("apps", []string{"list"}, "statefulsets", "daemonsets")
+	if opts.Kinds.Enabled(k8s.KindMetrics) {
+		add("metrics.k8s.io", []string{"get", "list"}, "pods", "nodes")
+	}
+	if opts.KubeletStats {
+		add("", []string{"get"}, "nodes/proxy")
+	}
+	// Used at startup to hide panels for resources the account cannot list
+	add("authorization.k8s.io", []string{"create"}, "selfsubjectaccessreviews")
+
+	return &rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Labels: labels(opts)},
+		Rules:      rules,
+	}
+}
+
+func clusterRoleBinding(opts Options) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Labels: labels(opts)},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: opts.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: opts.Name, Namespace: opts.Namespace}},
+	}
+}
+
+// leaderElectionRole lets -leader-elect manage its Lease. It is namespaced
+// so the account cannot touch the Leases of other components.
+func leaderElectionRole(opts Options) *rbacv1.Role {
+	meta := objectMeta(opts)
+	meta.Name += "-leader-election"
+	return &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     []string{"get", "create", "update"},
+		}},
+	}
+}
+
+func leaderElectionRoleBinding(opts Options) *rbacv1.RoleBinding {
+	meta := objectMeta(opts)
+	meta.Name += "-leader-election"
+	return &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+		ObjectMeta: meta,
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: meta.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: opts.Name, Namespace: opts.Namespace}},
+	}
+}
+
+func deployment(opts Options) *appsv1.Deployment {
+	env := []corev1.EnvVar{{Name: "LOG_FORMAT", Value: "json"}}
+	if len(strings.Split(opts.Kinds.String(), ",")) < len(k8s.AllKinds) {
+		env = append(env, corev1.EnvVar{Name: "WATCH_RESOURCES", Value: opts.Kinds.String()})
+	}
+	if opts.KubeletStats {
+		env = append(env, corev1.EnvVar{Name: "KUBELET_STATS", Value: "true"})
+	}
+	if opts.LeaderElect {
+		// The Lease lives in the pod's own namespace, which the Role covers
+		env = append(env,
+			corev1.EnvVar{Name: "LEADER_ELECT", Value: "true"},
+			corev1.EnvVar{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+		)
+	}
+
+	probe := func(path string, initialDelay, period int32) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler:        corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromString("http")}},
+			InitialDelaySeconds: initialDelay,
+			PeriodSeconds:       period,
+		}
+	}
+
+	noEscalation, nonRoot, readOnly := false, true, true
+	user := int64(runAsUser)
+	container := corev1.Container{
+		Name:            opts.Name,
+		Image:           opts.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"./pod-visualizer-web"},
+		Args:            []string{"-port", strconv.Itoa(opts.Port)},
+		Ports:           []corev1.ContainerPort{{Name: "http", ContainerPort: int32(opts.Port), Protocol: corev1.ProtocolTCP}},
+		Env:             env,
+		LivenessProbe:   probe("/health", 30, 30),
+		ReadinessProbe:  probe("/ready", 5, 5),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("200m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &noEscalation,
+			ReadOnlyRootFilesystem:   &readOnly,
+			RunAsNonRoot:             &nonRoot,
+			RunAsUser:                &user,
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		},
+	}
+
+	replicas := opts.Replicas
+	return &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: objectMeta(opts),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels(opts)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels(opts)},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: opts.Name,
+					Containers:         []corev1.Container{container},
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &nonRoot,
+						RunAsUser:    &user,
+						FSGroup:      &user,
+					},
+				},
+			},
+		},
+	}
+}
+
+func service(opts Options) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: objectMeta(opts),
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: labels(opts),
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromString("http"),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
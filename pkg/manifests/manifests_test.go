@@ -0,0 +1,148 @@
+package manifests
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// testOptions returns the -print-manifests defaults for the given kinds
+func testOptions(t *testing.T, kinds string) Options {
+	t.Helper()
+	parsed, err := k8s.ParseKinds(kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Options{Name: "pod-visualizer", Namespace: "pod-visualizer", Image: "pod-visualizer:latest", Replicas: 1, Port: 8080, Kinds: parsed}
+}
+
+// grants reports whether rules allow verb on group/resource
+func grants(rules []rbacv1.PolicyRule, verb, group, resource string) bool {
+	for _, rule := range rules {
+		if slices.Contains(rule.APIGroups, group) && slices.Contains(rule.Resources, resource) && slices.Contains(rule.Verbs, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClusterRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		kinds    string
+		want     []string
+		unwanted []string
+	}{
+		{
+			name: "all kinds",
+			want: []string{
+				"apps/deployments", "apps/replicasets", "/pods", "batch/jobs", "batch/cronjobs",
+				"autoscaling/horizontalpodautoscalers", "argoproj.io/rollouts", "argoproj.io/applications",
+				"kustomize.toolkit.fluxcd.io/kustomizations", "metrics.k8s.io/pods",
+			},
+		},
+		{
+			name:     "pods only",
+			kinds:    "pods",
+			want:     []string{"/pods", "/events"},
+			unwanted: []string{"apps/deployments", "batch/jobs", "metrics.k8s.io/pods"},
+		},
+		{
+			name:     "claims without pods list pods for their mounts",
+			kinds:    "pvcs",
+			want:     []string{"/persistentvolumeclaims", "/pods"},
+			unwanted: []string{"/events"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := clusterRole(testOptions(t, tt.kinds)).Rules
+
+			// Nodes, access reviews and the ownership trees are always needed
+			want := append([]string{"/nodes", "apps/statefulsets", "apps/daemonsets"}, tt.want...)
+			for _, resource := range want {
+				group, name, _ := strings.Cut(resource, "/")
+				if !grants(rules, "list", group, name) {
+					t.Errorf("ClusterRole does not grant list on %s", resource)
+				}
+			}
+			for _, resource := range tt.unwanted {
+				group, name, _ := strings.Cut(resource, "/")
+				if grants(rules, "list", group, name) {
+					t.Errorf("ClusterRole grants list on %s", resource)
+				}
+			}
+			if !grants(rules, "create", "authorization.k8s.io", "selfsubjectaccessreviews") {
+				t.Error("ClusterRole does not grant access reviews")
+			}
+			if grants(rules, "update", "coordination.k8s.io", "leases") {
+				t.Error("ClusterRole grants leases cluster-wide")
+			}
+		})
+	}
+}
+
+func TestWriteLeaderElection(t *testing.T) {
+	tests := []struct {
+		name        string
+		leaderElect bool
+	}{
+		{name: "disabled"},
+		{name: "enabled", leaderElect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := testOptions(t, "")
+			opts.LeaderElect = tt.leaderElect
+
+			var out bytes.Buffer
+			if err := Write(&out, opts); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			yaml := out.String()
+
+			for _, s := range []string{"kind: Role\n", "kind: RoleBinding\n", "name: LEADER_ELECT\n", "fieldPath: metadata.namespace\n"} {
+				if got := strings.Contains(yaml, s); got != tt.leaderElect {
+					t.Errorf("output has %q = %v, want %v", s, got, tt.leaderElect)
+				}
+			}
+		})
+	}
+
+	role := leaderElectionRole(testOptions(t, ""))
+	if role.Namespace != "pod-visualizer" {
+		t.Errorf("Role namespace = %q, want the install namespace", role.Namespace)
+	}
+	if !grants(role.Rules, "update", "coordination.k8s.io", "leases") {
+		t.Error("Role does not grant leases")
+	}
+}
+
+func TestDeploymentEnv(t *testing.T) {
+	opts := testOptions(t, "pods,deployments")
+	opts.KubeletStats = true
+	env := make(map[string]string)
+	for _, e := range deployment(opts).Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if env["WATCH_RESOURCES"] != "deployments,pods" {
+		t.Errorf("WATCH_RESOURCES = %q, want deployments,pods", env["WATCH_RESOURCES"])
+	}
+	if env["KUBELET_STATS"] != "true" {
+		t.Errorf("KUBELET_STATS = %q, want true", env["KUBELET_STATS"])
+	}
+
+	// Watching everything needs no WATCH_RESOURCES
+	for _, e := range deployment(testOptions(t, "")).Spec.Template.Spec.Containers[0].Env {
+		if e.Name == "WATCH_RESOURCES" {
+			t.Errorf("WATCH_RESOURCES = %q with all kinds", e.Value)
+		}
+	}
+}
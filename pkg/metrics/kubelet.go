@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// kubeletSummary mirrors the parts of the kubelet /stats/summary response
+// used for storage usage
+type kubeletSummary struct {
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Rootfs *fsStats `json:"rootfs"`
+		} `json:"containers"`
+		EphemeralStorage *fsStats `json:"ephemeral-storage"`
+	} `json:"pods"`
+}
+
+// fsStats mirrors a kubelet filesystem usage entry
+type fsStats struct {
+	UsedBytes *uint64 `json:"usedBytes"`
+}
+
+// used returns the used bytes, or 0 when the kubelet did not report them
+func (f *fsStats) used() int64 {
+	if f == nil || f.UsedBytes == nil {
+		return 0
+	}
+	return int64(*f.UsedBytes)
+}
+
+// AnnotateStorage fills in ephemeral-storage and container rootfs usage
+// from the kubelet summary API of every node hosting one of the pods,
+// reached through the API server's node proxy. Nodes that cannot be
+// queried are skipped; an error is returned only if none could be.
+func (c *Client) AnnotateStorage(ctx context.Context, pods []k8s.PodInfo) error {
+	nodes := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.NodeName != "" {
+			nodes[pod.NodeName] = true
+		}
+	}
+
+	type storage struct {
+		ephemeral int64
+		rootfs    int64
+	}
+	storageByPod := make(map[string]storage)
+
+	var lastErr error
+	queried := 0
+	for node := range nodes {
+		var summary kubeletSummary
+		path := fmt.Sprintf("/api/v1/nodes/%s/proxy/stats/summary", node)
+		if err := c.get(ctx, path, &summary); err != nil {
+			slog.Warn("Failed to get kubelet summary", "node", node, "error", err)
+			lastErr = err
+			continue
+		}
+		queried++
+
+		for _, pod := range summary.Pods {
+			var s storage
+			s.ephemeral = pod.EphemeralStorage.used()
+			for _, container := range pod.Containers {
+				s.rootfs += container.Rootfs.used()
+			}
+			storageByPod[pod.PodRef.Namespace+"/"+pod.PodRef.Name] = s
+		}
+	}
+
+	if queried == 0 && lastErr != nil {
+		return fmt.Errorf("failed to get kubelet summaries: %v", lastErr)
+	}
+
+	for i := range pods {
+		if s, ok := storageByPod[pods[i].Namespace+"/"+pods[i].Name]; ok {
+			pods[i].EphemeralStorageUsedBytes = s.ephemeral
+			pods[i].RootfsUsedBytes = s.rootfs
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// metricsAPIPath is the base path of the metrics-server aggregated API
+const metricsAPIPath = "/apis/metrics.k8s.io/v1beta1"
+
+// errRESTUnavailable is returned when the clientset cannot send raw API
+// requests, as with client-go's fake clientset
+var errRESTUnavailable = errors.New("clientset has no REST client")
+
+// Client queries the metrics.k8s.io API for live resource usage
+type Client struct {
+	clientset kubernetes.Interface
+}
+
+// containerMetrics mirrors the container entry of a metrics.k8s.io PodMetrics object
+type containerMetrics struct {
+	Name  string              `json:"name"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// podMetrics mirrors a metrics.k8s.io PodMetrics object
+type podMetrics struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Containers []containerMetrics `json:"containers"`
+}
+
+// nodeMetrics mirrors a metrics.k8s.io NodeMetrics object
+type nodeMetrics struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Usage corev1.ResourceList `json:"usage"`
+}
+
+// NewClient creates a metrics client sharing the given Kubernetes client's connection
+func NewClient(client k8s.Interface) *Client {
+	return &Client{clientset: client.GetClientset()}
+}
+
+// AnnotatePods fills in CPU and memory usage for the given pods, fetching
+// the metrics of each selected namespace (see k8s.SplitNamespaces)
+func (c *Client) AnnotatePods(ctx context.Context, namespace string, pods []k8s.PodInfo) error {
+	var list struct {
+		Items []podMetrics `json:"items"`
+	}
+	for _, namespace := range k8s.SplitNamespaces(namespace) {
+		path := metricsAPIPath + "/pods"
+		if namespace != "" {
+			path = fmt.Sprintf("%s/namespaces/%s/pods", metricsAPIPath, namespace)
+		}
+
+		var page struct {
+			Items []podMetrics `json:"items"`
+		}
+		if err := c.get(ctx, path, &page); err != nil {
+			return fmt.Errorf("failed to get pod metrics: %v", err)
+		}
+		list.Items = append(list.Items, page.Items...)
+	}
+
+	type usage struct {
+		cpu    int64
+		memory int64
+	}
+	usageByPod := make(map[string]usage, len(list.Items))
+	for _, item := range list.Items {
+		var u usage
+		for _, container := range item.Containers {
+			u.cpu += container.Usage.Cpu().MilliValue()
+			u.memory += container.Usage.Memory().Value()
+		}
+		usageByPod[item.Metadata.Namespace+"/"+item.Metadata.Name] = u
+	}
+
+	for i := range pods {
+		if u, ok := usageByPod[pods[i].Namespace+"/"+pods[i].Name]; ok {
+			pods[i].CPUUsageMilli = u.cpu
+			pods[i].MemoryUsageBytes = u.memory
+		}
+	}
+
+	return nil
+}
+
+// AnnotateNodes fills in CPU and memory usage for the given nodes
+func (c *Client) AnnotateNodes(ctx context.Context, nodes []k8s.NodeInfo) error {
+	var list struct {
+		Items []nodeMetrics `json:"items"`
+	}
+	if err := c.get(ctx, metricsAPIPath+"/nodes", &list); err != nil {
+		return fmt.Errorf("failed to get node metrics: %v", err)
+	}
+
+	usageByNode := make(map[string]corev1.ResourceList, len(list.Items))
+	for _, item := range list.Items {
+		usageByNode[item.Metadata.Name] = item.Usage
+	}
+
+	for i := range nodes {
+		if u, ok := usageByNode[nodes[i].Name]; ok {
+			nodes[i].CPUUsageMilli = u.Cpu().MilliValue()
+			nodes[i].MemoryUsageBytes = u.Memory().Value()
+		}
+	}
+
+	return nil
+}
+
+// get fetches and decodes a metrics.k8s.io resource
+func (c *Client) get(ctx context.Context, path string, into interface{}) error {
+	// Fake clientsets have no REST client to send raw requests with
+	restClient, ok := c.clientset.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return errRESTUnavailable
+	}
+
+	body, err := restClient.Get().AbsPath(path).DoRaw(ctx)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, into)
+}
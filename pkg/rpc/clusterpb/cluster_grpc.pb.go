@@ -0,0 +1,183 @@
+// The visualizer's aggregated cluster model, for tools that consume it
+// programmatically. Run go generate ./pkg/rpc/clusterpb after editing.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: podvisualizer/v1/cluster.proto
+
+package clusterpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ClusterService_GetClusterData_FullMethodName = "/podvisualizer.v1.ClusterService/GetClusterData"
+	ClusterService_Watch_FullMethodName          = "/podvisualizer.v1.ClusterService/Watch"
+)
+
+// ClusterServiceClient is the client API for ClusterService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClusterServiceClient interface {
+	// GetClusterData returns the current cluster state
+	GetClusterData(ctx context.Context, in *GetClusterDataRequest, opts ...grpc.CallOption) (*ClusterData, error)
+	// Watch streams the current cluster state, then a full ClusterData every
+	// time the state changes, until the client cancels
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ClusterService_WatchClient, error)
+}
+
+type clusterServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClusterServiceClient(cc grpc.ClientConnInterface) ClusterServiceClient {
+	return &clusterServiceClient{cc}
+}
+
+func (c *clusterServiceClient) GetClusterData(ctx context.Context, in *GetClusterDataRequest, opts ...grpc.CallOption) (*ClusterData, error) {
+	out := new(ClusterData)
+	err := c.cc.Invoke(ctx, ClusterService_GetClusterData_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ClusterService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ClusterService_ServiceDesc.Streams[0], ClusterService_Watch_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &clusterServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ClusterService_WatchClient interface {
+	Recv() (*ClusterData, error)
+	grpc.ClientStream
+}
+
+type clusterServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *clusterServiceWatchClient) Recv() (*ClusterData, error) {
+	m := new(ClusterData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ClusterServiceServer is the server API for ClusterService service.
+// All implementations must embed UnimplementedClusterServiceServer
+// for forward compatibility
+type ClusterServiceServer interface {
+	// GetClusterData returns the current cluster state
+	GetClusterData(context.Context, *GetClusterDataRequest) (*ClusterData, error)
+	// Watch streams the current cluster state, then a full ClusterData every
+	// time the state changes, until the client cancels
+	Watch(*WatchRequest, ClusterService_WatchServer) error
+	mustEmbedUnimplementedClusterServiceServer()
+}
+
+// UnimplementedClusterServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedClusterServiceServer struct {
+}
+
+func (UnimplementedClusterServiceServer) GetClusterData(context.Context, *GetClusterDataRequest) (*ClusterData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClusterData not implemented")
+}
+func (UnimplementedClusterServiceServer) Watch(*WatchRequest, ClusterService_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedClusterServiceServer) mustEmbedUnimplementedClusterServiceServer() {}
+
+// UnsafeClusterServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClusterServiceServer will
+// result in compilation errors.
+type UnsafeClusterServiceServer interface {
+	mustEmbedUnimplementedClusterServiceServer()
+}
+
+func RegisterClusterServiceServer(s grpc.ServiceRegistrar, srv ClusterServiceServer) {
+	s.RegisterService(&ClusterService_ServiceDesc, srv)
+}
+
+func _ClusterService_GetClusterData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClusterDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).GetClusterData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_GetClusterData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).GetClusterData(ctx, req.(*GetClusterDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ClusterServiceServer).Watch(m, &clusterServiceWatchServer{stream})
+}
+
+type ClusterService_WatchServer interface {
+	Send(*ClusterData) error
+	grpc.ServerStream
+}
+
+type clusterServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *clusterServiceWatchServer) Send(m *ClusterData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ClusterService_ServiceDesc is the grpc.ServiceDesc for ClusterService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ClusterService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "podvisualizer.v1.ClusterService",
+	HandlerType: (*ClusterServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetClusterData",
+			Handler:    _ClusterService_GetClusterData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ClusterService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "podvisualizer/v1/cluster.proto",
+}
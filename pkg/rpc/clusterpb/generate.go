@@ -0,0 +1,5 @@
+// Package clusterpb contains the Go bindings generated from
+// api/podvisualizer/v1/cluster.proto
+package clusterpb
+
+//go:generate protoc -I ../../../api --go_out=. --go_opt=module=pod-visualizer/pkg/rpc/clusterpb --go-grpc_out=. --go-grpc_opt=module=pod-visualizer/pkg/rpc/clusterpb podvisualizer/v1/cluster.proto
@@ -0,0 +1,105 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pod statuses beyond the core pod phases
+const (
+	Terminating      = "Terminating"
+	CrashLoopBackOff = "CrashLoopBackOff"
+	Evicted          = "Evicted"
+
+	// Completed is the status of Succeeded pods, as kubectl shows them
+	Completed = "Completed"
+)
+
+// Init is the status of pods still running init containers, shown with
+// their progress as Init:1/3 the way kubectl does
+const Init = "Init"
+
+// InitProgress returns the status of a pod that has finished done of its
+// total init containers
+func InitProgress(done, total int) string {
+	return fmt.Sprintf("%s:%d/%d", Init, done, total)
+}
+
+// IsInit reports whether a status is an InitProgress status
+func IsInit(status string) bool {
+	return strings.HasPrefix(status, Init+":")
+}
+
+// UnknownSymbol is shown for statuses missing from a mapping
+const UnknownSymbol = "❓"
+
+// Mapping maps lower-cased pod statuses to display symbols
+type Mapping map[string]string
+
+// DefaultMapping is the symbol table used by both the CLI and the web server
+var DefaultMapping = Mapping{
+	"running":          "✅",
+	"pending":          "⏳",
+	"failed":           "❌",
+	"completed":        "🏁",
+	"terminating":      "🛑",
+	"crashloopbackoff": "🔁",
+	"evicted":          "⚠️",
+	"init":             "🔧",
+}
+
+// Symbol returns the symbol for a status, matching case-insensitively
+func (m Mapping) Symbol(status string) string {
+	return m.SymbolOr(status, UnknownSymbol)
+}
+
+// SymbolOr returns the symbol for a status, or fallback when it is
+// unmapped. Init progress statuses share the symbol of Init.
+func (m Mapping) SymbolOr(status, fallback string) string {
+	if symbol, ok := m[strings.ToLower(status)]; ok {
+		return symbol
+	}
+	if symbol, ok := m[strings.ToLower(Init)]; ok && IsInit(status) {
+		return symbol
+	}
+	return fallback
+}
+
+// Symbol returns the symbol for a status using the default mapping
+func Symbol(status string) string {
+	return DefaultMapping.Symbol(status)
+}
+
+// ParseMapping parses overrides of the form "Running=OK,Failed=X" on top of
+// the default mapping. An empty spec returns the default mapping.
+func ParseMapping(spec string) (Mapping, error) {
+	return ParseMappingFrom(DefaultMapping, spec)
+}
+
+// ParseMappingFrom parses overrides like ParseMapping on top of base,
+// which is left unmodified
+func ParseMappingFrom(base Mapping, spec string) (Mapping, error) {
+	mapping := make(Mapping, len(base))
+	for status, symbol := range base {
+		mapping[status] = symbol
+	}
+
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		status, symbol, found := strings.Cut(entry, "=")
+		status = strings.ToLower(strings.TrimSpace(status))
+		if !found || status == "" {
+			return nil, fmt.Errorf("invalid status symbol %q, expected Status=Symbol", entry)
+		}
+		// Succeeded pods used to be shown by phase; keep such overrides working
+		if status == "succeeded" {
+			status = strings.ToLower(Completed)
+		}
+		mapping[status] = strings.TrimSpace(symbol)
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,146 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// Node is a resource in an ownership tree
+type Node struct {
+	Kind      string
+	Name      string
+	Namespace string
+	UID       string
+	Status    string
+	Children  []*Node
+}
+
+// kindOrder sorts roots and siblings so workloads come before what they own
+var kindOrder = map[string]int{
+	"CronJob":     0,
+	"Deployment":  1,
+	"StatefulSet": 2,
+	"DaemonSet":   3,
+	"Job":         4,
+	"ReplicaSet":  5,
+	"Pod":         6,
+}
+
+// builder collects nodes and the controller that owns each of them
+type builder struct {
+	nodes  map[types.UID]*Node
+	owners map[types.UID]types.UID
+	order  []types.UID
+}
+
+// add records a resource and its controller, if any
+func (b *builder) add(meta metav1.ObjectMeta, kind, status string) {
+	b.nodes[meta.UID] = &Node{
+		Kind:      kind,
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		UID:       string(meta.UID),
+		Status:    status,
+	}
+	b.order = append(b.order, meta.UID)
+
+	for _, owner := range meta.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller {
+			b.owners[meta.UID] = owner.UID
+		}
+	}
+}
+
+// Build assembles the ownership trees of the enabled kinds in the selected
+// namespaces (see k8s.SplitNamespaces, empty for all): Deployment →
+// ReplicaSet → Pod, CronJob → Job → Pod, and StatefulSet or DaemonSet →
+// Pod. Resources whose controller is not listed, such as bare pods, become
+// roots of their own; resources that may not be listed are left out.
+func Build(ctx context.Context, client k8s.Interface, namespace string, kinds k8s.Kinds) ([]*Node, error) {
+	objects, err := client.GetOwnershipObjects(ctx, namespace, kinds)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{nodes: make(map[types.UID]*Node), owners: make(map[types.UID]types.UID)}
+	for _, d := range objects.Deployments {
+		b.add(d.ObjectMeta, "Deployment", readyStatus(d.Status.ReadyReplicas, d.Spec.Replicas))
+	}
+	for _, rs := range objects.ReplicaSets {
+		// Scaled-down ReplicaSets from old revisions only add noise
+		if rs.Status.Replicas == 0 && rs.Spec.Replicas != nil && *rs.Spec.Replicas == 0 {
+			continue
+		}
+		b.add(rs.ObjectMeta, "ReplicaSet", readyStatus(rs.Status.ReadyReplicas, rs.Spec.Replicas))
+	}
+	for _, ss := range objects.StatefulSets {
+		b.add(ss.ObjectMeta, "StatefulSet", readyStatus(ss.Status.ReadyReplicas, ss.Spec.Replicas))
+	}
+	for _, ds := range objects.DaemonSets {
+		desired := ds.Status.DesiredNumberScheduled
+		b.add(ds.ObjectMeta, "DaemonSet", readyStatus(ds.Status.NumberReady, &desired))
+	}
+	for _, cj := range objects.CronJobs {
+		status := cj.Spec.Schedule
+		if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+			status += " (suspended)"
+		}
+		b.add(cj.ObjectMeta, "CronJob", status)
+	}
+	for _, job := range objects.Jobs {
+		b.add(job.ObjectMeta, "Job", fmt.Sprintf("%d succeeded, %d failed", job.Status.Succeeded, job.Status.Failed))
+	}
+	for i := range objects.Pods {
+		b.add(objects.Pods[i].ObjectMeta, "Pod", k8s.PodStatus(&objects.Pods[i]))
+	}
+
+	return b.trees(), nil
+}
+
+// trees links every node to its controller and returns the sorted roots
+func (b *builder) trees() []*Node {
+	var roots []*Node
+	for _, uid := range b.order {
+		node := b.nodes[uid]
+		if parent, ok := b.nodes[b.owners[uid]]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+// sortNodes orders nodes by kind, namespace and name, recursively
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+// readyStatus formats ready replicas against the desired count
+func readyStatus(ready int32, desired *int32) string {
+	want := int32(1)
+	if desired != nil {
+		want = *desired
+	}
+	return fmt.Sprintf("%d/%d ready", ready, want)
+}
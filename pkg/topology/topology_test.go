@@ -0,0 +1,131 @@
+package topology
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8stesting "k8s.io/client-go/testing"
+
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/k8s/fake"
+)
+
+// meta names an object owned by the controller with ownerUID, if any
+func meta(namespace, name, ownerUID string) metav1.ObjectMeta {
+	m := metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(name)}
+	if ownerUID != "" {
+		controller := true
+		m.OwnerReferences = []metav1.OwnerReference{{UID: types.UID(ownerUID), Controller: &controller}}
+	}
+	return m
+}
+
+// testObjects is a deployment with one replica set and pod, a
+// statefulset with a pod, and a bare pod
+func testObjects() []runtime.Object {
+	replicas := int32(1)
+	return []runtime.Object{
+		&appsv1.Deployment{ObjectMeta: meta("shop", "web", ""), Spec: appsv1.DeploymentSpec{Replicas: &replicas}},
+		&appsv1.ReplicaSet{ObjectMeta: meta("shop", "web-6d4f9", "web"), Spec: appsv1.ReplicaSetSpec{Replicas: &replicas}, Status: appsv1.ReplicaSetStatus{Replicas: 1}},
+		&corev1.Pod{ObjectMeta: meta("shop", "web-6d4f9-abcde", "web-6d4f9")},
+		&appsv1.StatefulSet{ObjectMeta: meta("shop", "db", ""), Spec: appsv1.StatefulSetSpec{Replicas: &replicas}},
+		&corev1.Pod{ObjectMeta: meta("shop", "db-0", "db")},
+		&corev1.Pod{ObjectMeta: meta("default", "debug", "")},
+	}
+}
+
+// outline renders trees as indented kind/name lines
+func outline(nodes []*Node, depth int) string {
+	var b strings.Builder
+	for _, node := range nodes {
+		b.WriteString(strings.Repeat("  ", depth) + node.Kind + "/" + node.Name + "\n")
+		b.WriteString(outline(node.Children, depth+1))
+	}
+	return b.String()
+}
+
+func TestBuild(t *testing.T) {
+	all, err := k8s.ParseKinds("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	podsOnly, err := k8s.ParseKinds("pods")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		kinds     k8s.Kinds
+		forbidden string
+		want      string
+	}{
+		{
+			name:  "all kinds",
+			kinds: all,
+			want: "Deployment/web\n  ReplicaSet/web-6d4f9\n    Pod/web-6d4f9-abcde\n" +
+				"StatefulSet/db\n  Pod/db-0\n" +
+				"Pod/debug\n",
+		},
+		{
+			name:      "one namespace",
+			namespace: "default",
+			kinds:     all,
+			want:      "Pod/debug\n",
+		},
+		{
+			name:  "deployments disabled",
+			kinds: podsOnly,
+			want:  "StatefulSet/db\n  Pod/db-0\nPod/debug\nPod/web-6d4f9-abcde\n",
+		},
+		{
+			name:      "forbidden kind is left out",
+			kinds:     all,
+			forbidden: "statefulsets",
+			want:      "Deployment/web\n  ReplicaSet/web-6d4f9\n    Pod/web-6d4f9-abcde\nPod/debug\nPod/db-0\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset := fake.NewClient(testObjects()...)
+			if tt.forbidden != "" {
+				clientset.PrependReactor("list", tt.forbidden, func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: tt.forbidden}, "", nil)
+				})
+			}
+
+			roots, err := Build(context.Background(), client, tt.namespace, tt.kinds)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got := outline(roots, 0); got != tt.want {
+				t.Errorf("Build() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildListError(t *testing.T) {
+	all, err := k8s.ParseKinds("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, clientset := fake.NewClient(testObjects()...)
+	clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewServiceUnavailable("etcd is down")
+	})
+
+	if _, err := Build(context.Background(), client, "", all); err == nil {
+		t.Error("Build() error = nil, want the failed pod list")
+	}
+}
@@ -0,0 +1,113 @@
+package visualizer
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+
+	"pod-visualizer/pkg/analysis"
+)
+
+// ANSI SGR sequences used by the visualizer
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiDim    = "\033[2m"
+)
+
+// ColorSupported reports whether f is a terminal that should get colors:
+// NO_COLOR must be unset and TERM must not be "dumb"
+func ColorSupported(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// SetColor turns ANSI color output on or off
+func (v *Visualizer) SetColor(enabled bool) {
+	v.color = enabled
+}
+
+// paint wraps s in an SGR sequence when color is enabled and the theme
+// defines one
+func (v *Visualizer) paint(code, s string) string {
+	if !v.color || code == "" || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// readinessColor is the theme's good color when everything is ready, bad
+// when nothing is and warn in between
+func (v *Visualizer) readinessColor(ready, total int) string {
+	switch {
+	case ready >= total:
+		return v.theme.Colors.Good
+	case ready <= 0:
+		return v.theme.Colors.Bad
+	default:
+		return v.theme.Colors.Warn
+	}
+}
+
+// healthColor colors a deployment health score by its band: good when
+// healthy, bad when failing and warn in between
+func (v *Visualizer) healthColor(score int) string {
+	switch {
+	case score >= analysis.HealthyScore:
+		return v.theme.Colors.Good
+	case score < analysis.DegradedScore:
+		return v.theme.Colors.Bad
+	default:
+		return v.theme.Colors.Warn
+	}
+}
+
+// readinessBar renders one block per item, with the ready blocks colored
+// by overall readiness
+func (v *Visualizer) readinessBar(ready, total int) string {
+	if ready > total {
+		total = ready
+	}
+	filled := strings.Repeat(v.theme.Block, ready)
+	empty := strings.Repeat(v.theme.Empty, total-ready)
+	return v.paint(v.readinessColor(ready, total), filled) + empty
+}
+
+// initBar draws the init containers of a pod, the finished ones with
+// InitBlock, so they stand apart from the readiness bar
+func (v *Visualizer) initBar(done, total int) string {
+	return v.paint(v.theme.Colors.Warn, strings.Repeat(v.theme.InitBlock, done)) + strings.Repeat(v.theme.Empty, total-done)
+}
+
+// sidecarBar draws a pod's sidecars dimmed, so they stand apart from the
+// app containers whose readiness matters most
+func (v *Visualizer) sidecarBar(ready, total int) string {
+	return v.paint(v.theme.Colors.Dim, strings.Repeat(v.theme.Block, ready)+strings.Repeat(v.theme.Empty, total-ready))
+}
+
+// fittedReadinessBar is readinessBar scaled down to at most cells blocks
+func (v *Visualizer) fittedReadinessBar(ready, total, cells int) string {
+	return v.readinessBar(scaledCounts(ready, total, cells))
+}
+
+// percentBar renders a fixed-width bar filled to percentage, colored like
+// readinessBar
+func (v *Visualizer) percentBar(percentage float64, width int) string {
+	filled := int(float64(width) * percentage / 100)
+	color := v.theme.Colors.Warn
+	switch {
+	case percentage >= 100:
+		color = v.theme.Colors.Good
+	case filled == 0:
+		color = v.theme.Colors.Bad
+	}
+	return v.paint(color, strings.Repeat(v.theme.Block, filled)) + strings.Repeat(v.theme.Empty, width-filled)
+}
@@ -2,73 +2,331 @@ package visualizer
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
+	"pod-visualizer/pkg/analysis"
 	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/status"
+	"pod-visualizer/pkg/topology"
 )
 
-// Visualizer handles the display of Kubernetes resources
+// Visualizer handles the display of Kubernetes resources. Display methods
+// write to the configured output and return the first write error.
 type Visualizer struct {
-	blockChar     string
-	emptyChar     string
+	theme         Theme
 	maxLineLength int
+	symbols       status.Mapping
+	color         bool
+	containers    bool
+	out           io.Writer
 }
 
-// New creates a new Visualizer with default settings
-func New() *Visualizer {
-	return &Visualizer{
-		blockChar:     "█",
-		emptyChar:     "░",
-		maxLineLength: 80,
+// New creates a Visualizer writing to stdout with the unicode theme and no
+// colors, adjusted by opts
+func New(opts ...Option) *Visualizer {
+	v := &Visualizer{
+		theme:         UnicodeTheme,
+		maxLineLength: defaultLineLength,
+		symbols:       UnicodeTheme.Symbols,
+		out:           os.Stdout,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// SetStatusSymbols overrides the status symbol mapping
+func (v *Visualizer) SetStatusSymbols(symbols status.Mapping) {
+	v.symbols = symbols
+}
+
+// Theme returns the theme the visualizer draws with
+func (v *Visualizer) Theme() Theme {
+	return v.theme
+}
+
+// symbol returns the glyph for a pod status
+func (v *Visualizer) symbol(podStatus string) string {
+	return v.symbols.SymbolOr(podStatus, v.theme.Unknown)
 }
 
 // DisplayPods shows a visual representation of pods and their containers
-func (v *Visualizer) DisplayPods(pods []k8s.PodInfo) {
+func (v *Visualizer) DisplayPods(pods []k8s.PodInfo) error {
+	w := v.writer()
 	if len(pods) == 0 {
-		fmt.Println("No pods found.")
-		return
+		fmt.Fprintln(w, "No pods found.")
+		return w.err
 	}
 
-	fmt.Printf("Pods Overview (%d total)\n", len(pods))
-	fmt.Println(strings.Repeat("-", 40))
+	fmt.Fprintf(w, "Pods Overview (%d total)\n", len(pods))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
 
 	totalContainers := 0
 	runningContainers := 0
 
 	for _, pod := range pods {
-		totalContainers += pod.ContainerCount
-		runningContainers += pod.ReadyContainers
-
-		// Create visual representation
-		status := v.getStatusSymbol(pod.Status)
-		readyBlocks := strings.Repeat(v.blockChar, pod.ReadyContainers)
-		notReadyBlocks := strings.Repeat(v.emptyChar, pod.ContainerCount-pod.ReadyContainers)
-
-		fmt.Printf("%s %s/%s: %s%s (%d/%d containers ready)\n",
-			status,
-			pod.Namespace,
-			pod.Name,
-			readyBlocks,
-			notReadyBlocks,
-			pod.ReadyContainers,
-			pod.ContainerCount,
+		ready, total := pod.Readiness()
+		totalContainers += total
+		runningContainers += ready
+		v.displayPod(w, pod)
+	}
+
+	fmt.Fprintln(w)
+	v.displayContainerSummary(w, runningContainers, totalContainers)
+	v.displayQOSSummary(w, k8s.CountQOSClasses(pods), len(pods))
+	return w.err
+}
+
+// DisplayPodGroups shows pods in sections with a header and container
+// subtotal per group, followed by the overall container summary
+func (v *Visualizer) DisplayPodGroups(grouping string, groups []k8s.PodGroup) error {
+	w := v.writer()
+	totalPods, totalContainers, runningContainers := 0, 0, 0
+	for _, group := range groups {
+		ready, total := group.ReadyContainers()
+		totalPods += len(group.Pods)
+		runningContainers += ready
+		totalContainers += total
+	}
+	if totalPods == 0 {
+		fmt.Fprintln(w, "No pods found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Pods Overview (%d total, %d %ss)\n", totalPods, len(groups), grouping)
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		ready, total := group.ReadyContainers()
+		title := grouping + " " + group.Name
+		if group.Ungrouped() {
+			title = group.Name
+		}
+		noun := "pods"
+		if len(group.Pods) == 1 {
+			noun = "pod"
+		}
+		header := fmt.Sprintf("%s (%d %s, %d/%d containers ready)", title, len(group.Pods), noun, ready, total)
+		fmt.Fprintln(w, v.paint(v.readinessColor(ready, total), v.truncate(header, v.lineWidth())))
+
+		for _, pod := range group.Pods {
+			v.displayPod(w, pod)
+		}
+	}
+
+	qos := make(map[string]int)
+	for _, group := range groups {
+		for class, count := range k8s.CountQOSClasses(group.Pods) {
+			qos[class] += count
+		}
+	}
+
+	fmt.Fprintln(w)
+	v.displayContainerSummary(w, runningContainers, totalContainers)
+	v.displayQOSSummary(w, qos, totalPods)
+	return w.err
+}
+
+// displayPod prints a pod's line and any warnings below it
+func (v *Visualizer) displayPod(w io.Writer, pod k8s.PodInfo) {
+	// Create visual representation; completed and evicted pods, which
+	// have stopped for good, are dimmed as a whole
+	symbol := v.symbol(pod.Status)
+	stopped := pod.Status == status.Completed || pod.Status == status.Evicted
+
+	// Sidecars get a dimmed bar of their own behind the app containers
+	ready, total := pod.ReadyContainers, pod.ContainerCount
+	sidecars, trail := "", ""
+	if pod.SidecarCount > 0 {
+		ready, total = pod.AppContainers()
+		sidecars = fmt.Sprintf(", %d/%d sidecars", pod.ReadySidecars, pod.SidecarCount)
+		if pod.SidecarsExcluded {
+			sidecars += " not counted"
+		}
+		trail = " " + v.sidecarBar(pod.ReadySidecars, pod.SidecarCount)
+	}
+	tail := fmt.Sprintf("(%d/%d containers ready%s%s%s)", ready, total, sidecars, debugSuffix(pod.EphemeralContainerCount), ageSuffix(pod.CreatedAt))
+
+	lead := ""
+	if pod.Initializing() {
+		// Init containers get a bar of their own ahead of the containers
+		// waiting on them
+		tail = fmt.Sprintf("(init %d/%d, %s", pod.InitContainersDone, pod.InitContainerCount, tail[1:])
+		lead = v.initBar(pod.InitContainersDone, pod.InitContainerCount) + " "
+	}
+	extra := displayWidth(lead) + displayWidth(trail)
+	name, cells := v.fit(symbol, pod.Namespace+"/"+pod.Name, total+extra, tail)
+	cells = max(cells-extra, 1)
+
+	bar := lead + v.fittedReadinessBar(ready, total, cells) + trail
+	if stopped {
+		ready, total := scaledCounts(ready, total, cells)
+		bar = strings.Repeat(v.theme.Block, ready) + strings.Repeat(v.theme.Empty, total-ready)
+	}
+
+	line := name + ": " + bar + " " + tail
+	if stopped {
+		line = v.paint(v.theme.Colors.Dim, line)
+	}
+	fmt.Fprintf(w, "%s %s\n", symbol, line)
+
+	if pod.RuntimeClassMismatch() {
+		runtime := pod.RuntimeClass
+		if runtime == "" {
+			runtime = "default"
+		}
+		fmt.Fprintf(w, "   %s runtime %s, namespace expects %s\n", v.theme.Warning, runtime, pod.ExpectedRuntimeClass)
+	} else if pod.RuntimeClass != "" {
+		fmt.Fprintf(w, "   runtime %s\n", pod.RuntimeClass)
+	}
+
+	// BestEffort pods are the first evicted, so they are called out
+	// along with any priority class; other pods stay on one line
+	var scheduling []string
+	if pod.QOSClass == "BestEffort" {
+		scheduling = append(scheduling, "qos "+pod.QOSClass)
+	}
+	if pod.PriorityClass != "" {
+		scheduling = append(scheduling, "priority "+pod.PriorityClass)
+	}
+	if len(scheduling) > 0 {
+		fmt.Fprintf(w, "   %s\n", strings.Join(scheduling, ", "))
+	}
+
+	if pod.CPUUsageMilli > 0 || pod.MemoryUsageBytes > 0 {
+		fmt.Fprintf(w, "   cpu %s %s  mem %s %s\n",
+			v.usageBar(pod.CPUUsageMilli, pod.CPURequestMilli),
+			formatUsage(pod.CPUUsageMilli, pod.CPURequestMilli, formatMilliCPU),
+			v.usageBar(pod.MemoryUsageBytes, pod.MemoryRequestBytes),
+			formatUsage(pod.MemoryUsageBytes, pod.MemoryRequestBytes, formatBytes),
 		)
 	}
 
-	fmt.Println()
-	v.displayContainerSummary(runningContainers, totalContainers)
+	v.displayCrashLoops(w, pod.CrashLoops, time.Now())
+
+	if pod.EvictionMessage != "" {
+		line := fmt.Sprintf("   %s evicted: %s", v.theme.Warning, pod.EvictionMessage)
+		fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+	}
+
+	if pod.Unschedulable != "" {
+		line := fmt.Sprintf("   %s unschedulable: %s", v.theme.Warning, pod.Unschedulable)
+		fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+	}
+
+	for _, block := range pod.BlockingTaints {
+		line := fmt.Sprintf("   %s untolerated taint %s on %s", v.theme.Warning, block.Taint, strings.Join(block.Nodes, ", "))
+		fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+	}
+
+	if pod.GitOps.NeedsAttention() {
+		fmt.Fprintln(w, v.truncate(v.gitOpsLine(pod.GitOps), v.lineWidth()))
+	}
+
+	if len(pod.NodePressure) > 0 {
+		fmt.Fprintf(w, "   %s node %s under %s\n", v.theme.Warning, pod.NodeName, strings.Join(pod.NodePressure, ", "))
+	}
+
+	if pod.EphemeralStorageNearLimit() {
+		fmt.Fprintf(w, "   %s ephemeral storage %s\n", v.theme.Warning,
+			formatUsage(pod.EphemeralStorageUsedBytes, pod.EphemeralStorageLimitBytes, formatBytes))
+	}
+
+	// Hints interpret the warnings above, so they come last
+	for _, hint := range pod.Hints {
+		fmt.Fprintln(w, v.truncate(fmt.Sprintf("   %s %s", v.theme.Hint, hint.Message), v.lineWidth()))
+	}
+
+	if v.containers {
+		v.displayContainers(w, pod)
+	}
+}
+
+// displayCrashLoops shows the restart cadence of crash-looping
+// containers, e.g. "   🔁 web exited 1 (Error) 25s ago, restart in 15s
+// (back-off 40s)", painted as a failure
+func (v *Visualizer) displayCrashLoops(w io.Writer, loops []analysis.CrashLoop, now time.Time) {
+	for _, loop := range loops {
+		line := fmt.Sprintf("   %s %s", v.symbol(status.CrashLoopBackOff), loop.Container)
+		backoff := "back-off " + k8s.FormatAge(loop.Backoff)
+		if loop.FinishedAt.IsZero() {
+			line += " " + backoff
+		} else {
+			line += fmt.Sprintf(" exited %d", loop.ExitCode)
+			if loop.Reason != "" {
+				line += " (" + loop.Reason + ")"
+			}
+			line += " " + k8s.FormatAge(now.Sub(loop.FinishedAt)) + " ago, "
+			if wait := loop.NextRestart().Sub(now); wait > 0 {
+				line += "restart in " + k8s.FormatAge(wait)
+			} else {
+				line += "restarting"
+			}
+			line += " (" + backoff + ")"
+		}
+		fmt.Fprintln(w, v.paint(v.theme.Colors.Bad, v.truncate(line, v.lineWidth())))
+	}
+}
+
+// WithContainers expands each pod into one line per container
+func WithContainers(enabled bool) Option {
+	return func(v *Visualizer) { v.containers = enabled }
+}
+
+// displayContainers lists a pod's containers below it, each with a
+// one-block readiness bar, its image, state and restarts, e.g.
+// "   └── █ web (nginx:1.25): Running, 2 restarts"
+func (v *Visualizer) displayContainers(w io.Writer, pod k8s.PodInfo) {
+	for i, container := range pod.Containers {
+		branch := v.theme.Branch
+		if i == len(pod.Containers)-1 {
+			branch = v.theme.LastBranch
+		}
+
+		block := v.paint(v.theme.Colors.Bad, v.theme.Empty)
+		if container.Ready {
+			block = v.paint(v.theme.Colors.Good, v.theme.Block)
+		}
+		state := container.State
+		if container.Reason != "" {
+			state += " (" + container.Reason + ")"
+		}
+		image := ""
+		if container.Image != "" {
+			image = " (" + container.Image + ")"
+		}
+
+		tail := state + ", " + restartCount(container.RestartCount)
+		room := v.lineWidth() - displayWidth("   "+branch+v.theme.Block+" : "+tail)
+		fmt.Fprintf(w, "   %s%s %s: %s\n", branch, block, v.truncate(container.Name+image, max(room, minNameWidth)), tail)
+	}
+}
+
+// restartCount renders a restart count, e.g. "1 restart" or "3 restarts"
+func restartCount(restarts int32) string {
+	if restarts == 1 {
+		return "1 restart"
+	}
+	return fmt.Sprintf("%d restarts", restarts)
 }
 
 // DisplayDeployments shows a visual representation of deployments and their replicas
-func (v *Visualizer) DisplayDeployments(deployments []k8s.DeploymentInfo) {
+func (v *Visualizer) DisplayDeployments(deployments []k8s.DeploymentInfo) error {
+	w := v.writer()
 	if len(deployments) == 0 {
-		fmt.Println("No deployments found.")
-		return
+		fmt.Fprintln(w, "No deployments found.")
+		return w.err
 	}
 
-	fmt.Printf("Deployments Overview (%d total)\n", len(deployments))
-	fmt.Println(strings.Repeat("-", 40))
+	fmt.Fprintf(w, "Deployments Overview (%d total)\n", len(deployments))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
 
 	totalReplicas := int32(0)
 	readyReplicas := int32(0)
@@ -77,27 +335,758 @@ func (v *Visualizer) DisplayDeployments(deployments []k8s.DeploymentInfo) {
 		totalReplicas += deployment.Replicas
 		readyReplicas += deployment.ReadyReplicas
 
-		// Create visual representation
-		readyBlocks := strings.Repeat(v.blockChar, int(deployment.ReadyReplicas))
-		notReadyBlocks := strings.Repeat(v.emptyChar, int(deployment.Replicas-deployment.ReadyReplicas))
-
-		fmt.Printf("📦 %s/%s: %s%s (%d/%d replicas ready)\n",
-			deployment.Namespace,
-			deployment.Name,
-			readyBlocks,
-			notReadyBlocks,
-			deployment.ReadyReplicas,
-			deployment.Replicas,
+		pinned := ""
+		if deployment.PinnedAtMax {
+			pinned = fmt.Sprintf(", pinned at max %d", deployment.MaxReplicas)
+		}
+		tail := fmt.Sprintf("(%d/%d replicas ready, health %d%s%s)", deployment.ReadyReplicas, deployment.Replicas, deployment.HealthScore, pinned, ageSuffix(deployment.CreatedAt))
+		name, cells := v.fit(v.theme.Workload, deployment.Namespace+"/"+deployment.Name, int(deployment.Replicas), tail)
+
+		fmt.Fprintf(w, "%s %s: %s %s\n",
+			v.theme.Workload,
+			v.paint(v.healthColor(deployment.HealthScore), name),
+			v.fittedReadinessBar(int(deployment.ReadyReplicas), int(deployment.Replicas), cells),
+			tail,
+		)
+		if deployment.GitOps.NeedsAttention() {
+			fmt.Fprintln(w, v.truncate(v.gitOpsLine(deployment.GitOps), v.lineWidth()))
+		}
+	}
+
+	fmt.Fprintln(w)
+	v.displayReplicaSummary(w, readyReplicas, totalReplicas)
+	return w.err
+}
+
+// DisplayArgoRollouts shows Argo Rollouts like deployments, followed by
+// the progress of each: the canary steps run and the traffic weight, or
+// the active and preview revisions of a blue-green rollout, and how many
+// pods run each pod template hash
+func (v *Visualizer) DisplayArgoRollouts(rollouts []k8s.ArgoRolloutInfo) error {
+	w := v.writer()
+	fmt.Fprintf(w, "Argo Rollouts Overview (%d total)\n", len(rollouts))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for _, rollout := range rollouts {
+		tail := fmt.Sprintf("(%d/%d replicas ready, %s, %s%s)", rollout.ReadyReplicas, rollout.Replicas, rollout.Strategy, rollout.Phase, ageSuffix(rollout.CreatedAt))
+		name, cells := v.fit(v.theme.Workload, rollout.Namespace+"/"+rollout.Name, int(rollout.Replicas), tail)
+		color := v.readinessColor(int(rollout.ReadyReplicas), int(rollout.Replicas))
+		if rollout.NeedsAttention() {
+			color = v.theme.Colors.Bad
+		}
+
+		fmt.Fprintf(w, "%s %s: %s %s\n",
+			v.theme.Workload,
+			v.paint(color, name),
+			v.fittedReadinessBar(int(rollout.ReadyReplicas), int(rollout.Replicas), cells),
+			tail,
 		)
+
+		if rollout.Strategy == k8s.RolloutCanary && rollout.Steps > 0 {
+			step := "done"
+			if rollout.Step < rollout.Steps {
+				step = fmt.Sprintf("step %d/%d %s", rollout.Step+1, rollout.Steps, rollout.StepName)
+			}
+			paused := ""
+			if rollout.Paused && rollout.Phase != k8s.RolloutPaused {
+				paused = ", paused"
+			}
+			line := fmt.Sprintf("   steps %s %s, weight %d%%%s", v.readinessBar(int(rollout.Step), int(rollout.Steps)), step, rollout.Weight, paused)
+			fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+		}
+
+		if len(rollout.Revisions) > 0 {
+			revisions := make([]string, len(rollout.Revisions))
+			for i, revision := range rollout.Revisions {
+				revisions[i] = fmt.Sprintf("%s %d/%d ready", revision.Hash, revision.ReadyPods, revision.Pods)
+				if revision.Role != "" {
+					revisions[i] = revision.Role + " " + revisions[i]
+				}
+			}
+			fmt.Fprintln(w, v.truncate("   "+strings.Join(revisions, " · "), v.lineWidth()))
+		}
+
+		if rollout.NeedsAttention() && rollout.Message != "" {
+			line := fmt.Sprintf("   %s %s", v.theme.Warning, rollout.Message)
+			fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+		}
+	}
+	return w.err
+}
+
+// gitOpsLine reports a drifted, failed or degraded GitOps status, e.g.
+// "⚠ argocd argocd/shop: OutOfSync, Healthy"
+func (v *Visualizer) gitOpsLine(status k8s.GitOpsStatus) string {
+	line := fmt.Sprintf("   %s %s %s: %s, %s", v.theme.Warning, status.Tool, status.Source, status.Sync, status.Health)
+	if status.Message != "" {
+		line += ": " + status.Message
+	}
+	return line
+}
+
+// DisplaySpread shows how a deployment's pods are placed across the
+// domains of each topology key, one block per pod, followed by the
+// topology spread constraints and anti-affinity terms the placement breaks
+func (v *Visualizer) DisplaySpread(detail k8s.DeploymentDetail) error {
+	w := v.writer()
+	if detail.Spread == nil {
+		fmt.Fprintln(w, "Spread unavailable: pods or nodes cannot be listed.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Pod Spread of %s/%s (%d/%d replicas ready)\n", detail.Namespace, detail.Name, detail.ReadyReplicas, detail.Replicas)
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for _, topology := range detail.Spread.Topologies {
+		fmt.Fprintf(w, "%s (skew %d)\n", topology.Key, topology.Skew)
+		width := 0
+		for _, domain := range topology.Domains {
+			width = max(width, len(domain.Name))
+		}
+		for _, domain := range topology.Domains {
+			fmt.Fprintf(w, "   %-*s %3d %s\n", width, domain.Name, domain.Pods, strings.Repeat(v.theme.Block, domain.Pods))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if len(detail.Spread.Violations) == 0 {
+		fmt.Fprintln(w, v.theme.OK+" No placement rules broken")
+		return w.err
+	}
+	for _, violation := range detail.Spread.Violations {
+		kind, color := "preferred", v.theme.Colors.Warn
+		if violation.Required {
+			kind, color = "required", v.theme.Colors.Bad
+		}
+		line := fmt.Sprintf("%s %s (%s): %s", v.theme.Warning, violation.Rule, kind, violation.Message)
+		fmt.Fprintln(w, v.paint(color, v.truncate(line, v.lineWidth())))
+	}
+	return w.err
+}
+
+// DisplayHPAs shows autoscalers with their replica range and metrics,
+// counting those pinned at maxReplicas
+func (v *Visualizer) DisplayHPAs(hpas []k8s.HPAInfo) error {
+	w := v.writer()
+	if len(hpas) == 0 {
+		fmt.Fprintln(w, "No horizontalpodautoscalers found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Autoscalers Overview (%d total)\n", len(hpas))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	atMax := 0
+	for _, hpa := range hpas {
+		status := v.theme.OK
+		if hpa.AtMaxReplicas() {
+			status = v.theme.Pending
+			atMax++
+		}
+
+		metrics := make([]string, len(hpa.Metrics))
+		for i, metric := range hpa.Metrics {
+			metrics[i] = fmt.Sprintf("%s %s/%s", metric.Name, metric.Current, metric.Target)
+		}
+		tail := fmt.Sprintf("(%d→%d replicas, %d-%d", hpa.CurrentReplicas, hpa.DesiredReplicas, hpa.MinReplicas, hpa.MaxReplicas)
+		if len(metrics) > 0 {
+			tail += "; " + strings.Join(metrics, ", ")
+		}
+		tail += ")"
+		name, _ := v.fit(status, fmt.Sprintf("%s/%s → %s/%s", hpa.Namespace, hpa.Name, hpa.TargetKind, hpa.TargetName), 0, tail)
+
+		fmt.Fprintf(w, "%s %s: %s\n", status, name, tail)
+	}
+
+	if atMax > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Autoscalers pinned at maxReplicas: %d\n", atMax)
+	}
+	return w.err
+}
+
+// DisplayPDBs shows disruption budgets with the workloads they cover,
+// flagging budgets that would block draining a node
+func (v *Visualizer) DisplayPDBs(pdbs []k8s.PDBInfo) error {
+	w := v.writer()
+	if len(pdbs) == 0 {
+		fmt.Fprintln(w, "No poddisruptionbudgets found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Disruption Budgets Overview (%d total)\n", len(pdbs))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	blocking := 0
+	for _, pdb := range pdbs {
+		status := v.theme.OK
+		if pdb.DrainBlocked() {
+			status = v.theme.Failed
+			blocking++
+		}
+
+		workloads := strings.Join(pdb.Workloads, ", ")
+		if workloads == "" {
+			workloads = "no pods"
+		}
+		tail := fmt.Sprintf("(%d disruptions allowed, %d/%d healthy", pdb.DisruptionsAllowed, pdb.CurrentHealthy, pdb.DesiredHealthy)
+		if pdb.DrainBlocked() {
+			tail += "; drain blocked on " + strings.Join(pdb.BlockedNodes, ", ")
+		}
+		tail += ")"
+		name, _ := v.fit(status, fmt.Sprintf("%s/%s → %s", pdb.Namespace, pdb.Name, workloads), 0, tail)
+
+		fmt.Fprintf(w, "%s %s: %s\n", status, name, tail)
+	}
+
+	if blocking > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Budgets that would block a node drain: %d\n", blocking)
+	}
+	return w.err
+}
+
+// DisplayJobs shows job completion progress and cronjob schedules
+func (v *Visualizer) DisplayJobs(jobs []k8s.JobInfo, cronJobs []k8s.CronJobInfo) error {
+	w := v.writer()
+	if len(jobs) == 0 && len(cronJobs) == 0 {
+		fmt.Fprintln(w, "No jobs found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Jobs Overview (%d jobs, %d cronjobs)\n", len(jobs), len(cronJobs))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	failedJobs := 0
+	for _, job := range jobs {
+		status, color := v.theme.Pending, v.theme.Colors.Warn
+		switch {
+		case job.JobFailed:
+			status, color = v.theme.Failed, v.theme.Colors.Bad
+			failedJobs++
+		case job.Complete:
+			status, color = v.theme.OK, v.theme.Colors.Good
+		}
+
+		succeeded := int(job.Succeeded)
+		if succeeded > int(job.Completions) {
+			succeeded = int(job.Completions)
+		}
+		tail := fmt.Sprintf("(%d/%d completions, %d active, %d failed)", job.Succeeded, job.Completions, job.Active, job.Failed)
+		name, cells := v.fit(status, job.Namespace+"/"+job.Name, int(job.Completions), tail)
+
+		done, total := scaledCounts(succeeded, int(job.Completions), cells)
+		doneBlocks := v.paint(color, strings.Repeat(v.theme.Block, done))
+		pendingBlocks := strings.Repeat(v.theme.Empty, total-done)
+
+		fmt.Fprintf(w, "%s %s: %s%s %s\n", status, name, doneBlocks, pendingBlocks, tail)
+	}
+
+	for _, cronJob := range cronJobs {
+		lastSchedule := "never"
+		if !cronJob.LastScheduleTime.IsZero() {
+			lastSchedule = cronJob.LastScheduleTime.Format(time.RFC3339)
+		}
+
+		suspended := ""
+		if cronJob.Suspended {
+			suspended = " [suspended]"
+		}
+
+		fmt.Fprintf(w, "%s %s/%s: %q (%d active, last scheduled %s)%s\n",
+			v.theme.Schedule,
+			cronJob.Namespace,
+			cronJob.Name,
+			cronJob.Schedule,
+			cronJob.Active,
+			lastSchedule,
+			suspended,
+		)
+	}
+
+	if failedJobs > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Failed jobs: %d\n", failedJobs)
 	}
+	return w.err
+}
+
+// DisplayServices shows services and how many of their endpoints are ready
+func (v *Visualizer) DisplayServices(services []k8s.ServiceInfo) error {
+	w := v.writer()
+	if len(services) == 0 {
+		fmt.Fprintln(w, "No services found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Services Overview (%d total)\n", len(services))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	noEndpoints := 0
+	for _, service := range services {
+		status := v.theme.OK
+		if service.HasSelector && service.ReadyEndpoints == 0 {
+			status = v.theme.Failed
+			noEndpoints++
+		} else if service.NotReadyEndpoints > 0 {
+			status = v.theme.Pending
+		}
+
+		endpoints := service.ReadyEndpoints + service.NotReadyEndpoints
+		tail := fmt.Sprintf("(%d/%d endpoints ready)", service.ReadyEndpoints, endpoints)
+		name, cells := v.fit(status, fmt.Sprintf("%s/%s (%s)", service.Namespace, service.Name, service.Type), endpoints, tail)
+
+		ready, total := scaledCounts(service.ReadyEndpoints, endpoints, cells)
+		readyBlocks := strings.Repeat(v.theme.Block, ready)
+		notReadyBlocks := strings.Repeat(v.theme.Empty, total-ready)
+
+		fmt.Fprintf(w, "%s %s: %s%s %s\n", status, name, readyBlocks, notReadyBlocks, tail)
+	}
+
+	if noEndpoints > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Services with no ready endpoints: %d\n", noEndpoints)
+	}
+	return w.err
+}
+
+// DisplayIngresses shows each Ingress and HTTPRoute rule as a chain from
+// host/path to its backend Service and that Service's ready endpoints
+func (v *Visualizer) DisplayIngresses(ingresses []k8s.IngressInfo) error {
+	w := v.writer()
+	if len(ingresses) == 0 {
+		fmt.Fprintln(w, "No ingresses or routes found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Ingress & Routes Overview (%d total)\n", len(ingresses))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	broken := 0
+	for _, ingress := range ingresses {
+		fmt.Fprintf(w, "%s/%s (%s)\n", ingress.Namespace, ingress.Name, ingress.Kind)
+		if len(ingress.Routes) == 0 {
+			fmt.Fprintln(w, "  no service backends")
+		}
+		for _, route := range ingress.Routes {
+			status := v.theme.OK
+			if !route.Healthy() {
+				status = v.theme.Failed
+				broken++
+			} else if route.NotReadyEndpoints > 0 {
+				status = v.theme.Pending
+			}
+
+			backend := route.Namespace + "/" + route.Service
+			if route.Port != "" {
+				backend += ":" + route.Port
+			}
+			tail := "(service not found)"
+			if route.ServiceFound {
+				tail = fmt.Sprintf("(%d/%d endpoints ready)", route.ReadyEndpoints, route.ReadyEndpoints+route.NotReadyEndpoints)
+			}
+			name, _ := v.fit("  "+status, fmt.Sprintf("%s%s → %s", route.Host, route.Path, backend), 0, tail)
+
+			fmt.Fprintf(w, "  %s %s: %s\n", status, name, tail)
+		}
+	}
+
+	if broken > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Routes with no ready backend: %d\n", broken)
+	}
+	return w.err
+}
+
+// DisplayPVCs shows volume claims with their bound volume and the pods
+// mounting them, counting the Pending and Lost ones
+func (v *Visualizer) DisplayPVCs(pvcs []k8s.PVCInfo) error {
+	w := v.writer()
+	if len(pvcs) == 0 {
+		fmt.Fprintln(w, "No persistentvolumeclaims found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Volume Claims Overview (%d total)\n", len(pvcs))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	stuck := 0
+	for _, pvc := range pvcs {
+		status := v.theme.OK
+		switch pvc.Phase {
+		case "Pending":
+			status = v.theme.Pending
+			stuck++
+		case "Lost":
+			status = v.theme.Failed
+			stuck++
+		}
+
+		tail := pvc.Phase
+		if pvc.VolumeName != "" {
+			tail += " → " + pvc.VolumeName
+		}
+		if len(pvc.MountedBy) > 0 {
+			tail += ", mounted by " + strings.Join(pvc.MountedBy, ", ")
+		}
+		class := pvc.StorageClass
+		if class == "" {
+			class = "no class"
+		}
+		name, _ := v.fit(status, fmt.Sprintf("%s/%s (%s, %s)", pvc.Namespace, pvc.Name, pvc.Capacity, class), 0, tail)
+
+		fmt.Fprintf(w, "%s %s: %s\n", status, name, tail)
+	}
+
+	if stuck > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Pending or Lost claims: %d\n", stuck)
+	}
+	return w.err
+}
+
+// DisplayNodes shows node usage against allocatable capacity
+func (v *Visualizer) DisplayNodes(nodes []k8s.NodeInfo) error {
+	w := v.writer()
+	if len(nodes) == 0 {
+		fmt.Fprintln(w, "No nodes found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Nodes Overview (%d total)\n", len(nodes))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for _, node := range nodes {
+		status := v.theme.OK
+		if !node.Ready {
+			status = v.theme.Failed
+		}
+
+		fmt.Fprintf(w, "%s %s\n", status, node.Name)
+		if len(node.Pressure) > 0 {
+			fmt.Fprintf(w, "   %s %s\n", v.theme.Warning, strings.Join(node.Pressure, ", "))
+		}
+		// Usage is only known when metrics-server was queried
+		if node.CPUUsageMilli > 0 || node.MemoryUsageBytes > 0 {
+			fmt.Fprintf(w, "   cpu %s %s  mem %s %s\n",
+				v.usageBar(node.CPUUsageMilli, node.CPUAllocatableMilli),
+				formatUsage(node.CPUUsageMilli, node.CPUAllocatableMilli, formatMilliCPU),
+				v.usageBar(node.MemoryUsageBytes, node.MemoryAllocatableBytes),
+				formatUsage(node.MemoryUsageBytes, node.MemoryAllocatableBytes, formatBytes),
+			)
+		}
+	}
+	return w.err
+}
+
+// DisplayZones shows the nodes of each topology zone with how many pods
+// they run, then the deployments with all their pods in a single zone
+func (v *Visualizer) DisplayZones(zones []k8s.ZoneInfo, workloads []k8s.WorkloadZones) error {
+	w := v.writer()
+	if len(zones) == 0 {
+		fmt.Fprintln(w, "No nodes found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Zones Overview (%d total)\n", len(zones))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for _, zone := range zones {
+		ready := 0
+		for _, node := range zone.Nodes {
+			if node.Ready {
+				ready++
+			}
+		}
+		title := "zone " + zone.Name
+		if zone.Unzoned() {
+			title = zone.Name
+		}
+		noun := "pods"
+		if zone.Pods == 1 {
+			noun = "pod"
+		}
+		header := fmt.Sprintf("%s (%d/%d nodes ready, %d %s)", title, ready, len(zone.Nodes), zone.Pods, noun)
+		fmt.Fprintln(w, v.paint(v.readinessColor(ready, len(zone.Nodes)), v.truncate(header, v.lineWidth())))
+
+		for _, node := range zone.Nodes {
+			status := v.theme.OK
+			if !node.Ready {
+				status = v.theme.Failed
+			}
+			fmt.Fprintf(w, "   %s %s\n", status, node.Name)
+		}
+	}
+
+	first := true
+	for _, workload := range workloads {
+		if workload.ConcentratedIn == "" {
+			continue
+		}
+		if first {
+			fmt.Fprintln(w)
+			first = false
+		}
+		line := fmt.Sprintf("%s %s/%s: all %d pods in zone %s", v.theme.Warning, workload.Namespace, workload.Name, workload.Pods, workload.ConcentratedIn)
+		fmt.Fprintln(w, v.truncate(line, v.lineWidth()))
+	}
+	return w.err
+}
+
+// ageSuffix renders ", age 3h" for a creation time, or nothing when unknown
+func ageSuffix(created time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+	return ", age " + k8s.FormatAge(time.Since(created))
+}
+
+// debugSuffix notes the ephemeral debug containers of a pod, if any
+func debugSuffix(count int) string {
+	switch count {
+	case 0:
+		return ""
+	case 1:
+		return ", 1 debug container"
+	default:
+		return fmt.Sprintf(", %d debug containers", count)
+	}
+}
+
+// usageBar renders used against a reference value (request or allocatable) as a short bar
+func (v *Visualizer) usageBar(used, reference int64) string {
+	barWidth := 10
+	if reference <= 0 {
+		return strings.Repeat(v.theme.Empty, barWidth)
+	}
+
+	filledWidth := int(float64(barWidth) * float64(used) / float64(reference))
+	if filledWidth > barWidth {
+		filledWidth = barWidth
+	}
+
+	return strings.Repeat(v.theme.Block, filledWidth) + strings.Repeat(v.theme.Empty, barWidth-filledWidth)
+}
+
+// formatUsage renders "used/reference" using the given unit formatter
+func formatUsage(used, reference int64, format func(int64) string) string {
+	if reference <= 0 {
+		return format(used) + "/-"
+	}
+	return format(used) + "/" + format(reference)
+}
 
-	fmt.Println()
-	v.displayReplicaSummary(readyReplicas, totalReplicas)
+// formatMilliCPU formats a CPU quantity in millicores
+func formatMilliCPU(milli int64) string {
+	return fmt.Sprintf("%dm", milli)
+}
+
+// formatBytes formats a memory quantity in mebibytes
+func formatBytes(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// DisplayTree shows ownership trees with box-drawing branches
+func (v *Visualizer) DisplayTree(roots []*topology.Node) error {
+	w := v.writer()
+	if len(roots) == 0 {
+		fmt.Fprintln(w, "No resources found.")
+		return w.err
+	}
+
+	fmt.Fprintln(w, "Ownership Tree")
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	for _, root := range roots {
+		fmt.Fprintf(w, "%s %s/%s [%s]\n", v.treeSymbol(root), root.Namespace, root.Name, root.Kind)
+		v.displayTreeChildren(w, root.Children, "")
+	}
+	return w.err
+}
+
+// displayTreeChildren prints children below their parent's prefix
+func (v *Visualizer) displayTreeChildren(w io.Writer, children []*topology.Node, prefix string) {
+	for i, child := range children {
+		branch, indent := v.theme.Branch, v.theme.Pipe
+		if i == len(children)-1 {
+			branch, indent = v.theme.LastBranch, v.theme.Space
+		}
+
+		fmt.Fprintf(w, "%s%s%s %s [%s] %s\n", prefix, branch, v.treeSymbol(child), child.Name, child.Kind, child.Status)
+		v.displayTreeChildren(w, child.Children, prefix+indent)
+	}
+}
+
+// treeSymbol returns the status symbol for pods and the workload marker otherwise
+func (v *Visualizer) treeSymbol(node *topology.Node) string {
+	if node.Kind == "Pod" {
+		return v.symbol(node.Status)
+	}
+	return v.theme.Workload
+}
+
+// DisplayImages shows the running images by repository with their versions,
+// flagging :latest tags and repositories running more than one version
+func (v *Visualizer) DisplayImages(images []k8s.ImageInfo) error {
+	w := v.writer()
+	if len(images) == 0 {
+		fmt.Fprintln(w, "No images found.")
+		return w.err
+	}
+
+	fmt.Fprintf(w, "Image Inventory (%d repositories)\n", len(images))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	latest, multiple := 0, 0
+	for _, image := range images {
+		status := v.theme.OK
+		var notes []string
+		if image.UsesLatest() {
+			status = v.theme.Warning
+			notes = append(notes, ":latest")
+			latest++
+		}
+		if image.MultipleVersions() {
+			status = v.theme.Warning
+			notes = append(notes, fmt.Sprintf("%d versions", len(image.Versions)))
+			multiple++
+		}
+
+		tail := fmt.Sprintf("(%d containers in %s", image.Containers, strings.Join(image.Namespaces, ", "))
+		if len(notes) > 0 {
+			tail += "; " + strings.Join(notes, ", ")
+		}
+		tail += ")"
+		name, _ := v.fit(status, image.Repository, 0, tail)
+		fmt.Fprintf(w, "%s %s: %s\n", status, name, tail)
+
+		for _, version := range image.Versions {
+			fmt.Fprintf(w, "   %s: %d containers in %s\n", shortDigest(version.Version), version.Containers, strings.Join(version.Namespaces, ", "))
+		}
+	}
+
+	if latest > 0 || multiple > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Repositories using :latest: %d, running multiple versions: %d\n", latest, multiple)
+	}
+	return w.err
+}
+
+// shortDigest abbreviates a "sha256:..." digest to 12 hex digits, as
+// container tools display image IDs; tags are returned unchanged
+func shortDigest(version string) string {
+	if algorithm, hex, found := strings.Cut(version, ":"); found && len(hex) > 12 {
+		return algorithm + ":" + hex[:12]
+	}
+	return version
+}
+
+// DisplayNamespaceDiff shows promotion drift between workloads of two namespaces
+func (v *Visualizer) DisplayNamespaceDiff(from, to string, compared int, drifts []k8s.WorkloadDrift) error {
+	w := v.writer()
+	fmt.Fprintf(w, "Promotion Drift %s -> %s (%d workloads compared)\n", from, to, compared)
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	if len(drifts) == 0 {
+		fmt.Fprintln(w, v.theme.OK+" No drift found.")
+		return w.err
+	}
+
+	for _, drift := range drifts {
+		if drift.Missing != "" {
+			fmt.Fprintf(w, "%s %s: missing in %s\n", v.theme.Unknown, drift.Name, drift.Missing)
+			continue
+		}
+
+		fmt.Fprintf(w, "%s %s: %d differences\n", v.theme.Warning, drift.Name, len(drift.Differences))
+		for _, difference := range drift.Differences {
+			fmt.Fprintf(w, "   %s\n", difference)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Drifted: %d/%d workloads\n", len(drifts), compared)
+	return w.err
+}
+
+// DisplayReferenceAudit shows pods referencing ConfigMaps or Secrets that
+// do not exist or lack the referenced key
+func (v *Visualizer) DisplayReferenceAudit(audits []k8s.ReferenceAudit) error {
+	w := v.writer()
+	fmt.Fprintln(w, "ConfigMap/Secret Reference Audit")
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+
+	if len(audits) == 0 {
+		fmt.Fprintln(w, v.theme.OK+" All references resolve.")
+		return w.err
+	}
+
+	for _, audit := range audits {
+		name := audit.Namespace + "/" + audit.Pod
+		if audit.Status != "" {
+			name += " (" + audit.Status + ")"
+		}
+		fmt.Fprintf(w, "%s %s: %d missing\n", v.theme.Failed, name, len(audit.Missing))
+		for _, ref := range audit.Missing {
+			fmt.Fprintf(w, "   %s\n", ref)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Pods with missing references: %d\n", len(audits))
+	return w.err
+}
+
+// DisplayPodTransition shows one pod status change as a timestamped line,
+// e.g. "15:04:05 ❌ shop/web-1: Running → CrashLoopBackOff (0/1 ready, 3 restarts)"
+func (v *Visualizer) DisplayPodTransition(transition k8s.PodTransition, at time.Time) error {
+	w := v.writer()
+	pod := transition.Pod
+	previous := transition.Previous
+	if previous == "" {
+		previous = "new"
+	}
+	fmt.Fprintf(w, "%s %s %s/%s: %s → %s (%d/%d ready, %d restarts)\n",
+		v.paint(v.theme.Colors.Dim, at.Format("15:04:05")),
+		v.symbol(pod.Status),
+		pod.Namespace, pod.Name,
+		previous, pod.Status,
+		pod.ReadyContainers, pod.ContainerCount, pod.Restarts,
+	)
+	return w.err
+}
+
+// DisplayWaitProgress redraws a single progress line for a resource being
+// waited on. Call it repeatedly; finish with a newline once waiting ends.
+func (v *Visualizer) DisplayWaitProgress(target k8s.WaitTarget, state k8s.WaitState, elapsed time.Duration) error {
+	w := v.writer()
+	symbol := v.theme.Pending
+	switch {
+	case state.Met:
+		symbol = v.theme.OK
+	case state.Failed:
+		symbol = v.theme.Failed
+	}
+
+	tail := fmt.Sprintf("(%d/%d) waiting for %s [%s]", state.Ready, state.Total, target.Condition, elapsed.Truncate(time.Second))
+	name, cells := v.fit(symbol, target.Namespace+"/"+target.String(), int(state.Total), tail)
+
+	// A wrapped progress line could not be redrawn in place, so it must fit
+	line := fmt.Sprintf("%s %s: %s %s",
+		symbol,
+		name,
+		v.fittedReadinessBar(int(state.Ready), int(state.Total), cells),
+		tail,
+	)
+
+	// Clear the rest of the previous line, which may have been longer
+	fmt.Fprintf(w, "\r%s\033[K", line)
+	return w.err
 }
 
 // displayContainerSummary shows an overall container status summary
-func (v *Visualizer) displayContainerSummary(running, total int) {
-	fmt.Println("Container Summary:")
+func (v *Visualizer) displayContainerSummary(w io.Writer, running, total int) {
+	fmt.Fprintln(w, "Container Summary:")
 
 	// Calculate percentage
 	percentage := 0.0
@@ -105,19 +1094,31 @@ func (v *Visualizer) displayContainerSummary(running, total int) {
 		percentage = float64(running) / float64(total) * 100
 	}
 
-	// Create a visual progress bar
-	barWidth := 50
-	filledWidth := int(float64(barWidth) * percentage / 100)
-	emptyWidth := barWidth - filledWidth
+	fmt.Fprintln(w, v.summaryLine(fmt.Sprintf("Running: %d/%d (%.1f%%)", running, total, percentage), percentage))
+}
 
-	progressBar := strings.Repeat(v.blockChar, filledWidth) + strings.Repeat(v.emptyChar, emptyWidth)
+// displayQOSSummary shows how many pods run in each QoS class and the
+// share of BestEffort pods, which have no resource guarantees; nothing
+// when no pod has been classified
+func (v *Visualizer) displayQOSSummary(w io.Writer, counts map[string]int, pods int) {
+	if len(counts) == 0 {
+		return
+	}
 
-	fmt.Printf("Running: %d/%d (%.1f%%) [%s]\n", running, total, percentage, progressBar)
+	classes := make([]string, len(k8s.QOSClasses))
+	for i, class := range k8s.QOSClasses {
+		classes[i] = fmt.Sprintf("%d %s", counts[class], class)
+	}
+	bestEffort := float64(counts["BestEffort"]) / float64(pods) * 100
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "QoS Summary:")
+	fmt.Fprintf(w, "%s (%.1f%% BestEffort)\n", strings.Join(classes, ", "), bestEffort)
 }
 
 // displayReplicaSummary shows an overall replica status summary
-func (v *Visualizer) displayReplicaSummary(ready, total int32) {
-	fmt.Println("Replica Summary:")
+func (v *Visualizer) displayReplicaSummary(w io.Writer, ready, total int32) {
+	fmt.Fprintln(w, "Replica Summary:")
 
 	// Calculate percentage
 	percentage := 0.0
@@ -125,28 +1126,5 @@ func (v *Visualizer) displayReplicaSummary(ready, total int32) {
 		percentage = float64(ready) / float64(total) * 100
 	}
 
-	// Create a visual progress bar
-	barWidth := 50
-	filledWidth := int(float64(barWidth) * percentage / 100)
-	emptyWidth := barWidth - filledWidth
-
-	progressBar := strings.Repeat(v.blockChar, filledWidth) + strings.Repeat(v.emptyChar, emptyWidth)
-
-	fmt.Printf("Ready: %d/%d (%.1f%%) [%s]\n", ready, total, percentage, progressBar)
-}
-
-// getStatusSymbol returns a symbol representing the pod status
-func (v *Visualizer) getStatusSymbol(status string) string {
-	switch strings.ToLower(status) {
-	case "running":
-		return "✅"
-	case "pending":
-		return "⏳"
-	case "failed":
-		return "❌"
-	case "succeeded":
-		return "✅"
-	default:
-		return "❓"
-	}
+	fmt.Fprintln(w, v.summaryLine(fmt.Sprintf("Ready: %d/%d (%.1f%%)", ready, total, percentage), percentage))
 }
@@ -0,0 +1,223 @@
+package visualizer
+
+import (
+	"strings"
+	"testing"
+
+	"pod-visualizer/pkg/analysis"
+	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/status"
+)
+
+// render returns what display writes with the ASCII theme, without
+// colors, on a terminal wide enough that no line is truncated
+func render(display func(*Visualizer) error) string {
+	return New(WithTheme(ASCIITheme), WithWidth(160)).Render(display)
+}
+
+// checkOutput fails t for every want missing from, and every unwanted
+// string present in, out
+func checkOutput(t *testing.T, out string, want, unwanted []string) {
+	t.Helper()
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			t.Errorf("output lacks %q:\n%s", s, out)
+		}
+	}
+	for _, s := range unwanted {
+		if strings.Contains(out, s) {
+			t.Errorf("output has %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestRenderDeploymentsGitOps(t *testing.T) {
+	tests := []struct {
+		name     string
+		gitOps   k8s.GitOpsStatus
+		want     []string
+		unwanted []string
+	}{
+		{
+			name:     "not managed",
+			want:     []string{"[DEPL] shop/web: ### (3/3 replicas ready, health 100)"},
+			unwanted: []string{"[WARN]"},
+		},
+		{
+			name:     "synced and healthy",
+			gitOps:   k8s.GitOpsStatus{Tool: k8s.GitOpsArgoCD, Source: "argocd/shop", Sync: k8s.SyncSynced, Health: k8s.GitOpsHealthy},
+			unwanted: []string{"[WARN]", "argocd/shop"},
+		},
+		{
+			name:   "out of sync",
+			gitOps: k8s.GitOpsStatus{Tool: k8s.GitOpsArgoCD, Source: "argocd/shop", Sync: k8s.SyncOutOfSync, Health: k8s.GitOpsHealthy},
+			want:   []string{"   [WARN] argocd argocd/shop: OutOfSync, Healthy\n"},
+		},
+		{
+			name:   "failed reconciliation with message",
+			gitOps: k8s.GitOpsStatus{Tool: k8s.GitOpsFlux, Source: "flux-system/apps", Sync: k8s.SyncFailed, Health: k8s.GitOpsDegraded, Message: "kustomize build failed"},
+			want:   []string{"   [WARN] flux flux-system/apps: Failed, Degraded: kustomize build failed\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deployments := []k8s.DeploymentInfo{{
+				Name: "web", Namespace: "shop", Replicas: 3, ReadyReplicas: 3, HealthScore: 100, GitOps: tt.gitOps,
+			}}
+			out := render(func(v *Visualizer) error { return v.DisplayDeployments(deployments) })
+			checkOutput(t, out, append([]string{"Deployments Overview (1 total)"}, tt.want...), tt.unwanted)
+		})
+	}
+}
+
+func TestRenderArgoRollouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		rollout  k8s.ArgoRolloutInfo
+		want     []string
+		unwanted []string
+	}{
+		{
+			name: "canary mid-way",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "checkout", Namespace: "shop", Strategy: k8s.RolloutCanary, Phase: k8s.RolloutPaused,
+				Replicas: 5, ReadyReplicas: 5, Step: 1, Steps: 4, StepName: "pause until promoted", Weight: 20, Paused: true,
+				Revisions: []k8s.ArgoRolloutRevision{
+					{Hash: "6d4f9", Role: k8s.RevisionStable, Pods: 4, ReadyPods: 4},
+					{Hash: "7b8c2", Role: k8s.RevisionCanary, Pods: 1, ReadyPods: 1},
+				},
+			},
+			want: []string{
+				"[DEPL] shop/checkout: ##### (5/5 replicas ready, Canary, Paused)",
+				"   steps #... step 2/4 pause until promoted, weight 20%\n",
+				"   stable 6d4f9 4/4 ready · canary 7b8c2 1/1 ready\n",
+			},
+			unwanted: []string{", paused", "[WARN]"},
+		},
+		{
+			name: "canary done",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "checkout", Namespace: "shop", Strategy: k8s.RolloutCanary, Phase: k8s.RolloutHealthy,
+				Replicas: 2, ReadyReplicas: 2, Step: 3, Steps: 3, Weight: 100,
+			},
+			want: []string{"   steps ### done, weight 100%\n"},
+		},
+		{
+			name: "manually paused while progressing",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "checkout", Namespace: "shop", Strategy: k8s.RolloutCanary, Phase: k8s.RolloutProgressing,
+				Replicas: 2, ReadyReplicas: 1, Step: 0, Steps: 2, StepName: "setWeight 50", Weight: 0, Paused: true,
+			},
+			want: []string{"step 1/2 setWeight 50, weight 0%, paused\n"},
+		},
+		{
+			name: "blue-green",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "api", Namespace: "shop", Strategy: k8s.RolloutBlueGreen, Phase: k8s.RolloutHealthy,
+				Replicas: 2, ReadyReplicas: 2,
+				Revisions: []k8s.ArgoRolloutRevision{
+					{Hash: "a1b2c", Role: k8s.RevisionActive, Pods: 2, ReadyPods: 2},
+					{Hash: "d3e4f", Role: k8s.RevisionPreview, Pods: 2, ReadyPods: 1},
+				},
+			},
+			want:     []string{"(2/2 replicas ready, BlueGreen, Healthy)", "   active a1b2c 2/2 ready · preview d3e4f 1/2 ready\n"},
+			unwanted: []string{"steps"},
+		},
+		{
+			name: "degraded with message",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "checkout", Namespace: "shop", Strategy: k8s.RolloutCanary, Phase: k8s.RolloutDegraded,
+				Message: "ProgressDeadlineExceeded: canary is not ready", Replicas: 2, ReadyReplicas: 1,
+			},
+			want: []string{"   [WARN] ProgressDeadlineExceeded: canary is not ready\n"},
+		},
+		{
+			name: "healthy message is not shown",
+			rollout: k8s.ArgoRolloutInfo{
+				Name: "checkout", Namespace: "shop", Strategy: k8s.RolloutCanary, Phase: k8s.RolloutHealthy,
+				Message: "rollout completed", Replicas: 1, ReadyReplicas: 1,
+			},
+			unwanted: []string{"rollout completed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := render(func(v *Visualizer) error { return v.DisplayArgoRollouts([]k8s.ArgoRolloutInfo{tt.rollout}) })
+			checkOutput(t, out, append([]string{"Argo Rollouts Overview (1 total)"}, tt.want...), tt.unwanted)
+		})
+	}
+}
+
+func TestRenderPodProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      k8s.PodInfo
+		want     []string
+		unwanted []string
+	}{
+		{
+			name:     "healthy",
+			pod:      k8s.PodInfo{Name: "web-1", Namespace: "shop", Status: "Running", ContainerCount: 1, ReadyContainers: 1},
+			want:     []string{"[ OK ] shop/web-1: # (1/1 containers ready)"},
+			unwanted: []string{"[WARN]", "[HINT]"},
+		},
+		{
+			name: "evicted",
+			pod: k8s.PodInfo{Name: "web-2", Namespace: "shop", Status: status.Evicted, ContainerCount: 1,
+				EvictionMessage: "The node was low on resource: memory."},
+			want: []string{"[EVIC] shop/web-2: . (0/1 containers ready)", "   [WARN] evicted: The node was low on resource: memory.\n"},
+		},
+		{
+			name: "unschedulable behind a taint",
+			pod: k8s.PodInfo{Name: "web-3", Namespace: "shop", Status: "Pending", ContainerCount: 1,
+				Unschedulable:  "0/3 nodes are available",
+				BlockingTaints: []analysis.TaintBlock{{Taint: "dedicated=gpu:NoSchedule", Nodes: []string{"node-a", "node-b"}}}},
+			want: []string{
+				"[WAIT] shop/web-3",
+				"   [WARN] unschedulable: 0/3 nodes are available\n",
+				"   [WARN] untolerated taint dedicated=gpu:NoSchedule on node-a, node-b\n",
+			},
+		},
+		{
+			name: "drifted and under node pressure, with a hint",
+			pod: k8s.PodInfo{Name: "web-4", Namespace: "shop", Status: "Running", ContainerCount: 2, ReadyContainers: 1,
+				NodeName: "node-a", NodePressure: []string{"MemoryPressure"},
+				GitOps: k8s.GitOpsStatus{Tool: k8s.GitOpsArgoCD, Source: "argocd/shop", Sync: k8s.SyncOutOfSync, Health: k8s.GitOpsDegraded},
+				Hints:  []analysis.Hint{{Cause: "OOMKilled", Container: "app", Message: "app was OOMKilled: raise its memory limit"}}},
+			want: []string{
+				"shop/web-4: #. (1/2 containers ready)",
+				"   [WARN] argocd argocd/shop: OutOfSync, Degraded\n",
+				"   [WARN] node node-a under MemoryPressure\n",
+				"   [HINT] app was OOMKilled: raise its memory limit\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := render(func(v *Visualizer) error { return v.DisplayPods([]k8s.PodInfo{tt.pod}) })
+			checkOutput(t, out, tt.want, tt.unwanted)
+		})
+	}
+}
+
+func TestRenderNodeProblems(t *testing.T) {
+	nodes := []k8s.NodeInfo{
+		{Name: "node-a", Ready: true},
+		{Name: "node-b", Ready: false},
+		{Name: "node-c", Ready: true, Pressure: []string{"DiskPressure", "PIDPressure"}},
+	}
+	out := render(func(v *Visualizer) error { return v.DisplayNodes(nodes) })
+	checkOutput(t, out, []string{
+		"Nodes Overview (3 total)",
+		"[ OK ] node-a\n",
+		"[FAIL] node-b\n",
+		"[ OK ] node-c\n   [WARN] DiskPressure, PIDPressure\n",
+	}, nil)
+
+	// Only the nodes needing attention are left for -problems-only
+	out = render(func(v *Visualizer) error { return v.DisplayNodes(k8s.Problems(nodes)) })
+	checkOutput(t, out, []string{"Nodes Overview (2 total)", "node-b", "node-c"}, []string{"node-a"})
+}
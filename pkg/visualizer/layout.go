@@ -0,0 +1,154 @@
+package visualizer
+
+import (
+	"math"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+const (
+	// defaultLineLength is the width assumed when none is configured
+	defaultLineLength = 80
+	// minNameWidth is the shortest a resource name is truncated to
+	minNameWidth = 16
+	// minBarWidth is how much of a long bar is kept before names are
+	// truncated to make room
+	minBarWidth = 10
+	// summaryBarWidth is the widest summary progress bar
+	summaryBarWidth = 50
+	// minSummaryBarWidth is the narrowest bar kept on the summary line;
+	// below it the bar wraps onto its own line
+	minSummaryBarWidth = 10
+)
+
+// TerminalWidth returns the column count of the terminal f is attached to,
+// or 0 when f is not a terminal
+func TerminalWidth(f *os.File) int {
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// WithWidth fits output to the given number of columns; 0 disables
+// truncation and wrapping, as suits output piped into a file or log
+func WithWidth(columns int) Option {
+	return func(v *Visualizer) { v.maxLineLength = columns }
+}
+
+// displayWidth approximates how many terminal cells s occupies: ANSI
+// sequences and variation selectors take none, emoji take two
+func displayWidth(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\033' {
+			// Skip an SGR sequence up to its final letter
+			j := i + 1
+			for j < len(s) && !(s[j] >= 'A' && s[j] <= 'Z' || s[j] >= 'a' && s[j] <= 'z') {
+				j++
+			}
+			i = j + 1
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == 0xFE0F || r == 0x200D:
+		case r >= 0x1F000, r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF, r == 0x23F3:
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
+
+// lineWidth returns the line width, or a width nothing exceeds when unlimited
+func (v *Visualizer) lineWidth() int {
+	if v.maxLineLength <= 0 {
+		return math.MaxInt
+	}
+	return v.maxLineLength
+}
+
+// truncate shortens plain text to at most width cells, marking the cut
+// with the theme's ellipsis
+func (v *Visualizer) truncate(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+
+	ellipsis := v.theme.Ellipsis
+	keep := width - displayWidth(ellipsis)
+	if keep <= 0 {
+		return ellipsis
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := displayWidth(string(r))
+		if used+w > keep {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + ellipsis
+}
+
+// fit sizes a "lead name: bar tail" line to the line width, returning the
+// name to print and the cells left for its bar. Long bars are scaled down
+// to minBarWidth first, then the name is truncated, down to minNameWidth.
+func (v *Visualizer) fit(lead, name string, barCells int, tail string) (string, int) {
+	if v.maxLineLength <= 0 {
+		return name, barCells
+	}
+
+	room := v.maxLineLength - displayWidth(lead) - len(" : ") - 1 - displayWidth(tail)
+	if displayWidth(name)+barCells <= room {
+		return name, barCells
+	}
+
+	name = v.truncate(name, max(room-min(barCells, minBarWidth), minNameWidth))
+	return name, max(min(barCells, room-displayWidth(name)), 1)
+}
+
+// scaledCounts maps ready of total items onto at most cells blocks. Partly
+// ready sets keep at least one filled and one empty block visible.
+func scaledCounts(ready, total, cells int) (int, int) {
+	if ready > total {
+		total = ready
+	}
+	if total <= cells {
+		return ready, total
+	}
+
+	filled := ready * cells / total
+	if ready > 0 && filled == 0 {
+		filled = 1
+	}
+	if ready < total && filled == cells {
+		filled = cells - 1
+	}
+	return filled, cells
+}
+
+// summaryLine writes "label [bar]", moving the bar onto its own line when
+// the terminal is too narrow for both
+func (v *Visualizer) summaryLine(label string, percentage float64) string {
+	width := summaryBarWidth
+	if v.maxLineLength > 0 {
+		room := v.maxLineLength - displayWidth(label) - len(" []")
+		if room < minSummaryBarWidth {
+			return label + "\n[" + v.percentBar(percentage, min(summaryBarWidth, max(v.maxLineLength-2, 1))) + "]"
+		}
+		width = min(width, room)
+	}
+	return label + " [" + v.percentBar(percentage, width) + "]"
+}
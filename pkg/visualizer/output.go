@@ -0,0 +1,47 @@
+package visualizer
+
+import (
+	"io"
+	"strings"
+)
+
+// WithOutput writes output to w instead of stdout
+func WithOutput(w io.Writer) Option {
+	return func(v *Visualizer) { v.out = w }
+}
+
+// errWriter remembers the first write error so a display method can write
+// many lines and check once at the end; later writes are skipped
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n, err := e.w.Write(p)
+	e.err = err
+	return n, err
+}
+
+// writer returns a fresh errWriter over the visualizer's output
+func (v *Visualizer) writer() *errWriter {
+	return &errWriter{w: v.out}
+}
+
+// Render returns what display writes as a string, for embedding the output
+// elsewhere. The visualizer's own output is left untouched.
+//
+//	text := viz.Render(func(v *visualizer.Visualizer) error {
+//		return v.DisplayPods(pods)
+//	})
+func (v *Visualizer) Render(display func(*Visualizer) error) string {
+	var b strings.Builder
+	rendered := *v
+	rendered.out = &b
+	// Writes to a strings.Builder cannot fail
+	_ = display(&rendered)
+	return b.String()
+}
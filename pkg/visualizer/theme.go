@@ -0,0 +1,197 @@
+package visualizer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"pod-visualizer/pkg/status"
+)
+
+// Theme is the set of characters and colors the visualizer draws with
+type Theme struct {
+	Name string
+
+	// Block and Empty draw the filled and unfilled parts of bars;
+	// InitBlock the init containers a pod has finished
+	Block     string
+	Empty     string
+	InitBlock string
+
+	// Symbols maps pod statuses to glyphs
+	Symbols status.Mapping
+
+	// Glyphs for other states and resources
+	OK       string
+	Pending  string
+	Failed   string
+	Warning  string
+	Hint     string
+	Unknown  string
+	Workload string
+	Schedule string
+
+	// Tree branches: a middle child, the last child, and the indents below each
+	Branch     string
+	LastBranch string
+	Pipe       string
+	Space      string
+
+	// Ellipsis marks names truncated to fit the terminal
+	Ellipsis string
+
+	Colors Palette
+}
+
+// Palette holds the ANSI sequences for each kind of state; an empty
+// sequence leaves text uncolored
+type Palette struct {
+	Good string
+	Warn string
+	Bad  string
+	Dim  string
+}
+
+// ansiPalette is the palette shared by the colored built-in themes
+var ansiPalette = Palette{Good: ansiGreen, Warn: ansiYellow, Bad: ansiRed, Dim: ansiDim}
+
+// asciiBranches are tree branches drawn without box-drawing characters
+var asciiBranches = [4]string{"|-- ", "`-- ", "|   ", "    "}
+
+// Built-in themes
+var (
+	// UnicodeTheme draws block characters, box-drawing trees and emoji
+	UnicodeTheme = Theme{
+		Name:       "unicode",
+		Block:      "█",
+		Empty:      "░",
+		InitBlock:  "▒",
+		Symbols:    status.DefaultMapping,
+		OK:         "✅",
+		Pending:    "⏳",
+		Failed:     "❌",
+		Warning:    "⚠️ ",
+		Hint:       "💡",
+		Unknown:    "❓",
+		Workload:   "📦",
+		Schedule:   "🕒",
+		Branch:     "├── ",
+		LastBranch: "└── ",
+		Pipe:       "│   ",
+		Space:      "    ",
+		Ellipsis:   "…",
+		Colors:     ansiPalette,
+	}
+
+	// ASCIITheme uses only printable ASCII, for terminals whose fonts lack
+	// block characters or emoji; colors are kept
+	ASCIITheme = Theme{
+		Name:      "ascii",
+		Block:     "#",
+		Empty:     ".",
+		InitBlock: "+",
+		Symbols: status.Mapping{
+			"running":          "[ OK ]",
+			"pending":          "[WAIT]",
+			"failed":           "[FAIL]",
+			"completed":        "[DONE]",
+			"terminating":      "[TERM]",
+			"crashloopbackoff": "[LOOP]",
+			"evicted":          "[EVIC]",
+			"init":             "[INIT]",
+		},
+		OK:         "[ OK ]",
+		Pending:    "[WAIT]",
+		Failed:     "[FAIL]",
+		Warning:    "[WARN]",
+		Hint:       "[HINT]",
+		Unknown:    "[ ?? ]",
+		Workload:   "[DEPL]",
+		Schedule:   "[CRON]",
+		Branch:     asciiBranches[0],
+		LastBranch: asciiBranches[1],
+		Pipe:       asciiBranches[2],
+		Space:      asciiBranches[3],
+		Ellipsis:   "...",
+		Colors:     ansiPalette,
+	}
+
+	// MinimalTheme is plain ASCII without colors, for CI logs and dumb
+	// terminals; only states that need attention are marked
+	MinimalTheme = Theme{
+		Name:      "minimal",
+		Block:     "#",
+		Empty:     "-",
+		InitBlock: "+",
+		Symbols: status.Mapping{
+			"running":          "-",
+			"pending":          "~",
+			"failed":           "!",
+			"completed":        "-",
+			"terminating":      "~",
+			"crashloopbackoff": "!",
+			"evicted":          "!",
+			"init":             "~",
+		},
+		OK:         "-",
+		Pending:    "~",
+		Failed:     "!",
+		Warning:    "!",
+		Hint:       "?",
+		Unknown:    "?",
+		Workload:   "*",
+		Schedule:   "@",
+		Branch:     asciiBranches[0],
+		LastBranch: asciiBranches[1],
+		Pipe:       asciiBranches[2],
+		Space:      asciiBranches[3],
+		Ellipsis:   "...",
+	}
+)
+
+// themes indexes the built-in themes by name
+var themes = map[string]Theme{
+	UnicodeTheme.Name: UnicodeTheme,
+	ASCIITheme.Name:   ASCIITheme,
+	MinimalTheme.Name: MinimalTheme,
+}
+
+// ThemeNames lists the built-in theme names
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTheme returns the built-in theme with the given name
+func LookupTheme(name string) (Theme, error) {
+	theme, ok := themes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q: expected one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
+
+// Option configures a Visualizer
+type Option func(*Visualizer)
+
+// WithTheme draws with the given theme, including its status glyphs
+func WithTheme(theme Theme) Option {
+	return func(v *Visualizer) {
+		v.theme = theme
+		v.symbols = theme.Symbols
+	}
+}
+
+// WithStatusSymbols overrides the theme's status glyphs
+func WithStatusSymbols(symbols status.Mapping) Option {
+	return func(v *Visualizer) { v.symbols = symbols }
+}
+
+// WithColor turns ANSI color output on or off
+func WithColor(enabled bool) Option {
+	return func(v *Visualizer) { v.color = enabled }
+}
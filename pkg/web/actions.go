@@ -0,0 +1,541 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+const (
+	// portForwardTTL is how long a port-forward stays open unless closed
+	portForwardTTL = 30 * time.Minute
+	// maxPortForwards bounds the port-forwards open at once
+	maxPortForwards = 20
+	// portForwardAddress is where forwarded ports listen on the server;
+	// loopback only, so a forward is never exposed beyond the host
+	portForwardAddress = "127.0.0.1"
+)
+
+// errActionsDisabled is returned for pod actions unless -enable-actions is set
+var errActionsDisabled = errors.New("pod actions are disabled; start the server with -enable-actions")
+
+// execUpgrader upgrades exec sessions. Unlike the read-only /ws stream it
+// keeps gorilla's same-origin check, so another site cannot open a shell
+// with the dashboard's cookie.
+var execUpgrader = websocket.Upgrader{}
+
+// actionPermission is the access an action needs from the server's
+// identity
+type actionPermission struct {
+	verb, group, resource, subresource string
+}
+
+// actionPermissions maps each action to the access it needs
+var actionPermissions = map[string]actionPermission{
+	"exec":        {"create", "", "pods", "exec"},
+	"portforward": {"create", "", "pods", "portforward"},
+	"delete":      {"delete", "", "pods", ""},
+	"restart":     {"patch", "apps", "deployments", ""},
+	"scale":       {"update", "apps", "deployments", "scale"},
+}
+
+// SetActions enables the write-mode endpoints below /api/pods/ and
+// /api/deployments/: exec, port-forward, pod deletion, rollout restart and
+// scaling.
+// They act with the server's own service account, so they are refused
+// unless dashboard authentication is configured, and each use is audited
+// with the dashboard user who asked for it.
+func (s *Server) SetActions(enabled bool) error {
+	if enabled && s.authMode() == AuthNone {
+		return fmt.Errorf("pod actions require dashboard authentication (-auth-mode token, basic or oidc)")
+	}
+	s.actions = enabled
+	return nil
+}
+
+// objectAction splits "{prefix}{namespace}/{name}/{action}"
+func objectAction(path, prefix string) (namespace, name, action string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// handlePodAction serves /api/pods/{namespace}/{name}/{action}
+func (s *Server) handlePodAction(w http.ResponseWriter, r *http.Request, namespace, name, action string) {
+	if !s.allowAction(w, r) {
+		return
+	}
+
+	switch action {
+	case "exec":
+		s.handleExec(w, r, namespace, name)
+	case "portforward":
+		s.handlePortForward(w, r, namespace, name)
+	case "delete":
+		s.handlePodDelete(w, r, namespace, name)
+	default:
+		http.Error(w, fmt.Sprintf("Unknown pod action %q", action), http.StatusNotFound)
+	}
+}
+
+// handleDeploymentAction serves /api/deployments/{namespace}/{name}/{action}
+func (s *Server) handleDeploymentAction(w http.ResponseWriter, r *http.Request, namespace, name, action string) {
+	if !s.allowAction(w, r) {
+		return
+	}
+
+	switch action {
+	case "restart":
+		s.handleDeploymentRestart(w, r, namespace, name)
+	case "scale":
+		s.handleDeploymentScale(w, r, namespace, name)
+	default:
+		http.Error(w, fmt.Sprintf("Unknown deployment action %q", action), http.StatusNotFound)
+	}
+}
+
+// allowAction checks that actions are enabled and that a request which
+// changes something comes from the dashboard's own origin
+func (s *Server) allowAction(w http.ResponseWriter, r *http.Request) bool {
+	if !s.actions {
+		http.Error(w, errActionsDisabled.Error(), http.StatusNotFound)
+		return false
+	}
+	if r.Method != http.MethodGet && !sameOrigin(r) {
+		http.Error(w, "Cross-origin action requests are not allowed", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// authorize checks with a SelfSubjectAccessReview that the server's
+// identity has the access the action needs, writing and auditing the
+// error if not
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, action, kind, namespace, name string) bool {
+	permission := actionPermissions[action]
+	allowed, err := s.client.CanI(r.Context(), namespace, permission.verb, permission.group, permission.resource, permission.subresource, name)
+	if err != nil {
+		audit(r, action, kind, namespace, name, err)
+		http.Error(w, fmt.Sprintf("Failed to check access: %v", err), http.StatusInternalServerError)
+		return false
+	}
+	if !allowed {
+		audit(r, action, kind, namespace, name, errActionDenied)
+		resource := permission.resource
+		if permission.subresource != "" {
+			resource += "/" + permission.subresource
+		}
+		http.Error(w, fmt.Sprintf("Not permitted to %s %s in namespace %s", permission.verb, resource, namespace), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// requirePost answers 405 unless the request is a POST
+func requirePost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodPost {
+		return true
+	}
+	w.Header().Set("Allow", http.MethodPost)
+	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// writeActionError maps a failed action's error to a status code
+func writeActionError(w http.ResponseWriter, err error) {
+	code := http.StatusBadGateway
+	switch {
+	case apierrors.IsNotFound(err):
+		code = http.StatusNotFound
+	case apierrors.IsForbidden(err):
+		code = http.StatusForbidden
+	case apierrors.IsConflict(err), apierrors.IsInvalid(err):
+		code = http.StatusConflict
+	}
+	http.Error(w, err.Error(), code)
+}
+
+// handlePodDelete deletes a pod on POST, leaving its controller, if any,
+// to replace it
+func (s *Server) handlePodDelete(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if !requirePost(w, r) || !s.authorize(w, r, "delete", "Pod", namespace, name) {
+		return
+	}
+
+	err := s.client.DeletePod(r.Context(), namespace, name)
+	audit(r, "delete", "Pod", namespace, name, err)
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleDeploymentRestart rolls every pod of a deployment on POST, like
+// kubectl rollout restart
+func (s *Server) handleDeploymentRestart(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if !requirePost(w, r) || !s.authorize(w, r, "restart", "Deployment", namespace, name) {
+		return
+	}
+
+	err := s.client.RestartDeployment(r.Context(), namespace, name, time.Now())
+	audit(r, "restart", "Deployment", namespace, name, err)
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ScaleRequest is the body of POST /api/deployments/{namespace}/{name}/scale
+type ScaleRequest struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+// ScaleData represents a scaled deployment's replicas for JSON response
+type ScaleData struct {
+	Replicas         int32 `json:"replicas"`
+	PreviousReplicas int32 `json:"previousReplicas"`
+}
+
+// handleDeploymentScale sets a deployment's desired replicas on POST
+// {"replicas": 3}, through the scale subresource
+func (s *Server) handleDeploymentScale(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if !requirePost(w, r) {
+		return
+	}
+	var body ScaleRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1024)).Decode(&body); err != nil || body.Replicas == nil || *body.Replicas < 0 {
+		http.Error(w, `Expected a body like {"replicas": 3}`, http.StatusBadRequest)
+		return
+	}
+	if !s.authorize(w, r, "scale", "Deployment", namespace, name) {
+		return
+	}
+
+	previous, err := s.client.ScaleDeployment(r.Context(), namespace, name, *body.Replicas)
+	audit(r, "scale", "Deployment", namespace, name, err, "replicas", *body.Replicas, "previous_replicas", previous)
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ScaleData{Replicas: *body.Replicas, PreviousReplicas: previous})
+}
+
+// execControl is a text frame on an exec session. The client sends
+// "resize" messages; the server ends the session with an "exit" message.
+type execControl struct {
+	Type  string `json:"type"`
+	Cols  uint16 `json:"cols,omitempty"`
+	Rows  uint16 `json:"rows,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// handleExec runs a command in a pod over a WebSocket, like kubectl exec:
+// binary frames carry stdin and output, text frames carry execControl.
+// ?container= picks the container, ?command= (repeatable, default sh) the
+// command and ?tty=false disables the terminal.
+func (s *Server) handleExec(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	query := r.URL.Query()
+	command := query["command"]
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+	tty := query.Get("tty") != "false"
+
+	if !s.authorize(w, r, "exec", "Pod", namespace, name) {
+		return
+	}
+
+	conn, err := execUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		requestLogger(r).Warn("Exec WebSocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	audit(r, "exec", "Pod", namespace, name, nil, "container", query.Get("container"), "command", strings.Join(command, " "))
+	logger := requestLogger(r).With("pod", namespace+"/"+name, "container", query.Get("container"), "command", strings.Join(command, " "))
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stdin, stdinWriter := io.Pipe()
+	resize := make(chan k8s.TerminalSize, 1)
+	go func() {
+		defer cancel()
+		defer stdinWriter.Close()
+		defer close(resize)
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			switch messageType {
+			case websocket.BinaryMessage:
+				if _, err := stdinWriter.Write(data); err != nil {
+					return
+				}
+			case websocket.TextMessage:
+				var control execControl
+				if json.Unmarshal(data, &control) == nil && control.Type == "resize" {
+					// Only the latest size matters
+					select {
+					case <-resize:
+					default:
+					}
+					resize <- k8s.TerminalSize{Width: control.Cols, Height: control.Rows}
+				}
+			}
+		}
+	}()
+
+	output := &wsOutput{conn: conn}
+	err = s.client.ExecPod(ctx, namespace, name, k8s.ExecOptions{
+		Container: query.Get("container"),
+		Command:   command,
+		Stdin:     stdin,
+		Stdout:    output,
+		Stderr:    output,
+		TTY:       tty,
+		Resize:    resize,
+	})
+	// Unblock the reader if it is waiting to hand over more input
+	stdin.Close()
+
+	exit := execControl{Type: "exit"}
+	if err != nil && ctx.Err() == nil {
+		exit.Error = err.Error()
+	}
+	output.writeJSON(exit)
+	logger.Info("Exec session ended", "error", exit.Error)
+}
+
+// wsOutput writes exec output to a WebSocket as binary frames; stdout and
+// stderr may write concurrently
+type wsOutput struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+// Write sends p as one binary frame
+func (o *wsOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeJSON sends v as a text frame
+func (o *wsOutput) writeJSON(v any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.conn.WriteJSON(v)
+}
+
+// PortForwardRequest is the body of POST /api/pods/{namespace}/{name}/portforward
+type PortForwardRequest struct {
+	Port int `json:"port"`
+}
+
+// PortForwardData represents an open port-forward for JSON response
+type PortForwardData struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Address   string    `json:"address"`
+	LocalPort int       `json:"localPort"`
+	PodPort   int       `json:"podPort"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// portForwards tracks the open port-forwards by ID
+type portForwards struct {
+	mu   sync.Mutex
+	byID map[string]*activeForward
+}
+
+// activeForward is an open port-forward and when it expires
+type activeForward struct {
+	forward *k8s.PortForward
+	data    PortForwardData
+}
+
+// newPortForwards creates an empty registry
+func newPortForwards() *portForwards {
+	return &portForwards{byID: make(map[string]*activeForward)}
+}
+
+// add registers a forward, closing it after portForwardTTL or when it
+// stops by itself
+func (p *portForwards) add(namespace, pod string, forward *k8s.PortForward) (PortForwardData, error) {
+	id, err := randomID()
+	if err != nil {
+		return PortForwardData{}, err
+	}
+
+	active := &activeForward{
+		forward: forward,
+		data: PortForwardData{
+			ID:        id,
+			Namespace: namespace,
+			Pod:       pod,
+			Address:   forward.Address,
+			LocalPort: forward.LocalPort,
+			PodPort:   forward.PodPort,
+			ExpiresAt: time.Now().Add(portForwardTTL).UTC(),
+		},
+	}
+
+	p.mu.Lock()
+	p.byID[id] = active
+	p.mu.Unlock()
+
+	go func() {
+		timer := time.NewTimer(portForwardTTL)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-forward.Done():
+		}
+		p.close(id)
+	}()
+
+	return active.data, nil
+}
+
+// count returns how many forwards are open
+func (p *portForwards) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.byID)
+}
+
+// list returns the pod's open forwards
+func (p *portForwards) list(namespace, pod string) []PortForwardData {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	forwards := []PortForwardData{}
+	for _, active := range p.byID {
+		if active.data.Namespace == namespace && active.data.Pod == pod {
+			forwards = append(forwards, active.data)
+		}
+	}
+	return forwards
+}
+
+// forwardsTo reports whether forward id is open to the pod
+func (p *portForwards) forwardsTo(id, namespace, pod string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	active, ok := p.byID[id]
+	return ok && active.data.Namespace == namespace && active.data.Pod == pod
+}
+
+// close stops a forward, reporting whether it was open
+func (p *portForwards) close(id string) bool {
+	p.mu.Lock()
+	active, ok := p.byID[id]
+	delete(p.byID, id)
+	p.mu.Unlock()
+
+	if ok {
+		active.forward.Close()
+	}
+	return ok
+}
+
+// closeAll stops every forward, for shutdown
+func (p *portForwards) closeAll() {
+	p.mu.Lock()
+	ids := make([]string, 0, len(p.byID))
+	for id := range p.byID {
+		ids = append(ids, id)
+	}
+	p.mu.Unlock()
+
+	for _, id := range ids {
+		p.close(id)
+	}
+}
+
+// randomID returns a random hex identifier
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// handlePortForward lists (GET), opens (POST {"port": 8080}) or closes
+// (DELETE ?id=) port-forwards to a pod. Forwarded ports listen on the
+// server's loopback interface and close after portForwardTTL.
+func (s *Server) handlePortForward(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.forwards.list(namespace, name))
+
+	case http.MethodPost:
+		var body PortForwardRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1024)).Decode(&body); err != nil || body.Port < 1 || body.Port > 65535 {
+			http.Error(w, `Expected a body like {"port": 8080}`, http.StatusBadRequest)
+			return
+		}
+		if s.forwards.count() >= maxPortForwards {
+			http.Error(w, fmt.Sprintf("Too many open port-forwards (max %d)", maxPortForwards), http.StatusTooManyRequests)
+			return
+		}
+		if !s.authorize(w, r, "portforward", "Pod", namespace, name) {
+			return
+		}
+
+		forward, err := s.client.PortForwardPod(r.Context(), namespace, name, body.Port, portForwardAddress)
+		if err != nil {
+			audit(r, "portforward", "Pod", namespace, name, err, "pod_port", body.Port)
+			http.Error(w, fmt.Sprintf("Failed to port-forward: %v", err), http.StatusBadGateway)
+			return
+		}
+		data, err := s.forwards.add(namespace, name, forward)
+		if err != nil {
+			forward.Close()
+			http.Error(w, fmt.Sprintf("Failed to port-forward: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		audit(r, "portforward", "Pod", namespace, name, nil, "pod_port", data.PodPort, "local_port", data.LocalPort, "id", data.ID)
+		writeJSON(w, http.StatusCreated, data)
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if !s.forwards.forwardsTo(id, namespace, name) || !s.forwards.close(id) {
+			http.Error(w, fmt.Sprintf("No open port-forward %q", id), http.StatusNotFound)
+			return
+		}
+		audit(r, "portforward-close", "Pod", namespace, name, nil, "id", id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPost, http.MethodDelete}, ", "))
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"pod-visualizer/pkg/k8s/fake"
+)
+
+// newActionServer returns a token-authenticated server with actions
+// enabled over a cluster holding the pod shop/web, whose access reviews
+// answer allowed
+func newActionServer(t *testing.T, allowed bool) (*Server, *corev1.Pod) {
+	t.Helper()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"}}
+	client, clientset := fake.NewClient(pod)
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+
+	s := NewServer(client, 0)
+	if err := s.SetAuth(context.Background(), AuthConfig{Mode: AuthToken, Token: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetActions(true); err != nil {
+		t.Fatal(err)
+	}
+	return s, pod
+}
+
+// actionRequest is an authenticated request for pod shop/web's action
+func actionRequest(method, action, origin string) *http.Request {
+	req := httptest.NewRequest(method, "http://dashboard.example.com/api/v1/pods/shop/web/"+action, nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	return req
+}
+
+func TestSetActionsRequiresAuth(t *testing.T) {
+	if err := newTestServer().SetActions(true); err == nil {
+		t.Error("SetActions(true) error = nil without authentication")
+	}
+}
+
+func TestAllowAction(t *testing.T) {
+	tests := []struct {
+		name        string
+		disabled    bool
+		denied      bool
+		cors        []string
+		method      string
+		origin      string
+		want        int
+		wantDeleted bool
+	}{
+		{name: "actions disabled", disabled: true, method: http.MethodPost, want: http.StatusNotFound},
+		{name: "no origin", method: http.MethodPost, want: http.StatusAccepted, wantDeleted: true},
+		{name: "same origin", method: http.MethodPost, origin: "http://dashboard.example.com", want: http.StatusAccepted, wantDeleted: true},
+		{name: "cross origin", method: http.MethodPost, origin: "https://evil.example.com", want: http.StatusForbidden},
+		{
+			name:   "cross origin allowed by CORS",
+			cors:   []string{"https://grafana.example.com"},
+			method: http.MethodPost,
+			origin: "https://grafana.example.com",
+			want:   http.StatusForbidden,
+		},
+		{name: "not a POST", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{name: "server identity may not delete", denied: true, method: http.MethodPost, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, pod := newActionServer(t, !tt.denied)
+			if tt.disabled {
+				if err := s.SetActions(false); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.cors != nil {
+				s.SetCORSOrigins(tt.cors)
+			}
+
+			rec := serve(s, actionRequest(tt.method, "delete", tt.origin))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, strings.TrimSpace(rec.Body.String()))
+			}
+
+			_, err := s.client.GetPod(context.Background(), pod.Namespace, pod.Name)
+			if deleted := err != nil; deleted != tt.wantDeleted {
+				t.Errorf("pod deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestDeploymentScaleBody(t *testing.T) {
+	s, _ := newActionServer(t, true)
+	for _, body := range []string{"", "{}", `{"replicas": -1}`, "not json"} {
+		req := httptest.NewRequest(http.MethodPost, "http://dashboard.example.com/api/v1/deployments/shop/web/scale", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer s3cret")
+		if rec := serve(s, req); rec.Code != http.StatusBadRequest {
+			t.Errorf("scale with body %q: status = %d, want %d", body, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package web
+
+import (
+	"pod-visualizer/pkg/alerts"
+	"pod-visualizer/pkg/k8s"
+)
+
+// SetAlerts enables alerting: every changed snapshot is offered to the
+// evaluator, which runs alongside the server and notifies its webhooks
+func (s *Server) SetAlerts(evaluator *alerts.Evaluator) {
+	s.alerts = evaluator
+}
+
+// toAlertSnapshot converts cluster data to the state alert rules see.
+// Completed pods count as ready, as they do for problemsOnly.
+func toAlertSnapshot(data ClusterData) alerts.Snapshot {
+	snapshot := alerts.Snapshot{
+		Pods:        make([]alerts.Pod, len(data.Pods)),
+		Deployments: make([]alerts.Deployment, len(data.Deployments)),
+		Nodes:       make([]alerts.Node, len(data.Nodes)),
+	}
+	for i, pod := range data.Pods {
+		snapshot.Pods[i] = alerts.Pod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Status:    pod.Status,
+			Ready:     !k8s.PodNeedsAttention(pod.Status, pod.ReadyContainers, pod.ContainerCount),
+		}
+	}
+	for i, deployment := range data.Deployments {
+		snapshot.Deployments[i] = alerts.Deployment{
+			Namespace:     deployment.Namespace,
+			Name:          deployment.Name,
+			Replicas:      deployment.Replicas,
+			ReadyReplicas: deployment.ReadyReplicas,
+		}
+	}
+	for i, node := range data.Nodes {
+		snapshot.Nodes[i] = alerts.Node{Name: node.Name, Ready: node.Ready}
+	}
+	return snapshot
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// kindArgoRollouts is reported unavailable when Argo Rollouts are
+// installed but fail to list
+const kindArgoRollouts = "rollouts"
+
+// ArgoRolloutData represents an Argo Rollout for JSON response
+type ArgoRolloutData struct {
+	UID             string `json:"uid"`
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Strategy        string `json:"strategy"`
+	Phase           string `json:"phase"`
+	Message         string `json:"message,omitempty"`
+	Replicas        int32  `json:"replicas"`
+	ReadyReplicas   int32  `json:"readyReplicas"`
+	UpdatedReplicas int32  `json:"updatedReplicas"`
+
+	// Step is the index of the canary step being run out of Steps, equal
+	// to Steps once all have run; Weight is the canary's traffic share
+	Step     int32  `json:"step"`
+	Steps    int32  `json:"steps"`
+	StepName string `json:"stepName,omitempty"`
+	Weight   int32  `json:"weight"`
+	Paused   bool   `json:"paused,omitempty"`
+	Aborted  bool   `json:"aborted,omitempty"`
+
+	StableHash  string                    `json:"stableHash,omitempty"`
+	CurrentHash string                    `json:"currentHash,omitempty"`
+	ActiveHash  string                    `json:"activeHash,omitempty"`
+	PreviewHash string                    `json:"previewHash,omitempty"`
+	Revisions   []ArgoRolloutRevisionData `json:"revisions"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ArgoRolloutRevisionData counts a rollout's pods of one pod template hash
+type ArgoRolloutRevisionData struct {
+	Hash      string `json:"hash"`
+	Role      string `json:"role,omitempty"`
+	Pods      int    `json:"pods"`
+	ReadyPods int    `json:"readyPods"`
+}
+
+// NeedsAttention reports whether the rollout is degraded or was aborted
+func (r ArgoRolloutData) NeedsAttention() bool {
+	return r.Phase == k8s.RolloutDegraded || r.Aborted
+}
+
+// toArgoRolloutData converts rollouts to their response format
+func toArgoRolloutData(rollouts []k8s.ArgoRolloutInfo) []ArgoRolloutData {
+	data := make([]ArgoRolloutData, len(rollouts))
+	for i, rollout := range rollouts {
+		revisions := make([]ArgoRolloutRevisionData, len(rollout.Revisions))
+		for j, revision := range rollout.Revisions {
+			revisions[j] = ArgoRolloutRevisionData{
+				Hash:      revision.Hash,
+				Role:      revision.Role,
+				Pods:      revision.Pods,
+				ReadyPods: revision.ReadyPods,
+			}
+		}
+
+		data[i] = ArgoRolloutData{
+			UID:             rollout.UID,
+			Name:            rollout.Name,
+			Namespace:       rollout.Namespace,
+			Strategy:        rollout.Strategy,
+			Phase:           rollout.Phase,
+			Message:         rollout.Message,
+			Replicas:        rollout.Replicas,
+			ReadyReplicas:   rollout.ReadyReplicas,
+			UpdatedReplicas: rollout.UpdatedReplicas,
+			Step:            rollout.Step,
+			Steps:           rollout.Steps,
+			StepName:        rollout.StepName,
+			Weight:          rollout.Weight,
+			Paused:          rollout.Paused,
+			Aborted:         rollout.Aborted,
+			StableHash:      rollout.StableHash,
+			CurrentHash:     rollout.CurrentHash,
+			ActiveHash:      rollout.ActiveHash,
+			PreviewHash:     rollout.PreviewHash,
+			Revisions:       revisions,
+			CreatedAt:       rollout.CreatedAt.UTC(),
+		}
+	}
+	return data
+}
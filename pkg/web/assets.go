@@ -0,0 +1,113 @@
+package web
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"os"
+)
+
+// staticFiles holds the frontend bundle compiled into the binary
+//
+//go:embed static
+var staticFiles embed.FS
+
+// uiTitle is the dashboard title reported to the frontend
+const uiTitle = "Pod Visualizer"
+
+// UIConfig is the bootstrap configuration fetched by the frontend on load
+type UIConfig struct {
+	Title            string          `json:"title"`
+	Version          string          `json:"version"`
+	DefaultNamespace string          `json:"defaultNamespace"`
+	Features         map[string]bool `json:"features"`
+	AuthMode         string          `json:"authMode"`
+
+	// Actions is set when the write-mode pod endpoints are enabled
+	Actions bool `json:"actions"`
+}
+
+// staticFS returns the embedded static directory
+func staticFS() fs.FS {
+	sub, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		// The embed directive guarantees the directory exists
+		panic(err)
+	}
+	return sub
+}
+
+// bundleVersion returns a short content hash of the embedded frontend,
+// used as an ETag so browsers revalidate assets after an upgrade
+func bundleVersion() string {
+	hash := sha256.New()
+	fs.WalkDir(staticFiles, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := staticFiles.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hash.Write([]byte(path))
+		hash.Write(content)
+		return nil
+	})
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// handleStatic serves the embedded frontend assets
+func (s *Server) handleStatic() http.Handler {
+	fileServer := http.FileServer(http.FS(staticFS()))
+	etag := `"` + s.version + `"`
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// handleIndex serves the single-page frontend
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	content, err := fs.ReadFile(staticFS(), "index.html")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(content)
+}
+
+// handleUIConfig serves the frontend bootstrap configuration
+func (s *Server) handleUIConfig(w http.ResponseWriter, r *http.Request) {
+	features := make(map[string]bool, len(s.kinds))
+	for kind, enabled := range s.kinds {
+		features[kind] = enabled
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	json.NewEncoder(w).Encode(UIConfig{
+		Title:            uiTitle,
+		Version:          s.version,
+		DefaultNamespace: os.Getenv("DEFAULT_NAMESPACE"),
+		Features:         features,
+		AuthMode:         s.authMode(),
+		Actions:          s.actions,
+	})
+}
@@ -0,0 +1,212 @@
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Authentication modes
+const (
+	AuthNone   = "none"
+	AuthToken  = "token"
+	AuthBasic  = "basic"
+	AuthOIDC   = "oidc"
+	authRealm  = "pod-visualizer"
+	authCookie = "pod_visualizer_token"
+)
+
+// AuthConfig configures dashboard authentication. Token is used by the
+// token mode, Username and Password by basic, and the OIDC fields by oidc,
+// which accepts ID tokens issued by OIDCIssuerURL for OIDCClientID.
+type AuthConfig struct {
+	Mode          string
+	Token         string
+	Username      string
+	Password      string
+	OIDCIssuerURL string
+	OIDCClientID  string
+}
+
+// authenticator checks credentials on incoming requests
+type authenticator struct {
+	config   AuthConfig
+	verifier *oidc.IDTokenVerifier
+}
+
+// newAuthenticator validates the config and prepares the authenticator.
+// In oidc mode the issuer's discovery document is fetched once here.
+func newAuthenticator(ctx context.Context, config AuthConfig) (*authenticator, error) {
+	if config.Mode == "" {
+		config.Mode = AuthNone
+	}
+
+	a := &authenticator{config: config}
+	switch config.Mode {
+	case AuthNone:
+	case AuthToken:
+		if config.Token == "" {
+			return nil, fmt.Errorf("token auth requires a token")
+		}
+	case AuthBasic:
+		if config.Username == "" || config.Password == "" {
+			return nil, fmt.Errorf("basic auth requires a username and password")
+		}
+	case AuthOIDC:
+		if config.OIDCIssuerURL == "" || config.OIDCClientID == "" {
+			return nil, fmt.Errorf("oidc auth requires an issuer URL and client ID")
+		}
+		provider, err := oidc.NewProvider(ctx, config.OIDCIssuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover OIDC issuer: %v", err)
+		}
+		a.verifier = provider.Verifier(&oidc.Config{ClientID: config.OIDCClientID})
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q, expected one of: %s", config.Mode,
+			strings.Join([]string{AuthNone, AuthToken, AuthBasic, AuthOIDC}, ", "))
+	}
+
+	return a, nil
+}
+
+// SetAuth enables authentication on the index page, /api/* and /ws
+func (s *Server) SetAuth(ctx context.Context, config AuthConfig) error {
+	a, err := newAuthenticator(ctx, config)
+	if err != nil {
+		return err
+	}
+	s.auth = a
+	return nil
+}
+
+// authMode returns the active authentication mode
+func (s *Server) authMode() string {
+	if s.auth == nil {
+		return AuthNone
+	}
+	return s.auth.config.Mode
+}
+
+// userKey is the context key for the authenticated user
+type userKey struct{}
+
+// requestUser returns who made the request, as found by requireAuth: the
+// basic auth username, the OIDC email or subject, or "token" for the shared
+// token. It is empty without authentication.
+func requestUser(ctx context.Context) string {
+	user, _ := ctx.Value(userKey{}).(string)
+	return user
+}
+
+// requireAuth wraps a handler so it only runs for authenticated requests
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.auth == nil {
+			next(w, r)
+			return
+		}
+		if user, ok := s.auth.authenticate(w, r); ok {
+			next(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+			return
+		}
+
+		if s.auth.config.Mode == AuthBasic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+authRealm+`"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+authRealm+`"`)
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+}
+
+// authenticate reports whether the request carries valid credentials and
+// who they identify
+func (a *authenticator) authenticate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	switch a.config.Mode {
+	case AuthNone:
+		return "", true
+
+	case AuthBasic:
+		username, password, ok := r.BasicAuth()
+		if !ok || !secureEqual(username, a.config.Username) || !secureEqual(password, a.config.Password) {
+			return "", false
+		}
+		return username, true
+
+	case AuthToken, AuthOIDC:
+		token, fromQuery := bearerToken(r)
+		if token == "" {
+			return "", false
+		}
+		user, ok := a.tokenUser(r.Context(), token)
+		if !ok {
+			return "", false
+		}
+
+		// Browsers cannot set headers on page loads or WebSocket upgrades,
+		// so a token passed as ?access_token= is kept in a cookie for the
+		// requests the frontend makes afterwards
+		if fromQuery {
+			http.SetCookie(w, &http.Cookie{
+				Name:     authCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+		return user, true
+	}
+
+	return "", false
+}
+
+// tokenUser checks a bearer token against the configured token or issuer.
+// The shared token identifies no one in particular; an ID token names its
+// email, or its subject when it carries none.
+func (a *authenticator) tokenUser(ctx context.Context, token string) (string, bool) {
+	if a.config.Mode == AuthToken {
+		return AuthToken, secureEqual(token, a.config.Token)
+	}
+
+	idToken, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		return "", false
+	}
+	var claims struct {
+		Email string `json:"email"`
+	}
+	if err := idToken.Claims(&claims); err == nil && claims.Email != "" {
+		return claims.Email, true
+	}
+	return idToken.Subject, true
+}
+
+// bearerToken extracts a token from the Authorization header, the
+// access_token query parameter or the auth cookie, reporting whether it
+// came from the query
+func bearerToken(r *http.Request) (string, bool) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token), false
+		}
+	}
+	if token := r.URL.Query().Get("access_token"); token != "" {
+		return token, true
+	}
+	if cookie, err := r.Cookie(authCookie); err == nil && cookie.Value != "" {
+		return cookie.Value, false
+	}
+	return "", false
+}
+
+// secureEqual compares secrets in constant time
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,168 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireAuth(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    AuthConfig
+		prepare   func(req *http.Request)
+		path      string
+		want      int
+		challenge string
+	}{
+		{
+			name:   "no auth",
+			config: AuthConfig{Mode: AuthNone},
+			want:   http.StatusOK,
+		},
+		{
+			name:      "token missing",
+			config:    AuthConfig{Mode: AuthToken, Token: "s3cret"},
+			want:      http.StatusUnauthorized,
+			challenge: `Bearer realm="pod-visualizer"`,
+		},
+		{
+			name:    "token in header",
+			config:  AuthConfig{Mode: AuthToken, Token: "s3cret"},
+			prepare: func(req *http.Request) { req.Header.Set("Authorization", "Bearer s3cret") },
+			want:    http.StatusOK,
+		},
+		{
+			name:      "wrong token",
+			config:    AuthConfig{Mode: AuthToken, Token: "s3cret"},
+			prepare:   func(req *http.Request) { req.Header.Set("Authorization", "Bearer guess") },
+			want:      http.StatusUnauthorized,
+			challenge: `Bearer realm="pod-visualizer"`,
+		},
+		{
+			name:   "token in query",
+			config: AuthConfig{Mode: AuthToken, Token: "s3cret"},
+			path:   "/api/v1/namespaces?access_token=s3cret",
+			want:   http.StatusOK,
+		},
+		{
+			name:    "token in cookie",
+			config:  AuthConfig{Mode: AuthToken, Token: "s3cret"},
+			prepare: func(req *http.Request) { req.AddCookie(&http.Cookie{Name: authCookie, Value: "s3cret"}) },
+			want:    http.StatusOK,
+		},
+		{
+			name:    "basic",
+			config:  AuthConfig{Mode: AuthBasic, Username: "admin", Password: "pw"},
+			prepare: func(req *http.Request) { req.SetBasicAuth("admin", "pw") },
+			want:    http.StatusOK,
+		},
+		{
+			name:      "basic with wrong password",
+			config:    AuthConfig{Mode: AuthBasic, Username: "admin", Password: "pw"},
+			prepare:   func(req *http.Request) { req.SetBasicAuth("admin", "guess") },
+			want:      http.StatusUnauthorized,
+			challenge: `Basic realm="pod-visualizer"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer()
+			if err := s.SetAuth(context.Background(), tt.config); err != nil {
+				t.Fatalf("SetAuth() error = %v", err)
+			}
+			path := tt.path
+			if path == "" {
+				path = "/api/v1/namespaces"
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.prepare != nil {
+				tt.prepare(req)
+			}
+
+			rec := serve(s, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got != tt.challenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestAccessTokenCookie(t *testing.T) {
+	s := newTestServer()
+	if err := s.SetAuth(context.Background(), AuthConfig{Mode: AuthToken, Token: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A token passed in the query is kept in a cookie for later requests
+	rec := serve(s, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces?access_token=s3cret", nil))
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == authCookie {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		t.Fatal("no auth cookie set for ?access_token=")
+	}
+	if cookie.Value != "s3cret" || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode || cookie.Path != "/" {
+		t.Errorf("cookie = %+v, want the token, HttpOnly, SameSite=Strict and Path=/", cookie)
+	}
+
+	// Header and cookie credentials are not copied into a new cookie
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+	req.AddCookie(cookie)
+	if rec := serve(s, req); len(rec.Result().Cookies()) != 0 {
+		t.Errorf("cookie set again for a cookie request: %v", rec.Result().Cookies())
+	}
+
+	// A wrong token in the query sets nothing
+	rec = serve(s, httptest.NewRequest(http.MethodGet, "/api/v1/namespaces?access_token=guess", nil))
+	if rec.Code != http.StatusUnauthorized || len(rec.Result().Cookies()) != 0 {
+		t.Errorf("wrong query token: status %d, cookies %v", rec.Code, rec.Result().Cookies())
+	}
+}
+
+func TestRequestUser(t *testing.T) {
+	s := newTestServer()
+	if err := s.SetAuth(context.Background(), AuthConfig{Mode: AuthBasic, Username: "admin", Password: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+	var user string
+	handler := s.requireAuth(func(w http.ResponseWriter, r *http.Request) { user = requestUser(r.Context()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("admin", "pw")
+	handler(httptest.NewRecorder(), req)
+	if user != "admin" {
+		t.Errorf("requestUser() = %q, want admin", user)
+	}
+}
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config AuthConfig
+		want   string
+	}{
+		{name: "token without a token", config: AuthConfig{Mode: AuthToken}, want: "requires a token"},
+		{name: "basic without a password", config: AuthConfig{Mode: AuthBasic, Username: "admin"}, want: "requires a username and password"},
+		{name: "oidc without a client", config: AuthConfig{Mode: AuthOIDC, OIDCIssuerURL: "https://issuer.example.com"}, want: "requires an issuer URL and client ID"},
+		{name: "unknown mode", config: AuthConfig{Mode: "ldap"}, want: `unknown auth mode "ldap"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newAuthenticator(context.Background(), tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("newAuthenticator() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
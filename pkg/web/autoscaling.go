@@ -0,0 +1,49 @@
+package web
+
+import "pod-visualizer/pkg/k8s"
+
+// HPAData represents HorizontalPodAutoscaler data for JSON response
+type HPAData struct {
+	UID             string          `json:"uid"`
+	Name            string          `json:"name"`
+	Namespace       string          `json:"namespace"`
+	TargetKind      string          `json:"targetKind"`
+	TargetName      string          `json:"targetName"`
+	MinReplicas     int32           `json:"minReplicas"`
+	MaxReplicas     int32           `json:"maxReplicas"`
+	CurrentReplicas int32           `json:"currentReplicas"`
+	DesiredReplicas int32           `json:"desiredReplicas"`
+	AtMaxReplicas   bool            `json:"atMaxReplicas"`
+	Metrics         []HPAMetricData `json:"metrics"`
+}
+
+// HPAMetricData represents one scaling metric for JSON response
+type HPAMetricData struct {
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Current string `json:"current"`
+}
+
+// toHPAData converts autoscalers to their response format
+func toHPAData(hpas []k8s.HPAInfo) []HPAData {
+	hpaData := make([]HPAData, len(hpas))
+	for i, hpa := range hpas {
+		hpaData[i] = HPAData{
+			UID:             hpa.UID,
+			Name:            hpa.Name,
+			Namespace:       hpa.Namespace,
+			TargetKind:      hpa.TargetKind,
+			TargetName:      hpa.TargetName,
+			MinReplicas:     hpa.MinReplicas,
+			MaxReplicas:     hpa.MaxReplicas,
+			CurrentReplicas: hpa.CurrentReplicas,
+			DesiredReplicas: hpa.DesiredReplicas,
+			AtMaxReplicas:   hpa.AtMaxReplicas(),
+			Metrics:         make([]HPAMetricData, len(hpa.Metrics)),
+		}
+		for j, metric := range hpa.Metrics {
+			hpaData[i].Metrics[j] = HPAMetricData{Name: metric.Name, Target: metric.Target, Current: metric.Current}
+		}
+	}
+	return hpaData
+}
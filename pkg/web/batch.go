@@ -0,0 +1,97 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const (
+	// maxBatchRefs caps how many resources a single batch request may describe
+	maxBatchRefs = 500
+	// batchConcurrency limits concurrent Kubernetes API calls per batch request
+	batchConcurrency = 8
+)
+
+// ResourceRef identifies a namespaced resource
+type ResourceRef struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// BatchDescribeRequest is the body of POST /api/batch/describe
+type BatchDescribeRequest struct {
+	Refs []ResourceRef `json:"refs"`
+}
+
+// BatchDescribeResult holds the outcome for a single ref
+type BatchDescribeResult struct {
+	Ref   ResourceRef `json:"ref"`
+	Pod   *PodData    `json:"pod,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// BatchDescribeResponse is the response of POST /api/batch/describe
+type BatchDescribeResponse struct {
+	Results []BatchDescribeResult `json:"results"`
+}
+
+// handleBatchDescribe returns details for many pods in one call,
+// fetching them with bounded concurrency
+func (s *Server) handleBatchDescribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatchDescribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Refs) > maxBatchRefs {
+		http.Error(w, fmt.Sprintf("Too many refs: %d (max %d)", len(req.Refs), maxBatchRefs), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	results := make([]BatchDescribeResult, len(req.Refs))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, ref := range req.Refs {
+		results[i].Ref = ref
+		if ref.Namespace == "" || ref.Name == "" {
+			results[i].Error = "namespace and name are required"
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, ref ResourceRef) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-r.Context().Done():
+				results[i].Error = r.Context().Err().Error()
+				return
+			}
+
+			pod, err := s.client.GetPod(r.Context(), ref.Namespace, ref.Name)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+
+			podData := s.toPodData(pod)
+			results[i].Pod = &podData
+		}(i, ref)
+	}
+
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, BatchDescribeResponse{Results: results})
+}
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultCacheTTL bounds how stale a cached filtered /api/cluster
+// response may be when no watch event invalidates it sooner
+const defaultCacheTTL = 5 * time.Second
+
+// cacheKey identifies a filtered view of the cluster
+type cacheKey struct {
+	namespace string
+	node      string
+}
+
+// cachedData is a filtered view and when it was fetched
+type cachedData struct {
+	data      ClusterData
+	fetchedAt time.Time
+}
+
+// clusterCache serves REST reads from memory. The unfiltered view is the
+// latest broadcast snapshot; filtered views are fetched on demand, kept
+// for at most the TTL and dropped whenever a watch-driven refresh finds
+// the cluster changed.
+type clusterCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[cacheKey]cachedData
+
+	// refreshed is when the watch path last fetched the whole cluster, in
+	// Unix nanoseconds; the broadcast snapshot is current as of then
+	refreshed atomic.Int64
+}
+
+func newClusterCache() *clusterCache {
+	return &clusterCache{ttl: defaultCacheTTL, entries: make(map[cacheKey]cachedData)}
+}
+
+// get returns a filtered view fetched within the TTL
+func (c *clusterCache) get(key cacheKey) (cachedData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.fetchedAt) >= c.ttl {
+		return cachedData{}, false
+	}
+	return entry, true
+}
+
+// put stores a filtered view; nothing is kept when caching is disabled
+func (c *clusterCache) put(key cacheKey, data ClusterData, fetchedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl <= 0 {
+		return
+	}
+	c.entries[key] = cachedData{data: data, fetchedAt: fetchedAt}
+}
+
+// refresh records a whole-cluster refresh from the watch path; changed
+// drops every filtered view, since any of them may now be out of date
+func (c *clusterCache) refresh(at time.Time, changed bool) {
+	c.refreshed.Store(at.UnixNano())
+	if !changed {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// lastRefresh returns when the broadcast snapshot was last confirmed current
+func (c *clusterCache) lastRefresh() (time.Time, bool) {
+	nanos := c.refreshed.Load()
+	return time.Unix(0, nanos), nanos != 0
+}
+
+// setTTL replaces the TTL; zero disables caching of filtered views
+func (c *clusterCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	clear(c.entries)
+}
+
+// SetCacheTTL sets how long filtered /api/cluster responses are served from
+// memory before they are fetched again (0 = always fetch). Watch events
+// invalidate them sooner; unfiltered responses always come from the watch
+// path's latest snapshot.
+func (s *Server) SetCacheTTL(ttl time.Duration) {
+	if ttl >= 0 {
+		s.cache.setTTL(ttl)
+	}
+}
+
+// writeStaleness reports how old the data in a response is: Age in whole
+// seconds since it was known current, and whether it came from memory
+func writeStaleness(w http.ResponseWriter, asOf time.Time, hit bool) {
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(asOf).Seconds())))
+	if hit {
+		w.Header().Set("X-Cache", "hit")
+	} else {
+		w.Header().Set("X-Cache", "miss")
+	}
+}
@@ -0,0 +1,68 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight
+const corsMaxAge = 600
+
+// Request and response headers cross-origin callers may use. Authorization
+// carries bearer tokens and basic credentials; the exposed headers are the
+// caching and tracing headers the API sets.
+const (
+	corsAllowHeaders  = "Authorization, Content-Type, If-None-Match, X-Request-ID"
+	corsAllowMethods  = "GET, POST, DELETE, OPTIONS"
+	corsExposeHeaders = "Age, ETag, X-Cache, X-Request-ID"
+)
+
+// SetCORSOrigins lets the listed origins, such as https://grafana.example.com,
+// call /api/* from the browser. "*" allows any origin, without credentials.
+// Write actions still have to come from the dashboard's own origin.
+func (s *Server) SetCORSOrigins(origins []string) {
+	s.corsOrigins = make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		s.corsOrigins[strings.TrimSuffix(origin, "/")] = true
+	}
+}
+
+// withCORS adds CORS headers to /api/* responses for allowed origins and
+// answers their preflight requests, which carry no credentials, before
+// authentication
+func (s *Server) withCORS(next http.Handler) http.Handler {
+	if len(s.corsOrigins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		switch {
+		case s.corsOrigins[origin]:
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		case s.corsOrigins["*"]:
+			header.Set("Access-Control-Allow-Origin", "*")
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		header.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			header.Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
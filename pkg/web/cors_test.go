@@ -0,0 +1,125 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name             string
+		origins          []string
+		method           string
+		path             string
+		origin           string
+		want             int
+		allowOrigin      string
+		allowCredentials string
+		allowMethods     string
+	}{
+		{
+			name:             "listed origin",
+			origins:          []string{"https://grafana.example.com/"},
+			origin:           "https://grafana.example.com",
+			want:             http.StatusOK,
+			allowOrigin:      "https://grafana.example.com",
+			allowCredentials: "true",
+		},
+		{
+			name:        "any origin, without credentials",
+			origins:     []string{"*"},
+			origin:      "https://ops.example.com",
+			want:        http.StatusOK,
+			allowOrigin: "*",
+		},
+		{
+			name:    "origin not listed",
+			origins: []string{"https://grafana.example.com"},
+			origin:  "https://evil.example.com",
+			want:    http.StatusOK,
+		},
+		{
+			name:   "no origins configured",
+			origin: "https://grafana.example.com",
+			want:   http.StatusOK,
+		},
+		{
+			name:    "outside the API",
+			origins: []string{"*"},
+			path:    "/health",
+			origin:  "https://grafana.example.com",
+			want:    http.StatusOK,
+		},
+		{
+			name:             "preflight",
+			origins:          []string{"https://grafana.example.com"},
+			method:           http.MethodOptions,
+			origin:           "https://grafana.example.com",
+			want:             http.StatusNoContent,
+			allowOrigin:      "https://grafana.example.com",
+			allowCredentials: "true",
+			allowMethods:     corsAllowMethods,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer()
+			if tt.origins != nil {
+				s.SetCORSOrigins(tt.origins)
+			}
+			method, path := tt.method, tt.path
+			if method == "" {
+				method = http.MethodGet
+			}
+			if path == "" {
+				path = "/api/v1/namespaces"
+			}
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+			}
+
+			rec := serve(s, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			header := rec.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			if got := header.Get("Access-Control-Allow-Credentials"); got != tt.allowCredentials {
+				t.Errorf("Access-Control-Allow-Credentials = %q, want %q", got, tt.allowCredentials)
+			}
+			if got := header.Get("Access-Control-Allow-Methods"); got != tt.allowMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.allowMethods)
+			}
+		})
+	}
+}
+
+// Preflights carry no credentials, so they are answered before
+// authentication while the request itself still needs them
+func TestCORSPreflightBeforeAuth(t *testing.T) {
+	s := newTestServer()
+	s.SetCORSOrigins([]string{"https://grafana.example.com"})
+	if err := s.SetAuth(context.Background(), AuthConfig{Mode: AuthToken, Token: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/v1/namespaces", nil)
+	preflight.Header.Set("Origin", "https://grafana.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	if rec := serve(s, preflight); rec.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces", nil)
+	req.Header.Set("Origin", "https://grafana.example.com")
+	if rec := serve(s, req); rec.Code != http.StatusUnauthorized {
+		t.Errorf("request status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// CustomResourceData represents a resource of a configured custom
+// resource type for JSON response
+type CustomResourceData struct {
+	UID       string    `json:"uid"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Desired   int32     `json:"desired"`
+	Ready     int32     `json:"ready"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Unknown is set when the desired count could not be read
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// kindCustomResources is reported unavailable when a configured custom
+// resource type fails to list
+const kindCustomResources = "customresources"
+
+// NeedsAttention reports whether the resource is short of its desired
+// count, or its desired count is unknown
+func (r CustomResourceData) NeedsAttention() bool {
+	return r.Unknown || r.Ready < r.Desired
+}
+
+// toCustomResourceData converts custom resources to their response format
+func toCustomResourceData(resources []k8s.CustomResourceInfo) []CustomResourceData {
+	data := make([]CustomResourceData, len(resources))
+	for i, resource := range resources {
+		data[i] = CustomResourceData{
+			UID:       resource.UID,
+			Kind:      resource.Kind,
+			Name:      resource.Name,
+			Namespace: resource.Namespace,
+			Desired:   resource.Desired,
+			Ready:     resource.Ready,
+			Unknown:   resource.Unknown,
+			CreatedAt: resource.CreatedAt.UTC(),
+		}
+	}
+	return data
+}
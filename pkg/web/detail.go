@@ -0,0 +1,401 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"pod-visualizer/pkg/analysis"
+	"pod-visualizer/pkg/k8s"
+)
+
+// PodDetailData represents the pod drill-down for JSON response
+type PodDetailData struct {
+	UID            string                `json:"uid"`
+	Name           string                `json:"name"`
+	Namespace      string                `json:"namespace"`
+	Status         string                `json:"status"`
+	StatusSymbol   string                `json:"statusSymbol"`
+	Phase          string                `json:"phase"`
+	NodeName       string                `json:"nodeName"`
+	PodIP          string                `json:"podIP"`
+	QOSClass       string                `json:"qosClass"`
+	RuntimeClass   string                `json:"runtimeClass,omitempty"`
+	StartTime      *time.Time            `json:"startTime,omitempty"`
+	InitContainers []ContainerDetailData `json:"initContainers,omitempty"`
+	Containers     []ContainerDetailData `json:"containers"`
+
+	// EphemeralContainers are debug containers added with kubectl debug
+	EphemeralContainers []ContainerDetailData `json:"ephemeralContainers,omitempty"`
+
+	// Unschedulable is the scheduler's message while it cannot place the pod
+	Unschedulable string `json:"unschedulable,omitempty"`
+
+	// ReadinessGates are the conditions the pod's readiness gates wait on;
+	// a gate whose condition is not set yet has an empty status
+	ReadinessGates []ConditionData `json:"readinessGates,omitempty"`
+
+	// Hints are the likely causes of the pod's trouble, likeliest first
+	Hints []HintData `json:"hints,omitempty"`
+
+	Conditions  []ConditionData  `json:"conditions"`
+	Owners      []OwnerData      `json:"owners"`
+	Tolerations []TolerationData `json:"tolerations"`
+	Events      []EventData      `json:"events"`
+}
+
+// ContainerDetailData represents a container's spec and status for JSON response
+type ContainerDetailData struct {
+	Name         string     `json:"name"`
+	Image        string     `json:"image"`
+	Ready        bool       `json:"ready"`
+	RestartCount int32      `json:"restartCount"`
+	State        string     `json:"state"`
+	Reason       string     `json:"reason,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+}
+
+// ConditionData represents a status condition for JSON response
+type ConditionData struct {
+	Type               string     `json:"type"`
+	Status             string     `json:"status"`
+	Reason             string     `json:"reason,omitempty"`
+	Message            string     `json:"message,omitempty"`
+	LastTransitionTime *time.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// HintData represents a likely cause of a pod's trouble for JSON response
+type HintData struct {
+	Cause     string `json:"cause"`
+	Container string `json:"container,omitempty"`
+	Message   string `json:"message"`
+}
+
+// OwnerData represents an owner reference for JSON response
+type OwnerData struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Controller bool   `json:"controller"`
+}
+
+// TolerationData represents a toleration for JSON response
+type TolerationData struct {
+	Key      string `json:"key,omitempty"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+	Effect   string `json:"effect,omitempty"`
+}
+
+// EventData represents an event for JSON response
+type EventData struct {
+	Type     string     `json:"type"`
+	Reason   string     `json:"reason"`
+	Message  string     `json:"message"`
+	Count    int32      `json:"count"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// handlePodDetail serves /api/v1/pods/{namespace}/{name}
+func (s *Server) handlePodDetail(w http.ResponseWriter, r *http.Request) {
+	if namespace, name, action, ok := objectAction(r.URL.Path, apiPrefix+"/pods/"); ok {
+		s.handlePodAction(w, r, namespace, name, action)
+		return
+	}
+
+	namespace, name, ok := namespacedName(r.URL.Path, apiPrefix+"/pods/")
+	if !ok {
+		http.Error(w, "Expected "+apiPrefix+"/pods/{namespace}/{name}", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.client.GetPodDetail(r.Context(), namespace, name)
+	if err != nil {
+		writeDetailError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.toPodDetailData(detail))
+}
+
+// namespacedName extracts "{namespace}/{name}" from a path below prefix
+func namespacedName(path, prefix string) (string, string, bool) {
+	namespace, name, found := strings.Cut(strings.TrimPrefix(path, prefix), "/")
+	if !found || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return namespace, name, true
+}
+
+// writeDetailError maps a lookup error to a status code
+func writeDetailError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case apierrors.IsNotFound(err):
+		code = http.StatusNotFound
+	case apierrors.IsForbidden(err):
+		code = http.StatusForbidden
+	}
+	http.Error(w, fmt.Sprintf("Failed to get detail: %v", err), code)
+}
+
+// toPodDetailData converts pod detail to its response format
+func (s *Server) toPodDetailData(detail k8s.PodDetail) PodDetailData {
+	data := PodDetailData{
+		UID:            detail.UID,
+		Name:           detail.Name,
+		Namespace:      detail.Namespace,
+		Status:         detail.Status,
+		StatusSymbol:   s.symbols.Symbol(detail.Status),
+		Phase:          detail.Phase,
+		NodeName:       detail.NodeName,
+		PodIP:          detail.PodIP,
+		QOSClass:       detail.QOSClass,
+		RuntimeClass:   detail.RuntimeClass,
+		StartTime:      optionalTime(detail.StartTime),
+		InitContainers: toContainerDetailData(detail.InitContainers),
+		Containers:     toContainerDetailData(detail.Containers),
+		Conditions:     toConditionData(detail.Conditions),
+		Owners:         make([]OwnerData, len(detail.Owners)),
+		Tolerations:    make([]TolerationData, len(detail.Tolerations)),
+		Events:         toEventData(detail.Events),
+
+		EphemeralContainers: toContainerDetailData(detail.EphemeralContainers),
+
+		Unschedulable:  detail.Unschedulable,
+		ReadinessGates: toConditionData(detail.ReadinessGates),
+	}
+
+	for _, hint := range detail.Hints {
+		data.Hints = append(data.Hints, HintData{Cause: hint.Cause, Container: hint.Container, Message: hint.Message})
+	}
+
+	for i, owner := range detail.Owners {
+		data.Owners[i] = OwnerData{Kind: owner.Kind, Name: owner.Name, Controller: owner.Controller}
+	}
+	for i, toleration := range detail.Tolerations {
+		data.Tolerations[i] = TolerationData{
+			Key:      toleration.Key,
+			Operator: toleration.Operator,
+			Value:    toleration.Value,
+			Effect:   toleration.Effect,
+		}
+	}
+
+	return data
+}
+
+// toContainerDetailData converts containers to their response format
+func toContainerDetailData(containers []k8s.ContainerDetail) []ContainerDetailData {
+	containerData := make([]ContainerDetailData, len(containers))
+	for i, container := range containers {
+		containerData[i] = ContainerDetailData{
+			Name:         container.Name,
+			Image:        container.Image,
+			Ready:        container.Ready,
+			RestartCount: container.RestartCount,
+			State:        container.State,
+			Reason:       container.Reason,
+			Message:      container.Message,
+			StartedAt:    optionalTime(container.StartedAt),
+		}
+	}
+	return containerData
+}
+
+// toConditionData converts conditions to their response format
+func toConditionData(conditions []k8s.ConditionInfo) []ConditionData {
+	conditionData := make([]ConditionData, len(conditions))
+	for i, condition := range conditions {
+		conditionData[i] = ConditionData{
+			Type:               condition.Type,
+			Status:             condition.Status,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: optionalTime(condition.LastTransitionTime),
+		}
+	}
+	return conditionData
+}
+
+// toEventData converts events to their response format
+func toEventData(events []k8s.EventInfo) []EventData {
+	eventData := make([]EventData, len(events))
+	for i, event := range events {
+		eventData[i] = EventData{
+			Type:     event.Type,
+			Reason:   event.Reason,
+			Message:  event.Message,
+			Count:    event.Count,
+			LastSeen: optionalTime(event.LastSeen),
+		}
+	}
+	return eventData
+}
+
+// DeploymentDetailData represents the deployment drill-down for JSON response
+type DeploymentDetailData struct {
+	UID                 string          `json:"uid"`
+	Name                string          `json:"name"`
+	Namespace           string          `json:"namespace"`
+	Strategy            string          `json:"strategy"`
+	MaxSurge            string          `json:"maxSurge,omitempty"`
+	MaxUnavailable      string          `json:"maxUnavailable,omitempty"`
+	Paused              bool            `json:"paused"`
+	Replicas            int32           `json:"replicas"`
+	UpdatedReplicas     int32           `json:"updatedReplicas"`
+	ReadyReplicas       int32           `json:"readyReplicas"`
+	AvailableReplicas   int32           `json:"availableReplicas"`
+	UnavailableReplicas int32           `json:"unavailableReplicas"`
+	Revision            int64           `json:"revision"`
+	Rollout             RolloutData     `json:"rollout"`
+	Conditions          []ConditionData `json:"conditions"`
+	Revisions           []RevisionData  `json:"revisions"`
+	Events              []EventData     `json:"events"`
+
+	// Spread is missing when the server cannot list the pods or nodes
+	Spread *SpreadData `json:"spread,omitempty"`
+}
+
+// SpreadData represents how a deployment's pods are placed across nodes
+// and zones, and the placement rules they break, for JSON response
+type SpreadData struct {
+	Topologies []TopologyData  `json:"topologies"`
+	Violations []ViolationData `json:"violations"`
+}
+
+// TopologyData represents the pods per domain of a topology key, such as
+// topology.kubernetes.io/zone, for JSON response
+type TopologyData struct {
+	Key     string       `json:"key"`
+	Domains []DomainData `json:"domains"`
+	Skew    int          `json:"skew"`
+}
+
+// DomainData represents a topology domain and its pod count for JSON
+// response
+type DomainData struct {
+	Name string `json:"name"`
+	Pods int    `json:"pods"`
+}
+
+// ViolationData represents a topology spread constraint or anti-affinity
+// term the placement breaks, for JSON response
+type ViolationData struct {
+	Rule     string `json:"rule"`
+	Key      string `json:"key"`
+	Required bool   `json:"required"`
+	Message  string `json:"message"`
+}
+
+// RolloutData represents the current rollout's progress for JSON response
+type RolloutData struct {
+	Complete bool   `json:"complete"`
+	Stuck    bool   `json:"stuck"`
+	Message  string `json:"message,omitempty"`
+}
+
+// RevisionData represents a rollout history entry for JSON response
+type RevisionData struct {
+	Revision      int64      `json:"revision"`
+	ReplicaSet    string     `json:"replicaSet"`
+	Images        []string   `json:"images"`
+	ChangeCause   string     `json:"changeCause,omitempty"`
+	Replicas      int32      `json:"replicas"`
+	ReadyReplicas int32      `json:"readyReplicas"`
+	Created       *time.Time `json:"created,omitempty"`
+}
+
+// handleDeploymentDetail serves /api/v1/deployments/{namespace}/{name}
+func (s *Server) handleDeploymentDetail(w http.ResponseWriter, r *http.Request) {
+	if namespace, name, action, ok := objectAction(r.URL.Path, apiPrefix+"/deployments/"); ok {
+		s.handleDeploymentAction(w, r, namespace, name, action)
+		return
+	}
+
+	namespace, name, ok := namespacedName(r.URL.Path, apiPrefix+"/deployments/")
+	if !ok {
+		http.Error(w, "Expected "+apiPrefix+"/deployments/{namespace}/{name}", http.StatusBadRequest)
+		return
+	}
+
+	detail, err := s.client.GetDeploymentDetail(r.Context(), namespace, name)
+	if err != nil {
+		writeDetailError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toDeploymentDetailData(detail))
+}
+
+// toDeploymentDetailData converts deployment detail to its response format
+func toDeploymentDetailData(detail k8s.DeploymentDetail) DeploymentDetailData {
+	data := DeploymentDetailData{
+		UID:                 detail.UID,
+		Name:                detail.Name,
+		Namespace:           detail.Namespace,
+		Strategy:            detail.Strategy,
+		MaxSurge:            detail.MaxSurge,
+		MaxUnavailable:      detail.MaxUnavailable,
+		Paused:              detail.Paused,
+		Replicas:            detail.Replicas,
+		UpdatedReplicas:     detail.UpdatedReplicas,
+		ReadyReplicas:       detail.ReadyReplicas,
+		AvailableReplicas:   detail.AvailableReplicas,
+		UnavailableReplicas: detail.UnavailableReplicas,
+		Revision:            detail.Revision,
+		Rollout: RolloutData{
+			Complete: detail.Rollout.Complete,
+			Stuck:    detail.Rollout.Stuck,
+			Message:  detail.Rollout.Message,
+		},
+		Conditions: toConditionData(detail.Conditions),
+		Revisions:  make([]RevisionData, len(detail.Revisions)),
+		Events:     toEventData(detail.Events),
+	}
+
+	for i, revision := range detail.Revisions {
+		data.Revisions[i] = RevisionData{
+			Revision:      revision.Revision,
+			ReplicaSet:    revision.ReplicaSet,
+			Images:        revision.Images,
+			ChangeCause:   revision.ChangeCause,
+			Replicas:      revision.Replicas,
+			ReadyReplicas: revision.ReadyReplicas,
+			Created:       optionalTime(revision.Created),
+		}
+	}
+
+	if detail.Spread != nil {
+		data.Spread = toSpreadData(*detail.Spread)
+	}
+
+	return data
+}
+
+// toSpreadData converts a deployment's spread to its response format
+func toSpreadData(spread analysis.Spread) *SpreadData {
+	data := &SpreadData{
+		Topologies: make([]TopologyData, len(spread.Topologies)),
+		Violations: make([]ViolationData, len(spread.Violations)),
+	}
+	for i, topology := range spread.Topologies {
+		domains := make([]DomainData, len(topology.Domains))
+		for j, domain := range topology.Domains {
+			domains[j] = DomainData{Name: domain.Name, Pods: domain.Pods}
+		}
+		data.Topologies[i] = TopologyData{Key: topology.Key, Domains: domains, Skew: topology.Skew}
+	}
+	for i, violation := range spread.Violations {
+		data.Violations[i] = ViolationData{
+			Rule:     violation.Rule,
+			Key:      violation.Key,
+			Required: violation.Required,
+			Message:  violation.Message,
+		}
+	}
+	return data
+}
@@ -0,0 +1,39 @@
+package web
+
+import "pod-visualizer/pkg/k8s"
+
+// PDBData represents PodDisruptionBudget data for JSON response
+type PDBData struct {
+	UID                string   `json:"uid"`
+	Name               string   `json:"name"`
+	Namespace          string   `json:"namespace"`
+	MinAvailable       string   `json:"minAvailable,omitempty"`
+	MaxUnavailable     string   `json:"maxUnavailable,omitempty"`
+	CurrentHealthy     int32    `json:"currentHealthy"`
+	DesiredHealthy     int32    `json:"desiredHealthy"`
+	ExpectedPods       int32    `json:"expectedPods"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	Workloads          []string `json:"workloads"`
+	BlockedNodes       []string `json:"blockedNodes"`
+}
+
+// toPDBData converts disruption budgets to their response format
+func toPDBData(pdbs []k8s.PDBInfo) []PDBData {
+	pdbData := make([]PDBData, len(pdbs))
+	for i, pdb := range pdbs {
+		pdbData[i] = PDBData{
+			UID:                pdb.UID,
+			Name:               pdb.Name,
+			Namespace:          pdb.Namespace,
+			MinAvailable:       pdb.MinAvailable,
+			MaxUnavailable:     pdb.MaxUnavailable,
+			CurrentHealthy:     pdb.CurrentHealthy,
+			DesiredHealthy:     pdb.DesiredHealthy,
+			ExpectedPods:       pdb.ExpectedPods,
+			DisruptionsAllowed: pdb.DisruptionsAllowed,
+			Workloads:          append([]string{}, pdb.Workloads...),
+			BlockedNodes:       append([]string{}, pdb.BlockedNodes...),
+		}
+	}
+	return pdbData
+}
@@ -0,0 +1,95 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool; one-off huge
+// responses are left to the GC rather than pinning their memory forever
+const maxPooledBuffer = 8 << 20
+
+// bufferPool recycles encoding buffers across requests
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// encodedSnapshot is the latest broadcast snapshot, marshaled once and
+// shared by every unfiltered /api/cluster request until the next broadcast
+type encodedSnapshot struct {
+	checksum string
+	body     []byte
+}
+
+// snapshotCache holds the most recent encodedSnapshot
+type snapshotCache struct {
+	latest atomic.Pointer[encodedSnapshot]
+}
+
+// store marshals data and replaces the cached snapshot
+func (c *snapshotCache) store(data ClusterData) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	c.latest.Store(&encodedSnapshot{checksum: data.Checksum, body: body})
+	return nil
+}
+
+// load returns the cached snapshot, if any
+func (c *snapshotCache) load() (*encodedSnapshot, bool) {
+	snapshot := c.latest.Load()
+	return snapshot, snapshot != nil
+}
+
+// writeJSON encodes v into a pooled buffer and writes it with a
+// Content-Length, avoiding a fresh allocation per response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONBytes(w, status, buf.Bytes())
+}
+
+// writeJSONBytes writes an already encoded JSON body
+func writeJSONBytes(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// prepareMessage marshals a WebSocket message once so it can be written to
+// any number of clients without re-encoding or re-framing it per client
+func prepareMessage(message WSMessage) (*websocket.PreparedMessage, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(message); err != nil {
+		return nil, err
+	}
+
+	// PreparedMessage copies the payload, so the buffer can be reused
+	return websocket.NewPreparedMessage(websocket.TextMessage, buf.Bytes())
+}
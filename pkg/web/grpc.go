@@ -0,0 +1,278 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"pod-visualizer/pkg/rpc/clusterpb"
+)
+
+// watchers fans broadcast snapshots out to gRPC Watch streams. Each
+// subscription holds only the latest snapshot, so a slow stream skips
+// intermediate states instead of blocking the broadcaster.
+type watchers struct {
+	mu     sync.Mutex
+	subs   map[chan ClusterData]struct{}
+	closed bool
+}
+
+// newWatchers creates an empty registry
+func newWatchers() *watchers {
+	return &watchers{subs: make(map[chan ClusterData]struct{})}
+}
+
+// subscribe registers a new subscription; it is closed on shutdown
+func (w *watchers) subscribe() chan ClusterData {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan ClusterData, 1)
+	if w.closed {
+		close(ch)
+		return ch
+	}
+	w.subs[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe removes a subscription
+func (w *watchers) unsubscribe(ch chan ClusterData) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.subs[ch]; ok {
+		delete(w.subs, ch)
+		close(ch)
+	}
+}
+
+// publish replaces any undelivered snapshot with data on every subscription
+func (w *watchers) publish(data ClusterData) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- data
+	}
+}
+
+// close ends every subscription
+func (w *watchers) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs {
+		delete(w.subs, ch)
+		close(ch)
+	}
+	w.closed = true
+}
+
+// SetGRPCPort serves the ClusterService gRPC API on port alongside the
+// HTTP server. Zero, the default, disables it.
+func (s *Server) SetGRPCPort(port int) {
+	s.grpcPort = port
+}
+
+// startGRPC starts the gRPC server if a port is configured or a grpc
+// socket was passed by socket activation
+func (s *Server) startGRPC() error {
+	if _, activated := s.activated["grpc"]; s.grpcPort == 0 && !activated {
+		return nil
+	}
+
+	listener, err := s.listen("grpc", s.grpcPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %v", err)
+	}
+
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryAuth),
+		grpc.StreamInterceptor(s.streamAuth),
+	}
+	if s.tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	s.grpcServer = grpc.NewServer(options...)
+	clusterpb.RegisterClusterServiceServer(s.grpcServer, &clusterService{server: s})
+
+	slog.Info("gRPC API available", "address", listener.Addr().String())
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil {
+			slog.Error("gRPC server stopped", "error", err)
+		}
+	}()
+	return nil
+}
+
+// stopGRPC ends Watch streams and drains in-flight calls
+func (s *Server) stopGRPC() {
+	if s.grpcServer == nil {
+		return
+	}
+	s.watchers.close()
+	s.grpcServer.GracefulStop()
+}
+
+// unaryAuth applies dashboard authentication to unary calls
+func (s *Server) unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authorizeRPC(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamAuth applies dashboard authentication to streaming calls
+func (s *Server) streamAuth(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorizeRPC(stream.Context()); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// authorizeRPC checks the authorization metadata with the same credentials
+// as the HTTP API: "Bearer <token>" or "Basic <base64>"
+func (s *Server) authorizeRPC(ctx context.Context) error {
+	if s.auth == nil {
+		return nil
+	}
+
+	header := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	r := (&http.Request{Header: http.Header{"Authorization": {header}}, URL: &url.URL{}}).WithContext(ctx)
+	if _, ok := s.auth.authenticate(nil, r); !ok {
+		return status.Error(codes.Unauthenticated, "invalid or missing credentials")
+	}
+	return nil
+}
+
+// clusterService implements the ClusterService gRPC API
+type clusterService struct {
+	clusterpb.UnimplementedClusterServiceServer
+	server *Server
+}
+
+// GetClusterData returns the current cluster state
+func (c *clusterService) GetClusterData(ctx context.Context, req *clusterpb.GetClusterDataRequest) (*clusterpb.ClusterData, error) {
+	data, err := c.server.getClusterData(ctx, req.GetNamespace(), req.GetNode())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to get cluster data: %v", err)
+	}
+	return toClusterPB(data), nil
+}
+
+// Watch sends the current state and then every changed state. Namespaced
+// watches cut their namespace out of each broadcast snapshot rather than
+// fetching it again.
+func (c *clusterService) Watch(req *clusterpb.WatchRequest, stream clusterpb.ClusterService_WatchServer) error {
+	ctx := stream.Context()
+	namespace := req.GetNamespace()
+
+	updates := c.server.watchers.subscribe()
+	defer c.server.watchers.unsubscribe(updates)
+
+	data, err := c.server.getClusterData(ctx, namespace, "")
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "failed to get cluster data: %v", err)
+	}
+	if err := stream.Send(toClusterPB(data)); err != nil {
+		return err
+	}
+	lastChecksum := data.Checksum
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			update = filterNamespaces(update, namespace)
+			if update.Checksum == lastChecksum {
+				continue
+			}
+			lastChecksum = update.Checksum
+
+			if err := stream.Send(toClusterPB(update)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toClusterPB converts cluster data to its protobuf form. The message
+// carries only pods and deployments, with totals over them; the other
+// kinds are served by /api/cluster alone.
+func toClusterPB(data ClusterData) *clusterpb.ClusterData {
+	message := &clusterpb.ClusterData{
+		SchemaVersion:       int32(data.SchemaVersion),
+		Checksum:            data.Checksum,
+		Pods:                make([]*clusterpb.Pod, len(data.Pods)),
+		Deployments:         make([]*clusterpb.Deployment, len(data.Deployments)),
+		TotalContainers:     int32(data.TotalContainers),
+		ReadyContainers:     int32(data.ReadyContainers),
+		ContainerPercentage: data.ContainerPercentage,
+		TotalReplicas:       data.TotalReplicas,
+		ReadyReplicas:       data.ReadyReplicas,
+		ReplicaPercentage:   data.ReplicaPercentage,
+		Unavailable:         data.Unavailable,
+		LastUpdated:         timestamppb.New(data.LastUpdated),
+	}
+
+	for i, pod := range data.Pods {
+		message.Pods[i] = &clusterpb.Pod{
+			Uid:                  pod.UID,
+			Name:                 pod.Name,
+			Namespace:            pod.Namespace,
+			Status:               pod.Status,
+			ContainerCount:       int32(pod.ContainerCount),
+			ReadyContainers:      int32(pod.ReadyContainers),
+			Restarts:             pod.Restarts,
+			NodeName:             pod.NodeName,
+			RuntimeClass:         pod.RuntimeClass,
+			RuntimeClassMismatch: pod.RuntimeClassMismatch,
+			CpuRequestMilli:      pod.CPURequestMilli,
+			MemoryRequestBytes:   pod.MemoryRequestBytes,
+			CpuUsageMilli:        pod.CPUUsageMilli,
+			MemoryUsageBytes:     pod.MemoryUsageBytes,
+		}
+		if pod.NodePressure != "" {
+			message.Pods[i].NodePressure = strings.Split(pod.NodePressure, ",")
+		}
+	}
+
+	for i, deployment := range data.Deployments {
+		message.Deployments[i] = &clusterpb.Deployment{
+			Uid:               deployment.UID,
+			Name:              deployment.Name,
+			Namespace:         deployment.Namespace,
+			Replicas:          deployment.Replicas,
+			ReadyReplicas:     deployment.ReadyReplicas,
+			AvailableReplicas: deployment.AvailableReplicas,
+		}
+	}
+
+	return message
+}
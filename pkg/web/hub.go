@@ -0,0 +1,207 @@
+package web
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// clientSendBuffer is how many messages may queue for a slow client before it is dropped
+	clientSendBuffer = 16
+	// writeTimeout bounds each WebSocket write so a stuck client cannot block its writer forever
+	writeTimeout = 10 * time.Second
+	// maxSkippedBroadcasts is how many consecutive broadcasts a client with
+	// a full buffer may miss before it is dropped. Skipped diffs make the
+	// client request a resync once it catches up.
+	maxSkippedBroadcasts = 3
+)
+
+// wsClient is a single WebSocket connection. Only its writePump goroutine
+// writes to conn; everything else hands messages over through send.
+// Messages are prepared once by the sender and shared between clients.
+type wsClient struct {
+	conn *websocket.Conn
+	send chan *websocket.PreparedMessage
+
+	// skipped counts consecutive broadcasts missed; owned by the hub
+	skipped int
+}
+
+// directMessage is a message addressed to a single client
+type directMessage struct {
+	client  *wsClient
+	message *websocket.PreparedMessage
+}
+
+// hub owns the set of connected clients. All mutations of the client set
+// happen on the run goroutine, so no locking is required.
+type hub struct {
+	clients    map[*wsClient]bool
+	register   chan *wsClient
+	unregister chan *wsClient
+	broadcast  chan *websocket.PreparedMessage
+	direct     chan directMessage
+	count      chan chan int
+	done       chan struct{}
+	stopOnce   sync.Once
+
+	// dropped counts clients disconnected for not keeping up
+	dropped atomic.Int64
+}
+
+// newHub creates a hub; call run to start it
+func newHub() *hub {
+	return &hub{
+		clients:    make(map[*wsClient]bool),
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+		broadcast:  make(chan *websocket.PreparedMessage),
+		direct:     make(chan directMessage),
+		count:      make(chan chan int),
+		done:       make(chan struct{}),
+	}
+}
+
+// newClient creates a client for the connection and starts its writer
+func newClient(conn *websocket.Conn) *wsClient {
+	c := &wsClient{
+		conn: conn,
+		send: make(chan *websocket.PreparedMessage, clientSendBuffer),
+	}
+	go c.writePump()
+	return c
+}
+
+// run processes registrations and broadcasts until stop is called
+func (h *hub) run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+			slog.Info("WebSocket client connected", "clients", len(h.clients))
+
+		case c := <-h.unregister:
+			h.remove(c)
+
+		case message := <-h.broadcast:
+			for c := range h.clients {
+				select {
+				case c.send <- message:
+					c.skipped = 0
+				default:
+					// Client is not keeping up; skip it rather than block
+					// everyone, and drop it if it stays behind
+					c.skipped++
+					if c.skipped > maxSkippedBroadcasts {
+						slog.Warn("Dropping slow WebSocket client", "skipped", c.skipped-1)
+						h.dropped.Add(1)
+						h.remove(c)
+					}
+				}
+			}
+
+		case d := <-h.direct:
+			if h.clients[d.client] {
+				select {
+				case d.client.send <- d.message:
+				default:
+					h.dropped.Add(1)
+					h.remove(d.client)
+				}
+			}
+
+		case reply := <-h.count:
+			reply <- len(h.clients)
+
+		case <-h.done:
+			for c := range h.clients {
+				h.remove(c)
+			}
+			return
+		}
+	}
+}
+
+// remove closes a client's send channel, which makes its writer hang up
+func (h *hub) remove(c *wsClient) {
+	if _, ok := h.clients[c]; ok {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// Register adds a client to the hub
+func (h *hub) Register(c *wsClient) {
+	select {
+	case h.register <- c:
+	case <-h.done:
+		close(c.send)
+	}
+}
+
+// Unregister removes a client from the hub
+func (h *hub) Unregister(c *wsClient) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
+
+// Broadcast sends a message to every registered client
+func (h *hub) Broadcast(message *websocket.PreparedMessage) {
+	select {
+	case h.broadcast <- message:
+	case <-h.done:
+	}
+}
+
+// Send delivers a message to a single registered client
+func (h *hub) Send(c *wsClient, message *websocket.PreparedMessage) {
+	select {
+	case h.direct <- directMessage{client: c, message: message}:
+	case <-h.done:
+	}
+}
+
+// Count returns the number of connected clients
+func (h *hub) Count() int {
+	reply := make(chan int, 1)
+	select {
+	case h.count <- reply:
+		return <-reply
+	case <-h.done:
+		return 0
+	}
+}
+
+// stop disconnects all clients and stops the hub
+func (h *hub) stop() {
+	h.stopOnce.Do(func() { close(h.done) })
+}
+
+// writePump writes queued messages to the connection. When send is closed
+// the client is sent a close frame and the connection is closed.
+func (c *wsClient) writePump() {
+	defer c.conn.Close()
+
+	for message := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WritePreparedMessage(message); err != nil {
+			slog.Warn("Failed to send data to WebSocket client", "error", err)
+			// Closing the connection ends the reader, which unregisters the
+			// client; drain until then so the hub never blocks on us
+			c.conn.Close()
+			for range c.send {
+			}
+			return
+		}
+	}
+
+	c.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+		time.Now().Add(time.Second))
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// testClient is a client without a connection; tests read its send channel
+func testClient() *wsClient {
+	return &wsClient{send: make(chan *websocket.PreparedMessage, clientSendBuffer)}
+}
+
+// testMessage prepares a text message
+func testMessage(t *testing.T, text string) *websocket.PreparedMessage {
+	t.Helper()
+	message, err := websocket.NewPreparedMessage(websocket.TextMessage, []byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+// receive returns the next message queued for c, failing t if there is
+// none or c was removed
+func receive(t *testing.T, c *wsClient) *websocket.PreparedMessage {
+	t.Helper()
+	select {
+	case message, ok := <-c.send:
+		if !ok {
+			t.Fatal("client was removed")
+		}
+		return message
+	case <-time.After(time.Second):
+		t.Fatal("no message queued")
+		return nil
+	}
+}
+
+// removed reports whether c's send channel was closed, draining anything
+// still queued
+func removed(c *wsClient) bool {
+	for {
+		select {
+		case _, ok := <-c.send:
+			if !ok {
+				return true
+			}
+		case <-time.After(time.Second):
+			return false
+		}
+	}
+}
+
+func TestHubBroadcast(t *testing.T) {
+	h := newHub()
+	go h.run()
+	defer h.stop()
+
+	a, b := testClient(), testClient()
+	h.Register(a)
+	h.Register(b)
+	if got := h.Count(); got != 2 {
+		t.Fatalf("Count() = %d, want 2", got)
+	}
+
+	message := testMessage(t, "snapshot")
+	h.Broadcast(message)
+	if receive(t, a) != message || receive(t, b) != message {
+		t.Error("broadcast did not reach both clients")
+	}
+
+	only := testMessage(t, "resume")
+	h.Send(b, only)
+	if receive(t, b) != only {
+		t.Error("direct message did not reach its client")
+	}
+	select {
+	case <-a.send:
+		t.Error("direct message reached another client")
+	default:
+	}
+
+	h.Unregister(a)
+	if !removed(a) {
+		t.Error("unregistered client was not closed")
+	}
+	if got := h.Count(); got != 1 {
+		t.Errorf("Count() = %d after unregistering, want 1", got)
+	}
+}
+
+func TestHubDropsSlowClient(t *testing.T) {
+	h := newHub()
+	go h.run()
+	defer h.stop()
+
+	slow, fast := testClient(), testClient()
+	h.Register(slow)
+	h.Register(fast)
+
+	// The slow client fills its buffer, then misses broadcasts until it
+	// has missed more than maxSkippedBroadcasts in a row
+	message := testMessage(t, "snapshot")
+	for i := 0; i < clientSendBuffer+maxSkippedBroadcasts; i++ {
+		h.Broadcast(message)
+		receive(t, fast)
+	}
+	if got := h.Count(); got != 2 {
+		t.Fatalf("Count() = %d before the limit, want 2", got)
+	}
+
+	h.Broadcast(message)
+	receive(t, fast)
+	if got := h.Count(); got != 1 {
+		t.Errorf("Count() = %d after the limit, want 1", got)
+	}
+	if got := h.dropped.Load(); got != 1 {
+		t.Errorf("dropped = %d, want 1", got)
+	}
+	if !removed(slow) {
+		t.Error("slow client was not closed")
+	}
+}
+
+func TestHubStop(t *testing.T) {
+	h := newHub()
+	go h.run()
+
+	c := testClient()
+	h.Register(c)
+	h.stop()
+	h.stop()
+	if !removed(c) {
+		t.Error("client was not closed on stop")
+	}
+
+	// Calls after stop return instead of blocking
+	late := testClient()
+	h.Register(late)
+	if !removed(late) {
+		t.Error("client registered after stop was not closed")
+	}
+	h.Broadcast(testMessage(t, "snapshot"))
+	h.Unregister(c)
+	if got := h.Count(); got != 0 {
+		t.Errorf("Count() = %d after stop, want 0", got)
+	}
+}
@@ -0,0 +1,218 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+const (
+	// defaultIdleWindow is how long a deployment must stay quiet to be reported
+	defaultIdleWindow = 7 * 24 * time.Hour
+	// idleSampleInterval is how often deployment activity is sampled
+	idleSampleInterval = 5 * time.Minute
+	// idleCPUPerReplicaMilli is the CPU usage per replica at or below which
+	// a deployment counts as quiet
+	idleCPUPerReplicaMilli = 5
+)
+
+// IdleWorkloadData represents a scale-down candidate for JSON response
+type IdleWorkloadData struct {
+	Namespace     string    `json:"namespace"`
+	Name          string    `json:"name"`
+	Replicas      int32     `json:"replicas"`
+	CPUUsageMilli int64     `json:"cpuUsageMilli"`
+	IdleSince     time.Time `json:"idleSince"`
+	Savings       Savings   `json:"savings"`
+}
+
+// Savings are the requests freed by scaling a workload to zero
+type Savings struct {
+	CPURequestMilli    int64 `json:"cpuRequestMilli"`
+	MemoryRequestBytes int64 `json:"memoryRequestBytes"`
+}
+
+// IdleResponse is the response body of /api/idle
+type IdleResponse struct {
+	// Window is the quiet period required, as a Go duration
+	Window string `json:"window"`
+	// TrackingSince is when sampling started; nothing can have been
+	// idle for longer than the server has been observing it
+	TrackingSince time.Time          `json:"trackingSince"`
+	Candidates    []IdleWorkloadData `json:"candidates"`
+	Total         Savings            `json:"total"`
+}
+
+// idleState records when a deployment was first seen quiet
+type idleState struct {
+	since    time.Time
+	restarts int32
+	activity k8s.DeploymentActivity
+}
+
+// idleTracker follows how long each deployment has stayed quiet: near-zero
+// CPU, no new restarts and no traffic reported through
+// k8s.LastTrafficAnnotation. State is kept in memory, so the window starts
+// over when the server restarts.
+type idleTracker struct {
+	mu      sync.Mutex
+	started time.Time
+	quiet   map[string]idleState
+}
+
+// newIdleTracker creates an empty tracker
+func newIdleTracker() *idleTracker {
+	return &idleTracker{started: time.Now(), quiet: make(map[string]idleState)}
+}
+
+// observe updates the tracker with one activity sample taken at now
+func (t *idleTracker) observe(activities []k8s.DeploymentActivity, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := make(map[string]bool, len(activities))
+	for _, activity := range activities {
+		key := activity.Namespace + "/" + activity.Name
+		seen[key] = true
+
+		quiet := activity.Replicas > 0 && activity.CPUUsageMilli <= idleCPUPerReplicaMilli*int64(activity.Replicas)
+		state, tracked := t.quiet[key]
+		if !quiet || (tracked && activity.Restarts > state.restarts) {
+			delete(t.quiet, key)
+			continue
+		}
+
+		if !tracked {
+			state.since = now
+		}
+		if activity.LastTraffic.After(state.since) {
+			state.since = activity.LastTraffic
+		}
+		state.restarts = activity.Restarts
+		state.activity = activity
+		t.quiet[key] = state
+	}
+
+	// Deleted deployments are no longer candidates
+	for key := range t.quiet {
+		if !seen[key] {
+			delete(t.quiet, key)
+		}
+	}
+}
+
+// candidates returns deployments quiet for at least window, optionally
+// limited to the selected namespaces
+func (t *idleTracker) candidates(window time.Duration, namespace string, now time.Time) []IdleWorkloadData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	candidates := []IdleWorkloadData{}
+	for _, state := range t.quiet {
+		activity := state.activity
+		if now.Sub(state.since) < window || !k8s.InNamespaces(namespace, activity.Namespace) {
+			continue
+		}
+		candidates = append(candidates, IdleWorkloadData{
+			Namespace:     activity.Namespace,
+			Name:          activity.Name,
+			Replicas:      activity.Replicas,
+			CPUUsageMilli: activity.CPUUsageMilli,
+			IdleSince:     state.since,
+			Savings: Savings{
+				CPURequestMilli:    activity.CPURequestMilli,
+				MemoryRequestBytes: activity.MemoryRequestBytes,
+			},
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Namespace != candidates[j].Namespace {
+			return candidates[i].Namespace < candidates[j].Namespace
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	return candidates
+}
+
+// SetIdleWindow sets how long a deployment must stay quiet before it is
+// listed at /api/idle. Non-positive values keep the default of 7 days.
+func (s *Server) SetIdleWindow(window time.Duration) {
+	if window > 0 {
+		s.idleWindow = window
+	}
+}
+
+// sampleIdle feeds deployment activity into the idle tracker every
+// idleSampleInterval until ctx is done. It needs live usage, so it only
+// runs with metrics.
+func (s *Server) sampleIdle(ctx context.Context) {
+	if !s.kinds.Enabled(k8s.KindMetrics) || !s.kinds.Enabled(k8s.KindDeployments) || !s.kinds.Enabled(k8s.KindPods) {
+		return
+	}
+
+	ticker := time.NewTicker(idleSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		s.observeIdle(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// observeIdle takes a single activity sample
+func (s *Server) observeIdle(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pods, err := s.client.GetPods(ctx, "")
+	if err != nil {
+		slog.Error("Failed to sample idle workloads", "error", err)
+		return
+	}
+	// Without usage every deployment would look idle, so skip the sample
+	if err := s.metrics.AnnotatePods(ctx, "", pods); err != nil {
+		slog.Error("Failed to sample idle workloads", "error", err)
+		return
+	}
+
+	activities, err := s.client.GetDeploymentActivity(ctx, "", pods)
+	if err != nil {
+		slog.Error("Failed to sample idle workloads", "error", err)
+		return
+	}
+	s.idle.observe(activities, time.Now())
+}
+
+// handleIdle lists deployments that have been quiet for the idle window as
+// scale-down candidates, with the requests that scaling them to zero frees
+func (s *Server) handleIdle(w http.ResponseWriter, r *http.Request) {
+	if !s.kinds.Enabled(k8s.KindMetrics) {
+		http.Error(w, "Idle detection needs the metrics resource kind", http.StatusNotFound)
+		return
+	}
+
+	candidates := s.idle.candidates(s.idleWindow, namespaceParam(r), time.Now())
+
+	response := IdleResponse{
+		Window:        s.idleWindow.String(),
+		TrackingSince: s.idle.started,
+		Candidates:    candidates,
+	}
+	for _, candidate := range candidates {
+		response.Total.CPURequestMilli += candidate.Savings.CPURequestMilli
+		response.Total.MemoryRequestBytes += candidate.Savings.MemoryRequestBytes
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
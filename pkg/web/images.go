@@ -0,0 +1,77 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// ImageData represents one image repository for JSON response
+type ImageData struct {
+	Repository       string             `json:"repository"`
+	Versions         []ImageVersionData `json:"versions"`
+	Containers       int                `json:"containers"`
+	Namespaces       []string           `json:"namespaces"`
+	UsesLatest       bool               `json:"usesLatest"`
+	MultipleVersions bool               `json:"multipleVersions"`
+}
+
+// ImageVersionData represents one tag or digest for JSON response
+type ImageVersionData struct {
+	Version    string   `json:"version"`
+	Containers int      `json:"containers"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// ImagesResponse is the response body of /api/images
+type ImagesResponse struct {
+	Images []ImageData `json:"images"`
+
+	// Counts of repositories flagged by UsesLatest and MultipleVersions
+	LatestTagged     int `json:"latestTagged"`
+	MultipleVersions int `json:"multipleVersions"`
+}
+
+// handleImages serves /api/images, the container images running in the
+// cluster (or ?namespace=) grouped by repository
+func (s *Server) handleImages(w http.ResponseWriter, r *http.Request) {
+	pods, err := s.client.GetPods(r.Context(), namespaceParam(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pods: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toImagesResponse(k8s.ImageInventory(pods)))
+}
+
+// toImagesResponse converts an image inventory to its response format
+func toImagesResponse(images []k8s.ImageInfo) ImagesResponse {
+	response := ImagesResponse{Images: make([]ImageData, len(images))}
+	for i, image := range images {
+		data := ImageData{
+			Repository:       image.Repository,
+			Versions:         make([]ImageVersionData, len(image.Versions)),
+			Containers:       image.Containers,
+			Namespaces:       image.Namespaces,
+			UsesLatest:       image.UsesLatest(),
+			MultipleVersions: image.MultipleVersions(),
+		}
+		for j, version := range image.Versions {
+			data.Versions[j] = ImageVersionData{
+				Version:    version.Version,
+				Containers: version.Containers,
+				Namespaces: version.Namespaces,
+			}
+		}
+
+		if data.UsesLatest {
+			response.LatestTagged++
+		}
+		if data.MultipleVersions {
+			response.MultipleVersions++
+		}
+		response.Images[i] = data
+	}
+	return response
+}
@@ -0,0 +1,53 @@
+package web
+
+import "pod-visualizer/pkg/k8s"
+
+// IngressData represents an Ingress or HTTPRoute for JSON response
+type IngressData struct {
+	UID       string      `json:"uid"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Kind      string      `json:"kind"`
+	Class     string      `json:"class,omitempty"`
+	Routes    []RouteData `json:"routes"`
+}
+
+// RouteData represents one host/path → Service hop for JSON response
+type RouteData struct {
+	Host              string `json:"host"`
+	Path              string `json:"path"`
+	Service           string `json:"service"`
+	Namespace         string `json:"namespace"`
+	Port              string `json:"port,omitempty"`
+	ServiceFound      bool   `json:"serviceFound"`
+	ReadyEndpoints    int    `json:"readyEndpoints"`
+	NotReadyEndpoints int    `json:"notReadyEndpoints"`
+}
+
+// toIngressData converts ingresses and routes to their response format
+func toIngressData(ingresses []k8s.IngressInfo) []IngressData {
+	ingressData := make([]IngressData, len(ingresses))
+	for i, ingress := range ingresses {
+		ingressData[i] = IngressData{
+			UID:       ingress.UID,
+			Name:      ingress.Name,
+			Namespace: ingress.Namespace,
+			Kind:      ingress.Kind,
+			Class:     ingress.Class,
+			Routes:    make([]RouteData, len(ingress.Routes)),
+		}
+		for j, route := range ingress.Routes {
+			ingressData[i].Routes[j] = RouteData{
+				Host:              route.Host,
+				Path:              route.Path,
+				Service:           route.Service,
+				Namespace:         route.Namespace,
+				Port:              route.Port,
+				ServiceFound:      route.ServiceFound,
+				ReadyEndpoints:    route.ReadyEndpoints,
+				NotReadyEndpoints: route.NotReadyEndpoints,
+			}
+		}
+	}
+	return ingressData
+}
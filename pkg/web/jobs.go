@@ -0,0 +1,83 @@
+package web
+
+import (
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// JobData represents job data for JSON response
+type JobData struct {
+	UID            string     `json:"uid"`
+	Name           string     `json:"name"`
+	Namespace      string     `json:"namespace"`
+	Owner          string     `json:"owner,omitempty"`
+	Completions    int32      `json:"completions"`
+	Succeeded      int32      `json:"succeeded"`
+	Failed         int32      `json:"failed"`
+	Active         int32      `json:"active"`
+	Complete       bool       `json:"complete"`
+	JobFailed      bool       `json:"jobFailed"`
+	StartTime      *time.Time `json:"startTime,omitempty"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+}
+
+// CronJobData represents cronjob data for JSON response
+type CronJobData struct {
+	UID                string     `json:"uid"`
+	Name               string     `json:"name"`
+	Namespace          string     `json:"namespace"`
+	Schedule           string     `json:"schedule"`
+	Suspended          bool       `json:"suspended"`
+	Active             int        `json:"active"`
+	LastScheduleTime   *time.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time `json:"lastSuccessfulTime,omitempty"`
+}
+
+// toJobData converts jobs to their response format
+func toJobData(jobs []k8s.JobInfo) []JobData {
+	jobData := make([]JobData, len(jobs))
+	for i, job := range jobs {
+		jobData[i] = JobData{
+			UID:            job.UID,
+			Name:           job.Name,
+			Namespace:      job.Namespace,
+			Owner:          job.Owner,
+			Completions:    job.Completions,
+			Succeeded:      job.Succeeded,
+			Failed:         job.Failed,
+			Active:         job.Active,
+			Complete:       job.Complete,
+			JobFailed:      job.JobFailed,
+			StartTime:      optionalTime(job.StartTime),
+			CompletionTime: optionalTime(job.CompletionTime),
+		}
+	}
+	return jobData
+}
+
+// toCronJobData converts cronjobs to their response format
+func toCronJobData(cronJobs []k8s.CronJobInfo) []CronJobData {
+	cronJobData := make([]CronJobData, len(cronJobs))
+	for i, cronJob := range cronJobs {
+		cronJobData[i] = CronJobData{
+			UID:                cronJob.UID,
+			Name:               cronJob.Name,
+			Namespace:          cronJob.Namespace,
+			Schedule:           cronJob.Schedule,
+			Suspended:          cronJob.Suspended,
+			Active:             cronJob.Active,
+			LastScheduleTime:   optionalTime(cronJob.LastScheduleTime),
+			LastSuccessfulTime: optionalTime(cronJob.LastSuccessfulTime),
+		}
+	}
+	return cronJobData
+}
+
+// optionalTime returns nil for zero times so they are omitted from JSON
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Lease timings, the client-go defaults used by controllers: a dead leader
+// is replaced within about 15s
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// LeaderElection identifies the Lease that replicas of the server compete
+// for, and this replica among them
+type LeaderElection struct {
+	Namespace string
+	Name      string
+	Identity  string
+}
+
+// SetLeaderElection makes replicas share the watch load: only the replica
+// holding the Lease watches the cluster and evaluates alerts, while the
+// others keep their snapshot current by polling, at most every
+// followerRefreshInterval, and serve reads from it
+func (s *Server) SetLeaderElection(election LeaderElection) {
+	s.election = &election
+}
+
+// leading reports whether this replica runs the watchers; it always does
+// without leader election
+func (s *Server) leading() bool {
+	return s.election == nil || s.leader.Load()
+}
+
+// setLeading records whether this replica holds the Lease, and has the
+// refresh ticker pick up the interval that goes with it
+func (s *Server) setLeading(leading bool) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+
+	s.leader.Store(leading)
+	s.planChanged()
+}
+
+// runElection competes for the Lease until ctx is done, calling lead with
+// a context that is cancelled when leadership is lost
+func (s *Server) runElection(ctx context.Context, lead func(ctx context.Context)) {
+	election := *s.election
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: election.Namespace, Name: election.Name},
+		Client:     s.client.GetClientset().CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: election.Identity},
+	}
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            election.Name,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				s.setLeading(true)
+				slog.Info("Acquired leader lease, starting watchers", "lease", election.Namespace+"/"+election.Name, "identity", election.Identity)
+				lead(ctx)
+			},
+			OnStoppedLeading: func() {
+				s.setLeading(false)
+				slog.Info("Lost leader lease, stopping watchers", "lease", election.Namespace+"/"+election.Name)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != election.Identity {
+					slog.Info("Following leader", "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		slog.Error("Invalid leader election config, watchers will not run", "error", err)
+		return
+	}
+
+	// Run returns when leadership is lost; stand for election again
+	for ctx.Err() == nil {
+		elector.Run(ctx)
+	}
+}
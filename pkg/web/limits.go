@@ -0,0 +1,99 @@
+package web
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultMaxWebSocketClients caps concurrent dashboard connections
+	defaultMaxWebSocketClients = 500
+
+	// maxClientMessageSize bounds a single message read from a client;
+	// clients only ever send small control messages such as resync
+	maxClientMessageSize = 4096
+
+	// Clients may send a short burst of control messages, then one per
+	// second; excess messages are ignored
+	clientMessageRate  = 1
+	clientMessageBurst = 5
+
+	// wsRetryAfter is suggested to clients turned away at the limit
+	wsRetryAfter = 30 * time.Second
+)
+
+var (
+	errTooManyClients      = errors.New("too many WebSocket clients")
+	errTooManyClientsForIP = errors.New("too many WebSocket clients from this address")
+)
+
+// connLimiter admits WebSocket connections up to a total and a per-client
+// address limit; zero disables a limit
+type connLimiter struct {
+	mu       sync.Mutex
+	max      int
+	maxPerIP int
+	total    int
+	perIP    map[string]int
+
+	rejectedTotal atomic.Int64
+	rejectedPerIP atomic.Int64
+}
+
+// newConnLimiter creates a limiter with the default total limit
+func newConnLimiter() *connLimiter {
+	return &connLimiter{max: defaultMaxWebSocketClients, perIP: make(map[string]int)}
+}
+
+// setLimits replaces the limits; connections already admitted are kept
+func (l *connLimiter) setLimits(max, maxPerIP int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.max, l.maxPerIP = max, maxPerIP
+}
+
+// acquire admits a connection from ip, or reports which limit it hit.
+// Every successful acquire must be paired with a release.
+func (l *connLimiter) acquire(ip string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.max > 0 && l.total >= l.max {
+		l.rejectedTotal.Add(1)
+		return errTooManyClients
+	}
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		l.rejectedPerIP.Add(1)
+		return errTooManyClientsForIP
+	}
+	l.total++
+	l.perIP[ip]++
+	return nil
+}
+
+// release frees a connection admitted by acquire
+func (l *connLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total--
+	if l.perIP[ip]--; l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// newMessageLimiter creates the rate limiter for one client's messages
+func newMessageLimiter() *rate.Limiter {
+	return rate.NewLimiter(clientMessageRate, clientMessageBurst)
+}
+
+// SetWebSocketLimits caps concurrent WebSocket connections in total and
+// per client address (0 = unlimited). Behind a proxy every client shares
+// the proxy's address, so keep the per-address limit off there.
+func (s *Server) SetWebSocketLimits(maxClients, maxPerIP int) {
+	s.wsLimiter.setLimits(maxClients, maxPerIP)
+}
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// listenFdsStart is the first file descriptor systemd passes (SD_LISTEN_FDS_START)
+const listenFdsStart = 3
+
+// Socket names recognised in LISTEN_FDNAMES (FileDescriptorName= in the
+// .socket unit). Unnamed sockets are assigned in this order.
+var socketNames = []string{"http", "grpc"}
+
+// SetBindAddress sets the address the HTTP and gRPC servers listen on,
+// e.g. 127.0.0.1 to accept only local connections. Empty binds all interfaces.
+func (s *Server) SetBindAddress(address string) {
+	s.bindAddress = address
+}
+
+// listen returns the socket-activated listener for name, or listens on
+// the bind address and port
+func (s *Server) listen(name string, port int) (net.Listener, error) {
+	if listener, ok := s.activated[name]; ok {
+		delete(s.activated, name)
+		return listener, nil
+	}
+	return net.Listen("tcp", net.JoinHostPort(s.bindAddress, strconv.Itoa(port)))
+}
+
+// displayHost is the host shown in startup log messages
+func (s *Server) displayHost() string {
+	if s.bindAddress == "" || s.bindAddress == "0.0.0.0" || s.bindAddress == "::" {
+		return "localhost"
+	}
+	return s.bindAddress
+}
+
+// socketActivation takes over the listening sockets passed by systemd
+// socket activation, keyed by name. It returns nil when the process was
+// not socket-activated. The LISTEN_* variables are unset so that child
+// processes do not try to claim the same sockets.
+func socketActivation() (map[string]net.Listener, error) {
+	if os.Getenv("LISTEN_PID") != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LISTEN_FDS: %v", err)
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make(map[string]net.Listener)
+	for i := 0; i < count; i++ {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		if !contains(socketNames, name) {
+			if i >= len(socketNames) {
+				return nil, fmt.Errorf("unexpected socket %d (%q): name sockets http or grpc", i, name)
+			}
+			name = socketNames[i]
+		}
+
+		file := os.NewFile(uintptr(listenFdsStart+i), name)
+		listener, err := net.FileListener(file)
+		// FileListener duplicates the descriptor, so the original can go
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to use activated socket %s: %v", name, err)
+		}
+		listeners[name] = listener
+		slog.Info("Using socket-activated listener", "name", name, "address", listener.Addr().String())
+	}
+
+	return listeners, nil
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
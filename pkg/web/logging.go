@@ -0,0 +1,32 @@
+package web
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+// requestLogger returns a logger carrying the request's ID, its client
+// address and, when filtered, its namespace
+func requestLogger(r *http.Request) *slog.Logger {
+	logger := slog.With("client_ip", clientIP(r), "path", r.URL.Path)
+	if id := requestID(r.Context()); id != "" {
+		logger = logger.With("request_id", id)
+	}
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		logger = logger.With("forwarded_for", forwarded)
+	}
+	if namespace := namespaceParam(r); namespace != "" {
+		logger = logger.With("namespace", namespace)
+	}
+	return logger
+}
+
+// clientIP returns the host part of the request's remote address
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,37 @@
+package web
+
+import (
+	"net/http"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// GetName returns the pod's name
+func (p PodData) GetName() string {
+	return p.Name
+}
+
+// GetName returns the deployment's name
+func (d DeploymentData) GetName() string {
+	return d.Name
+}
+
+// GetName returns the rollout's name
+func (r ArgoRolloutData) GetName() string {
+	return r.Name
+}
+
+// nameFilterParam parses ?nameFilter=, a glob or /regexp/ matched against
+// pod, deployment and rollout names, see k8s.NameFilter
+func nameFilterParam(r *http.Request) (*k8s.NameFilter, error) {
+	return k8s.ParseNameFilter(r.URL.Query().Get("nameFilter"))
+}
+
+// filterByName narrows data to the pods, deployments and rollouts whose
+// names match filter. Other kinds are left as they are.
+func filterByName(data ClusterData, filter *k8s.NameFilter) ClusterData {
+	data.Pods = append([]PodData{}, k8s.FilterByName(data.Pods, filter)...)
+	data.Deployments = append([]DeploymentData{}, k8s.FilterByName(data.Deployments, filter)...)
+	data.ArgoRollouts = k8s.FilterByName(data.ArgoRollouts, filter)
+	return data
+}
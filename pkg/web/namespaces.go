@@ -0,0 +1,62 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// NamespaceData represents namespace data for JSON response
+type NamespaceData struct {
+	Name        string `json:"name"`
+	Phase       string `json:"phase"`
+	PodCount    int    `json:"podCount"`
+	RunningPods int    `json:"runningPods"`
+	FailingPods int    `json:"failingPods"`
+}
+
+// NamespacesResponse is the response body of /api/namespaces
+type NamespacesResponse struct {
+	Namespaces []NamespaceData `json:"namespaces"`
+}
+
+// namespaceParam returns the namespaces a request selects with
+// ?namespaces=a,b or ?namespace=a, in the canonical form k8s.SplitNamespaces
+// takes, so that one selection always maps to one cache entry. It is empty
+// for all namespaces.
+func namespaceParam(r *http.Request) string {
+	query := r.URL.Query()
+	selection := query.Get("namespace")
+	if namespaces := query.Get("namespaces"); namespaces != "" {
+		selection = namespaces
+	}
+	return strings.Join(k8s.SplitNamespaces(selection), ",")
+}
+
+// handleNamespaces lists namespaces with pod counts for the namespace picker
+func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
+	namespaces, err := s.client.GetNamespaces(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get namespaces: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NamespacesResponse{Namespaces: toNamespaceData(namespaces)})
+}
+
+// toNamespaceData converts namespaces to their response format
+func toNamespaceData(namespaces []k8s.NamespaceInfo) []NamespaceData {
+	namespaceData := make([]NamespaceData, len(namespaces))
+	for i, namespace := range namespaces {
+		namespaceData[i] = NamespaceData{
+			Name:        namespace.Name,
+			Phase:       namespace.Phase,
+			PodCount:    namespace.PodCount,
+			RunningPods: namespace.RunningPods,
+			FailingPods: namespace.FailingPods,
+		}
+	}
+	return namespaceData
+}
@@ -0,0 +1,386 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// swaggerUIVersion is the swagger-ui-dist release the /api/docs page loads
+const swaggerUIVersion = "5.17.14"
+
+// apiParam is a path or query parameter of an API operation
+type apiParam struct {
+	name        string
+	in          string
+	kind        string
+	description string
+	enum        []string
+	required    bool
+}
+
+// pathParam returns a required string path parameter
+func pathParam(name, description string) apiParam {
+	return apiParam{name: name, in: "path", kind: "string", description: description, required: true}
+}
+
+// queryParam returns an optional query parameter of kind string, integer
+// or boolean
+func queryParam(name, kind, description string) apiParam {
+	return apiParam{name: name, in: "query", kind: kind, description: description}
+}
+
+// apiOperation describes one endpoint of the HTTP API. The request and
+// response fields hold a value of the JSON body's Go type, nil for none.
+type apiOperation struct {
+	method   string
+	path     string
+	id       string
+	summary  string
+	params   []apiParam
+	request  any
+	manifest bool // the request body is a YAML or JSON manifest
+	status   int
+	response any
+	public   bool // served without authentication
+}
+
+var (
+	namespaceFilter  = queryParam("namespace", "string", "Only this namespace")
+	namespacesFilter = queryParam("namespaces", "string", "Only these comma-separated namespaces, in place of namespace")
+	nodeFilter       = queryParam("node", "string", "Only pods on this node")
+	podParams        = []apiParam{pathParam("namespace", "Pod namespace"), pathParam("name", "Pod name")}
+	deploymentParam  = []apiParam{pathParam("namespace", "Deployment namespace"), pathParam("name", "Deployment name")}
+)
+
+// apiOperations lists the endpoints described by the OpenAPI document.
+// The pod and deployment actions answer 404 unless actions are enabled;
+// exec and /ws are WebSockets and /metrics is Prometheus text, so they are
+// left out.
+var apiOperations = []apiOperation{
+	{method: http.MethodGet, path: "/api", id: "getAPIVersions",
+		summary: "Lists the API versions the server serves", response: APIVersions{}},
+	{method: http.MethodGet, path: apiPrefix + "/ui-config", id: "getUIConfig",
+		summary: "Returns the bootstrap configuration of the dashboard", response: UIConfig{}},
+	{method: http.MethodGet, path: apiPrefix + "/cluster", id: "getCluster",
+		summary: "Returns the pods, deployments and nodes of the cluster",
+		params: []apiParam{
+			namespaceFilter,
+			namespacesFilter,
+			nodeFilter,
+			queryParam("nameFilter", "string", "Only pods, deployments and rollouts whose names match this glob, such as web-*, or /regexp/"),
+			queryParam("problemsOnly", "boolean", "Only the pods, deployments, rollouts, nodes and other resources that need attention"),
+			queryParam("hideCompleted", "boolean", "Leave out Completed pods, those whose containers all exited successfully"),
+			queryParam("qos", "string", "Only pods in these comma-separated QoS classes: Guaranteed, Burstable or BestEffort"),
+			queryParam("priorityClass", "string", "Only pods with these comma-separated priority class names; an empty name matches pods without one"),
+			{name: "sortBy", in: "query", kind: "string", description: "Pod sort order", enum: k8s.PodSortOrders},
+			queryParam("reverse", "boolean", "Reverse the pod sort order"),
+			queryParam("limit", "integer", "Page size; pages cannot be combined with sortBy or reverse"),
+			queryParam("cursor", "string", "The nextCursor of the previous page"),
+		},
+		response: ClusterData{}},
+	{method: http.MethodGet, path: apiPrefix + "/namespaces", id: "listNamespaces",
+		summary: "Lists namespaces with their pod counts", response: NamespacesResponse{}},
+
+	{method: http.MethodGet, path: apiPrefix + "/pods/{namespace}/{name}", id: "getPod",
+		summary: "Describes a pod", params: podParams, response: PodDetailData{}},
+	{method: http.MethodPost, path: apiPrefix + "/pods/{namespace}/{name}/delete", id: "deletePod",
+		summary: "Deletes a pod, leaving its controller, if any, to replace it", params: podParams,
+		status: http.StatusAccepted},
+	{method: http.MethodGet, path: apiPrefix + "/pods/{namespace}/{name}/portforward", id: "listPortForwards",
+		summary: "Lists the open port-forwards to a pod", params: podParams, response: []PortForwardData{}},
+	{method: http.MethodPost, path: apiPrefix + "/pods/{namespace}/{name}/portforward", id: "openPortForward",
+		summary: "Opens a port-forward to a pod on the server's loopback interface", params: podParams,
+		request: PortForwardRequest{}, status: http.StatusCreated, response: PortForwardData{}},
+	{method: http.MethodDelete, path: apiPrefix + "/pods/{namespace}/{name}/portforward", id: "closePortForward",
+		summary: "Closes a port-forward to a pod",
+		params: []apiParam{podParams[0], podParams[1],
+			{name: "id", in: "query", kind: "string", description: "Port-forward ID", required: true}},
+		status: http.StatusNoContent},
+
+	{method: http.MethodGet, path: apiPrefix + "/deployments/{namespace}/{name}", id: "getDeployment",
+		summary: "Describes a deployment", params: deploymentParam, response: DeploymentDetailData{}},
+	{method: http.MethodPost, path: apiPrefix + "/deployments/{namespace}/{name}/restart", id: "restartDeployment",
+		summary: "Rolls every pod of a deployment, like kubectl rollout restart", params: deploymentParam,
+		status: http.StatusAccepted},
+	{method: http.MethodPost, path: apiPrefix + "/deployments/{namespace}/{name}/scale", id: "scaleDeployment",
+		summary: "Sets the desired replicas of a deployment", params: deploymentParam,
+		request: ScaleRequest{}, response: ScaleData{}},
+	{method: http.MethodPost, path: apiPrefix + "/preview", id: "previewDeployment",
+		summary:  "Reports how a Deployment manifest would render before it is applied",
+		manifest: true, response: PreviewData{}},
+
+	{method: http.MethodGet, path: apiPrefix + "/topology", id: "getTopology",
+		summary: "Returns the ownership trees of the cluster's workloads",
+		params:  []apiParam{namespaceFilter, namespacesFilter}, response: []TopologyNodeData{}},
+	{method: http.MethodGet, path: apiPrefix + "/topology/zones", id: "getZones",
+		summary: "Groups the nodes and pods by topology zone, flagging deployments concentrated in one zone",
+		params:  []apiParam{namespaceFilter, namespacesFilter}, response: ZonesResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/snapshot", id: "getSnapshot",
+		summary: "Returns a freshly fetched snapshot of the cluster",
+		params:  []apiParam{namespaceFilter, namespacesFilter, nodeFilter}, response: ClusterData{}},
+	{method: http.MethodGet, path: apiPrefix + "/idle", id: "getIdle",
+		summary: "Lists deployments quiet for the idle window as scale-down candidates",
+		params:  []apiParam{namespaceFilter, namespacesFilter}, response: IdleResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/images", id: "getImages",
+		summary: "Lists the running container images by repository",
+		params:  []apiParam{namespaceFilter, namespacesFilter}, response: ImagesResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/summary", id: "getSummary",
+		summary: "Returns per-namespace aggregates of the cluster",
+		params:  []apiParam{namespaceFilter, namespacesFilter}, response: SummaryResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/overview", id: "getOverview",
+		summary:  "Returns the node count, allocatable and requested CPU and memory, pods per node and Kubernetes version",
+		response: OverviewResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/problems", id: "getProblems",
+		summary:  "Ranks the most broken resources for triage",
+		params:   []apiParam{namespaceFilter, namespacesFilter, queryParam("limit", "integer", "Cap on each list")},
+		response: ProblemsResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/history", id: "getHistory",
+		summary:  "Returns the readiness samples of the last hours",
+		params:   []apiParam{queryParam("hours", "integer", fmt.Sprintf("How many hours back, default %d", defaultHistoryHours))},
+		response: HistoryResponse{}},
+	{method: http.MethodGet, path: apiPrefix + "/startup", id: "getStartup",
+		summary: "Returns per-deployment percentiles of how long pods took from creation to Ready",
+		params: []apiParam{namespaceFilter, namespacesFilter,
+			queryParam("hours", "integer", fmt.Sprintf("Pods Ready in how many hours back, default %d", defaultHistoryHours))},
+		response: StartupResponse{}},
+	{method: http.MethodPost, path: apiPrefix + "/batch/describe", id: "batchDescribe",
+		summary: "Describes many pods in one call", request: BatchDescribeRequest{}, response: BatchDescribeResponse{}},
+
+	{method: http.MethodGet, path: "/health", id: "getHealth",
+		summary: "Reports that the server is up", response: map[string]string{}, public: true},
+	{method: http.MethodGet, path: "/ready", id: "getReady",
+		summary: "Reports whether the server can reach the Kubernetes API", response: map[string]string{}, public: true},
+}
+
+// OpenAPI returns the OpenAPI 3.0 document describing the HTTP API of a
+// server at version. Its schemas are derived from the response types, so
+// the document cannot drift from what the handlers encode.
+func OpenAPI(version string) ([]byte, error) {
+	schemas := schemaBuilder{components: map[string]any{}}
+	paths := map[string]map[string]any{}
+
+	for _, op := range apiOperations {
+		operation := map[string]any{
+			"operationId": op.id,
+			"summary":     op.summary,
+		}
+
+		if len(op.params) > 0 {
+			params := make([]any, len(op.params))
+			for i, param := range op.params {
+				schema := map[string]any{"type": param.kind}
+				if param.enum != nil {
+					schema["enum"] = param.enum
+				}
+				params[i] = map[string]any{
+					"name":        param.name,
+					"in":          param.in,
+					"description": param.description,
+					"required":    param.required,
+					"schema":      schema,
+				}
+			}
+			operation["parameters"] = params
+		}
+
+		switch {
+		case op.manifest:
+			manifest := map[string]any{"schema": map[string]any{"type": "string"}}
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/yaml": manifest, "application/json": manifest},
+			}
+		case op.request != nil:
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemas.schema(reflect.TypeOf(op.request))),
+			}
+		}
+
+		status := op.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		if op.response != nil {
+			success["content"] = jsonContent(schemas.schema(reflect.TypeOf(op.response)))
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(status): success,
+			"default": map[string]any{
+				"description": "Error message",
+				"content":     map[string]any{"text/plain": map[string]any{"schema": map[string]any{"type": "string"}}},
+			},
+		}
+		if op.public {
+			operation["security"] = []any{}
+		}
+
+		if paths[op.path] == nil {
+			paths[op.path] = map[string]any{}
+		}
+		paths[op.path][strings.ToLower(op.method)] = operation
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   uiTitle + " API",
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+				"basicAuth":  map[string]any{"type": "http", "scheme": "basic"},
+			},
+		},
+		// Either scheme, matching -auth-mode, or none without authentication
+		"security": []any{
+			map[string]any{"bearerAuth": []string{}},
+			map[string]any{"basicAuth": []string{}},
+			map[string]any{},
+		},
+	}, "", "  ")
+}
+
+// jsonContent returns a media type map for a JSON body
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaBuilder derives schemas from Go types as encoding/json encodes
+// them, collecting named structs as components
+type schemaBuilder struct {
+	components map[string]any
+}
+
+// schema returns the schema of t, a reference for named structs
+func (b *schemaBuilder) schema(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := b.schema(t.Elem())
+		if _, ok := schema["$ref"]; ok {
+			// Siblings of $ref are ignored in OpenAPI 3.0
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		if _, ok := b.components[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			b.components[t.Name()] = nil
+			b.components[t.Name()] = b.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// object returns the schema of a struct's JSON fields. Fields without
+// omitempty are always encoded, so they are required.
+func (b *schemaBuilder) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.schema(field.Type)
+		property["x-go-name"] = field.Name
+		properties[name] = property
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// handleOpenAPI serves the OpenAPI document of the server's API
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	body, err := OpenAPI(s.version)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to build OpenAPI document: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSONBytes(w, http.StatusOK, body)
+}
+
+// handleAPIDocs serves a Swagger UI page for /api/openapi.json. The page
+// loads Swagger UI from a CDN, so it needs the browser to have internet
+// access; the document itself does not.
+func (s *Server) handleAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, apiDocsPage, uiTitle, swaggerUIVersion, swaggerUIVersion)
+}
+
+// apiDocsPage is the Swagger UI page, formatted with the title and twice
+// the swagger-ui-dist version
+const apiDocsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@%s/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
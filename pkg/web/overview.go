@@ -0,0 +1,116 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// NodePodCount is how many pods run on a node
+type NodePodCount struct {
+	Node string `json:"node"`
+	Pods int    `json:"pods"`
+}
+
+// OverviewResponse is the response body of /api/overview, the capacity of
+// the cluster at a glance. Pods that finished, Succeeded or Failed, hold
+// no resources and are left out.
+type OverviewResponse struct {
+	// KubernetesVersion is empty when the server version is unavailable
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	Nodes      int `json:"nodes"`
+	ReadyNodes int `json:"readyNodes"`
+	Pods       int `json:"pods"`
+
+	CPUAllocatableMilli    int64 `json:"cpuAllocatableMilli"`
+	MemoryAllocatableBytes int64 `json:"memoryAllocatableBytes"`
+	CPURequestedMilli      int64 `json:"cpuRequestedMilli"`
+	MemoryRequestedBytes   int64 `json:"memoryRequestedBytes"`
+
+	// PodsPerNode counts the pods of every node, nodes without pods
+	// included, sorted by node name. Pods not yet bound to a node are
+	// UnscheduledPods.
+	PodsPerNode     []NodePodCount `json:"podsPerNode"`
+	MinPodsPerNode  int            `json:"minPodsPerNode"`
+	MaxPodsPerNode  int            `json:"maxPodsPerNode"`
+	UnscheduledPods int            `json:"unscheduledPods"`
+}
+
+// handleOverview serves /api/overview, the node count, allocatable and
+// requested CPU and memory, pods per node and Kubernetes version
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pods, err := s.client.GetPods(ctx, "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pods: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var nodes []k8s.NodeInfo
+	if s.kinds.Enabled(k8s.KindNodes) {
+		nodes, err = s.client.GetNodes(ctx)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get nodes: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	response := summarizeCapacity(nodes, pods)
+
+	// The version is optional; the header still works without it
+	if response.KubernetesVersion, err = s.client.ServerVersion(); err != nil {
+		requestLogger(r).Warn("Failed to get server version", "error", err)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// summarizeCapacity adds up the allocatable resources of nodes and the
+// requests of the pods still holding them, counting pods per node
+func summarizeCapacity(nodes []k8s.NodeInfo, pods []k8s.PodInfo) OverviewResponse {
+	response := OverviewResponse{Nodes: len(nodes)}
+	perNode := make(map[string]int)
+	for _, node := range nodes {
+		perNode[node.Name] = 0
+		if node.Ready {
+			response.ReadyNodes++
+		}
+		response.CPUAllocatableMilli += node.CPUAllocatableMilli
+		response.MemoryAllocatableBytes += node.MemoryAllocatableBytes
+	}
+
+	for _, pod := range pods {
+		if pod.Phase == string(corev1.PodSucceeded) || pod.Phase == string(corev1.PodFailed) {
+			continue
+		}
+		response.Pods++
+		response.CPURequestedMilli += pod.CPURequestMilli
+		response.MemoryRequestedBytes += pod.MemoryRequestBytes
+		if pod.NodeName == "" {
+			response.UnscheduledPods++
+			continue
+		}
+		perNode[pod.NodeName]++
+	}
+
+	response.PodsPerNode = make([]NodePodCount, 0, len(perNode))
+	for node, count := range perNode {
+		response.PodsPerNode = append(response.PodsPerNode, NodePodCount{Node: node, Pods: count})
+	}
+	sort.Slice(response.PodsPerNode, func(i, j int) bool {
+		return response.PodsPerNode[i].Node < response.PodsPerNode[j].Node
+	})
+	for i, count := range response.PodsPerNode {
+		if i == 0 || count.Pods < response.MinPodsPerNode {
+			response.MinPodsPerNode = count.Pods
+		}
+		response.MaxPodsPerNode = max(response.MaxPodsPerNode, count.Pods)
+	}
+
+	return response
+}
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultMaxPageSize caps the pods per /api/cluster page
+const defaultMaxPageSize = 1000
+
+// SetMaxPageSize caps the ?limit= of paginated /api/cluster requests; a
+// ?cursor= without a limit gets pages of this size (0 = no cap)
+func (s *Server) SetMaxPageSize(size int) {
+	s.maxPageSize = size
+}
+
+// errPagedSort rejects combining pagination with a sort order; cursors
+// mark a position in namespace/name order
+var errPagedSort = errors.New("pagination supports only the default namespace/name order")
+
+// pageParams reads ?limit= and ?cursor=, returning the page size and the
+// decoded cursor position
+func (s *Server) pageParams(r *http.Request) (int, string, error) {
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return 0, "", fmt.Errorf("invalid limit %q: expected a positive number", value)
+		}
+		limit = parsed
+	}
+
+	after := ""
+	if value := query.Get("cursor"); value != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || !strings.Contains(string(decoded), "/") {
+			return 0, "", fmt.Errorf("invalid cursor %q", value)
+		}
+		after = string(decoded)
+		// Without a cap either, the rest is returned in one page
+		if limit == 0 {
+			limit = s.maxPageSize
+		}
+	}
+
+	if s.maxPageSize > 0 && limit > s.maxPageSize {
+		limit = s.maxPageSize
+	}
+	return limit, after, nil
+}
+
+// paginatePods returns up to limit pods following the cursor position
+// after ("namespace/name", empty for the first page), and the cursor of the
+// next page, empty on the last. Pods must be in namespace/name order. The
+// cursor names a pod rather than an offset, so pods appearing or going away
+// between requests neither repeat nor skip the pods after them.
+func paginatePods(pods []PodData, limit int, after string) ([]PodData, string) {
+	start := 0
+	if after != "" {
+		namespace, name, _ := strings.Cut(after, "/")
+		start = sort.Search(len(pods), func(i int) bool {
+			if pods[i].Namespace != namespace {
+				return pods[i].Namespace > namespace
+			}
+			return pods[i].Name > name
+		})
+	}
+
+	end := len(pods)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	page := pods[start:end]
+	if end == len(pods) {
+		return page, ""
+	}
+	last := pods[end-1]
+	return page, base64.RawURLEncoding.EncodeToString([]byte(last.Namespace + "/" + last.Name))
+}
@@ -0,0 +1,128 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// cursor encodes a namespace/name position as pageParams expects it
+func cursor(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(position))
+}
+
+func TestPaginatePods(t *testing.T) {
+	pods := []PodData{
+		{Namespace: "a", Name: "web-1"}, {Namespace: "a", Name: "web-2"},
+		{Namespace: "b", Name: "api-1"}, {Namespace: "c", Name: "db-0"},
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		after    string
+		want     []string
+		wantNext string
+	}{
+		{name: "no limit", want: []string{"a/web-1", "a/web-2", "b/api-1", "c/db-0"}},
+		{name: "first page", limit: 2, want: []string{"a/web-1", "a/web-2"}, wantNext: cursor("a/web-2")},
+		{name: "next page", limit: 2, after: "a/web-2", want: []string{"b/api-1", "c/db-0"}},
+		{name: "limit past the end", limit: 10, after: "b/api-1", want: []string{"c/db-0"}},
+		{name: "cursor pod gone", limit: 1, after: "a/web-3", want: []string{"b/api-1"}, wantNext: cursor("b/api-1")},
+		{name: "cursor namespace gone", limit: 5, after: "bb/x", want: []string{"c/db-0"}},
+		{name: "past the last pod", limit: 2, after: "z/z", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, next := paginatePods(pods, tt.limit, tt.after)
+			if got := podNames(page); !slices.Equal(got, tt.want) {
+				t.Errorf("page = %v, want %v", got, tt.want)
+			}
+			if next != tt.wantNext {
+				t.Errorf("next = %q, want %q", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		max       int
+		wantLimit int
+		wantAfter string
+		wantErr   bool
+	}{
+		{name: "none", max: 1000},
+		{name: "limit", query: "limit=50", max: 1000, wantLimit: 50},
+		{name: "limit over the cap", query: "limit=5000", max: 1000, wantLimit: 1000},
+		{name: "no cap", query: "limit=5000", wantLimit: 5000},
+		{name: "cursor without a limit", query: "cursor=" + cursor("a/web-1"), max: 1000, wantLimit: 1000, wantAfter: "a/web-1"},
+		{name: "zero limit", query: "limit=0", max: 1000, wantErr: true},
+		{name: "bad limit", query: "limit=ten", max: 1000, wantErr: true},
+		{name: "bad cursor", query: "cursor=!!", max: 1000, wantErr: true},
+		{name: "cursor without a name", query: "cursor=" + cursor("web-1"), max: 1000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer()
+			s.SetMaxPageSize(tt.max)
+			limit, after, err := s.pageParams(httptest.NewRequest(http.MethodGet, "/api/v1/cluster?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pageParams() error = %v, want error %v", err, tt.wantErr)
+			}
+			if limit != tt.wantLimit || after != tt.wantAfter {
+				t.Errorf("pageParams() = %d, %q, want %d, %q", limit, after, tt.wantLimit, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestClusterPages(t *testing.T) {
+	s := newTestServer([]runtime.Object{
+		testPod("a", "web-1", true), testPod("a", "web-2", true), testPod("b", "api-1", true),
+		testPod("c", "db-0", true), testPod("c", "db-1", false),
+	}...)
+
+	// Following nextCursor visits every pod once, in order
+	var got []string
+	path := "/api/v1/cluster?limit=2"
+	for pages := 0; path != ""; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not end")
+		}
+		rec := serve(s, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d: %s", path, rec.Code, rec.Body.String())
+		}
+		var data ClusterData
+		if err := json.Unmarshal(rec.Body.Bytes(), &data); err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Pods) > 2 {
+			t.Errorf("page of %d pods, want at most 2", len(data.Pods))
+		}
+		got = append(got, podNames(data.Pods)...)
+
+		path = ""
+		if data.NextCursor != "" {
+			path = "/api/v1/cluster?limit=2&cursor=" + data.NextCursor
+		}
+	}
+	if want := []string{"a/web-1", "a/web-2", "b/api-1", "c/db-0", "c/db-1"}; !slices.Equal(got, want) {
+		t.Errorf("paged pods = %v, want %v", got, want)
+	}
+
+	// Cursors mark a position in the default order only
+	if rec := serve(s, httptest.NewRequest(http.MethodGet, "/api/v1/cluster?limit=2&sortBy=restarts", nil)); rec.Code != http.StatusBadRequest {
+		t.Errorf("paged sort status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,246 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// maxPartialNamespaces is how many changed namespaces a debounced refresh
+// re-lists on their own; beyond it a full refresh is cheaper
+const maxPartialNamespaces = 8
+
+// pendingRefresh collects the namespaces whose watch events arrived
+// within a debounce window
+type pendingRefresh struct {
+	namespaces map[string]bool
+	// all is set by changes not tied to one namespace, such as an expired
+	// watch, which need the whole cluster re-listed
+	all bool
+}
+
+// requestNamespaceRefresh asks the watcher loop for a debounced refresh of
+// namespace, or of the whole cluster when namespace is empty
+func (s *Server) requestNamespaceRefresh(namespace string) {
+	s.pendingMu.Lock()
+	if namespace == "" {
+		s.pending.all = true
+	} else {
+		if s.pending.namespaces == nil {
+			s.pending.namespaces = make(map[string]bool)
+		}
+		s.pending.namespaces[namespace] = true
+	}
+	s.pendingMu.Unlock()
+
+	s.requestRefresh()
+}
+
+// takePending returns and clears the changes collected since the last
+// refresh: the sorted changed namespaces, and whether everything changed
+func (s *Server) takePending() ([]string, bool) {
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+
+	pending := s.pending
+	s.pending = pendingRefresh{}
+
+	namespaces := make([]string, 0, len(pending.namespaces))
+	for namespace := range pending.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	return namespaces, pending.all
+}
+
+// refreshPending broadcasts the changes collected in a debounce window.
+// Changes confined to a few namespaces re-list just those and merge them
+// into the last refreshed snapshot; anything else, or the first refresh,
+// re-lists the whole cluster.
+func (s *Server) refreshPending(ctx context.Context) {
+	namespaces, all := s.takePending()
+	if all || len(namespaces) == 0 || len(namespaces) > maxPartialNamespaces || s.lastRefresh == nil {
+		s.refreshAndBroadcast(ctx)
+		return
+	}
+
+	start := time.Now()
+	fresh, err := s.getClusterData(ctx, strings.Join(namespaces, ","), "")
+	if err != nil {
+		slog.Error("Failed to get cluster data", "namespaces", strings.Join(namespaces, ","), "error", err)
+		return
+	}
+	clusterData := mergeNamespaces(*s.lastRefresh, fresh, namespaces)
+	slog.Debug("Refreshed changed namespaces", "namespaces", strings.Join(namespaces, ","), "pods", len(fresh.Pods), "duration", time.Since(start))
+
+	s.queueBroadcast(clusterData)
+}
+
+// mergeNamespaces returns base with everything in namespaces replaced by
+// fresh, a snapshot of just those namespaces. Nodes, which no namespace
+// owns, and the kinds reported unavailable come from fresh; totals are
+// recomputed over the merged lists.
+func mergeNamespaces(base, fresh ClusterData, namespaces []string) ClusterData {
+	merged := fresh
+	merged.Pods = replaceNamespaces(base.Pods, fresh.Pods, namespaces)
+	merged.Deployments = replaceNamespaces(base.Deployments, fresh.Deployments, namespaces)
+	merged.HPAs = replaceNamespaces(base.HPAs, fresh.HPAs, namespaces)
+	merged.PDBs = replaceNamespaces(base.PDBs, fresh.PDBs, namespaces)
+	merged.Jobs = replaceNamespaces(base.Jobs, fresh.Jobs, namespaces)
+	merged.CronJobs = replaceNamespaces(base.CronJobs, fresh.CronJobs, namespaces)
+	merged.Services = replaceNamespaces(base.Services, fresh.Services, namespaces)
+	merged.Ingresses = replaceNamespaces(base.Ingresses, fresh.Ingresses, namespaces)
+	merged.PVCs = replaceNamespaces(base.PVCs, fresh.PVCs, namespaces)
+	merged.ArgoRollouts = replaceNamespaces(base.ArgoRollouts, fresh.ArgoRollouts, namespaces)
+
+	// Custom resources are ordered by kind before namespace
+	merged.CustomResources = replaceNamespaces(base.CustomResources, fresh.CustomResources, namespaces)
+	sort.SliceStable(merged.CustomResources, func(i, j int) bool {
+		return merged.CustomResources[i].Kind < merged.CustomResources[j].Kind
+	})
+
+	return withTotals(merged)
+}
+
+// filterNamespaces narrows data to the namespaces of selection, as taken
+// by k8s.SplitNamespaces, the way a fetch of just those namespaces would
+// return them: nodes are kept and totals describe what is left
+func filterNamespaces(data ClusterData, selection string) ClusterData {
+	if selection == "" {
+		return data
+	}
+	namespaces := k8s.SplitNamespaces(selection)
+	data.Pods = keepNamespaces(data.Pods, namespaces)
+	data.Deployments = keepNamespaces(data.Deployments, namespaces)
+	data.HPAs = keepNamespaces(data.HPAs, namespaces)
+	data.PDBs = keepNamespaces(data.PDBs, namespaces)
+	data.Jobs = keepNamespaces(data.Jobs, namespaces)
+	data.CronJobs = keepNamespaces(data.CronJobs, namespaces)
+	data.Services = keepNamespaces(data.Services, namespaces)
+	data.Ingresses = keepNamespaces(data.Ingresses, namespaces)
+	data.PVCs = keepNamespaces(data.PVCs, namespaces)
+	data.CustomResources = keepNamespaces(data.CustomResources, namespaces)
+	data.ArgoRollouts = keepNamespaces(data.ArgoRollouts, namespaces)
+	return withTotals(data)
+}
+
+// withTotals recomputes the container and replica totals of data over its
+// pods and deployments, and its checksum
+func withTotals(data ClusterData) ClusterData {
+	data.TotalContainers, data.ReadyContainers = 0, 0
+	for _, pod := range data.Pods {
+		ready, total := k8s.PodReadiness(pod.Status, pod.ReadyContainers, pod.ContainerCount)
+		data.TotalContainers += total
+		data.ReadyContainers += ready
+	}
+	data.TotalReplicas, data.ReadyReplicas = 0, 0
+	for _, deployment := range data.Deployments {
+		data.TotalReplicas += deployment.Replicas
+		data.ReadyReplicas += deployment.ReadyReplicas
+	}
+
+	data.ContainerPercentage = 0
+	if data.TotalContainers > 0 {
+		data.ContainerPercentage = float64(data.ReadyContainers) / float64(data.TotalContainers) * 100
+	}
+	data.ReplicaPercentage = 0
+	if data.TotalReplicas > 0 {
+		data.ReplicaPercentage = float64(data.ReadyReplicas) / float64(data.TotalReplicas) * 100
+	}
+
+	data.Checksum = data.computeChecksum()
+	return data
+}
+
+// namespaced is a snapshot entry that belongs to a namespace
+type namespaced interface {
+	GetNamespace() string
+}
+
+// replaceNamespaces returns base with its entries in namespaces replaced
+// by fresh, keeping the namespace order the lists are sorted in
+func replaceNamespaces[T namespaced](base, fresh []T, namespaces []string) []T {
+	merged := make([]T, 0, len(base)+len(fresh))
+	for _, item := range base {
+		if !slices.Contains(namespaces, item.GetNamespace()) {
+			merged = append(merged, item)
+		}
+	}
+	merged = append(merged, fresh...)
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].GetNamespace() < merged[j].GetNamespace()
+	})
+	return merged
+}
+
+// keepNamespaces returns the entries of items in namespaces, preserving
+// order
+func keepNamespaces[T namespaced](items []T, namespaces []string) []T {
+	kept := make([]T, 0, len(items))
+	for _, item := range items {
+		if slices.Contains(namespaces, item.GetNamespace()) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// GetNamespace returns the pod's namespace
+func (p PodData) GetNamespace() string {
+	return p.Namespace
+}
+
+// GetNamespace returns the deployment's namespace
+func (d DeploymentData) GetNamespace() string {
+	return d.Namespace
+}
+
+// GetNamespace returns the autoscaler's namespace
+func (h HPAData) GetNamespace() string {
+	return h.Namespace
+}
+
+// GetNamespace returns the disruption budget's namespace
+func (p PDBData) GetNamespace() string {
+	return p.Namespace
+}
+
+// GetNamespace returns the job's namespace
+func (j JobData) GetNamespace() string {
+	return j.Namespace
+}
+
+// GetNamespace returns the cron job's namespace
+func (c CronJobData) GetNamespace() string {
+	return c.Namespace
+}
+
+// GetNamespace returns the service's namespace
+func (s ServiceData) GetNamespace() string {
+	return s.Namespace
+}
+
+// GetNamespace returns the ingress's namespace
+func (i IngressData) GetNamespace() string {
+	return i.Namespace
+}
+
+// GetNamespace returns the claim's namespace
+func (p PVCData) GetNamespace() string {
+	return p.Namespace
+}
+
+// GetNamespace returns the custom resource's namespace
+func (r CustomResourceData) GetNamespace() string {
+	return r.Namespace
+}
+
+// GetNamespace returns the rollout's namespace
+func (r ArgoRolloutData) GetNamespace() string {
+	return r.Namespace
+}
@@ -0,0 +1,170 @@
+package web
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"pod-visualizer/pkg/k8s/fake"
+)
+
+// testPod returns a pod with one container, ready or not
+func testPod(namespace, name string, ready bool) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: ready}},
+		},
+	}
+}
+
+// testDeployment returns a deployment with ready of replicas ready
+func testDeployment(namespace, name string, replicas, ready int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(namespace + "/" + name)},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{Replicas: replicas, ReadyReplicas: ready},
+	}
+}
+
+// podNames returns namespace/name of each pod, in order
+func podNames(pods []PodData) []string {
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.Namespace + "/" + pod.Name
+	}
+	return names
+}
+
+func TestMergeNamespaces(t *testing.T) {
+	ctx := context.Background()
+	client, clientset := fake.NewClient(
+		testPod("a", "web", true), testPod("b", "api", true), testPod("c", "db", true),
+		testDeployment("a", "web", 1, 1), testDeployment("b", "api", 2, 2),
+	)
+	s := NewServer(client, 0)
+
+	base, err := s.getClusterData(ctx, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Namespace b changes: its pod stops being ready and a second appears
+	pod := testPod("b", "api", false)
+	if _, err := clientset.CoreV1().Pods("b").Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientset.CoreV1().Pods("b").Create(ctx, testPod("b", "worker", true), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clientset.AppsV1().Deployments("b").Update(ctx, testDeployment("b", "api", 2, 1), metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, err := s.getClusterData(ctx, "b", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := mergeNamespaces(base, fresh, []string{"b"})
+
+	full, err := s.getClusterData(ctx, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := podNames(merged.Pods), podNames(full.Pods); !slices.Equal(got, want) {
+		t.Errorf("merged pods = %v, want %v", got, want)
+	}
+	if merged.TotalContainers != 4 || merged.ReadyContainers != 3 || merged.ContainerPercentage != 75 {
+		t.Errorf("merged containers = %d/%d (%.0f%%), want 3/4 (75%%)", merged.ReadyContainers, merged.TotalContainers, merged.ContainerPercentage)
+	}
+	if merged.TotalReplicas != 3 || merged.ReadyReplicas != 2 {
+		t.Errorf("merged replicas = %d/%d, want 2/3", merged.ReadyReplicas, merged.TotalReplicas)
+	}
+	if merged.Checksum != full.Checksum {
+		t.Errorf("merged checksum = %s, want the full refresh's %s", merged.Checksum, full.Checksum)
+	}
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	ctx := context.Background()
+	client, _ := fake.NewClient(
+		testPod("a", "web", true), testPod("b", "api", false), testPod("c", "db", true),
+		testDeployment("a", "web", 1, 1), testDeployment("b", "api", 1, 0),
+	)
+	s := NewServer(client, 0)
+
+	full, err := s.getClusterData(ctx, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filterNamespaces(full, ""); got.Checksum != full.Checksum || len(got.Pods) != 3 {
+		t.Error("filterNamespaces() with no selection changed the snapshot")
+	}
+
+	filtered := filterNamespaces(full, "b, c")
+	direct, err := s.getClusterData(ctx, "b,c", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := podNames(filtered.Pods), podNames(direct.Pods); !slices.Equal(got, want) {
+		t.Errorf("filtered pods = %v, want %v", got, want)
+	}
+	if filtered.ReadyContainers != 1 || filtered.TotalContainers != 2 || filtered.TotalReplicas != 1 || filtered.ReadyReplicas != 0 {
+		t.Errorf("filtered totals = %d/%d containers, %d/%d replicas, want 1/2 and 0/1",
+			filtered.ReadyContainers, filtered.TotalContainers, filtered.ReadyReplicas, filtered.TotalReplicas)
+	}
+	if filtered.Checksum != direct.Checksum {
+		t.Errorf("filtered checksum = %s, want the direct fetch's %s", filtered.Checksum, direct.Checksum)
+	}
+}
+
+func TestWithTotals(t *testing.T) {
+	data := withTotals(ClusterData{
+		Pods: []PodData{
+			{Status: "Running", ContainerCount: 2, ReadyContainers: 1},
+			// Completed and evicted pods count toward neither total
+			{Status: "Completed", ContainerCount: 1},
+			{Status: "Evicted", ContainerCount: 1},
+		},
+		Deployments: []DeploymentData{{Replicas: 4, ReadyReplicas: 1}},
+	})
+	if data.ReadyContainers != 1 || data.TotalContainers != 2 || data.ContainerPercentage != 50 {
+		t.Errorf("containers = %d/%d (%.0f%%), want 1/2 (50%%)", data.ReadyContainers, data.TotalContainers, data.ContainerPercentage)
+	}
+	if data.ReadyReplicas != 1 || data.TotalReplicas != 4 || data.ReplicaPercentage != 25 {
+		t.Errorf("replicas = %d/%d (%.0f%%), want 1/4 (25%%)", data.ReadyReplicas, data.TotalReplicas, data.ReplicaPercentage)
+	}
+	if data.Checksum == "" || data.Checksum != data.computeChecksum() {
+		t.Error("checksum not recomputed")
+	}
+
+	if empty := withTotals(ClusterData{}); empty.ContainerPercentage != 0 || empty.ReplicaPercentage != 0 {
+		t.Errorf("empty snapshot percentages = %.0f, %.0f, want 0", empty.ContainerPercentage, empty.ReplicaPercentage)
+	}
+}
+
+func TestTakePending(t *testing.T) {
+	s := newTestServer()
+	s.requestNamespaceRefresh("b")
+	s.requestNamespaceRefresh("a")
+	s.requestNamespaceRefresh("b")
+	if namespaces, all := s.takePending(); !slices.Equal(namespaces, []string{"a", "b"}) || all {
+		t.Errorf("takePending() = %v, %v, want [a b], false", namespaces, all)
+	}
+	if namespaces, all := s.takePending(); len(namespaces) != 0 || all {
+		t.Errorf("takePending() after taking = %v, %v, want nothing", namespaces, all)
+	}
+
+	s.requestNamespaceRefresh("a")
+	s.requestNamespaceRefresh("")
+	if _, all := s.takePending(); !all {
+		t.Error("takePending() all = false after a cluster-wide request")
+	}
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/yaml"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// maxPreviewBody bounds the size of a posted manifest
+const maxPreviewBody = 1 << 20
+
+// PreviewData represents a deployment preview for JSON response
+type PreviewData struct {
+	Name                    string           `json:"name"`
+	Namespace               string           `json:"namespace"`
+	Replicas                int32            `json:"replicas"`
+	PodCPURequestMilli      int64            `json:"podCpuRequestMilli"`
+	PodMemoryRequestBytes   int64            `json:"podMemoryRequestBytes"`
+	TotalCPURequestMilli    int64            `json:"totalCpuRequestMilli"`
+	TotalMemoryRequestBytes int64            `json:"totalMemoryRequestBytes"`
+	Quotas                  []QuotaCheckData `json:"quotas"`
+	Nodes                   []NodeFitData    `json:"nodes"`
+	SchedulableReplicas     int32            `json:"schedulableReplicas"`
+	Allowed                 bool             `json:"allowed"`
+	Reasons                 []string         `json:"reasons,omitempty"`
+}
+
+// QuotaCheckData represents a quota comparison for JSON response
+type QuotaCheckData struct {
+	Quota     string `json:"quota"`
+	Resource  string `json:"resource"`
+	Hard      int64  `json:"hard"`
+	Used      int64  `json:"used"`
+	Requested int64  `json:"requested"`
+	Exceeds   bool   `json:"exceeds"`
+}
+
+// NodeFitData represents a node's capacity for the previewed pods for JSON response
+type NodeFitData struct {
+	Name   string `json:"name"`
+	Fits   int32  `json:"fits"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// handlePreview accepts a Deployment manifest as YAML or JSON and reports
+// how it would render before it is applied. Like a validating webhook it
+// answers with allowed and the reasons for a denial, but never blocks
+// anything itself, so CD pipelines decide what to do with the result.
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPreviewBody))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read manifest: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var deployment appsv1.Deployment
+	if err := yaml.Unmarshal(body, &deployment); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid manifest: %v", err), http.StatusBadRequest)
+		return
+	}
+	if deployment.Kind != "Deployment" {
+		http.Error(w, fmt.Sprintf("Expected kind Deployment, got %q", deployment.Kind), http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.client.PreviewDeployment(r.Context(), &deployment)
+	if err != nil {
+		writeDetailError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPreviewData(result))
+}
+
+// toPreviewData converts a preview to its response format
+func toPreviewData(result k8s.PreviewResult) PreviewData {
+	data := PreviewData{
+		Name:                    result.Name,
+		Namespace:               result.Namespace,
+		Replicas:                result.Replicas,
+		PodCPURequestMilli:      result.PodCPURequestMilli,
+		PodMemoryRequestBytes:   result.PodMemoryRequestBytes,
+		TotalCPURequestMilli:    result.TotalCPURequestMilli,
+		TotalMemoryRequestBytes: result.TotalMemoryRequestBytes,
+		Quotas:                  make([]QuotaCheckData, len(result.Quotas)),
+		Nodes:                   make([]NodeFitData, len(result.Nodes)),
+		SchedulableReplicas:     result.SchedulableReplicas,
+		Allowed:                 result.Allowed,
+		Reasons:                 result.Reasons,
+	}
+
+	for i, check := range result.Quotas {
+		data.Quotas[i] = QuotaCheckData{
+			Quota:     check.Quota,
+			Resource:  check.Resource,
+			Hard:      check.Hard,
+			Used:      check.Used,
+			Requested: check.Requested,
+			Exceeds:   check.Exceeds,
+		}
+	}
+	for i, node := range result.Nodes {
+		data.Nodes[i] = NodeFitData{Name: node.Name, Fits: node.Fits, Reason: node.Reason}
+	}
+
+	return data
+}
@@ -0,0 +1,223 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pod-visualizer/pkg/analysis"
+	"pod-visualizer/pkg/k8s"
+)
+
+// NeedsAttention reports whether the pod is not fully ready
+func (p PodData) NeedsAttention() bool {
+	return k8s.PodNeedsAttention(p.Status, p.ReadyContainers, p.ContainerCount)
+}
+
+// GetStatus returns the pod's status
+func (p PodData) GetStatus() string {
+	return p.Status
+}
+
+// NeedsAttention reports whether the deployment is below desired replicas
+func (d DeploymentData) NeedsAttention() bool {
+	return k8s.DeploymentNeedsAttention(d.ReadyReplicas, d.Replicas)
+}
+
+// NeedsAttention reports whether the autoscaler is pinned at maxReplicas
+func (h HPAData) NeedsAttention() bool {
+	return h.AtMaxReplicas
+}
+
+// NeedsAttention reports whether a node drain would violate the budget
+func (p PDBData) NeedsAttention() bool {
+	return len(p.BlockedNodes) > 0
+}
+
+// NeedsAttention reports whether any route has no ready backend
+func (i IngressData) NeedsAttention() bool {
+	for _, route := range i.Routes {
+		if !k8s.RouteHealthy(route.ServiceFound, route.ReadyEndpoints) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsAttention reports whether the claim is Pending or Lost
+func (p PVCData) NeedsAttention() bool {
+	return k8s.PVCNeedsAttention(p.Phase)
+}
+
+// NeedsAttention reports whether the node is NotReady or under pressure
+func (n NodeData) NeedsAttention() bool {
+	return !n.Ready || len(n.Pressure) > 0
+}
+
+// blockingTaints joins the taints of blocks, keeping PodData comparable for
+// snapshot diffs
+func blockingTaints(blocks []analysis.TaintBlock) string {
+	taints := make([]string, len(blocks))
+	for i, block := range blocks {
+		taints[i] = block.Taint
+	}
+	return strings.Join(taints, ",")
+}
+
+// problemsOnly narrows data to the pods, deployments, Argo Rollouts,
+// autoscalers, disruption budgets, ingresses, claims, custom resources
+// and nodes that need attention and drops the other kinds. Totals keep
+// describing everything the request matched, so a client can show how
+// much of it is unhealthy.
+func problemsOnly(data ClusterData) ClusterData {
+	data.Pods = append([]PodData{}, k8s.Problems(data.Pods)...)
+	data.Deployments = append([]DeploymentData{}, k8s.Problems(data.Deployments)...)
+	data.ArgoRollouts = k8s.Problems(data.ArgoRollouts)
+	data.HPAs = append([]HPAData{}, k8s.Problems(data.HPAs)...)
+	data.PDBs = append([]PDBData{}, k8s.Problems(data.PDBs)...)
+	data.Ingresses = append([]IngressData{}, k8s.Problems(data.Ingresses)...)
+	data.PVCs = append([]PVCData{}, k8s.Problems(data.PVCs)...)
+	data.CustomResources = k8s.Problems(data.CustomResources)
+	data.Nodes = k8s.Problems(data.Nodes)
+	data.Jobs = []JobData{}
+	data.CronJobs = []CronJobData{}
+	data.Services = []ServiceData{}
+	return data
+}
+
+// defaultProblemsLimit is how many entries each /api/problems list holds
+// unless ?limit= says otherwise
+const defaultProblemsLimit = 10
+
+// PendingPodData is a Pending pod with how long it has been waiting
+type PendingPodData struct {
+	PodData
+	PendingSeconds int64 `json:"pendingSeconds"`
+}
+
+// LaggingDeploymentData is a deployment with how many replicas it is short
+type LaggingDeploymentData struct {
+	DeploymentData
+	MissingReplicas int32 `json:"missingReplicas"`
+}
+
+// BlockingTaintData is a taint that keeps Pending pods off every node they
+// could otherwise run on, with those pods as namespace/name
+type BlockingTaintData struct {
+	Taint string   `json:"taint"`
+	Pods  []string `json:"pods"`
+}
+
+// ProblemsResponse is the response body of /api/problems: the worst pods
+// and deployments, most broken first, and the taints blocking the most
+// pods
+type ProblemsResponse struct {
+	MostRestarts        []PodData               `json:"mostRestarts"`
+	LongestPending      []PendingPodData        `json:"longestPending"`
+	FurthestFromDesired []LaggingDeploymentData `json:"furthestFromDesired"`
+	BlockingTaints      []BlockingTaintData     `json:"blockingTaints"`
+}
+
+// handleProblems serves /api/problems, ranking the cluster's (or
+// ?namespace=) most broken resources for triage. ?limit= caps each list.
+func (s *Server) handleProblems(w http.ResponseWriter, r *http.Request) {
+	limit := defaultProblemsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			http.Error(w, fmt.Sprintf("Invalid limit %q: expected a positive integer", value), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	clusterData, err := s.clusterView(w, r, namespaceParam(r), "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get cluster data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rankProblems(clusterData, limit, time.Now()))
+}
+
+// rankProblems picks the pods with the most restarts, the pods Pending the
+// longest, the deployments the most replicas short of desired and the
+// taints blocking the most pods, at most limit of each. Ties are broken by
+// namespace and name, or taint.
+func rankProblems(data ClusterData, limit int, now time.Time) ProblemsResponse {
+	response := ProblemsResponse{
+		MostRestarts:        []PodData{},
+		LongestPending:      []PendingPodData{},
+		FurthestFromDesired: []LaggingDeploymentData{},
+		BlockingTaints:      []BlockingTaintData{},
+	}
+
+	blocked := make(map[string][]string)
+	for _, pod := range data.Pods {
+		if pod.BlockingTaints != "" {
+			for _, taint := range strings.Split(pod.BlockingTaints, ",") {
+				blocked[taint] = append(blocked[taint], pod.Namespace+"/"+pod.Name)
+			}
+		}
+		if pod.Restarts > 0 {
+			response.MostRestarts = append(response.MostRestarts, pod)
+		}
+		if k8s.PodIsPending(pod.Status) {
+			response.LongestPending = append(response.LongestPending, PendingPodData{
+				PodData:        pod,
+				PendingSeconds: int64(now.Sub(pod.CreatedAt).Seconds()),
+			})
+		}
+	}
+	for _, deployment := range data.Deployments {
+		if missing := deployment.Replicas - deployment.ReadyReplicas; missing > 0 {
+			response.FurthestFromDesired = append(response.FurthestFromDesired, LaggingDeploymentData{
+				DeploymentData:  deployment,
+				MissingReplicas: missing,
+			})
+		}
+	}
+
+	for taint, pods := range blocked {
+		sort.Strings(pods)
+		response.BlockingTaints = append(response.BlockingTaints, BlockingTaintData{Taint: taint, Pods: pods})
+	}
+
+	sort.Slice(response.MostRestarts, func(i, j int) bool {
+		a, b := response.MostRestarts[i], response.MostRestarts[j]
+		if a.Restarts != b.Restarts {
+			return a.Restarts > b.Restarts
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	sort.Slice(response.LongestPending, func(i, j int) bool {
+		a, b := response.LongestPending[i], response.LongestPending[j]
+		if a.PendingSeconds != b.PendingSeconds {
+			return a.PendingSeconds > b.PendingSeconds
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	sort.Slice(response.FurthestFromDesired, func(i, j int) bool {
+		a, b := response.FurthestFromDesired[i], response.FurthestFromDesired[j]
+		if a.MissingReplicas != b.MissingReplicas {
+			return a.MissingReplicas > b.MissingReplicas
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	sort.Slice(response.BlockingTaints, func(i, j int) bool {
+		a, b := response.BlockingTaints[i], response.BlockingTaints[j]
+		if len(a.Pods) != len(b.Pods) {
+			return len(a.Pods) > len(b.Pods)
+		}
+		return a.Taint < b.Taint
+	})
+
+	response.MostRestarts = response.MostRestarts[:min(limit, len(response.MostRestarts))]
+	response.LongestPending = response.LongestPending[:min(limit, len(response.LongestPending))]
+	response.FurthestFromDesired = response.FurthestFromDesired[:min(limit, len(response.FurthestFromDesired))]
+	response.BlockingTaints = response.BlockingTaints[:min(limit, len(response.BlockingTaints))]
+	return response
+}
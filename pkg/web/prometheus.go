@@ -0,0 +1,159 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Metric names exposed on /metrics, shared with the Grafana dashboard export
+const (
+	MetricPods               = "pod_visualizer_pods"
+	MetricContainers         = "pod_visualizer_containers"
+	MetricContainersReady    = "pod_visualizer_containers_ready"
+	MetricRestarts           = "pod_visualizer_container_restarts"
+	MetricDeploymentReplicas = "pod_visualizer_deployment_replicas"
+	MetricDeploymentReady    = "pod_visualizer_deployment_ready_replicas"
+	MetricWebSocketClients   = "pod_visualizer_websocket_clients"
+	MetricWebSocketRejected  = "pod_visualizer_websocket_rejected_total"
+	MetricWebSocketDropped   = "pod_visualizer_websocket_dropped_total"
+	MetricSnapshotAgeSeconds = "pod_visualizer_snapshot_age_seconds"
+	MetricSnapshotSeq        = "pod_visualizer_snapshot_seq"
+	MetricLeader             = "pod_visualizer_leader"
+)
+
+// Labels used on the exposed series
+const (
+	labelNamespace = "namespace"
+	labelStatus    = "status"
+)
+
+// prometheusContentType is the text exposition format content type
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelEscaper escapes label values as the text exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// handleMetrics exposes the latest broadcast snapshot in the Prometheus
+// text format. Series are aggregated per namespace (and per deployment)
+// rather than per pod to keep cardinality bounded on large clusters.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	writeGauge(&buf, MetricWebSocketClients, "Connected WebSocket clients.", nil, float64(s.hub.Count()))
+	writeTypedHeader(&buf, MetricWebSocketRejected, "WebSocket connections refused by a connection limit.", "counter")
+	writeSample(&buf, MetricWebSocketRejected, [][2]string{{"limit", "total"}}, float64(s.wsLimiter.rejectedTotal.Load()))
+	writeSample(&buf, MetricWebSocketRejected, [][2]string{{"limit", "per_ip"}}, float64(s.wsLimiter.rejectedPerIP.Load()))
+	writeTypedHeader(&buf, MetricWebSocketDropped, "WebSocket clients disconnected for not keeping up with broadcasts.", "counter")
+	writeSample(&buf, MetricWebSocketDropped, nil, float64(s.hub.dropped.Load()))
+	if s.election != nil {
+		leader := 0.0
+		if s.leading() {
+			leader = 1
+		}
+		writeGauge(&buf, MetricLeader, "Whether this replica holds the leader lease and runs the watchers.", nil, leader)
+	}
+
+	_, seq, data, ok := s.history.latest()
+	if ok {
+		writeSnapshotMetrics(&buf, data)
+		writeGauge(&buf, MetricSnapshotSeq, "Sequence number of the latest broadcast snapshot.", nil, float64(seq))
+		writeGauge(&buf, MetricSnapshotAgeSeconds, "Seconds since the latest snapshot was taken.", nil, time.Since(data.LastUpdated).Seconds())
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.Write(buf.Bytes())
+}
+
+// writeSnapshotMetrics writes the per-namespace series for a snapshot
+func writeSnapshotMetrics(buf *bytes.Buffer, data ClusterData) {
+	pods := make(map[[2]string]int)
+	containers := make(map[string]int)
+	ready := make(map[string]int)
+	restarts := make(map[string]int32)
+	for _, pod := range data.Pods {
+		pods[[2]string{pod.Namespace, pod.Status}]++
+		containers[pod.Namespace] += pod.ContainerCount
+		ready[pod.Namespace] += pod.ReadyContainers
+		restarts[pod.Namespace] += pod.Restarts
+	}
+
+	writeHeader(buf, MetricPods, "Pods by namespace and status.")
+	for _, key := range sortedKeys(pods, func(k [2]string) string { return k[0] + "/" + k[1] }) {
+		writeSample(buf, MetricPods, [][2]string{{labelNamespace, key[0]}, {labelStatus, key[1]}}, float64(pods[key]))
+	}
+
+	writeHeader(buf, MetricContainers, "Containers by namespace.")
+	for _, namespace := range sortedKeys(containers, identity) {
+		writeSample(buf, MetricContainers, [][2]string{{labelNamespace, namespace}}, float64(containers[namespace]))
+	}
+
+	writeHeader(buf, MetricContainersReady, "Ready containers by namespace.")
+	for _, namespace := range sortedKeys(ready, identity) {
+		writeSample(buf, MetricContainersReady, [][2]string{{labelNamespace, namespace}}, float64(ready[namespace]))
+	}
+
+	writeHeader(buf, MetricRestarts, "Container restarts summed by namespace.")
+	for _, namespace := range sortedKeys(restarts, identity) {
+		writeSample(buf, MetricRestarts, [][2]string{{labelNamespace, namespace}}, float64(restarts[namespace]))
+	}
+
+	writeHeader(buf, MetricDeploymentReplicas, "Desired replicas by deployment.")
+	for _, deployment := range data.Deployments {
+		labels := [][2]string{{labelNamespace, deployment.Namespace}, {"deployment", deployment.Name}}
+		writeSample(buf, MetricDeploymentReplicas, labels, float64(deployment.Replicas))
+	}
+
+	writeHeader(buf, MetricDeploymentReady, "Ready replicas by deployment.")
+	for _, deployment := range data.Deployments {
+		labels := [][2]string{{labelNamespace, deployment.Namespace}, {"deployment", deployment.Name}}
+		writeSample(buf, MetricDeploymentReady, labels, float64(deployment.ReadyReplicas))
+	}
+}
+
+// writeGauge writes a single unlabelled or labelled gauge with its header
+func writeGauge(buf *bytes.Buffer, name, help string, labels [][2]string, value float64) {
+	writeHeader(buf, name, help)
+	writeSample(buf, name, labels, value)
+}
+
+// writeHeader writes the HELP and TYPE lines for a gauge
+func writeHeader(buf *bytes.Buffer, name, help string) {
+	writeTypedHeader(buf, name, help, "gauge")
+}
+
+// writeTypedHeader writes the HELP and TYPE lines for a metric of any type
+func writeTypedHeader(buf *bytes.Buffer, name, help, metricType string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// writeSample writes one sample line
+func writeSample(buf *bytes.Buffer, name string, labels [][2]string, value float64) {
+	buf.WriteString(name)
+	if len(labels) > 0 {
+		buf.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(buf, `%s="%s"`, label[0], labelEscaper.Replace(label[1]))
+		}
+		buf.WriteByte('}')
+	}
+	fmt.Fprintf(buf, " %g\n", value)
+}
+
+// identity is the sort key of a string map key
+func identity(s string) string { return s }
+
+// sortedKeys returns map keys ordered by key function, for stable output
+func sortedKeys[K comparable, V any](m map[K]V, key func(K) string) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return key(keys[i]) < key(keys[j]) })
+	return keys
+}
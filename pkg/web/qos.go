@@ -0,0 +1,33 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// GetQOSClass returns the pod's QoS class
+func (p PodData) GetQOSClass() string {
+	return p.QOSClass
+}
+
+// GetPriorityClass returns the pod's priority class name
+func (p PodData) GetPriorityClass() string {
+	return p.PriorityClassName
+}
+
+// qosParams parses ?qos=, a comma-separated list of QoS classes, and
+// ?priorityClass=, a comma-separated list of priority class names. An
+// empty priorityClass entry, as in ?priorityClass=,batch, selects pods
+// without one.
+func qosParams(r *http.Request) (qosClasses, priorityClasses []string, err error) {
+	query := r.URL.Query()
+	if qosClasses, err = k8s.ParseQOSClasses(query.Get("qos")); err != nil {
+		return nil, nil, err
+	}
+	if query.Has("priorityClass") {
+		priorityClasses = strings.Split(query.Get("priorityClass"), ",")
+	}
+	return qosClasses, priorityClasses, nil
+}
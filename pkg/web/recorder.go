@@ -0,0 +1,114 @@
+package web
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSampleInterval is how often the recorder samples the latest snapshot
+	defaultSampleInterval = 30 * time.Second
+	// recorderBatchSize is how many samples are buffered before a write
+	recorderBatchSize = 10
+	// recorderFlushInterval bounds how long samples may sit unwritten
+	recorderFlushInterval = 5 * time.Minute
+)
+
+// HistorySink persists sampled cluster snapshots
+type HistorySink interface {
+	WriteSnapshots(snapshots []ClusterData) error
+}
+
+// historyRecorder decouples history persistence from the broadcast path.
+// Broadcasts only replace the pending snapshot; a separate goroutine samples
+// it at a fixed interval, skips unchanged snapshots and writes in batches,
+// so event storms cost at most one sample per interval.
+type historyRecorder struct {
+	sink     HistorySink
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending *ClusterData
+
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// newHistoryRecorder creates a recorder writing to sink every interval
+func newHistoryRecorder(sink HistorySink, interval time.Duration) *historyRecorder {
+	if interval <= 0 {
+		interval = defaultSampleInterval
+	}
+	return &historyRecorder{
+		sink:     sink,
+		interval: interval,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Offer records data as the latest snapshot; it never blocks on the sink
+func (r *historyRecorder) Offer(data ClusterData) {
+	r.mu.Lock()
+	r.pending = &data
+	r.mu.Unlock()
+}
+
+// run samples and writes snapshots until stop is called
+func (r *historyRecorder) run() {
+	defer close(r.stopped)
+
+	sampleTicker := time.NewTicker(r.interval)
+	defer sampleTicker.Stop()
+	flushTicker := time.NewTicker(recorderFlushInterval)
+	defer flushTicker.Stop()
+
+	var batch []ClusterData
+	lastChecksum := ""
+
+	for {
+		select {
+		case <-sampleTicker.C:
+			r.mu.Lock()
+			pending := r.pending
+			r.pending = nil
+			r.mu.Unlock()
+
+			if pending == nil || pending.Checksum == lastChecksum {
+				continue
+			}
+			lastChecksum = pending.Checksum
+			batch = append(batch, *pending)
+
+			if len(batch) >= recorderBatchSize {
+				batch = r.flush(batch)
+			}
+
+		case <-flushTicker.C:
+			batch = r.flush(batch)
+
+		case <-r.done:
+			r.flush(batch)
+			return
+		}
+	}
+}
+
+// flush writes the batch to the sink and returns an empty batch
+func (r *historyRecorder) flush(batch []ClusterData) []ClusterData {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := r.sink.WriteSnapshots(batch); err != nil {
+		slog.Error("Failed to write history snapshots", "count", len(batch), "error", err)
+	}
+	return nil
+}
+
+// stop flushes any buffered samples and waits for the recorder to exit
+func (r *historyRecorder) stop() {
+	r.once.Do(func() { close(r.done) })
+	<-r.stopped
+}
@@ -0,0 +1,200 @@
+package web
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// resumeHistorySize is how many recent snapshots are kept for resuming clients
+const resumeHistorySize = 32
+
+// WebSocket message types
+const (
+	// MessageSnapshot carries a full ClusterData and resets the client's state
+	MessageSnapshot = "snapshot"
+	// MessageDiff carries the changes since the snapshot identified by BaseToken
+	MessageDiff = "diff"
+	// MessageResync is sent by a client that missed a diff to request a full snapshot
+	MessageResync = "resync"
+)
+
+// WSMessage is the envelope for every WebSocket message. Token identifies
+// the snapshot the client holds after applying the message and Seq is its
+// sequence number; a client whose token differs from a diff's BaseToken has
+// missed an update and should send a resync request. A reconnecting client
+// passes its token back as ?resume=<token> to receive a catch-up diff.
+type WSMessage struct {
+	Type      string       `json:"type"`
+	Token     string       `json:"token,omitempty"`
+	Seq       uint64       `json:"seq,omitempty"`
+	BaseToken string       `json:"baseToken,omitempty"`
+	Data      *ClusterData `json:"data,omitempty"`
+	Diff      *ClusterDiff `json:"diff,omitempty"`
+}
+
+// ClusterDiff describes the changes between two snapshots. Pods,
+// deployments and the other lists are diffed by UID; the totals, nodes and
+// the rest are carried in Summary, whose diffed lists are left empty.
+type ClusterDiff struct {
+	UpsertedPods        []PodData        `json:"upsertedPods,omitempty"`
+	RemovedPods         []string         `json:"removedPods,omitempty"`
+	UpsertedDeployments []DeploymentData `json:"upsertedDeployments,omitempty"`
+	RemovedDeployments  []string         `json:"removedDeployments,omitempty"`
+	Summary             ClusterData      `json:"summary"`
+
+	// Changes to the other lists, nil for those that did not change
+	HPAs            *ListDiff[HPAData]            `json:"hpas,omitempty"`
+	PDBs            *ListDiff[PDBData]            `json:"pdbs,omitempty"`
+	Jobs            *ListDiff[JobData]            `json:"jobs,omitempty"`
+	CronJobs        *ListDiff[CronJobData]        `json:"cronJobs,omitempty"`
+	Services        *ListDiff[ServiceData]        `json:"services,omitempty"`
+	Ingresses       *ListDiff[IngressData]        `json:"ingresses,omitempty"`
+	PVCs            *ListDiff[PVCData]            `json:"pvcs,omitempty"`
+	CustomResources *ListDiff[CustomResourceData] `json:"customResources,omitempty"`
+	ArgoRollouts    *ListDiff[ArgoRolloutData]    `json:"argoRollouts,omitempty"`
+}
+
+// ListDiff is the change to one list of a snapshot: the entries added or
+// changed, and the UIDs of those removed
+type ListDiff[T any] struct {
+	Upserted []T      `json:"upserted,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+}
+
+// snapshotHistory is a bounded, token-addressed store of recent snapshots
+type snapshotHistory struct {
+	mu        sync.Mutex
+	seq       uint64
+	order     []string
+	snapshots map[string]ClusterData
+}
+
+// newSnapshotHistory creates an empty history
+func newSnapshotHistory() *snapshotHistory {
+	return &snapshotHistory{snapshots: make(map[string]ClusterData)}
+}
+
+// add records a snapshot and returns its token and sequence number,
+// evicting the oldest snapshot when full
+func (h *snapshotHistory) add(data ClusterData) (string, uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	token := strconv.FormatUint(h.seq, 10)
+	h.snapshots[token] = data
+	h.order = append(h.order, token)
+
+	if len(h.order) > resumeHistorySize {
+		delete(h.snapshots, h.order[0])
+		h.order = h.order[1:]
+	}
+
+	return token, h.seq
+}
+
+// latest returns the most recently added snapshot
+func (h *snapshotHistory) latest() (string, uint64, ClusterData, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.order) == 0 {
+		return "", 0, ClusterData{}, false
+	}
+
+	token := h.order[len(h.order)-1]
+	return token, h.seq, h.snapshots[token], true
+}
+
+// get returns the snapshot for a token, if it is still retained
+func (h *snapshotHistory) get(token string) (ClusterData, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, ok := h.snapshots[token]
+	return data, ok
+}
+
+// diffClusterData computes the changes needed to turn base into current
+func diffClusterData(base, current ClusterData) ClusterDiff {
+	var diff ClusterDiff
+
+	basePods := make(map[string]PodData, len(base.Pods))
+	for _, pod := range base.Pods {
+		basePods[pod.UID] = pod
+	}
+	for _, pod := range current.Pods {
+		if prev, ok := basePods[pod.UID]; !ok || prev != pod {
+			diff.UpsertedPods = append(diff.UpsertedPods, pod)
+		}
+		delete(basePods, pod.UID)
+	}
+	for uid := range basePods {
+		diff.RemovedPods = append(diff.RemovedPods, uid)
+	}
+
+	baseDeployments := make(map[string]DeploymentData, len(base.Deployments))
+	for _, deployment := range base.Deployments {
+		baseDeployments[deployment.UID] = deployment
+	}
+	for _, deployment := range current.Deployments {
+		if prev, ok := baseDeployments[deployment.UID]; !ok || prev != deployment {
+			diff.UpsertedDeployments = append(diff.UpsertedDeployments, deployment)
+		}
+		delete(baseDeployments, deployment.UID)
+	}
+	for uid := range baseDeployments {
+		diff.RemovedDeployments = append(diff.RemovedDeployments, uid)
+	}
+
+	diff.HPAs = diffList(base.HPAs, current.HPAs, func(h HPAData) string { return h.UID })
+	diff.PDBs = diffList(base.PDBs, current.PDBs, func(p PDBData) string { return p.UID })
+	diff.Jobs = diffList(base.Jobs, current.Jobs, func(j JobData) string { return j.UID })
+	diff.CronJobs = diffList(base.CronJobs, current.CronJobs, func(c CronJobData) string { return c.UID })
+	diff.Services = diffList(base.Services, current.Services, func(s ServiceData) string { return s.UID })
+	diff.Ingresses = diffList(base.Ingresses, current.Ingresses, func(i IngressData) string { return i.UID })
+	diff.PVCs = diffList(base.PVCs, current.PVCs, func(p PVCData) string { return p.UID })
+	diff.CustomResources = diffList(base.CustomResources, current.CustomResources, func(r CustomResourceData) string { return r.UID })
+	diff.ArgoRollouts = diffList(base.ArgoRollouts, current.ArgoRollouts, func(r ArgoRolloutData) string { return r.UID })
+
+	diff.Summary = current
+	diff.Summary.Pods = nil
+	diff.Summary.Deployments = nil
+	diff.Summary.HPAs = nil
+	diff.Summary.PDBs = nil
+	diff.Summary.Jobs = nil
+	diff.Summary.CronJobs = nil
+	diff.Summary.Services = nil
+	diff.Summary.Ingresses = nil
+	diff.Summary.PVCs = nil
+	diff.Summary.CustomResources = nil
+	diff.Summary.ArgoRollouts = nil
+
+	return diff
+}
+
+// diffList diffs two versions of a list by the UID uid returns, nil when
+// nothing changed. Entries hold slices, so they are compared deeply.
+func diffList[T any](base, current []T, uid func(T) string) *ListDiff[T] {
+	var diff ListDiff[T]
+
+	baseItems := make(map[string]T, len(base))
+	for _, item := range base {
+		baseItems[uid(item)] = item
+	}
+	for _, item := range current {
+		if prev, ok := baseItems[uid(item)]; !ok || !reflect.DeepEqual(prev, item) {
+			diff.Upserted = append(diff.Upserted, item)
+		}
+		delete(baseItems, uid(item))
+	}
+	for id := range baseItems {
+		diff.Removed = append(diff.Removed, id)
+	}
+
+	if len(diff.Upserted) == 0 && len(diff.Removed) == 0 {
+		return nil
+	}
+	return &diff
+}
@@ -0,0 +1,187 @@
+package web
+
+import (
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// uids returns the sorted UIDs of items
+func uids[T any](items []T, uid func(T) string) []string {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, uid(item))
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// applyList applies a list diff the way the frontend does, keyed by uid
+func applyList[T any](base []T, diff *ListDiff[T], uid func(T) string) []T {
+	if diff == nil {
+		return base
+	}
+	items := make(map[string]T)
+	for _, item := range base {
+		items[uid(item)] = item
+	}
+	for _, id := range diff.Removed {
+		delete(items, id)
+	}
+	for _, item := range diff.Upserted {
+		items[uid(item)] = item
+	}
+	merged := make([]T, 0, len(items))
+	for _, item := range items {
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+func TestSnapshotHistory(t *testing.T) {
+	h := newSnapshotHistory()
+	if _, _, _, ok := h.latest(); ok {
+		t.Fatal("latest() of an empty history ok = true")
+	}
+
+	var first string
+	for i := 1; i <= resumeHistorySize+1; i++ {
+		token, seq := h.add(ClusterData{Checksum: strconv.Itoa(i)})
+		if seq != uint64(i) {
+			t.Fatalf("add() seq = %d, want %d", seq, i)
+		}
+		if i == 1 {
+			first = token
+		}
+	}
+
+	if _, ok := h.get(first); ok {
+		t.Error("oldest snapshot still retained past resumeHistorySize")
+	}
+	token, seq, data, ok := h.latest()
+	if !ok || seq != resumeHistorySize+1 || data.Checksum != strconv.Itoa(resumeHistorySize+1) {
+		t.Errorf("latest() = %q, %d, %q, %v", token, seq, data.Checksum, ok)
+	}
+	if got, ok := h.get(token); !ok || got.Checksum != data.Checksum {
+		t.Error("get() of the latest token did not return it")
+	}
+}
+
+func TestDiffClusterData(t *testing.T) {
+	base := ClusterData{
+		Pods:            []PodData{{UID: "p1", Name: "web-1", Status: "Running"}, {UID: "p2", Name: "web-2", Status: "Running"}},
+		Deployments:     []DeploymentData{{UID: "d1", Name: "web", Replicas: 2, ReadyReplicas: 2}},
+		Services:        []ServiceData{{UID: "s1", Name: "web"}, {UID: "s2", Name: "old"}},
+		Ingresses:       []IngressData{{UID: "i1", Name: "web", Routes: []RouteData{{Host: "shop.example.com"}}}},
+		TotalContainers: 2,
+	}
+	current := ClusterData{
+		Pods:            []PodData{{UID: "p1", Name: "web-1", Status: "Running"}, {UID: "p2", Name: "web-2", Status: "CrashLoopBackOff"}, {UID: "p3", Name: "web-3", Status: "Pending"}},
+		Deployments:     []DeploymentData{{UID: "d1", Name: "web", Replicas: 2, ReadyReplicas: 2}},
+		Services:        []ServiceData{{UID: "s1", Name: "web", ReadyEndpoints: 1}},
+		Ingresses:       []IngressData{{UID: "i1", Name: "web", Routes: []RouteData{{Host: "shop.example.com"}}}},
+		Jobs:            []JobData{{UID: "j1", Name: "migrate"}},
+		TotalContainers: 3,
+	}
+
+	diff := diffClusterData(base, current)
+
+	podUID := func(p PodData) string { return p.UID }
+	if got := uids(diff.UpsertedPods, podUID); !slices.Equal(got, []string{"p2", "p3"}) {
+		t.Errorf("UpsertedPods = %v, want [p2 p3]", got)
+	}
+	if len(diff.RemovedPods) != 0 || len(diff.UpsertedDeployments) != 0 || len(diff.RemovedDeployments) != 0 {
+		t.Errorf("unchanged pods or deployments in diff: %+v", diff)
+	}
+
+	// Lists that did not change are left out; the others apply by UID
+	if diff.Ingresses != nil || diff.HPAs != nil || diff.PVCs != nil {
+		t.Errorf("unchanged lists in diff: ingresses %v, hpas %v, pvcs %v", diff.Ingresses, diff.HPAs, diff.PVCs)
+	}
+	serviceUID := func(s ServiceData) string { return s.UID }
+	if diff.Services == nil || !slices.Equal(diff.Services.Removed, []string{"s2"}) {
+		t.Errorf("Services diff = %+v, want s2 removed", diff.Services)
+	}
+	if got := uids(applyList(base.Services, diff.Services, serviceUID), serviceUID); !slices.Equal(got, []string{"s1"}) {
+		t.Errorf("applied services = %v, want [s1]", got)
+	}
+	jobUID := func(j JobData) string { return j.UID }
+	if got := uids(applyList(base.Jobs, diff.Jobs, jobUID), jobUID); !slices.Equal(got, []string{"j1"}) {
+		t.Errorf("applied jobs = %v, want [j1]", got)
+	}
+
+	// The summary carries the rest of the snapshot without the diffed lists
+	if diff.Summary.TotalContainers != 3 {
+		t.Errorf("Summary.TotalContainers = %d, want 3", diff.Summary.TotalContainers)
+	}
+	if diff.Summary.Pods != nil || diff.Summary.Services != nil || diff.Summary.Jobs != nil || diff.Summary.Ingresses != nil {
+		t.Error("Summary carries diffed lists")
+	}
+}
+
+func TestDiffClusterDataUnchanged(t *testing.T) {
+	data := ClusterData{
+		Pods:      []PodData{{UID: "p1", Name: "web-1"}},
+		PDBs:      []PDBData{{UID: "b1", Name: "web", BlockedNodes: []string{"node-a"}}},
+		Ingresses: []IngressData{{UID: "i1", Routes: []RouteData{{Host: "shop.example.com"}}}},
+	}
+	diff := diffClusterData(data, data)
+	if len(diff.UpsertedPods) != 0 || len(diff.RemovedPods) != 0 || diff.PDBs != nil || diff.Ingresses != nil {
+		t.Errorf("diff of identical snapshots = %+v, want empty", diff)
+	}
+}
+
+func TestWebSocketResume(t *testing.T) {
+	s := newTestServer()
+	go s.hub.run()
+	defer s.hub.stop()
+
+	base, _ := s.history.add(ClusterData{Checksum: "a", Pods: []PodData{{UID: "p1", Name: "web-1"}}})
+	latest, _ := s.history.add(ClusterData{Checksum: "b", Pods: []PodData{{UID: "p2", Name: "web-2"}}})
+
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+	tests := []struct {
+		name   string
+		resume string
+		want   string
+	}{
+		{name: "no token", want: MessageSnapshot},
+		{name: "retained token", resume: base, want: MessageDiff},
+		{name: "unknown token", resume: "999", want: MessageSnapshot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := wsURL
+			if tt.resume != "" {
+				url += "?resume=" + tt.resume
+			}
+			conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+
+			var message WSMessage
+			if err := conn.ReadJSON(&message); err != nil {
+				t.Fatalf("ReadJSON() error = %v", err)
+			}
+			if message.Type != tt.want || message.Token != latest {
+				t.Fatalf("message type %q token %q, want %q at %q", message.Type, message.Token, tt.want, latest)
+			}
+			if message.Type == MessageDiff {
+				if message.BaseToken != base || len(message.Diff.UpsertedPods) != 1 || !slices.Equal(message.Diff.RemovedPods, []string{"p1"}) {
+					t.Errorf("diff = %+v from %q, want p2 upserted and p1 removed from %q", message.Diff, message.BaseToken, base)
+				}
+			}
+		})
+	}
+}
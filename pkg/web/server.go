@@ -2,223 +2,724 @@ package web
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"html/template"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
-	"os"
-	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/watch"
+	"google.golang.org/grpc"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	"pod-visualizer/pkg/alerts"
+	"pod-visualizer/pkg/history"
 	"pod-visualizer/pkg/k8s"
+	"pod-visualizer/pkg/metrics"
+	"pod-visualizer/pkg/status"
 )
 
 // Server represents the web server
 type Server struct {
-	client     *k8s.Client
+	client  k8s.Interface
+	metrics *metrics.Client
+	symbols status.Mapping
+	kinds   k8s.Kinds
+	auth    *authenticator
+	denied  []string
+
+	// kubeletStats enables per-pod storage usage from the kubelet summary API
+	kubeletStats bool
+
+	idle       *idleTracker
+	idleWindow time.Duration
+
+	// maxPageSize caps the pods per paginated /api/cluster response
+	maxPageSize int
+
+	cache *clusterCache
+
+	debounce time.Duration
+	refresh  chan struct{}
+
+	// pending collects the namespaces changed within a debounce window;
+	// lastRefresh is the snapshot they are merged into, and is only
+	// touched by the watcher loop
+	pendingMu   sync.Mutex
+	pending     pendingRefresh
+	lastRefresh *ClusterData
+
+	// settingsMu guards the settings Reconfigure may change while the server
+	// runs; settingsChanged is closed and replaced on every change
+	settingsMu      sync.Mutex
+	priority        []string
+	refreshInterval time.Duration
+	customResources []k8s.CustomResourceType
+	settingsChanged chan struct{}
+
 	port       int
-	template   *template.Template
+	mux        *http.ServeMux
+	httpServer *http.Server
+	version    string
 	upgrader   websocket.Upgrader
-	clients    map[*websocket.Conn]bool
+	hub        *hub
+	wsLimiter  *connLimiter
 	broadcast  chan ClusterData
-	clientsMux sync.RWMutex
+	history    *snapshotHistory
+	encoded    snapshotCache
+	recorder   *historyRecorder
+	timeline   history.Backend
+	alerts     *alerts.Evaluator
+
+	// cancel stops the watchers and broadcast loop started by Start
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	// stopMu guards httpServer and stopped, which records a Stop that
+	// came before Start had a server to shut down
+	stopMu  sync.Mutex
+	stopped bool
+
+	// election is set when replicas elect one to run the watchers, and
+	// leader is whether this replica holds the lease; see SetLeaderElection
+	election *LeaderElection
+	leader   atomic.Bool
+
+	// actions enables the write-mode pod endpoints; see SetActions
+	actions  bool
+	forwards *portForwards
+
+	tlsConfig *tls.Config
+	hsts      bool
+
+	// corsOrigins may call /api/* cross-origin; see SetCORSOrigins
+	corsOrigins map[string]bool
+
+	bindAddress string
+	activated   map[string]net.Listener
+
+	grpcPort   int
+	grpcServer *grpc.Server
+	watchers   *watchers
 }
 
 // PodData represents pod data for JSON response
 type PodData struct {
+	UID             string `json:"uid"`
 	Name            string `json:"name"`
 	Namespace       string `json:"namespace"`
 	Status          string `json:"status"`
 	ContainerCount  int    `json:"containerCount"`
 	ReadyContainers int    `json:"readyContainers"`
+	Restarts        int32  `json:"restarts"`
+	NodeName        string `json:"nodeName"`
 	StatusSymbol    string `json:"statusSymbol"`
+
+	// CreatedAt is in UTC so equal timestamps compare equal in snapshot diffs
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Deployment is the deployment owning the pod through its ReplicaSet
+	Deployment string `json:"deployment,omitempty"`
+
+	// StartupSeconds is how long the pod took from creation to Ready, 0
+	// while it is not Ready
+	StartupSeconds float64 `json:"startupSeconds,omitempty"`
+
+	InitContainerCount      int `json:"initContainerCount,omitempty"`
+	InitContainersDone      int `json:"initContainersDone,omitempty"`
+	EphemeralContainerCount int `json:"ephemeralContainerCount,omitempty"`
+
+	// Sidecars count toward the container counts unless SidecarsExcluded
+	SidecarCount     int  `json:"sidecarCount,omitempty"`
+	ReadySidecars    int  `json:"readySidecars,omitempty"`
+	SidecarsExcluded bool `json:"sidecarsExcluded,omitempty"`
+
+	RuntimeClass         string `json:"runtimeClass,omitempty"`
+	ExpectedRuntimeClass string `json:"expectedRuntimeClass,omitempty"`
+	RuntimeClassMismatch bool   `json:"runtimeClassMismatch"`
+
+	QOSClass          string `json:"qosClass,omitempty"`
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Unschedulable is the scheduler's message while it cannot place the pod
+	Unschedulable string `json:"unschedulable,omitempty"`
+
+	// BlockingTaints are the comma-separated taints, as key=value:Effect,
+	// that keep a Pending pod off every node its tolerations and
+	// nodeSelector would otherwise allow
+	BlockingTaints string `json:"blockingTaints,omitempty"`
+
+	// EvictionMessage is why the kubelet evicted the pod
+	EvictionMessage string `json:"evictionMessage,omitempty"`
+
+	// The most restarted container in CrashLoopBackOff, when its last run
+	// ended (RFC 3339, keeping PodData comparable) and how long the
+	// kubelet waits after it before the next restart
+	CrashLoopContainer string  `json:"crashLoopContainer,omitempty"`
+	LastExitReason     string  `json:"lastExitReason,omitempty"`
+	LastRestartAt      string  `json:"lastRestartAt,omitempty"`
+	BackoffSeconds     float64 `json:"backoffSeconds,omitempty"`
+
+	// Hint is the likeliest cause of an unhealthy pod's trouble, see
+	// analysis.Hints; the pod detail lists all of them
+	HintCause string `json:"hintCause,omitempty"`
+	Hint      string `json:"hint,omitempty"`
+
+	// NodePressure is a comma-separated list, keeping PodData comparable
+	// for snapshot diffs
+	NodePressure string `json:"nodePressure,omitempty"`
+
+	// Zone is the topology zone of the pod's node
+	Zone string `json:"zone,omitempty"`
+
+	// GitOps* are the sync status reported by the Argo CD Application or
+	// Flux Kustomization deploying the pod's Deployment, empty when neither does
+	GitOpsTool    string `json:"gitOpsTool,omitempty"`
+	GitOpsSource  string `json:"gitOpsSource,omitempty"`
+	GitOpsSync    string `json:"gitOpsSync,omitempty"`
+	GitOpsHealth  string `json:"gitOpsHealth,omitempty"`
+	GitOpsMessage string `json:"gitOpsMessage,omitempty"`
+
+	CPURequestMilli    int64 `json:"cpuRequestMilli"`
+	MemoryRequestBytes int64 `json:"memoryRequestBytes"`
+	CPUUsageMilli      int64 `json:"cpuUsageMilli"`
+	MemoryUsageBytes   int64 `json:"memoryUsageBytes"`
+
+	EphemeralStorageLimitBytes int64 `json:"ephemeralStorageLimitBytes,omitempty"`
+	EphemeralStorageUsedBytes  int64 `json:"ephemeralStorageUsedBytes,omitempty"`
+	RootfsUsedBytes            int64 `json:"rootfsUsedBytes,omitempty"`
+	EphemeralStorageNearLimit  bool  `json:"ephemeralStorageNearLimit"`
 }
 
 // DeploymentData represents deployment data for JSON response
 type DeploymentData struct {
+	UID               string `json:"uid"`
 	Name              string `json:"name"`
 	Namespace         string `json:"namespace"`
 	Replicas          int32  `json:"replicas"`
 	ReadyReplicas     int32  `json:"readyReplicas"`
 	AvailableReplicas int32  `json:"availableReplicas"`
+
+	// PinnedAtMax is set when the deployment's autoscaler is at maxReplicas
+	PinnedAtMax bool `json:"pinnedAtMax,omitempty"`
+
+	// HealthScore rates the deployment from 0 to 100 on replica readiness,
+	// pod restarts, recent warning events and a stuck rollout
+	HealthScore   int   `json:"healthScore"`
+	Restarts      int32 `json:"restarts,omitempty"`
+	WarningEvents int   `json:"warningEvents,omitempty"`
+	RolloutStuck  bool  `json:"rolloutStuck,omitempty"`
+
+	// GitOps* are the sync status reported by the Argo CD Application or
+	// Flux Kustomization deploying it, empty when neither does
+	GitOpsTool    string `json:"gitOpsTool,omitempty"`
+	GitOpsSource  string `json:"gitOpsSource,omitempty"`
+	GitOpsSync    string `json:"gitOpsSync,omitempty"`
+	GitOpsHealth  string `json:"gitOpsHealth,omitempty"`
+	GitOpsMessage string `json:"gitOpsMessage,omitempty"`
+
+	// CreatedAt is in UTC so equal timestamps compare equal in snapshot diffs
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// NodeData represents node capacity and usage for JSON response
+type NodeData struct {
+	Name                   string   `json:"name"`
+	Ready                  bool     `json:"ready"`
+	CPUAllocatableMilli    int64    `json:"cpuAllocatableMilli"`
+	MemoryAllocatableBytes int64    `json:"memoryAllocatableBytes"`
+	CPUUsageMilli          int64    `json:"cpuUsageMilli"`
+	MemoryUsageBytes       int64    `json:"memoryUsageBytes"`
+	Pressure               []string `json:"pressure,omitempty"`
+	Zone                   string   `json:"zone,omitempty"`
 }
 
+const (
+	// defaultRefreshInterval is how often a full refresh is broadcast without watch events
+	defaultRefreshInterval = 10 * time.Second
+	// defaultDebounce is the window over which bursts of watch events are coalesced
+	defaultDebounce = 500 * time.Millisecond
+	// slowRefreshInterval is the minimum polling interval for non-priority namespaces
+	slowRefreshInterval = 60 * time.Second
+	// followerRefreshInterval is the minimum polling interval of replicas
+	// that do not hold the leader lease, so that they add little load
+	followerRefreshInterval = 2 * time.Minute
+	// stopTimeout bounds the drain when Start's context is cancelled
+	stopTimeout = 15 * time.Second
+)
+
+// SchemaVersion is the version of the ClusterData JSON format.
+// Bump it whenever fields are renamed, removed or change meaning.
+const SchemaVersion = 1
+
 // ClusterData represents the complete cluster state
 type ClusterData struct {
+	SchemaVersion       int              `json:"schemaVersion"`
+	Checksum            string           `json:"checksum"`
 	Pods                []PodData        `json:"pods"`
 	Deployments         []DeploymentData `json:"deployments"`
+	HPAs                []HPAData        `json:"hpas"`
+	PDBs                []PDBData        `json:"pdbs"`
+	Jobs                []JobData        `json:"jobs"`
+	CronJobs            []CronJobData    `json:"cronJobs"`
+	Services            []ServiceData    `json:"services"`
+	Ingresses           []IngressData    `json:"ingresses"`
+	PVCs                []PVCData        `json:"pvcs"`
+	Nodes               []NodeData       `json:"nodes,omitempty"`
 	TotalContainers     int              `json:"totalContainers"`
 	ReadyContainers     int              `json:"readyContainers"`
 	ContainerPercentage float64          `json:"containerPercentage"`
 	TotalReplicas       int32            `json:"totalReplicas"`
 	ReadyReplicas       int32            `json:"readyReplicas"`
 	ReplicaPercentage   float64          `json:"replicaPercentage"`
+	Unavailable         []string         `json:"unavailable,omitempty"`
 	LastUpdated         time.Time        `json:"lastUpdated"`
+
+	// Set on paginated /api/cluster responses: the pod count across all
+	// pages and the cursor of the next page, empty on the last one
+	TotalPods  int    `json:"totalPods,omitempty"`
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	// CustomResources are the resources of the configured custom
+	// resource types, ArgoRollouts the Argo Rollouts where installed
+	CustomResources []CustomResourceData `json:"customResources,omitempty"`
+	ArgoRollouts    []ArgoRolloutData    `json:"argoRollouts,omitempty"`
 }
 
 // NewServer creates a new web server
-func NewServer(client *k8s.Client, port int) *Server {
-	return &Server{
+func NewServer(client k8s.Interface, port int) *Server {
+	s := &Server{
 		client:    client,
+		metrics:   metrics.NewClient(client),
+		symbols:   status.DefaultMapping,
+		kinds:     allKinds(),
 		port:      port,
+		mux:       http.NewServeMux(),
+		version:   bundleVersion(),
 		upgrader:  websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
-		clients:   make(map[*websocket.Conn]bool),
+		hub:       newHub(),
+		wsLimiter: newConnLimiter(),
 		broadcast: make(chan ClusterData, 256),
+		history:   newSnapshotHistory(),
+		idle:      newIdleTracker(),
+		watchers:  newWatchers(),
+		forwards:  newPortForwards(),
+
+		refreshInterval: defaultRefreshInterval,
+		idleWindow:      defaultIdleWindow,
+		maxPageSize:     defaultMaxPageSize,
+		cache:           newClusterCache(),
+		debounce:        defaultDebounce,
+		refresh:         make(chan struct{}, 1),
+		settingsChanged: make(chan struct{}),
 	}
+	s.routes()
+	return s
 }
 
-// Start starts the web server
-func (s *Server) Start() error {
-	// Load templates
-	tmpl, err := template.ParseGlob(filepath.Join("pkg", "web", "templates", "*.html"))
-	if err != nil {
-		return fmt.Errorf("failed to parse templates: %v", err)
+// routes registers all handlers on the server's own mux
+func (s *Server) routes() {
+	s.mux.HandleFunc("/", s.requireAuth(s.handleIndex))
+	s.mux.HandleFunc("/api", s.requireAuth(s.handleAPIVersions))
+	s.mux.HandleFunc("/api/", s.requireAuth(s.handleAPINotFound))
+	s.handleAPI("/ui-config", s.handleUIConfig)
+	s.handleAPI("/cluster", s.handleClusterData)
+	s.handleAPI("/namespaces", s.handleNamespaces)
+	s.handleAPI("/pods/", s.handlePodDetail)
+	s.handleAPI("/deployments/", s.handleDeploymentDetail)
+	s.handleAPI("/preview", s.handlePreview)
+	s.handleAPI("/topology", s.handleTopology)
+	s.handleAPI("/topology/zones", s.handleZones)
+	s.handleAPI("/snapshot", s.handleSnapshot)
+	s.handleAPI("/idle", s.handleIdle)
+	s.handleAPI("/images", s.handleImages)
+	s.handleAPI("/summary", s.handleSummary)
+	s.handleAPI("/overview", s.handleOverview)
+	s.handleAPI("/problems", s.handleProblems)
+	s.handleAPI("/history", s.handleHistory)
+	s.handleAPI("/startup", s.handleStartup)
+	s.handleAPI("/batch/describe", s.handleBatchDescribe)
+	s.handleAPI("/openapi.json", s.handleOpenAPI)
+	s.handleAPI("/docs", s.handleAPIDocs)
+	s.mux.HandleFunc("/ws", s.requireAuth(s.handleWebSocket))
+	s.mux.HandleFunc("/metrics", s.requireAuth(s.handleMetrics))
+	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/ready", s.handleReady)
+	s.mux.Handle("/static/", http.StripPrefix("/static/", s.handleStatic()))
+}
+
+// Handler returns the server's HTTP handler, including its middleware, for
+// embedding in another server
+func (s *Server) Handler() http.Handler {
+	return s.middleware(s.mux)
+}
+
+// SetStatusSymbols overrides the status symbol mapping
+func (s *Server) SetStatusSymbols(symbols status.Mapping) {
+	s.symbols = symbols
+}
+
+// SetResourceKinds limits which resource kinds are fetched and watched
+func (s *Server) SetResourceKinds(kinds k8s.Kinds) {
+	s.kinds = kinds
+}
+
+// SetPriorityNamespaces limits event-driven updates to the given namespaces.
+// All other namespaces are only refreshed on the slower background schedule.
+func (s *Server) SetPriorityNamespaces(namespaces []string) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.priority = namespaces
+}
+
+// customResourceTypes returns the configured custom resource types
+func (s *Server) customResourceTypes() []k8s.CustomResourceType {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	return s.customResources
+}
+
+// SetRefreshInterval sets how often a full refresh is broadcast when no
+// watch events arrive. Non-positive values keep the default.
+func (s *Server) SetRefreshInterval(interval time.Duration) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	if interval > 0 {
+		s.refreshInterval = interval
 	}
-	s.template = tmpl
+}
 
-	// Setup routes
-	http.HandleFunc("/", s.handleIndex)
-	http.HandleFunc("/api/cluster", s.handleClusterData)
-	http.HandleFunc("/ws", s.handleWebSocket)
-	http.HandleFunc("/health", s.handleHealth)
-	http.HandleFunc("/ready", s.handleReady)
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(filepath.Join("pkg", "web", "static")))))
+// SetDebounce sets the window over which bursts of watch events are
+// coalesced into a single refresh. Zero refreshes on every event.
+func (s *Server) SetDebounce(window time.Duration) {
+	if window >= 0 {
+		s.debounce = window
+	}
+}
 
-	// Start WebSocket broadcaster and watcher goroutines
-	go s.handleBroadcast()
-	go s.watchKubernetesEvents()
+// SetKubeletStats enables per-pod ephemeral-storage and rootfs usage from
+// each node's kubelet summary API, queried through the API server proxy.
+// It costs one request per node per refresh and needs nodes/proxy access.
+func (s *Server) SetKubeletStats(enabled bool) {
+	s.kubeletStats = enabled
+}
 
-	log.Printf("Starting web server on port %d", s.port)
-	log.Printf("WebSocket endpoint available at ws://localhost:%d/ws", s.port)
-	log.Printf("Open http://localhost:%d in your browser", s.port)
+// SetHistorySink enables history persistence. Snapshots are sampled every
+// interval (default 30s) and written in batches, independently of broadcasts.
+func (s *Server) SetHistorySink(sink HistorySink, interval time.Duration) {
+	s.recorder = newHistoryRecorder(sink, interval)
+}
 
-	return http.ListenAndServe(fmt.Sprintf(":%d", s.port), nil)
+// allKinds returns a set with every resource kind enabled
+func allKinds() k8s.Kinds {
+	kinds, _ := k8s.ParseKinds("")
+	return kinds
 }
 
-// handleIndex serves the main page
-func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
-	if r.URL.Path != "/" {
-		http.NotFound(w, r)
-		return
+// Start starts the web server and serves until Stop is called or ctx is
+// done. The watchers and broadcast loop run under ctx, so cancelling it
+// stops them and then shuts the server down as Stop does.
+func (s *Server) Start(ctx context.Context) error {
+	activated, err := socketActivation()
+	if err != nil {
+		return err
+	}
+	s.activated = activated
+
+	s.stopMu.Lock()
+	stopped := s.stopped
+	s.stopMu.Unlock()
+	if stopped {
+		return nil
 	}
 
-	// Get default namespace from environment variable
-	defaultNamespace := os.Getenv("DEFAULT_NAMESPACE")
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.checkAccess(ctx)
+
+	// Start WebSocket hub, broadcaster and watcher goroutines
+	go s.hub.run()
+	go s.handleBroadcast(ctx)
+	if s.recorder != nil {
+		go s.recorder.run()
+	}
+	if s.alerts != nil {
+		go s.alerts.Run()
+	}
+	go s.watchKubernetesEvents(ctx)
+	go s.sampleIdle(ctx)
+	if err := s.startGRPC(); err != nil {
+		s.cancel()
+		return err
+	}
+
+	scheme, wsScheme := "http", "ws"
+	if s.tlsConfig != nil {
+		scheme, wsScheme = "https", "wss"
+	}
+	listener, err := s.listen("http", s.port)
+	if err != nil {
+		s.cancel()
+		return err
+	}
+	host := s.displayHost()
+	slog.Info("Starting web server", "address", listener.Addr().String(),
+		"url", fmt.Sprintf("%s://%s:%d", scheme, host, s.port),
+		"websocket", fmt.Sprintf("%s://%s:%d/ws", wsScheme, host, s.port))
+
+	// A Stop during startup found no server to shut down; finish it here
+	s.stopMu.Lock()
+	if s.stopped {
+		s.stopMu.Unlock()
+		listener.Close()
+		s.stopBackground()
+		return nil
+	}
+	s.httpServer = &http.Server{
+		Handler:   s.withHSTS(s.Handler()),
+		TLSConfig: s.tlsConfig,
+	}
+	s.stopMu.Unlock()
+	go func() {
+		<-ctx.Done()
+		stopCtx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		defer cancel()
+		if err := s.Stop(stopCtx); err != nil {
+			slog.Error("Error during shutdown", "error", err)
+		}
+	}()
 
-	data := struct {
-		DefaultNamespace string
-	}{
-		DefaultNamespace: defaultNamespace,
+	if s.tlsConfig != nil {
+		// The certificate comes from TLSConfig, so no files are passed
+		err = s.httpServer.ServeTLS(listener, "", "")
+	} else {
+		err = s.httpServer.Serve(listener)
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return err
 	}
+	return nil
+}
+
+// checkAccess hides kinds the server's identity may not list, so that a
+// read-only service account with limited RBAC gets a partial dashboard
+// instead of failing every request
+func (s *Server) checkAccess(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	err := s.template.ExecuteTemplate(w, "index.html", data)
+	accessible, denied, err := s.client.AccessibleKinds(ctx, "", s.kinds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		slog.Warn("Could not check RBAC access, assuming all kinds are listable", "error", err)
 		return
 	}
+	if len(denied) > 0 {
+		slog.Warn("Hiding kinds not permitted to be listed cluster-wide", "kinds", strings.Join(denied, ","))
+	}
+
+	s.kinds = accessible
+	s.denied = denied
+}
+
+// Stop gracefully shuts down the server: the watchers stop, WebSocket
+// clients are sent a close frame and in-flight HTTP requests are drained
+// until ctx expires. Only the first call has any effect. A Stop before
+// Start is serving makes Start return without serving.
+func (s *Server) Stop(ctx context.Context) error {
+	var err error
+	s.stopOnce.Do(func() { err = s.stop(ctx) })
+	return err
+}
+
+// stop does the work of Stop
+func (s *Server) stop(ctx context.Context) error {
+	s.hub.stop()
+
+	// httpServer is only set once Start has launched the background
+	// goroutines; until then Start sees stopped and returns instead
+	s.stopMu.Lock()
+	s.stopped = true
+	httpServer := s.httpServer
+	s.stopMu.Unlock()
+	if httpServer == nil {
+		return nil
+	}
+	s.stopBackground()
+	return httpServer.Shutdown(ctx)
+}
+
+// stopBackground stops the watchers, the gRPC server and the other work
+// Start launched besides serving HTTP
+func (s *Server) stopBackground() {
+	s.cancel()
+	s.stopGRPC()
+	if s.recorder != nil {
+		s.recorder.stop()
+	}
+	if s.alerts != nil {
+		s.alerts.Stop()
+	}
+	s.forwards.closeAll()
+	if s.timeline != nil {
+		if err := s.timeline.Close(); err != nil {
+			slog.Error("Failed to close history", "error", err)
+		}
+	}
 }
 
 // handleClusterData serves cluster data as JSON
 func (s *Server) handleClusterData(w http.ResponseWriter, r *http.Request) {
-	namespace := r.URL.Query().Get("namespace")
+	namespace := namespaceParam(r)
+	nodeName := r.URL.Query().Get("node")
 
-	// Get pod information
-	pods, err := s.client.GetPods(r.Context(), namespace)
+	order, sorted, err := podOrder(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get pods: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Get deployment information
-	deployments, err := s.client.GetDeployments(r.Context(), namespace)
+	problems := false
+	if value := r.URL.Query().Get("problemsOnly"); value != "" {
+		if problems, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid problemsOnly %q: expected true or false", value), http.StatusBadRequest)
+			return
+		}
+	}
+
+	hideCompleted := false
+	if value := r.URL.Query().Get("hideCompleted"); value != "" {
+		if hideCompleted, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid hideCompleted %q: expected true or false", value), http.StatusBadRequest)
+			return
+		}
+	}
+
+	names, err := nameFilterParam(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get deployments: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Convert to response format
-	podData := make([]PodData, len(pods))
-	totalContainers := 0
-	readyContainers := 0
+	qosClasses, priorityClasses, err := qosParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	scheduled := len(qosClasses) > 0 || len(priorityClasses) > 0
 
-	for i, pod := range pods {
-		totalContainers += pod.ContainerCount
-		readyContainers += pod.ReadyContainers
+	limit, after, err := s.pageParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	paged := limit > 0 || after != ""
+	if paged && sorted {
+		http.Error(w, errPagedSort.Error(), http.StatusBadRequest)
+		return
+	}
 
-		podData[i] = PodData{
-			Name:            pod.Name,
-			Namespace:       pod.Namespace,
-			Status:          pod.Status,
-			ContainerCount:  pod.ContainerCount,
-			ReadyContainers: pod.ReadyContainers,
-			StatusSymbol:    getStatusSymbol(pod.Status),
+	// Unfiltered requests are answered from the latest broadcast snapshot,
+	// which the watcher keeps current, without fetching or encoding again
+	if namespace == "" && nodeName == "" && names == nil && !scheduled && !hideCompleted && !sorted && !problems && !paged {
+		if snapshot, ok := s.encoded.load(); ok {
+			if refreshed, ok := s.cache.lastRefresh(); ok {
+				writeStaleness(w, refreshed, true)
+			}
+			if notModified(w, r, snapshot.checksum) {
+				return
+			}
+			writeJSONBytes(w, http.StatusOK, snapshot.body)
+			return
 		}
 	}
 
-	deploymentData := make([]DeploymentData, len(deployments))
-	totalReplicas := int32(0)
-	readyReplicasTotal := int32(0)
-
-	for i, deployment := range deployments {
-		totalReplicas += deployment.Replicas
-		readyReplicasTotal += deployment.ReadyReplicas
+	clusterData, err := s.clusterView(w, r, namespace, nodeName)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get cluster data: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if names != nil {
+		clusterData = filterByName(clusterData, names)
+	}
+	if scheduled {
+		clusterData.Pods = append([]PodData{}, k8s.FilterByQOS(clusterData.Pods, qosClasses, priorityClasses)...)
+	}
+	if hideCompleted {
+		clusterData.Pods = append([]PodData{}, k8s.HideCompleted(clusterData.Pods)...)
+	}
+	if problems {
+		clusterData = problemsOnly(clusterData)
+	}
+	if sorted {
+		// The view may be shared with the cache, so sort a copy
+		clusterData.Pods = slices.Clone(clusterData.Pods)
+		sortPodData(clusterData.Pods, order)
+	}
+	if paged {
+		clusterData.TotalPods = len(clusterData.Pods)
+		clusterData.Pods, clusterData.NextCursor = paginatePods(clusterData.Pods, limit, after)
+	}
 
-		deploymentData[i] = DeploymentData{
-			Name:              deployment.Name,
-			Namespace:         deployment.Namespace,
-			Replicas:          deployment.Replicas,
-			ReadyReplicas:     deployment.ReadyReplicas,
-			AvailableReplicas: deployment.AvailableReplicas,
-		}
+	// The checksum, and the ETag taken from it, describe the filtered
+	// payload rather than the snapshot it was cut from
+	if names != nil || scheduled || hideCompleted || problems || sorted || paged {
+		clusterData.Checksum = clusterData.computeChecksum()
+	}
+	if notModified(w, r, clusterData.Checksum) {
+		return
 	}
 
-	// Calculate percentages
-	containerPercentage := 0.0
-	if totalContainers > 0 {
-		containerPercentage = float64(readyContainers) / float64(totalContainers) * 100
+	writeJSON(w, http.StatusOK, clusterData)
+}
+
+// notModified sets the ETag of a response with the given checksum, and
+// answers 304 Not Modified if the request's If-None-Match already has it
+func notModified(w http.ResponseWriter, r *http.Request, checksum string) bool {
+	etag := `"` + checksum + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return true
 	}
+	w.Header().Set("ETag", etag)
+	return false
+}
 
-	replicaPercentage := 0.0
-	if totalReplicas > 0 {
-		replicaPercentage = float64(readyReplicasTotal) / float64(totalReplicas) * 100
+// clusterView returns the cluster filtered by namespace and node from
+// memory where possible: the whole cluster from the latest broadcast, a
+// filtered view from the cache, or else freshly fetched and cached. The
+// response's Age and X-Cache headers report which, and how stale it is.
+func (s *Server) clusterView(w http.ResponseWriter, r *http.Request, namespace, nodeName string) (ClusterData, error) {
+	if namespace == "" && nodeName == "" {
+		refreshed, ok := s.cache.lastRefresh()
+		if _, _, latest, hasLatest := s.history.latest(); ok && hasLatest {
+			writeStaleness(w, refreshed, true)
+			return latest, nil
+		}
 	}
 
-	clusterData := ClusterData{
-		Pods:                podData,
-		Deployments:         deploymentData,
-		TotalContainers:     totalContainers,
-		ReadyContainers:     readyContainers,
-		ContainerPercentage: containerPercentage,
-		TotalReplicas:       totalReplicas,
-		ReadyReplicas:       readyReplicasTotal,
-		ReplicaPercentage:   replicaPercentage,
-		LastUpdated:         time.Now(),
+	key := cacheKey{namespace: namespace, node: nodeName}
+	if entry, ok := s.cache.get(key); ok {
+		writeStaleness(w, entry.fetchedAt, true)
+		return entry.data, nil
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(clusterData)
-}
-
-// getStatusSymbol returns a symbol for the pod status
-func getStatusSymbol(status string) string {
-	switch status {
-	case "Running":
-		return "✅"
-	case "Pending":
-		return "⏳"
-	case "Failed":
-		return "❌"
-	case "Succeeded":
-		return "✅"
-	default:
-		return "❓"
+	start := time.Now()
+	clusterData, err := s.getClusterData(r.Context(), namespace, nodeName)
+	if err != nil {
+		requestLogger(r).Error("Failed to get cluster data", "error", err, "duration", time.Since(start))
+		return ClusterData{}, err
 	}
+	requestLogger(r).Debug("Fetched filtered cluster data", "pods", len(clusterData.Pods), "duration", time.Since(start))
+	s.cache.put(key, clusterData, start)
+	writeStaleness(w, start, false)
+	return clusterData, nil
 }
 
 // handleHealth returns a simple health check
@@ -233,6 +734,18 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // handleReady checks if the server can connect to Kubernetes API
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	// A recent watch-driven refresh proves the API is reachable; probes
+	// arriving in between need not query it again
+	_, refreshInterval, _ := s.watchPlan()
+	if refreshed, ok := s.cache.lastRefresh(); ok && time.Since(refreshed) < 2*refreshInterval {
+		writeStaleness(w, refreshed, true)
+		writeJSON(w, http.StatusOK, map[string]string{
+			"status":    "ready",
+			"timestamp": time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
 	// Test connection to Kubernetes API
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
@@ -259,165 +772,478 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 
 // handleWebSocket handles WebSocket connections
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	logger := requestLogger(r)
+
+	ip := clientIP(r)
+	if err := s.wsLimiter.acquire(ip); err != nil {
+		code := http.StatusServiceUnavailable
+		if err == errTooManyClientsForIP {
+			code = http.StatusTooManyRequests
+		}
+		logger.Warn("Rejecting WebSocket client", "reason", err)
+		w.Header().Set("Retry-After", strconv.Itoa(int(wsRetryAfter.Seconds())))
+		http.Error(w, err.Error(), code)
+		return
+	}
+	defer s.wsLimiter.release(ip)
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade failed: %v", err)
+		logger.Warn("WebSocket upgrade failed", "error", err)
 		return
 	}
-	defer func() {
-		s.clientsMux.Lock()
-		delete(s.clients, conn)
-		s.clientsMux.Unlock()
-		conn.Close()
-	}()
+	conn.SetReadLimit(maxClientMessageSize)
+	client := newClient(conn)
+	messages := newMessageLimiter()
+
+	// Queue initial data before registering, as a catch-up diff when resuming
+	if message, err := s.initialMessage(r.URL.Query().Get("resume")); err != nil {
+		logger.Error("Failed to get initial cluster data", "error", err)
+	} else if prepared, err := prepareMessage(message); err != nil {
+		logger.Error("Failed to encode initial cluster data", "error", err)
+	} else {
+		client.send <- prepared
+	}
 
-	// Register new client
-	s.clientsMux.Lock()
-	s.clients[conn] = true
-	s.clientsMux.Unlock()
+	s.hub.Register(client)
+	defer s.hub.Unregister(client)
 
-	log.Printf("New WebSocket client connected. Total clients: %d", len(s.clients))
+	// Keep connection alive and handle client messages
+	for {
+		var request WSMessage
+		if err := conn.ReadJSON(&request); err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				continue
+			}
+			logger.Info("WebSocket client disconnected", "reason", err)
+			break
+		}
+		if !messages.Allow() {
+			logger.Debug("Ignoring WebSocket message over the rate limit", "type", request.Type)
+			continue
+		}
 
-	// Send initial data immediately
-	clusterData, err := s.getClusterData(context.Background(), "")
-	if err == nil {
-		conn.WriteJSON(clusterData)
+		if request.Type == MessageResync {
+			if message, ok := s.snapshotMessage(); ok {
+				if prepared, err := prepareMessage(message); err == nil {
+					s.hub.Send(client, prepared)
+				}
+			}
+		}
 	}
+}
 
-	// Keep connection alive and handle client messages
-	for {
-		_, _, err := conn.ReadMessage()
+// initialMessage builds the first message for a newly connected client,
+// positioned at the latest broadcast snapshot so subsequent diffs apply. If
+// the client presents a resume token that is still retained, it receives only
+// the changes since that snapshot; otherwise it receives a full snapshot.
+func (s *Server) initialMessage(resumeToken string) (WSMessage, error) {
+	token, seq, clusterData, ok := s.history.latest()
+	if !ok {
+		data, err := s.getClusterData(context.Background(), "", "")
 		if err != nil {
-			log.Printf("WebSocket client disconnected: %v", err)
-			break
+			return WSMessage{}, err
 		}
+		clusterData = data
+		token, seq = s.history.add(clusterData)
 	}
+
+	if resumeToken != "" {
+		if base, ok := s.history.get(resumeToken); ok {
+			diff := diffClusterData(base, clusterData)
+			return WSMessage{
+				Type:      MessageDiff,
+				Token:     token,
+				Seq:       seq,
+				BaseToken: resumeToken,
+				Diff:      &diff,
+			}, nil
+		}
+	}
+
+	return WSMessage{
+		Type:  MessageSnapshot,
+		Token: token,
+		Seq:   seq,
+		Data:  &clusterData,
+	}, nil
 }
 
-// handleBroadcast broadcasts cluster data to all connected WebSocket clients
-func (s *Server) handleBroadcast() {
+// snapshotMessage builds a full snapshot of the latest broadcast state
+func (s *Server) snapshotMessage() (WSMessage, bool) {
+	token, seq, clusterData, ok := s.history.latest()
+	if !ok {
+		return WSMessage{}, false
+	}
+
+	return WSMessage{
+		Type:  MessageSnapshot,
+		Token: token,
+		Seq:   seq,
+		Data:  &clusterData,
+	}, true
+}
+
+// handleBroadcast sends each new cluster snapshot to all connected WebSocket
+// clients as a diff against the previous broadcast, until ctx is done
+func (s *Server) handleBroadcast(ctx context.Context) {
 	for {
 		select {
+		case <-ctx.Done():
+			return
+
 		case clusterData := <-s.broadcast:
-			s.clientsMux.RLock()
-			for conn := range s.clients {
-				err := conn.WriteJSON(clusterData)
-				if err != nil {
-					log.Printf("Error sending data to WebSocket client: %v", err)
-					conn.Close()
-					delete(s.clients, conn)
+			prevToken, _, prev, hasPrev := s.history.latest()
+			if hasPrev && prev.Checksum == clusterData.Checksum {
+				// Nothing changed since the last broadcast, which is
+				// therefore current as of this refresh
+				s.cache.refresh(clusterData.LastUpdated, false)
+				continue
+			}
+
+			if s.recorder != nil {
+				s.recorder.Offer(clusterData)
+			}
+			// Only one replica notifies, so alerts are not sent N times
+			if s.alerts != nil && s.leading() {
+				s.alerts.Offer(toAlertSnapshot(clusterData))
+			}
+
+			token, seq := s.history.add(clusterData)
+			s.cache.refresh(clusterData.LastUpdated, true)
+			message := WSMessage{
+				Type:  MessageSnapshot,
+				Token: token,
+				Seq:   seq,
+				Data:  &clusterData,
+			}
+			if hasPrev {
+				diff := diffClusterData(prev, clusterData)
+				message = WSMessage{
+					Type:      MessageDiff,
+					Token:     token,
+					Seq:       seq,
+					BaseToken: prevToken,
+					Diff:      &diff,
 				}
 			}
-			s.clientsMux.RUnlock()
+
+			// Encode once for every client and for /api/cluster
+			if err := s.encoded.store(clusterData); err != nil {
+				slog.Error("Failed to encode cluster data", "error", err)
+			}
+			prepared, err := prepareMessage(message)
+			if err != nil {
+				slog.Error("Failed to encode WebSocket message", "error", err)
+				continue
+			}
+			s.hub.Broadcast(prepared)
+			s.watchers.publish(clusterData)
 		}
 	}
 }
 
-// watchKubernetesEvents watches for changes in Kubernetes resources and broadcasts updates
-func (s *Server) watchKubernetesEvents() {
-	log.Println("Starting Kubernetes events watcher...")
+// watchKubernetesEvents watches for changes in Kubernetes resources and
+// broadcasts updates until ctx is done
+func (s *Server) watchKubernetesEvents(ctx context.Context) {
+	slog.Info("Starting Kubernetes events watcher")
 
-	ctx := context.Background()
+	if s.election != nil {
+		go s.runElection(ctx, s.runWatchers)
+	} else {
+		go s.runWatchers(ctx)
+	}
 
-	for {
-		// Watch pods
-		go s.watchPods(ctx)
+	// Send periodic updates as fallback, and coalesce bursts of watch
+	// events into at most one refresh per debounce window
+	_, refreshInterval, changed := s.watchPlan()
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
 
-		// Watch deployments
-		go s.watchDeployments(ctx)
-
-		// Send periodic updates every 10 seconds as fallback
-		ticker := time.NewTicker(10 * time.Second)
-		for range ticker.C {
-			clusterData, err := s.getClusterData(ctx, "")
-			if err != nil {
-				log.Printf("Error getting cluster data: %v", err)
-				continue
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopped Kubernetes events watcher")
+			return
+
+		case <-changed:
+			_, refreshInterval, changed = s.watchPlan()
+			ticker.Reset(refreshInterval)
+
+		case <-ticker.C:
+			// A full refresh covers whatever changed since the last one
+			s.takePending()
+			s.refreshAndBroadcast(ctx)
+
+		case <-s.refresh:
+			if debounce == nil {
+				debounce = time.After(s.debounce)
 			}
 
-			select {
-			case s.broadcast <- clusterData:
-			default:
-				// Channel is full, skip this update
-			}
+		case <-debounce:
+			debounce = nil
+			s.refreshPending(ctx)
 		}
 	}
 }
 
-// watchPods watches for pod changes
-func (s *Server) watchPods(ctx context.Context) {
-	for {
-		watcher, err := s.client.GetClientset().CoreV1().Pods("").Watch(ctx, metav1.ListOptions{})
-		if err != nil {
-			log.Printf("Error creating pod watcher: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
-		}
+// watchPlan returns the namespaces to watch, the interval between full
+// refreshes, and a channel closed when Reconfigure or a change of
+// leadership changes either. Every namespace is watched unless priority
+// namespaces are configured, in which case the remaining namespaces fall
+// back to slower polling. Replicas that do not lead poll slower still.
+func (s *Server) watchPlan() ([]string, time.Duration, <-chan struct{}) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+
+	namespaces, refreshInterval := []string{""}, s.refreshInterval
+	if len(s.priority) > 0 {
+		namespaces, refreshInterval = s.priority, max(refreshInterval, slowRefreshInterval)
+	}
+	if !s.leading() {
+		refreshInterval = max(refreshInterval, followerRefreshInterval)
+	}
+	return namespaces, refreshInterval, s.settingsChanged
+}
 
-		for event := range watcher.ResultChan() {
-			if event.Type == watch.Added || event.Type == watch.Modified || event.Type == watch.Deleted {
-				clusterData, err := s.getClusterData(ctx, "")
-				if err != nil {
-					log.Printf("Error getting cluster data after pod event: %v", err)
-					continue
-				}
+// planChanged wakes up everything following watchPlan; callers hold
+// settingsMu
+func (s *Server) planChanged() {
+	close(s.settingsChanged)
+	s.settingsChanged = make(chan struct{})
+}
 
-				select {
-				case s.broadcast <- clusterData:
-				default:
-					// Channel is full, skip this update
-				}
+// runWatchers watches the planned namespaces until ctx is done, starting
+// over whenever a reconfiguration changes them
+func (s *Server) runWatchers(ctx context.Context) {
+	var (
+		watching []string
+		stop     context.CancelFunc
+	)
+	for {
+		namespaces, refreshInterval, changed := s.watchPlan()
+		if stop == nil || !slices.Equal(namespaces, watching) {
+			if stop != nil {
+				stop()
 			}
+			if namespaces[0] != "" {
+				slog.Info("Priority namespaces get real-time updates", "namespaces", strings.Join(namespaces, ","), "refresh_interval", refreshInterval.String())
+			}
+			var watchCtx context.Context
+			watchCtx, stop = context.WithCancel(ctx)
+			s.startWatchers(watchCtx, namespaces)
+			watching = namespaces
 		}
 
-		watcher.Stop()
-		time.Sleep(1 * time.Second) // Brief pause before restarting watcher
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			stop()
+			return
+		}
 	}
 }
 
-// watchDeployments watches for deployment changes
-func (s *Server) watchDeployments(ctx context.Context) {
-	for {
-		watcher, err := s.client.GetClientset().AppsV1().Deployments("").Watch(ctx, metav1.ListOptions{})
-		if err != nil {
-			log.Printf("Error creating deployment watcher: %v", err)
-			time.Sleep(5 * time.Second)
-			continue
+// startWatchers watches pods and deployments in each namespace until ctx
+// is done
+func (s *Server) startWatchers(ctx context.Context, namespaces []string) {
+	for _, namespace := range namespaces {
+		// Watch pods
+		if s.kinds.Enabled(k8s.KindPods) {
+			go s.watchPods(ctx, namespace)
 		}
 
-		for event := range watcher.ResultChan() {
-			if event.Type == watch.Added || event.Type == watch.Modified || event.Type == watch.Deleted {
-				clusterData, err := s.getClusterData(ctx, "")
-				if err != nil {
-					log.Printf("Error getting cluster data after deployment event: %v", err)
-					continue
-				}
-
-				select {
-				case s.broadcast <- clusterData:
-				default:
-					// Channel is full, skip this update
-				}
-			}
+		// Watch deployments
+		if s.kinds.Enabled(k8s.KindDeployments) {
+			go s.watchDeployments(ctx, namespace)
 		}
+	}
+}
 
-		watcher.Stop()
-		time.Sleep(1 * time.Second) // Brief pause before restarting watcher
+// requestRefresh asks the watcher loop for a debounced refresh
+func (s *Server) requestRefresh() {
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+		// A refresh is already pending
+	}
+}
+
+// refreshAndBroadcast fetches cluster data and queues it for broadcast
+func (s *Server) refreshAndBroadcast(ctx context.Context) {
+	start := time.Now()
+	clusterData, err := s.getClusterData(ctx, "", "")
+	if err != nil {
+		slog.Error("Failed to get cluster data", "error", err)
+		return
+	}
+	slog.Debug("Refreshed cluster data", "pods", len(clusterData.Pods), "duration", time.Since(start))
+
+	s.queueBroadcast(clusterData)
+}
+
+// queueBroadcast queues a refreshed snapshot for broadcast and keeps it
+// for the next partial refresh to merge into
+func (s *Server) queueBroadcast(clusterData ClusterData) {
+	s.lastRefresh = &clusterData
+
+	select {
+	case s.broadcast <- clusterData:
+	default:
+		// Channel is full, skip this update
 	}
 }
 
 // getClusterData is a helper method to get cluster data
-func (s *Server) getClusterData(ctx context.Context, namespace string) (ClusterData, error) {
+func (s *Server) getClusterData(ctx context.Context, namespace, nodeName string) (ClusterData, error) {
+	var (
+		pods        []k8s.PodInfo
+		deployments []k8s.DeploymentInfo
+		hpas        []k8s.HPAInfo
+		pdbs        []k8s.PDBInfo
+		jobs        []k8s.JobInfo
+		cronJobs    []k8s.CronJobInfo
+		services    []k8s.ServiceInfo
+		ingresses   []k8s.IngressInfo
+		pvcs        []k8s.PVCInfo
+		rollouts    []k8s.ArgoRolloutInfo
+		err         error
+	)
+
+	// Kinds denied at startup, or whose list call is forbidden now, are
+	// reported as unavailable rather than failing the whole snapshot
+	unavailable := append([]string(nil), s.denied...)
+	forbidden := func(kind string, err error) bool {
+		if apierrors.IsForbidden(err) {
+			unavailable = append(unavailable, kind)
+			return true
+		}
+		return false
+	}
+
 	// Get pod information
-	pods, err := s.client.GetPods(ctx, namespace)
-	if err != nil {
-		return ClusterData{}, err
+	if s.kinds.Enabled(k8s.KindPods) {
+		pods, err = s.client.GetPodsOnNode(ctx, namespace, nodeName)
+		if err != nil && !forbidden(k8s.KindPods, err) {
+			return ClusterData{}, err
+		}
+
+		// Namespace runtime policies are optional; skip them if namespaces can't be listed
+		if policies, err := s.client.GetRuntimeClassPolicies(ctx); err == nil {
+			k8s.ApplyRuntimeClassPolicies(pods, policies)
+		}
 	}
 
 	// Get deployment information
-	deployments, err := s.client.GetDeployments(ctx, namespace)
+	if s.kinds.Enabled(k8s.KindDeployments) {
+		deployments, err = s.client.GetDeployments(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindDeployments, err) {
+			return ClusterData{}, err
+		}
+
+		// Warning events are optional; score without them if events can't be listed
+		warnings, _ := s.client.CountDeploymentWarnings(ctx, namespace, pods)
+		k8s.ApplyDeploymentHealth(deployments, pods, warnings)
+
+		// So is the sync status of Argo CD and Flux
+		gitOps, _ := s.client.GetGitOpsStatus(ctx)
+		k8s.ApplyGitOpsStatus(deployments, pods, gitOps)
+
+		// Argo Rollouts, where installed, are listed beside deployments;
+		// failing to list them leaves the rest of the snapshot intact
+		rollouts, err = s.client.GetArgoRollouts(ctx, namespace)
+		if err != nil {
+			slog.Warn("Failed to list Argo Rollouts", "error", err)
+			unavailable = append(unavailable, kindArgoRollouts)
+		}
+		k8s.ApplyArgoRolloutPods(rollouts, pods)
+	}
+
+	// Get autoscaler information, marking deployments pinned at maxReplicas
+	if s.kinds.Enabled(k8s.KindHPAs) {
+		hpas, err = s.client.GetHPAs(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindHPAs, err) {
+			return ClusterData{}, err
+		}
+		k8s.ApplyHPAs(deployments, hpas)
+	}
+
+	// Get disruption budget information
+	if s.kinds.Enabled(k8s.KindPDBs) {
+		pdbs, err = s.client.GetPDBs(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindPDBs, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	// Get job information
+	if s.kinds.Enabled(k8s.KindJobs) {
+		jobs, err = s.client.GetJobs(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindJobs, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	if s.kinds.Enabled(k8s.KindCronJobs) {
+		cronJobs, err = s.client.GetCronJobs(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindCronJobs, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	// Get service information
+	if s.kinds.Enabled(k8s.KindServices) {
+		services, err = s.client.GetServices(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindServices, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	// Get ingress and route information
+	if s.kinds.Enabled(k8s.KindIngresses) {
+		ingresses, err = s.client.GetIngresses(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindIngresses, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	// Get persistentvolumeclaim information
+	if s.kinds.Enabled(k8s.KindPVCs) {
+		pvcs, err = s.client.GetPVCs(ctx, namespace)
+		if err != nil && !forbidden(k8s.KindPVCs, err) {
+			return ClusterData{}, err
+		}
+	}
+
+	// Custom resource types are optional and only listed once configured;
+	// those that fail to list are reported unavailable
+	customResources, err := s.client.GetCustomResources(ctx, namespace, s.customResourceTypes())
 	if err != nil {
-		return ClusterData{}, err
+		slog.Warn("Failed to list custom resources", "error", err)
+		unavailable = append(unavailable, kindCustomResources)
+	}
+
+	// Nodes are optional: they only add pressure, taint, zone and usage information
+	var nodes []k8s.NodeInfo
+	if s.kinds.Enabled(k8s.KindNodes) {
+		if nodes, err = s.client.GetNodes(ctx); err == nil {
+			k8s.ApplyNodePressure(pods, nodes)
+			k8s.ApplyTaintBlocks(pods, nodes)
+			k8s.ApplyZones(pods, nodes)
+		}
+	}
+
+	// Nodes are reported whenever they can be listed, with live usage on
+	// top when metrics-server is available
+	s.annotateUsage(ctx, namespace, pods, nodes)
+	nodeData := toNodeData(nodes)
+
+	// Storage usage comes from each node's kubelet and is opt-in
+	if s.kubeletStats {
+		if err := s.metrics.AnnotateStorage(ctx, pods); err != nil {
+			slog.Warn("Failed to get kubelet storage usage", "error", err)
+		}
 	}
 
 	// Convert to response format
@@ -426,17 +1252,11 @@ func (s *Server) getClusterData(ctx context.Context, namespace string) (ClusterD
 	readyContainers := 0
 
 	for i, pod := range pods {
-		totalContainers += pod.ContainerCount
-		readyContainers += pod.ReadyContainers
+		ready, total := pod.Readiness()
+		totalContainers += total
+		readyContainers += ready
 
-		podData[i] = PodData{
-			Name:            pod.Name,
-			Namespace:       pod.Namespace,
-			Status:          pod.Status,
-			ContainerCount:  pod.ContainerCount,
-			ReadyContainers: pod.ReadyContainers,
-			StatusSymbol:    getStatusSymbol(pod.Status),
-		}
+		podData[i] = s.toPodData(pod)
 	}
 
 	deploymentData := make([]DeploymentData, len(deployments))
@@ -448,11 +1268,23 @@ func (s *Server) getClusterData(ctx context.Context, namespace string) (ClusterD
 		readyReplicasTotal += deployment.ReadyReplicas
 
 		deploymentData[i] = DeploymentData{
+			UID:               deployment.UID,
 			Name:              deployment.Name,
 			Namespace:         deployment.Namespace,
 			Replicas:          deployment.Replicas,
 			ReadyReplicas:     deployment.ReadyReplicas,
 			AvailableReplicas: deployment.AvailableReplicas,
+			PinnedAtMax:       deployment.PinnedAtMax,
+			HealthScore:       deployment.HealthScore,
+			Restarts:          deployment.Restarts,
+			WarningEvents:     deployment.WarningEvents,
+			RolloutStuck:      deployment.RolloutStuck,
+			GitOpsTool:        deployment.GitOps.Tool,
+			GitOpsSource:      deployment.GitOps.Source,
+			GitOpsSync:        deployment.GitOps.Sync,
+			GitOpsHealth:      deployment.GitOps.Health,
+			GitOpsMessage:     deployment.GitOps.Message,
+			CreatedAt:         deployment.CreatedAt.UTC(),
 		}
 	}
 
@@ -467,15 +1299,153 @@ func (s *Server) getClusterData(ctx context.Context, namespace string) (ClusterD
 		replicaPercentage = float64(readyReplicasTotal) / float64(totalReplicas) * 100
 	}
 
-	return ClusterData{
+	clusterData := ClusterData{
+		SchemaVersion:       SchemaVersion,
 		Pods:                podData,
 		Deployments:         deploymentData,
+		HPAs:                toHPAData(hpas),
+		PDBs:                toPDBData(pdbs),
+		Jobs:                toJobData(jobs),
+		CronJobs:            toCronJobData(cronJobs),
+		Services:            toServiceData(services),
+		Ingresses:           toIngressData(ingresses),
+		PVCs:                toPVCData(pvcs),
+		CustomResources:     toCustomResourceData(customResources),
+		ArgoRollouts:        toArgoRolloutData(rollouts),
+		Nodes:               nodeData,
 		TotalContainers:     totalContainers,
 		ReadyContainers:     readyContainers,
 		ContainerPercentage: containerPercentage,
 		TotalReplicas:       totalReplicas,
 		ReadyReplicas:       readyReplicasTotal,
 		ReplicaPercentage:   replicaPercentage,
-		LastUpdated:         time.Now(),
-	}, nil
+		Unavailable:         unavailable,
+	}
+	clusterData.Checksum = clusterData.computeChecksum()
+	clusterData.LastUpdated = time.Now()
+
+	return clusterData, nil
+}
+
+// computeChecksum returns a SHA-256 of the snapshot content, excluding
+// the timestamp and the checksum itself, so identical snapshots hash equally
+func (d ClusterData) computeChecksum() string {
+	d.Checksum = ""
+	d.LastUpdated = time.Time{}
+
+	// Encode straight into the hash rather than materializing the body
+	hash := sha256.New()
+	if err := json.NewEncoder(hash).Encode(d); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// toPodData converts a pod to its response format
+func (s *Server) toPodData(pod k8s.PodInfo) PodData {
+	data := PodData{
+		UID:             pod.UID,
+		Name:            pod.Name,
+		Namespace:       pod.Namespace,
+		Status:          pod.Status,
+		ContainerCount:  pod.ContainerCount,
+		ReadyContainers: pod.ReadyContainers,
+		CreatedAt:       pod.CreatedAt.UTC(),
+		Deployment:      pod.Deployment,
+		StartupSeconds:  pod.StartupDuration().Seconds(),
+		Restarts:        pod.Restarts,
+		NodeName:        pod.NodeName,
+		StatusSymbol:    s.symbols.Symbol(pod.Status),
+
+		InitContainerCount:      pod.InitContainerCount,
+		InitContainersDone:      pod.InitContainersDone,
+		EphemeralContainerCount: pod.EphemeralContainerCount,
+
+		SidecarCount:     pod.SidecarCount,
+		ReadySidecars:    pod.ReadySidecars,
+		SidecarsExcluded: pod.SidecarsExcluded,
+
+		RuntimeClass:         pod.RuntimeClass,
+		ExpectedRuntimeClass: pod.ExpectedRuntimeClass,
+		RuntimeClassMismatch: pod.RuntimeClassMismatch(),
+
+		QOSClass:          pod.QOSClass,
+		PriorityClassName: pod.PriorityClass,
+
+		Unschedulable:   pod.Unschedulable,
+		EvictionMessage: pod.EvictionMessage,
+		BlockingTaints:  blockingTaints(pod.BlockingTaints),
+
+		NodePressure: strings.Join(pod.NodePressure, ","),
+		Zone:         pod.Zone,
+
+		GitOpsTool:    pod.GitOps.Tool,
+		GitOpsSource:  pod.GitOps.Source,
+		GitOpsSync:    pod.GitOps.Sync,
+		GitOpsHealth:  pod.GitOps.Health,
+		GitOpsMessage: pod.GitOps.Message,
+
+		CPURequestMilli:    pod.CPURequestMilli,
+		MemoryRequestBytes: pod.MemoryRequestBytes,
+		CPUUsageMilli:      pod.CPUUsageMilli,
+		MemoryUsageBytes:   pod.MemoryUsageBytes,
+
+		EphemeralStorageLimitBytes: pod.EphemeralStorageLimitBytes,
+		EphemeralStorageUsedBytes:  pod.EphemeralStorageUsedBytes,
+		RootfsUsedBytes:            pod.RootfsUsedBytes,
+		EphemeralStorageNearLimit:  pod.EphemeralStorageNearLimit(),
+	}
+
+	if len(pod.Hints) > 0 {
+		data.HintCause = pod.Hints[0].Cause
+		data.Hint = pod.Hints[0].Message
+	}
+	if len(pod.CrashLoops) > 0 {
+		loop := pod.CrashLoops[0]
+		data.CrashLoopContainer = loop.Container
+		data.LastExitReason = loop.Reason
+		data.BackoffSeconds = loop.Backoff.Seconds()
+		if !loop.FinishedAt.IsZero() {
+			data.LastRestartAt = loop.FinishedAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return data
+}
+
+// annotateUsage annotates pods and nodes with live usage when the metrics
+// kind is enabled and metrics-server answers; otherwise they are left
+// without usage
+func (s *Server) annotateUsage(ctx context.Context, namespace string, pods []k8s.PodInfo, nodes []k8s.NodeInfo) {
+	if !s.kinds.Enabled(k8s.KindMetrics) {
+		return
+	}
+	if err := s.metrics.AnnotatePods(ctx, namespace, pods); err != nil {
+		return
+	}
+	if len(nodes) > 0 {
+		_ = s.metrics.AnnotateNodes(ctx, nodes)
+	}
+}
+
+// toNodeData converts nodes to their response format, with whatever usage
+// annotateUsage added
+func toNodeData(nodes []k8s.NodeInfo) []NodeData {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	nodeData := make([]NodeData, len(nodes))
+	for i, node := range nodes {
+		nodeData[i] = NodeData{
+			Name:                   node.Name,
+			Ready:                  node.Ready,
+			CPUAllocatableMilli:    node.CPUAllocatableMilli,
+			MemoryAllocatableBytes: node.MemoryAllocatableBytes,
+			CPUUsageMilli:          node.CPUUsageMilli,
+			MemoryUsageBytes:       node.MemoryUsageBytes,
+			Pressure:               node.Pressure,
+			Zone:                   node.Zone,
+		}
+	}
+	return nodeData
 }
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"pod-visualizer/pkg/k8s/fake"
+)
+
+// newTestServer returns a server over a fake cluster holding objects
+func newTestServer(objects ...runtime.Object) *Server {
+	client, _ := fake.NewClient(objects...)
+	return NewServer(client, 0)
+}
+
+// serve runs req through the server's full handler chain
+func serve(s *Server, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStopBeforeStart(t *testing.T) {
+	s := newTestServer()
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Start(context.Background()) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start() kept serving after Stop")
+	}
+}
@@ -0,0 +1,33 @@
+package web
+
+import "pod-visualizer/pkg/k8s"
+
+// ServiceData represents service data for JSON response
+type ServiceData struct {
+	UID               string `json:"uid"`
+	Name              string `json:"name"`
+	Namespace         string `json:"namespace"`
+	Type              string `json:"type"`
+	ClusterIP         string `json:"clusterIP"`
+	HasSelector       bool   `json:"hasSelector"`
+	ReadyEndpoints    int    `json:"readyEndpoints"`
+	NotReadyEndpoints int    `json:"notReadyEndpoints"`
+}
+
+// toServiceData converts services to their response format
+func toServiceData(services []k8s.ServiceInfo) []ServiceData {
+	serviceData := make([]ServiceData, len(services))
+	for i, service := range services {
+		serviceData[i] = ServiceData{
+			UID:               service.UID,
+			Name:              service.Name,
+			Namespace:         service.Namespace,
+			Type:              service.Type,
+			ClusterIP:         service.ClusterIP,
+			HasSelector:       service.HasSelector,
+			ReadyEndpoints:    service.ReadyEndpoints,
+			NotReadyEndpoints: service.NotReadyEndpoints,
+		}
+	}
+	return serviceData
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"pod-visualizer/pkg/alerts"
+	"pod-visualizer/pkg/k8s"
+)
+
+// Settings are the parts of the server's configuration that can change
+// while it runs, without restarting it or dropping WebSocket clients
+type Settings struct {
+	// RefreshInterval is how often a full refresh is broadcast; zero
+	// keeps the default
+	RefreshInterval time.Duration
+	// PriorityNamespaces get real-time updates; empty watches all
+	PriorityNamespaces []string
+	// AlertRules replace the rules of the alert evaluator, if alerting is
+	// enabled
+	AlertRules []alerts.Rule
+	// CustomResources are the custom resource types listed beside the
+	// built-in kinds
+	CustomResources []k8s.CustomResourceType
+}
+
+// Reconfigure applies settings to the running server. Watches restart
+// only if the priority namespaces changed.
+func (s *Server) Reconfigure(settings Settings) {
+	s.settingsMu.Lock()
+	s.refreshInterval = defaultRefreshInterval
+	if settings.RefreshInterval > 0 {
+		s.refreshInterval = settings.RefreshInterval
+	}
+	s.priority = settings.PriorityNamespaces
+	s.customResources = settings.CustomResources
+	s.planChanged()
+	s.settingsMu.Unlock()
+
+	if s.alerts != nil {
+		s.alerts.SetRules(settings.AlertRules)
+	}
+	slog.Info("Applied new settings", "refresh_interval", settings.RefreshInterval.String(),
+		"priority_namespaces", strings.Join(settings.PriorityNamespaces, ","), "alert_rules", len(settings.AlertRules),
+		"custom_resources", len(settings.CustomResources))
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotTimeFormat is the UTC timestamp used in snapshot file names
+const snapshotTimeFormat = "20060102T150405Z"
+
+// Snapshot fetches the complete current cluster state, optionally filtered
+// by namespace and node, for attaching to incident postmortems
+func (s *Server) Snapshot(ctx context.Context, namespace, nodeName string) (ClusterData, error) {
+	return s.getClusterData(ctx, namespace, nodeName)
+}
+
+// SnapshotFileName returns the timestamped file name for a snapshot
+func SnapshotFileName(data ClusterData) string {
+	return fmt.Sprintf("pod-visualizer-snapshot-%s.json", data.LastUpdated.UTC().Format(snapshotTimeFormat))
+}
+
+// WriteSnapshotFile writes a snapshot as indented JSON into dir and
+// returns the path of the new file
+func WriteSnapshotFile(dir string, data ClusterData) (string, error) {
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+
+	path := filepath.Join(dir, SnapshotFileName(data))
+	if err := os.WriteFile(path, append(body, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	return path, nil
+}
+
+// handleSnapshot serves a fresh snapshot as a timestamped JSON download,
+// honouring the same ?namespace=, ?namespaces= and ?node= filters as
+// /api/cluster
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	data, err := s.Snapshot(ctx, namespaceParam(r), r.URL.Query().Get("node"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get cluster data: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", SnapshotFileName(data)))
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, data)
+}
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// podOrder reads the sortBy and reverse query parameters. sorted is false
+// when neither is given and pods keep their namespace, then name order.
+func podOrder(r *http.Request) (order k8s.PodOrder, sorted bool, err error) {
+	query := r.URL.Query()
+
+	reverse := false
+	if value := query.Get("reverse"); value != "" {
+		if reverse, err = strconv.ParseBool(value); err != nil {
+			return nil, false, fmt.Errorf("invalid reverse %q: expected true or false", value)
+		}
+	}
+
+	sortBy := query.Get("sortBy")
+	order, err = k8s.ParsePodOrder(sortBy, reverse)
+	if err != nil {
+		return nil, false, err
+	}
+	return order, sortBy != "" || reverse, nil
+}
+
+// sortKey returns the pod's sort fields
+func (p PodData) sortKey() k8s.PodSortKey {
+	return k8s.PodSortKey{
+		Namespace:       p.Namespace,
+		Name:            p.Name,
+		Status:          p.Status,
+		ContainerCount:  p.ContainerCount,
+		ReadyContainers: p.ReadyContainers,
+		Restarts:        p.Restarts,
+		CreatedAt:       p.CreatedAt,
+	}
+}
+
+// sortPodData orders pods in place
+func sortPodData(pods []PodData, order k8s.PodOrder) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return order(pods[i].sortKey(), pods[j].sortKey())
+	})
+}
@@ -0,0 +1,101 @@
+package web
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"pod-visualizer/pkg/history"
+	"pod-visualizer/pkg/k8s"
+)
+
+// DeploymentStartupData summarizes how long a deployment's pods took from
+// creation to Ready, for tuning probes and autoscaling
+type DeploymentStartupData struct {
+	Namespace  string  `json:"namespace"`
+	Deployment string  `json:"deployment"`
+	Pods       int     `json:"pods"`
+	P50Seconds float64 `json:"p50Seconds"`
+	P90Seconds float64 `json:"p90Seconds"`
+	P99Seconds float64 `json:"p99Seconds"`
+	MaxSeconds float64 `json:"maxSeconds"`
+}
+
+// StartupResponse is the response body of /api/startup
+type StartupResponse struct {
+	Hours       int                     `json:"hours"`
+	Deployments []DeploymentStartupData `json:"deployments"`
+}
+
+// handleStartup serves the startup-time percentiles of the deployments
+// whose pods became Ready in the last ?hours=N hours, optionally in
+// ?namespace= only
+func (s *Server) handleStartup(w http.ResponseWriter, r *http.Request) {
+	if s.timeline == nil {
+		http.Error(w, "History is not enabled", http.StatusNotFound)
+		return
+	}
+
+	hours, err := hoursParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	startups, err := s.timeline.StartupsSince(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get startups: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	namespaces := k8s.SplitNamespaces(namespaceParam(r))
+	if namespaces[0] != "" {
+		startups = slices.DeleteFunc(startups, func(startup history.Startup) bool {
+			return !slices.Contains(namespaces, startup.Namespace)
+		})
+	}
+
+	writeJSON(w, http.StatusOK, StartupResponse{Hours: hours, Deployments: summarizeStartups(startups)})
+}
+
+// summarizeStartups groups startups by deployment and computes their
+// percentiles, ordered by namespace and deployment
+func summarizeStartups(startups []history.Startup) []DeploymentStartupData {
+	type key struct{ namespace, deployment string }
+	durations := make(map[key][]time.Duration)
+	for _, startup := range startups {
+		k := key{startup.Namespace, startup.Deployment}
+		durations[k] = append(durations[k], startup.Duration)
+	}
+
+	summaries := make([]DeploymentStartupData, 0, len(durations))
+	for k, values := range durations {
+		slices.Sort(values)
+		summaries = append(summaries, DeploymentStartupData{
+			Namespace:  k.namespace,
+			Deployment: k.deployment,
+			Pods:       len(values),
+			P50Seconds: percentile(values, 50).Seconds(),
+			P90Seconds: percentile(values, 90).Seconds(),
+			P99Seconds: percentile(values, 99).Seconds(),
+			MaxSeconds: values[len(values)-1].Seconds(),
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Deployment < summaries[j].Deployment
+	})
+	return summaries
+}
+
+// percentile returns the nearest-rank pth percentile of sorted, which
+// must not be empty
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
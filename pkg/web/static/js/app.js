@@ -1,29 +1,85 @@
 // Pod Visualizer Frontend JavaScript
 
 let currentNamespace = '';
+let currentNode = new URLSearchParams(window.location.search).get('node') || '';
 let autoRefreshInterval = null;
 let namespaceList = new Set();
+let namespaceDetails = new Map(); // name -> /api/v1/namespaces entry
 let websocket = null;
 let isWebSocketEnabled = false;
 let reconnectAttempts = 0;
 const MAX_RECONNECT_ATTEMPTS = 5;
 const RECONNECT_DELAY = 2000; // 2 seconds
+const HISTORY_HOURS = 24;
+const HISTORY_REFRESH = 5 * 60 * 1000; // the recorder writes at most every 5 minutes
+const SUMMARY_REFRESH = 30 * 1000;
+const HEALTHY_SCORE = 90; // deployment health bands, as in pkg/analysis
+const DEGRADED_SCORE = 60;
+
+// The API version this page calls, and the newest ClusterData schemaVersion
+// it renders
+const API_BASE = '/api/v1';
+const SCHEMA_VERSION = 1;
+let schemaWarned = false;
+
+// Whether the namespace heatmap is shown in place of the pod grid
+let showHeatmap = false;
+let summaryInterval = null;
+
+// Latest full (unfiltered) cluster state and its resume token
+let latestClusterData = null;
+let resumeToken = '';
 
 // Track pods for animations
-let previousPods = new Map(); // podName -> podData
+let previousPods = new Map(); // podKey -> podData
 let animationQueue = [];
 
+// Bootstrap configuration from /api/v1/ui-config
+let uiConfig = { title: 'Pod Visualizer', version: '', defaultNamespace: '', features: {}, authMode: 'none', actions: false };
+
+// Fetch the bootstrap configuration; falls back to defaults on failure
+async function loadUIConfig() {
+    try {
+        const response = await fetch(`${API_BASE}/ui-config`);
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+        uiConfig = { ...uiConfig, ...(await response.json()) };
+        console.log('Loaded UI config, bundle version', uiConfig.version);
+    } catch (error) {
+        console.error('Error loading UI config:', error);
+    }
+}
+
 // Initialize the application
-document.addEventListener('DOMContentLoaded', function() {
+document.addEventListener('DOMContentLoaded', async function() {
     console.log('Pod Visualizer frontend loaded');
     
-    // Get default namespace from meta tag
-    const defaultNamespace = document.querySelector('meta[name="default-namespace"]')?.getAttribute('content') || '';
+    await loadUIConfig();
+    document.title = uiConfig.title;
+    const heading = document.querySelector('.logo h1');
+    if (heading) {
+        heading.textContent = uiConfig.title;
+    }
+    
+    // Apply default namespace from the bootstrap config
+    const defaultNamespace = uiConfig.defaultNamespace || '';
     if (defaultNamespace) {
         currentNamespace = defaultNamespace;
         console.log('Setting default namespace to:', defaultNamespace);
     }
     
+    // Offer every namespace in the picker, not just those seen so far
+    loadNamespaces();
+    
+    // Show node capacity in the stats bar
+    loadOverview();
+    setInterval(loadOverview, SUMMARY_REFRESH);
+    
+    // Chart readiness history when the server records it
+    loadHistory();
+    setInterval(loadHistory, HISTORY_REFRESH);
+    
     // Try to connect to WebSocket first
     connectWebSocket();
     
@@ -57,8 +113,11 @@ async function loadData() {
         if (currentNamespace) {
             params.append('namespace', currentNamespace);
         }
+        if (currentNode) {
+            params.append('node', currentNode);
+        }
         
-        const response = await fetch(`/api/cluster?${params.toString()}`);
+        const response = await fetch(`${API_BASE}/cluster?${params.toString()}`);
         
         if (!response.ok) {
             throw new Error(`HTTP error! status: ${response.status}`);
@@ -79,11 +138,305 @@ async function loadData() {
 
 // Update the dashboard with cluster data
 function updateDashboard(data) {
+    if (data.schemaVersion > SCHEMA_VERSION && !schemaWarned) {
+        schemaWarned = true;
+        console.warn(`Cluster data has schemaVersion ${data.schemaVersion}, this page renders up to ${SCHEMA_VERSION}; reload to update it`);
+    }
     // Update stats
     updateStatsBar(data);
     
-    // Update pods with animations
-    updatePodsWithAnimations(data.pods);
+    // Update pods with animations, unless RBAC forbids listing them
+    const unavailable = data.unavailable || [];
+    if (unavailable.includes('pods')) {
+        document.getElementById('pods-container').innerHTML =
+            '<div class="empty-state">Not permitted to list pods</div>';
+        previousPods.clear();
+    } else {
+        updatePodsWithAnimations(data.pods);
+    }
+    
+    // Update deployments, Argo Rollouts, autoscalers, disruption budgets,
+    // services, ingresses, volume claims, custom resources and batch
+    // workloads
+    renderDeployments(data.deployments || []);
+    renderArgoRollouts(data.argoRollouts || []);
+    renderHPAs(data.hpas || []);
+    renderPDBs(data.pdbs || []);
+    renderServices(data.services || []);
+    renderIngresses(data.ingresses || []);
+    renderPVCs(data.pvcs || []);
+    renderCustomResources(data.customResources || []);
+    renderJobs(data.jobs || [], data.cronJobs || []);
+}
+
+// Render deployments with their ready replicas, worst health score first
+// and colored by it, offering a rollout restart when the server has
+// actions enabled
+function renderDeployments(deployments) {
+    const section = document.getElementById('deployments-section');
+    const container = document.getElementById('deployments-container');
+    if (!section || !container) return;
+    
+    section.hidden = deployments.length === 0;
+    
+    const byHealth = [...deployments].sort((a, b) => a.healthScore - b.healthScore);
+    container.innerHTML = byHealth.map(dep => {
+        let state = 'running', label = 'Healthy';
+        if (dep.healthScore < DEGRADED_SCORE) {
+            state = 'failed';
+            label = 'Failing';
+        } else if (dep.healthScore < HEALTHY_SCORE) {
+            state = 'pending';
+            label = 'Degraded';
+        }
+        const actions = uiConfig.actions
+            ? `<button class="action-btn" onclick="scaleDeployment('${dep.namespace}', '${dep.name}', ${dep.replicas})">Scale</button>
+               <button class="action-btn" onclick="restartDeployment('${dep.namespace}', '${dep.name}')">Restart</button>`
+            : '';
+        return `
+            <div class="resource-row" data-uid="${dep.uid}">
+                <div class="resource-name">${dep.namespace}/${dep.name}</div>
+                <div class="resource-detail">${dep.readyReplicas}/${dep.replicas} ready · ${dep.availableReplicas} available</div>
+                <div class="pod-status ${state}" title="Health score out of 100">${label} ${dep.healthScore}</div>
+                ${gitOpsBadge(dep)}
+                ${actions}
+            </div>
+        `;
+    }).join('');
+}
+
+// Render Argo Rollouts with their canary step and weight, or blue-green
+// revisions, and the pods running each pod template hash; degraded and
+// aborted rollouts first
+function renderArgoRollouts(rollouts) {
+    const section = document.getElementById('argo-rollouts-section');
+    const container = document.getElementById('argo-rollouts-container');
+    if (!section || !container) return;
+    
+    section.hidden = rollouts.length === 0;
+    
+    const troubled = rollout => rollout.phase === 'Degraded' || rollout.aborted;
+    const ordered = [...rollouts.filter(troubled), ...rollouts.filter(rollout => !troubled(rollout))];
+    
+    container.innerHTML = ordered.map(rollout => {
+        const state = troubled(rollout) ? 'failed' : rollout.phase === 'Healthy' ? 'running' : 'pending';
+        let progress = 'blue-green';
+        if (rollout.strategy === 'Canary') {
+            const step = rollout.step < rollout.steps ? `step ${rollout.step + 1}/${rollout.steps} ${rollout.stepName}` : 'all steps done';
+            progress = `canary ${step} · weight ${rollout.weight}%`;
+        }
+        const revisions = rollout.revisions
+            .map(revision => `${revision.role ? revision.role + ' ' : ''}${revision.hash} ${revision.readyPods}/${revision.pods}`)
+            .join(', ');
+        const percent = rollout.steps > 0 ? rollout.step / rollout.steps * 100 : 100;
+        return `
+            <div class="resource-row" data-uid="${rollout.uid}">
+                <div class="resource-name">${rollout.namespace}/${rollout.name}</div>
+                <div class="resource-detail" title="${(rollout.message || '').replace(/"/g, '&quot;')}">${rollout.readyReplicas}/${rollout.replicas} ready · ${progress}${rollout.paused ? ' · paused' : ''}${revisions ? ` · ${revisions}` : ''}</div>
+                ${rollout.strategy === 'Canary' ? `<div class="usage-track" title="Canary steps run"><div class="usage-fill" style="width: ${percent}%"></div></div>` : ''}
+                <div class="pod-status ${state}">${rollout.aborted ? 'Aborted' : rollout.phase}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Whether a workload's GitOps tool reports drift, a failed sync or
+// degraded health, as GitOpsStatus.NeedsAttention on the server
+function gitOpsNeedsAttention(item) {
+    return item.gitOpsSync === 'OutOfSync' || item.gitOpsSync === 'Failed' ||
+           item.gitOpsHealth === 'Degraded' || item.gitOpsHealth === 'Missing';
+}
+
+// Badge with the sync status of a workload deployed by Argo CD or Flux
+function gitOpsBadge(item) {
+    if (!item.gitOpsTool) return '';
+    const state = item.gitOpsSync === 'Failed' || item.gitOpsHealth === 'Degraded' ? 'failed'
+        : gitOpsNeedsAttention(item) ? 'pending' : 'running';
+    const title = `${item.gitOpsTool} ${item.gitOpsSource}: ${item.gitOpsSync}, ${item.gitOpsHealth}${item.gitOpsMessage ? ` (${item.gitOpsMessage})` : ''}`;
+    return `<div class="pod-status ${state}" title="${title.replace(/"/g, '&quot;')}">${item.gitOpsSync}</div>`;
+}
+
+// Render autoscalers with replica range and metrics, flagging those pinned
+// at maxReplicas since they cannot scale out any further
+function renderHPAs(hpas) {
+    const section = document.getElementById('hpas-section');
+    const container = document.getElementById('hpas-container');
+    if (!section || !container) return;
+    
+    section.hidden = hpas.length === 0;
+    
+    container.innerHTML = hpas.map(hpa => {
+        const state = hpa.atMaxReplicas ? 'pending' : 'running';
+        const label = hpa.atMaxReplicas ? 'At max' : `${hpa.currentReplicas}→${hpa.desiredReplicas}`;
+        const metrics = hpa.metrics.map(metric => `${metric.name} ${metric.current}/${metric.target}`).join(', ') || 'no metrics';
+        return `
+            <div class="resource-row" data-uid="${hpa.uid}">
+                <div class="resource-name">${hpa.namespace}/${hpa.name}</div>
+                <div class="resource-detail">${hpa.targetKind}/${hpa.targetName} · ${hpa.currentReplicas} replicas (${hpa.minReplicas}–${hpa.maxReplicas}) · ${metrics}</div>
+                <div class="pod-status ${state}">${label}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Render disruption budgets with the workloads they cover, flagging those
+// that would block draining a node
+function renderPDBs(pdbs) {
+    const section = document.getElementById('pdbs-section');
+    const container = document.getElementById('pdbs-container');
+    if (!section || !container) return;
+    
+    section.hidden = pdbs.length === 0;
+    
+    container.innerHTML = pdbs.map(pdb => {
+        const blocked = pdb.blockedNodes.length > 0;
+        const state = blocked ? 'failed' : 'running';
+        const label = blocked ? `Blocks drain of ${pdb.blockedNodes.join(', ')}` : `${pdb.disruptionsAllowed} allowed`;
+        const budget = pdb.minAvailable ? `minAvailable ${pdb.minAvailable}` : `maxUnavailable ${pdb.maxUnavailable}`;
+        return `
+            <div class="resource-row" data-uid="${pdb.uid}">
+                <div class="resource-name">${pdb.namespace}/${pdb.name}</div>
+                <div class="resource-detail">${pdb.workloads.join(', ') || 'no pods'} · ${budget} · ${pdb.currentHealthy}/${pdb.desiredHealthy} healthy · ${pdb.disruptionsAllowed} disruptions allowed</div>
+                <div class="pod-status ${state}">${label}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Render services with endpoint readiness, flagging services with no ready endpoints
+function renderServices(services) {
+    const section = document.getElementById('services-section');
+    const container = document.getElementById('services-container');
+    if (!section || !container) return;
+    
+    section.hidden = services.length === 0;
+    
+    container.innerHTML = services.map(service => {
+        const total = service.readyEndpoints + service.notReadyEndpoints;
+        const outage = service.hasSelector && service.readyEndpoints === 0;
+        const state = outage ? 'failed' : service.notReadyEndpoints > 0 ? 'pending' : 'running';
+        const label = outage ? 'No endpoints' : `${service.readyEndpoints}/${total} ready`;
+        return `
+            <div class="resource-row" data-uid="${service.uid}">
+                <div class="resource-name">${service.namespace}/${service.name}</div>
+                <div class="resource-detail">${service.type} · ${service.clusterIP || 'none'}</div>
+                <div class="pod-status ${state}">${label}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Render each Ingress or HTTPRoute rule as a host/path → service → endpoints
+// chain, flagging rules whose traffic has no ready backend
+function renderIngresses(ingresses) {
+    const section = document.getElementById('ingresses-section');
+    const container = document.getElementById('ingresses-container');
+    if (!section || !container) return;
+    
+    section.hidden = ingresses.length === 0;
+    
+    container.innerHTML = ingresses.map(ingress => ingress.routes.map(route => {
+        const total = route.readyEndpoints + route.notReadyEndpoints;
+        const broken = !route.serviceFound || route.readyEndpoints === 0;
+        const state = broken ? 'failed' : route.notReadyEndpoints > 0 ? 'pending' : 'running';
+        const label = !route.serviceFound ? 'No service' : route.readyEndpoints === 0 ? 'No endpoints' : `${route.readyEndpoints}/${total} ready`;
+        const port = route.port ? `:${route.port}` : '';
+        return `
+            <div class="resource-row" data-uid="${ingress.uid}">
+                <div class="resource-name">${ingress.namespace}/${ingress.name}</div>
+                <div class="resource-detail">${ingress.kind} · ${route.host}${route.path} → ${route.namespace}/${route.service}${port}</div>
+                <div class="pod-status ${state}">${label}</div>
+            </div>
+        `;
+    }).join('')).join('');
+}
+
+// Render the resources of configured custom resource types as a bar of
+// ready out of desired, those short of it first
+function renderCustomResources(resources) {
+    const section = document.getElementById('custom-resources-section');
+    const container = document.getElementById('custom-resources-container');
+    if (!section || !container) return;
+    
+    section.hidden = resources.length === 0;
+    
+    const short = resource => resource.unknown || resource.ready < resource.desired;
+    const ordered = [...resources.filter(short), ...resources.filter(resource => !short(resource))];
+    
+    container.innerHTML = ordered.map(resource => {
+        // A desired count that could not be read is shown as unknown, not as met
+        const desired = resource.unknown ? 'unknown' : resource.desired;
+        const state = resource.ready === 0 && resource.desired > 0 ? 'failed' : short(resource) ? 'pending' : 'running';
+        const percent = resource.unknown ? 0 : resource.desired > 0 ? Math.min(100, resource.ready / resource.desired * 100) : 100;
+        return `
+            <div class="resource-row" data-uid="${resource.uid}">
+                <div class="resource-name">${resource.kind} ${resource.namespace}/${resource.name}</div>
+                <div class="usage-track" title="${resource.ready}/${desired} ready"><div class="usage-fill" style="width: ${percent}%"></div></div>
+                <div class="pod-status ${state}">${resource.ready}/${desired}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Render volume claims, Pending and Lost first since they keep the pods
+// mounting them from starting
+function renderPVCs(pvcs) {
+    const section = document.getElementById('pvcs-section');
+    const container = document.getElementById('pvcs-container');
+    if (!section || !container) return;
+    
+    section.hidden = pvcs.length === 0;
+    
+    const stuck = pvc => pvc.phase === 'Pending' || pvc.phase === 'Lost';
+    const ordered = [...pvcs.filter(stuck), ...pvcs.filter(pvc => !stuck(pvc))];
+    
+    container.innerHTML = ordered.map(pvc => {
+        const state = pvc.phase === 'Lost' ? 'failed' : pvc.phase === 'Pending' ? 'pending' : 'running';
+        const volume = pvc.volumeName ? `→ ${pvc.volumeName}` : 'unbound';
+        const mounts = pvc.mountedBy.length > 0 ? `mounted by ${pvc.mountedBy.join(', ')}` : 'not mounted';
+        return `
+            <div class="resource-row" data-uid="${pvc.uid}">
+                <div class="resource-name">${pvc.namespace}/${pvc.name}</div>
+                <div class="resource-detail">${pvc.capacity} · ${pvc.storageClass || 'no class'} · ${volume} · ${mounts}</div>
+                <div class="pod-status ${state}">${pvc.phase}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Render jobs and cronjobs as compact rows
+function renderJobs(jobs, cronJobs) {
+    const section = document.getElementById('jobs-section');
+    const container = document.getElementById('jobs-container');
+    if (!section || !container) return;
+    
+    section.hidden = jobs.length === 0 && cronJobs.length === 0;
+    
+    const jobRows = jobs.map(job => {
+        const state = job.jobFailed ? 'failed' : job.complete ? 'running' : 'pending';
+        const label = job.jobFailed ? 'Failed' : job.complete ? 'Complete' : 'Active';
+        return `
+            <div class="resource-row" data-uid="${job.uid}">
+                <div class="resource-name">${job.namespace}/${job.name}</div>
+                <div class="resource-detail">${job.succeeded}/${job.completions} completions · ${job.active} active · ${job.failed} failed</div>
+                <div class="pod-status ${state}">${label}</div>
+            </div>
+        `;
+    });
+    
+    const cronJobRows = cronJobs.map(cronJob => {
+        const lastSchedule = cronJob.lastScheduleTime ? new Date(cronJob.lastScheduleTime).toLocaleString() : 'never';
+        const state = cronJob.suspended ? 'pending' : 'running';
+        return `
+            <div class="resource-row" data-uid="${cronJob.uid}">
+                <div class="resource-name">${cronJob.namespace}/${cronJob.name}</div>
+                <div class="resource-detail">${cronJob.schedule} · ${cronJob.active} active · last scheduled ${lastSchedule}</div>
+                <div class="pod-status ${state}">${cronJob.suspended ? 'Suspended' : 'Scheduled'}</div>
+            </div>
+        `;
+    });
+    
+    container.innerHTML = [...jobRows, ...cronJobRows].join('');
 }
 
 // Update stats bar
@@ -109,7 +462,7 @@ function updatePodsWithAnimations(pods) {
     
     // Create a map of current pods
     const currentPods = new Map();
-    pods.forEach(pod => currentPods.set(pod.name, pod));
+    pods.forEach(pod => currentPods.set(podKey(pod), pod));
     
     // Find new, updated, and removed pods
     const newPods = [];
@@ -117,11 +470,11 @@ function updatePodsWithAnimations(pods) {
     const removedPods = [];
     
     // Check for new and updated pods
-    currentPods.forEach((pod, name) => {
-        if (!previousPods.has(name)) {
+    currentPods.forEach((pod, key) => {
+        if (!previousPods.has(key)) {
             newPods.push(pod);
         } else {
-            const prevPod = previousPods.get(name);
+            const prevPod = previousPods.get(key);
             if (hasSignificantChange(prevPod, pod)) {
                 updatedPods.push({ previous: prevPod, current: pod });
             }
@@ -129,15 +482,15 @@ function updatePodsWithAnimations(pods) {
     });
     
     // Check for removed pods
-    previousPods.forEach((pod, name) => {
-        if (!currentPods.has(name)) {
+    previousPods.forEach((pod, key) => {
+        if (!currentPods.has(key)) {
             removedPods.push(pod);
         }
     });
     
     // Handle removed pods first
     removedPods.forEach(pod => {
-        const existingCard = document.querySelector(`[data-pod-name="${pod.name}"]`);
+        const existingCard = document.querySelector(`[data-pod-key="${podKey(pod)}"]`);
         if (existingCard) {
             existingCard.classList.add('leaving');
             setTimeout(() => {
@@ -177,7 +530,7 @@ function updatePodsWithAnimations(pods) {
         
         // Update existing pods
         updatedPods.forEach(({ previous, current }) => {
-            const existingCard = document.querySelector(`[data-pod-name="${current.name}"]`);
+            const existingCard = document.querySelector(`[data-pod-key="${podKey(current)}"]`);
             if (existingCard) {
                 updatePodCard(existingCard, previous, current);
             }
@@ -186,16 +539,24 @@ function updatePodsWithAnimations(pods) {
     
     // Store current state for next comparison
     previousPods.clear();
-    currentPods.forEach((pod, name) => previousPods.set(name, { ...pod }));
+    currentPods.forEach((pod, key) => previousPods.set(key, { ...pod }));
+}
+
+// Stable key for a pod: its UID, falling back to namespace/name
+function podKey(pod) {
+    return pod.uid || `${pod.namespace}/${pod.name}`;
 }
 
 // Create HTML for a single pod card
 function createPodCard(pod, isNew = false) {
-    const statusClass = pod.status.toLowerCase();
+    const statusClass = podStatusClass(pod);
     const containers = generateContainerBlocks(pod);
+    const tooltip = podTooltip(pod);
     
     return `
-        <div class="pod-card ${isNew ? 'new' : ''}" data-pod-name="${pod.name}">
+        <div class="pod-card ${isNew ? 'new' : ''} ${pod.nodePressure ? 'node-pressure' : ''} ${gitOpsNeedsAttention(pod) ? 'gitops-drift' : ''}" data-pod-key="${podKey(pod)}" data-pod-name="${pod.name}"
+             data-status="${pod.status}" data-restarts="${pod.restarts || 0}" data-node="${pod.nodeName || ''}"
+             title="${tooltip}">
             <div class="pod-header">
                 <div class="pod-info">
                     <h3>${pod.name}</h3>
@@ -207,25 +568,238 @@ function createPodCard(pod, isNew = false) {
                 ${containers}
             </div>
             <div class="pod-stats">
-                ${pod.readyContainers}/${pod.containerCount} containers ready
+                ${podStatsText(pod)}
             </div>
+            <div class="usage-bars">${generateUsageBars(pod)}</div>
+            <div class="runtime-class">${runtimeClassLabel(pod)}</div>
+            <div class="crash-loop">${crashLoopLabel(pod)}</div>
+            <div class="pod-hint" title="${pod.hintCause || ''}">${pod.hint ? `💡 ${pod.hint}` : ''}</div>
+            ${podActions(pod)}
+        </div>
+    `;
+}
+
+// Offer a delete button on pod cards when the server has actions enabled
+function podActions(pod) {
+    if (!uiConfig.actions) return '';
+    return `
+        <div class="pod-actions">
+            <button class="action-btn danger" onclick="deletePod('${pod.namespace}', '${pod.name}')">Delete</button>
+        </div>
+    `;
+}
+
+// Delete a pod, leaving its controller to replace it
+function deletePod(namespace, name) {
+    runAction(`${API_BASE}/pods/${encodeURIComponent(namespace)}/${encodeURIComponent(name)}/delete`,
+        `Delete pod ${namespace}/${name}?`);
+}
+
+// Roll every pod of a deployment, like kubectl rollout restart
+function restartDeployment(namespace, name) {
+    runAction(`${API_BASE}/deployments/${encodeURIComponent(namespace)}/${encodeURIComponent(name)}/restart`,
+        `Restart deployment ${namespace}/${name}?`);
+}
+
+// Ask for a deployment's desired replicas and set them
+function scaleDeployment(namespace, name, replicas) {
+    const answer = prompt(`Replicas for deployment ${namespace}/${name}:`, replicas);
+    if (answer === null) return;
+    const desired = Number(answer.trim());
+    if (answer.trim() === '' || !Number.isInteger(desired) || desired < 0) {
+        alert(`Invalid replica count: ${answer}`);
+        return;
+    }
+    runAction(`${API_BASE}/deployments/${encodeURIComponent(namespace)}/${encodeURIComponent(name)}/scale`,
+        null, { replicas: desired });
+}
+
+// POST an action, after confirmation when question is given; the server
+// audits it under the signed-in user. The WebSocket delivers the outcome,
+// polling refreshes.
+async function runAction(url, question, body) {
+    if (question && !confirm(question)) return;
+    try {
+        const options = { method: 'POST' };
+        if (body !== undefined) {
+            options.headers = { 'Content-Type': 'application/json' };
+            options.body = JSON.stringify(body);
+        }
+        const response = await fetch(url, options);
+        if (!response.ok) {
+            throw new Error((await response.text()).trim() || `HTTP error! status: ${response.status}`);
+        }
+        if (!isWebSocketEnabled) {
+            loadData();
+        }
+    } catch (error) {
+        console.error('Action failed:', error);
+        alert(`Action failed: ${error.message}`);
+    }
+}
+
+// Describe the pod's runtimeClass, flagging mismatches with the namespace policy
+function runtimeClassLabel(pod) {
+    if (pod.runtimeClassMismatch) {
+        return `<span class="runtime-mismatch" title="Namespace expects ${pod.expectedRuntimeClass}">⚠ runtime ${pod.runtimeClass || 'default'}, expected ${pod.expectedRuntimeClass}</span>`;
+    }
+    return pod.runtimeClass ? `runtime ${pod.runtimeClass}` : '';
+}
+
+// Describe a crash-looping container's restart cadence: how long ago its
+// last run ended, the kubelet's back-off and when it restarts next
+function crashLoopLabel(pod) {
+    if (!pod.crashLoopContainer) return '';
+    const parts = [`🔁 ${pod.crashLoopContainer}`];
+    if (pod.lastRestartAt) {
+        parts.push(`${pod.lastExitReason || 'exited'} ${formatAge(pod.lastRestartAt)} ago`);
+    }
+    parts.push(`back-off ${formatSeconds(pod.backoffSeconds)}`);
+    if (pod.lastRestartAt) {
+        const wait = (new Date(pod.lastRestartAt).getTime() + pod.backoffSeconds * 1000 - Date.now()) / 1000;
+        parts.push(wait > 0 ? `restart in ${formatSeconds(wait)}` : 'restarting');
+    }
+    return parts.join(' · ');
+}
+
+// Format a duration in seconds like the CLI, e.g. 40s or 2m30s
+function formatSeconds(seconds) {
+    const s = Math.round(seconds);
+    if (s < 60) return `${s}s`;
+    return s % 60 ? `${Math.floor(s / 60)}m${s % 60}s` : `${Math.floor(s / 60)}m`;
+}
+
+// Generate usage-vs-request bars from metrics-server data
+function generateUsageBars(pod) {
+    if (!pod.cpuUsageMilli && !pod.memoryUsageBytes) {
+        return storageUsageBar(pod);
+    }
+    
+    const mebibytes = bytes => `${Math.round(bytes / (1024 * 1024))}Mi`;
+    return usageBar('CPU', pod.cpuUsageMilli, pod.cpuRequestMilli, v => `${v}m`) +
+           usageBar('Mem', pod.memoryUsageBytes, pod.memoryRequestBytes, mebibytes) +
+           storageUsageBar(pod);
+}
+
+// Render ephemeral-storage usage against its limit, when the kubelet reports it
+function storageUsageBar(pod) {
+    if (!pod.ephemeralStorageUsedBytes) {
+        return '';
+    }
+
+    const mebibytes = bytes => `${Math.round(bytes / (1024 * 1024))}Mi`;
+    const label = pod.ephemeralStorageNearLimit ? '⚠ Disk' : 'Disk';
+    return usageBar(label, pod.ephemeralStorageUsedBytes, pod.ephemeralStorageLimitBytes, mebibytes);
+}
+
+// Render a single usage bar; usage above the request is flagged as over
+function usageBar(label, used, requested, format) {
+    const percent = requested > 0 ? Math.min(100, used / requested * 100) : 0;
+    const over = requested > 0 && used > requested;
+    const requestLabel = requested > 0 ? format(requested) : 'no request';
+    
+    return `
+        <div class="usage-bar" title="${label}: ${format(used)} / ${requestLabel}">
+            <span class="usage-label">${label}</span>
+            <div class="usage-track"><div class="usage-fill ${over ? 'over' : ''}" style="width: ${percent}%"></div></div>
         </div>
     `;
 }
 
+// Build the tooltip text shown when hovering a pod card
+function podTooltip(pod) {
+    const pressure = pod.nodePressure ? ` (${pod.nodePressure.split(',').join(', ')})` : '';
+    const created = hasTimestamp(pod.createdAt) ? `\nCreated: ${new Date(pod.createdAt).toLocaleString()}` : '';
+    const unschedulable = pod.unschedulable ? `\nUnschedulable: ${pod.unschedulable}` : '';
+    const evicted = pod.evictionMessage ? `\nEvicted: ${pod.evictionMessage}` : '';
+    const gitOps = gitOpsNeedsAttention(pod) ? `\n${pod.gitOpsTool} ${pod.gitOpsSource}: ${pod.gitOpsSync}, ${pod.gitOpsHealth}` : '';
+    return `Status: ${pod.status}\nRestarts: ${pod.restarts || 0}\nNode: ${pod.nodeName || 'unscheduled'}${pressure}${unschedulable}${evicted}${gitOps}${created}`;
+}
+
+// Completed and evicted pods have stopped for good and stay out of the
+// readiness totals, as on the server
+function countsTowardReadiness(pod) {
+    return pod.status !== 'Completed' && pod.status !== 'Evicted';
+}
+
+// Pods running init containers report Init:1/3; they share one style
+function isInitializing(pod) {
+    return pod.status.startsWith('Init:');
+}
+
+// CSS class for a pod's status badge
+function podStatusClass(pod) {
+    return isInitializing(pod) ? 'init' : pod.status.toLowerCase();
+}
+
+// Summarize init progress, container readiness, sidecars, debug
+// containers, scheduling failures and, when known, the pod's age
+function podStatsText(pod) {
+    const app = appContainers(pod);
+    let text = `${app.ready}/${app.total} containers ready`;
+    if (pod.sidecarCount) {
+        text += ` · ${pod.readySidecars || 0}/${pod.sidecarCount} sidecars${pod.sidecarsExcluded ? ' not counted' : ''}`;
+    }
+    if (isInitializing(pod)) {
+        text = `${pod.initContainersDone || 0}/${pod.initContainerCount} init done · ${text}`;
+    }
+    if (pod.ephemeralContainerCount) {
+        text += ` · ${pod.ephemeralContainerCount} debug`;
+    }
+    if (pod.unschedulable) {
+        text += ' · ⚠ unschedulable';
+    }
+    return hasTimestamp(pod.createdAt) ? `${text} · ${formatAge(pod.createdAt)}` : text;
+}
+
+// Go encodes an unset time as year 1; treat it as missing
+function hasTimestamp(timestamp) {
+    return Boolean(timestamp) && !timestamp.startsWith('0001-');
+}
+
+// Render the time since a timestamp the way kubectl does, e.g. 45s, 3h20m, 5d
+function formatAge(timestamp) {
+    const seconds = Math.max(0, Math.floor((Date.now() - new Date(timestamp).getTime()) / 1000));
+    const minutes = Math.floor(seconds / 60);
+    const hours = Math.floor(minutes / 60);
+    const days = Math.floor(hours / 24);
+
+    if (seconds < 60) return `${seconds}s`;
+    if (minutes < 10) return seconds % 60 ? `${minutes}m${seconds % 60}s` : `${minutes}m`;
+    if (hours < 1) return `${minutes}m`;
+    if (hours < 8) return minutes % 60 ? `${hours}h${minutes % 60}m` : `${hours}h`;
+    if (hours < 48) return `${hours}h`;
+    if (days < 8) return hours % 24 ? `${days}d${hours % 24}h` : `${days}d`;
+    return `${days}d`;
+}
+
 // Update an existing pod card with animations
 function updatePodCard(cardElement, previousPod, currentPod) {
+    // Keep metadata attributes in sync for tooltips and exports
+    cardElement.dataset.status = currentPod.status;
+    cardElement.dataset.restarts = currentPod.restarts || 0;
+    cardElement.dataset.node = currentPod.nodeName || '';
+    cardElement.title = podTooltip(currentPod);
+    cardElement.classList.toggle('node-pressure', Boolean(currentPod.nodePressure));
+    cardElement.classList.toggle('gitops-drift', gitOpsNeedsAttention(currentPod));
+    
     // Update status if changed
     const statusElement = cardElement.querySelector('.pod-status');
     if (previousPod.status !== currentPod.status) {
-        statusElement.className = `pod-status ${currentPod.status.toLowerCase()}`;
+        statusElement.className = `pod-status ${podStatusClass(currentPod)}`;
         statusElement.textContent = currentPod.status;
         statusElement.classList.add('status-change');
         setTimeout(() => statusElement.classList.remove('status-change'), 800);
     }
     
-    // Update container blocks if changed
-    if (previousPod.readyContainers !== currentPod.readyContainers || 
+    // Init progress and sidecars redraw the blocks; otherwise animate
+    // readiness changes
+    if (isInitializing(previousPod) || isInitializing(currentPod) || previousPod.sidecarCount || currentPod.sidecarCount) {
+        if (previousPod.status !== currentPod.status || previousPod.readyContainers !== currentPod.readyContainers ||
+            previousPod.containerCount !== currentPod.containerCount || previousPod.readySidecars !== currentPod.readySidecars) {
+            cardElement.querySelector('.container-blocks').innerHTML = generateContainerBlocks(currentPod);
+        }
+    } else if (previousPod.readyContainers !== currentPod.readyContainers || 
         previousPod.containerCount !== currentPod.containerCount) {
         
         const blocksContainer = cardElement.querySelector('.container-blocks');
@@ -233,10 +807,35 @@ function updatePodCard(cardElement, previousPod, currentPod) {
         
         // Animate container changes
         animateContainerChanges(blocksContainer, previousPod, currentPod);
-        
-        // Update stats
-        const statsElement = cardElement.querySelector('.pod-stats');
-        statsElement.textContent = `${currentPod.readyContainers}/${currentPod.containerCount} containers ready`;
+    }
+    
+    // Refresh stats, whose age advances even when nothing else changed
+    const statsElement = cardElement.querySelector('.pod-stats');
+    statsElement.textContent = podStatsText(currentPod);
+    
+    // Refresh runtime class label
+    const runtimeElement = cardElement.querySelector('.runtime-class');
+    if (runtimeElement) {
+        runtimeElement.innerHTML = runtimeClassLabel(currentPod);
+    }
+    
+    // Refresh the crash-loop countdown
+    const crashLoopElement = cardElement.querySelector('.crash-loop');
+    if (crashLoopElement) {
+        crashLoopElement.textContent = crashLoopLabel(currentPod);
+    }
+    
+    // Refresh the root-cause hint
+    const hintElement = cardElement.querySelector('.pod-hint');
+    if (hintElement) {
+        hintElement.textContent = currentPod.hint ? `💡 ${currentPod.hint}` : '';
+        hintElement.title = currentPod.hintCause || '';
+    }
+    
+    // Refresh usage bars
+    const usageElement = cardElement.querySelector('.usage-bars');
+    if (usageElement) {
+        usageElement.innerHTML = generateUsageBars(currentPod);
     }
 }
 
@@ -244,22 +843,46 @@ function updatePodCard(cardElement, previousPod, currentPod) {
 function generateContainerBlocks(pod) {
     let blocks = '';
     
+    // Init containers, while they run, ahead of the containers waiting on them
+    if (isInitializing(pod)) {
+        for (let i = 0; i < pod.initContainerCount; i++) {
+            const done = i < (pod.initContainersDone || 0);
+            blocks += `<div class="container-block init ${done ? 'done' : ''}" title="Init container ${i + 1}: ${done ? 'Done' : 'Waiting'}"></div>`;
+        }
+        blocks += '<div class="init-separator"></div>';
+    }
+    
     // Ready containers
-    for (let i = 0; i < pod.readyContainers; i++) {
+    const app = appContainers(pod);
+    for (let i = 0; i < app.ready; i++) {
         blocks += `<div class="container-block ready" title="Container ${i + 1}: Ready"></div>`;
     }
     
     // Not ready containers
-    const notReadyCount = pod.containerCount - pod.readyContainers;
+    const notReadyCount = app.total - app.ready;
     for (let i = 0; i < notReadyCount; i++) {
         const status = pod.status === 'Pending' ? 'pending' : 
                       pod.status === 'Failed' ? 'failed' : 'not-ready';
-        blocks += `<div class="container-block ${status}" title="Container ${pod.readyContainers + i + 1}: ${status}"></div>`;
+        blocks += `<div class="container-block ${status}" title="Container ${app.ready + i + 1}: ${status}"></div>`;
+    }
+    
+    // Sidecars, behind the app containers
+    for (let i = 0; i < (pod.sidecarCount || 0); i++) {
+        const ready = i < (pod.readySidecars || 0);
+        blocks += `<div class="container-block sidecar ${ready ? 'ready' : ''}" title="Sidecar ${i + 1}: ${ready ? 'Ready' : 'Not ready'}"></div>`;
     }
     
     return blocks;
 }
 
+// Ready and total containers of a pod other than its sidecars
+function appContainers(pod) {
+    if (!pod.sidecarCount || pod.sidecarsExcluded) {
+        return { ready: pod.readyContainers, total: pod.containerCount };
+    }
+    return { ready: pod.readyContainers - (pod.readySidecars || 0), total: pod.containerCount - pod.sidecarCount };
+}
+
 // Animate container block changes
 function animateContainerChanges(container, prevPod, currentPod) {
     const prevReady = prevPod.readyContainers;
@@ -312,6 +935,19 @@ function animateContainerChanges(container, prevPod, currentPod) {
 // Check if there's a significant change between pods
 function hasSignificantChange(prevPod, currentPod) {
     return prevPod.status !== currentPod.status ||
+           prevPod.restarts !== currentPod.restarts ||
+           prevPod.nodeName !== currentPod.nodeName ||
+           prevPod.runtimeClass !== currentPod.runtimeClass ||
+           prevPod.runtimeClassMismatch !== currentPod.runtimeClassMismatch ||
+           prevPod.nodePressure !== currentPod.nodePressure ||
+           prevPod.unschedulable !== currentPod.unschedulable ||
+           prevPod.gitOpsSync !== currentPod.gitOpsSync ||
+           prevPod.gitOpsHealth !== currentPod.gitOpsHealth ||
+           prevPod.lastRestartAt !== currentPod.lastRestartAt ||
+           prevPod.hint !== currentPod.hint ||
+           prevPod.cpuUsageMilli !== currentPod.cpuUsageMilli ||
+           prevPod.memoryUsageBytes !== currentPod.memoryUsageBytes ||
+           prevPod.ephemeralStorageNearLimit !== currentPod.ephemeralStorageNearLimit ||
            prevPod.readyContainers !== currentPod.readyContainers ||
            prevPod.containerCount !== currentPod.containerCount;
 }
@@ -326,6 +962,174 @@ function updateNamespaceList(pods, deployments) {
     populateNamespaceFilter();
 }
 
+// Download a snapshot of the current view for attaching to postmortems
+function downloadSnapshot() {
+    const params = new URLSearchParams();
+    if (currentNamespace) params.set('namespace', currentNamespace);
+    if (currentNode) params.set('node', currentNode);
+    const query = params.toString();
+    window.location.href = API_BASE + '/snapshot' + (query ? '?' + query : '');
+}
+
+// Load namespaces with pod counts from the server for the picker
+async function loadNamespaces() {
+    try {
+        const response = await fetch(`${API_BASE}/namespaces`);
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+        const body = await response.json();
+        body.namespaces.forEach(namespace => {
+            namespaceList.add(namespace.name);
+            namespaceDetails.set(namespace.name, namespace);
+        });
+        populateNamespaceFilter();
+    } catch (error) {
+        // Fall back to namespaces seen in cluster data
+        console.error('Error loading namespaces:', error);
+    }
+}
+
+// Load the cluster overview into the stats bar: nodes, requested against
+// allocatable CPU and memory, and the Kubernetes version
+async function loadOverview() {
+    try {
+        const response = await fetch(`${API_BASE}/overview`);
+        if (!response.ok) {
+            return;
+        }
+        const overview = await response.json();
+        const percent = (used, total) => total > 0 ? ` (${Math.round(used / total * 100)}%)` : '';
+        const cores = milli => (milli / 1000).toFixed(1);
+        const gibibytes = bytes => (bytes / (1024 * 1024 * 1024)).toFixed(1);
+
+        const nodes = document.getElementById('overview-nodes');
+        nodes.textContent = `${overview.readyNodes}/${overview.nodes}`;
+        nodes.parentElement.title = `${overview.minPodsPerNode}–${overview.maxPodsPerNode} pods per node` +
+            (overview.unscheduledPods ? `, ${overview.unscheduledPods} unscheduled` : '');
+        document.getElementById('overview-cpu').textContent =
+            `${cores(overview.cpuRequestedMilli)}/${cores(overview.cpuAllocatableMilli)} cores${percent(overview.cpuRequestedMilli, overview.cpuAllocatableMilli)}`;
+        document.getElementById('overview-memory').textContent =
+            `${gibibytes(overview.memoryRequestedBytes)}/${gibibytes(overview.memoryAllocatableBytes)} GiB${percent(overview.memoryRequestedBytes, overview.memoryAllocatableBytes)}`;
+        document.getElementById('overview-version').textContent = overview.kubernetesVersion || '–';
+        document.querySelectorAll('[data-overview]').forEach(item => { item.hidden = false; });
+    } catch (error) {
+        console.error('Error loading overview:', error);
+    }
+}
+
+// Load readiness history and chart it; the section stays hidden when the
+// server has history disabled
+async function loadHistory() {
+    try {
+        const response = await fetch(`${API_BASE}/history?hours=${HISTORY_HOURS}`);
+        if (!response.ok) {
+            return;
+        }
+        const body = await response.json();
+        document.getElementById('history-hours').textContent = body.hours;
+        renderHistory(body.samples);
+        document.getElementById('history-section').hidden = false;
+    } catch (error) {
+        console.error('Error loading history:', error);
+    }
+}
+
+// Draw container readiness percentage over the last HISTORY_HOURS hours
+function renderHistory(samples) {
+    const chart = document.getElementById('history-chart');
+    const end = Date.now();
+    const start = end - HISTORY_HOURS * 60 * 60 * 1000;
+    const x = time => ((new Date(time).getTime() - start) / (end - start) * 1000).toFixed(1);
+    const y = percentage => (120 - percentage * 1.2).toFixed(1);
+
+    const points = samples.map(sample => `${x(sample.time)},${y(sample.containerPercentage)}`).join(' ');
+    const latest = samples.length ? samples[samples.length - 1].containerPercentage.toFixed(1) : '–';
+    chart.innerHTML = `
+        <title>${samples.length} samples, latest ${latest}% ready</title>
+        <line class="history-grid" x1="0" y1="${y(50)}" x2="1000" y2="${y(50)}"></line>
+        <polyline class="history-line" points="${points}"></polyline>
+    `;
+}
+
+// Show the namespace heatmap in place of the pod grid, or switch back
+function toggleHeatmap() {
+    showHeatmap = !showHeatmap;
+    document.getElementById('heatmap-btn').classList.toggle('active', showHeatmap);
+    document.getElementById('pods-container').hidden = showHeatmap;
+    document.getElementById('heatmap-container').hidden = !showHeatmap;
+    
+    clearInterval(summaryInterval);
+    summaryInterval = null;
+    if (showHeatmap) {
+        loadSummary();
+        summaryInterval = setInterval(loadSummary, SUMMARY_REFRESH);
+    }
+}
+
+// Load per-namespace aggregates for the heatmap
+async function loadSummary() {
+    try {
+        const response = await fetch(`${API_BASE}/summary`);
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+        renderHeatmap(await response.json());
+    } catch (error) {
+        console.error('Error loading summary:', error);
+        document.getElementById('heatmap-container').innerHTML = `<div class="error">Failed to load namespace summary: ${error.message}</div>`;
+    }
+}
+
+// Draw one tile per namespace, coloured from red to green by the lower of
+// its ready container and healthy deployment percentages; clicking a tile
+// filters the pod grid to it
+function renderHeatmap(summary) {
+    const container = document.getElementById('heatmap-container');
+    if (summary.namespaces.length === 0) {
+        container.innerHTML = '<div class="empty-state">No namespaces found</div>';
+        return;
+    }
+    
+    container.innerHTML = summary.namespaces.map(ns => {
+        const healthyDeployments = ns.deployments > 0 ? ns.healthyDeployments / ns.deployments * 100 : 100;
+        const hue = Math.round(Math.min(ns.readyPercentage, healthyDeployments) * 1.2); // 0 red .. 120 green
+        const phases = Object.entries(ns.podsByPhase)
+            .sort(([a], [b]) => a.localeCompare(b))
+            .map(([phase, count]) => `${count} ${phase}`)
+            .join(' · ');
+        const warnings = summary.warningEventsAvailable ? `${ns.warningEvents} warnings` : 'warnings unavailable';
+        return `
+            <div class="heatmap-tile" style="background: hsla(${hue}, 70%, 40%, 0.35); border-color: hsl(${hue}, 70%, 45%)"
+                 onclick="selectNamespace('${ns.namespace}')" title="${phases || 'no pods'}">
+                <div class="heatmap-name">${ns.namespace}</div>
+                <div class="heatmap-ready">${ns.readyPercentage.toFixed(0)}%</div>
+                <div class="heatmap-detail">${ns.pods} pods · ${ns.readyContainers}/${ns.totalContainers} containers</div>
+                <div class="heatmap-detail">${ns.healthyDeployments}/${ns.deployments} deployments healthy · ${warnings}</div>
+            </div>
+        `;
+    }).join('');
+}
+
+// Filter to a namespace picked from the heatmap and return to the pod grid
+function selectNamespace(namespace) {
+    currentNamespace = namespace;
+    document.getElementById('namespace').value = namespace;
+    toggleHeatmap();
+    if (!isWebSocketEnabled) {
+        loadData();
+    }
+}
+
+// Label a namespace option with its pod counts, when known
+function namespaceLabel(name) {
+    const details = namespaceDetails.get(name);
+    if (!details) return name;
+    const failing = details.failingPods > 0 ? `, ${details.failingPods} failing` : '';
+    const terminating = details.phase === 'Terminating' ? ' (terminating)' : '';
+    return `${name}${terminating} · ${details.podCount} pods${failing}`;
+}
+
 // Populate namespace filter dropdown
 function populateNamespaceFilter() {
     const select = document.getElementById('namespace');
@@ -338,7 +1142,7 @@ function populateNamespaceFilter() {
     Array.from(namespaceList).sort().forEach(namespace => {
         const option = document.createElement('option');
         option.value = namespace;
-        option.textContent = namespace;
+        option.textContent = namespaceLabel(namespace);
         select.appendChild(option);
     });
     
@@ -428,7 +1232,8 @@ document.addEventListener('keydown', function(event) {
 function connectWebSocket() {
     try {
         const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-        const wsUrl = `${protocol}//${window.location.host}/ws`;
+        const resumeQuery = resumeToken ? `?resume=${encodeURIComponent(resumeToken)}` : '';
+        const wsUrl = `${protocol}//${window.location.host}/ws${resumeQuery}`;
         
         console.log('Attempting to connect to WebSocket:', wsUrl);
         websocket = new WebSocket(wsUrl);
@@ -449,21 +1254,37 @@ function connectWebSocket() {
         
         websocket.onmessage = function(event) {
             try {
-                const data = JSON.parse(event.data);
-                console.log('📡 Received WebSocket data update');
+                const message = JSON.parse(event.data);
+                const data = applyWebSocketMessage(message);
+                if (!data) {
+                    return;
+                }
+                console.log(`📡 Received WebSocket ${message.type} update`);
                 
-                // Filter data based on current namespace if needed
+                // Filter data based on current namespace and node if needed
                 let filteredData = data;
-                if (currentNamespace) {
+                if (currentNamespace || currentNode) {
                     filteredData = {
                         ...data,
-                        pods: data.pods.filter(pod => pod.namespace === currentNamespace),
-                        deployments: (data.deployments || []).filter(dep => dep.namespace === currentNamespace)
+                        pods: data.pods.filter(pod =>
+                            (!currentNamespace || pod.namespace === currentNamespace) &&
+                            (!currentNode || pod.nodeName === currentNode)),
+                        deployments: (data.deployments || []).filter(dep => !currentNamespace || dep.namespace === currentNamespace),
+                        argoRollouts: (data.argoRollouts || []).filter(rollout => !currentNamespace || rollout.namespace === currentNamespace),
+                        hpas: (data.hpas || []).filter(hpa => !currentNamespace || hpa.namespace === currentNamespace),
+                        pdbs: (data.pdbs || []).filter(pdb => !currentNamespace || pdb.namespace === currentNamespace),
+                        services: (data.services || []).filter(service => !currentNamespace || service.namespace === currentNamespace),
+                        ingresses: (data.ingresses || []).filter(ingress => !currentNamespace || ingress.namespace === currentNamespace),
+                        pvcs: (data.pvcs || []).filter(pvc => !currentNamespace || pvc.namespace === currentNamespace),
+                        customResources: (data.customResources || []).filter(resource => !currentNamespace || resource.namespace === currentNamespace),
+                        jobs: (data.jobs || []).filter(job => !currentNamespace || job.namespace === currentNamespace),
+                        cronJobs: (data.cronJobs || []).filter(cronJob => !currentNamespace || cronJob.namespace === currentNamespace)
                     };
                     
                     // Recalculate totals for filtered data
-                    filteredData.totalContainers = filteredData.pods.reduce((sum, pod) => sum + pod.containerCount, 0);
-                    filteredData.readyContainers = filteredData.pods.reduce((sum, pod) => sum + pod.readyContainers, 0);
+                    const counted = filteredData.pods.filter(countsTowardReadiness);
+                    filteredData.totalContainers = counted.reduce((sum, pod) => sum + pod.containerCount, 0);
+                    filteredData.readyContainers = counted.reduce((sum, pod) => sum + pod.readyContainers, 0);
                 }
                 
                 updateDashboard(filteredData);
@@ -506,6 +1327,50 @@ function connectWebSocket() {
     }
 }
 
+// Apply a snapshot or diff message to the latest cluster state.
+// Returns the new full state, or null if the message could not be applied.
+function applyWebSocketMessage(message) {
+    if (message.type === 'snapshot') {
+        latestClusterData = message.data;
+    } else if (message.type === 'diff') {
+        if (!latestClusterData || message.baseToken !== resumeToken) {
+            console.warn(`Missed an update before seq ${message.seq}, requesting a full resync`);
+            websocket.send(JSON.stringify({ type: 'resync' }));
+            return null;
+        }
+        latestClusterData = applyClusterDiff(latestClusterData, message.diff);
+    } else {
+        console.warn('Unknown WebSocket message type:', message.type);
+        return null;
+    }
+    
+    resumeToken = message.token;
+    return latestClusterData;
+}
+
+// Apply a ClusterDiff to a full snapshot, keyed by UID
+function applyClusterDiff(base, diff) {
+    const mergeByUid = (items, upserted, removed) => {
+        const byUid = new Map((items || []).map(item => [item.uid, item]));
+        (removed || []).forEach(uid => byUid.delete(uid));
+        (upserted || []).forEach(item => byUid.set(item.uid, item));
+        return Array.from(byUid.values()).sort((a, b) =>
+            a.namespace.localeCompare(b.namespace) || a.name.localeCompare(b.name));
+    };
+    
+    const state = {
+        ...diff.summary,
+        pods: mergeByUid(base.pods, diff.upsertedPods, diff.removedPods),
+        deployments: mergeByUid(base.deployments, diff.upsertedDeployments, diff.removedDeployments)
+    };
+    // The other lists are only sent when they changed
+    ['hpas', 'pdbs', 'jobs', 'cronJobs', 'services', 'ingresses', 'pvcs', 'customResources', 'argoRollouts'].forEach(key => {
+        const change = diff[key];
+        state[key] = change ? mergeByUid(base[key], change.upserted, change.removed) : (base[key] || []);
+    });
+    return state;
+}
+
 // Update connection status indicator
 function updateConnectionStatus(status) {
     const statusDot = document.getElementById('connection-status');
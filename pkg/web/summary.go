@@ -0,0 +1,119 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// NamespaceSummary aggregates one namespace's pods, deployments and warning
+// events for the namespace heatmap
+type NamespaceSummary struct {
+	Namespace   string         `json:"namespace"`
+	Pods        int            `json:"pods"`
+	PodsByPhase map[string]int `json:"podsByPhase"`
+	PodsByQOS   map[string]int `json:"podsByQOS"`
+
+	// ReadyPercentage is the share of ready containers; 100 when the
+	// namespace runs none
+	ReadyContainers int     `json:"readyContainers"`
+	TotalContainers int     `json:"totalContainers"`
+	ReadyPercentage float64 `json:"readyPercentage"`
+
+	Deployments        int `json:"deployments"`
+	HealthyDeployments int `json:"healthyDeployments"`
+	WarningEvents      int `json:"warningEvents"`
+}
+
+// SummaryResponse is the response body of /api/summary
+type SummaryResponse struct {
+	Namespaces []NamespaceSummary `json:"namespaces"`
+
+	// WarningEventsAvailable is false when events could not be listed, so
+	// WarningEvents counts are missing rather than zero
+	WarningEventsAvailable bool `json:"warningEventsAvailable"`
+}
+
+// handleSummary serves /api/summary, per-namespace aggregates of the
+// cluster (or ?namespace=) sorted by namespace
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace := namespaceParam(r)
+
+	pods, err := s.client.GetPods(ctx, namespace)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pods: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var deployments []k8s.DeploymentInfo
+	if s.kinds.Enabled(k8s.KindDeployments) {
+		deployments, err = s.client.GetDeployments(ctx, namespace)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get deployments: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Warning counts are optional; the heatmap still works without them
+	warnings, err := s.client.CountWarningEvents(ctx, namespace)
+	if err != nil {
+		requestLogger(r).Warn("Failed to count warning events", "error", err)
+	}
+
+	response := summarizeNamespaces(pods, deployments, warnings)
+	response.WarningEventsAvailable = err == nil
+	writeJSON(w, http.StatusOK, response)
+}
+
+// summarizeNamespaces aggregates pods, deployments and warning event counts
+// by namespace
+func summarizeNamespaces(pods []k8s.PodInfo, deployments []k8s.DeploymentInfo, warnings map[string]int) SummaryResponse {
+	byNamespace := make(map[string]*NamespaceSummary)
+	get := func(namespace string) *NamespaceSummary {
+		summary := byNamespace[namespace]
+		if summary == nil {
+			summary = &NamespaceSummary{Namespace: namespace, PodsByPhase: make(map[string]int), PodsByQOS: make(map[string]int)}
+			byNamespace[namespace] = summary
+		}
+		return summary
+	}
+
+	for _, pod := range pods {
+		summary := get(pod.Namespace)
+		summary.Pods++
+		summary.PodsByPhase[pod.Phase]++
+		if pod.QOSClass != "" {
+			summary.PodsByQOS[pod.QOSClass]++
+		}
+		ready, total := pod.Readiness()
+		summary.ReadyContainers += ready
+		summary.TotalContainers += total
+	}
+	for _, deployment := range deployments {
+		summary := get(deployment.Namespace)
+		summary.Deployments++
+		if !k8s.DeploymentNeedsAttention(deployment.ReadyReplicas, deployment.Replicas) {
+			summary.HealthyDeployments++
+		}
+	}
+	for namespace, count := range warnings {
+		get(namespace).WarningEvents = count
+	}
+
+	response := SummaryResponse{Namespaces: make([]NamespaceSummary, 0, len(byNamespace))}
+	for _, summary := range byNamespace {
+		summary.ReadyPercentage = 100
+		if summary.TotalContainers > 0 {
+			summary.ReadyPercentage = float64(summary.ReadyContainers) / float64(summary.TotalContainers) * 100
+		}
+		response.Namespaces = append(response.Namespaces, *summary)
+	}
+	sort.Slice(response.Namespaces, func(i, j int) bool {
+		return response.Namespaces[i].Namespace < response.Namespaces[j].Namespace
+	})
+
+	return response
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pod-visualizer/pkg/history"
+)
+
+// defaultHistoryHours is the chart range /api/history returns by default
+const defaultHistoryHours = 24
+
+// HistorySampleData represents a readiness sample for JSON response
+type HistorySampleData struct {
+	Time                time.Time `json:"time"`
+	Pods                int       `json:"pods"`
+	TotalContainers     int       `json:"totalContainers"`
+	ReadyContainers     int       `json:"readyContainers"`
+	ContainerPercentage float64   `json:"containerPercentage"`
+	TotalReplicas       int32     `json:"totalReplicas"`
+	ReadyReplicas       int32     `json:"readyReplicas"`
+	ReplicaPercentage   float64   `json:"replicaPercentage"`
+}
+
+// HistoryResponse is the response body of /api/history
+type HistoryResponse struct {
+	Hours   int                 `json:"hours"`
+	Samples []HistorySampleData `json:"samples"`
+}
+
+// timelineSink adapts a history backend to the recorder's HistorySink
+type timelineSink struct {
+	backend history.Backend
+
+	// recorded holds the UIDs of the pods of the last snapshot whose
+	// startup has been stored, so each snapshot sends only new ones
+	recorded map[string]bool
+}
+
+// WriteSnapshots reduces snapshots to readiness samples and the startups
+// of newly Ready deployment pods, and stores them
+func (t *timelineSink) WriteSnapshots(snapshots []ClusterData) error {
+	samples := make([]history.Sample, len(snapshots))
+	for i, data := range snapshots {
+		samples[i] = history.Sample{
+			Time:                data.LastUpdated,
+			Pods:                len(data.Pods),
+			TotalContainers:     data.TotalContainers,
+			ReadyContainers:     data.ReadyContainers,
+			ContainerPercentage: data.ContainerPercentage,
+			TotalReplicas:       data.TotalReplicas,
+			ReadyReplicas:       data.ReadyReplicas,
+			ReplicaPercentage:   data.ReplicaPercentage,
+		}
+	}
+	if err := t.backend.Append(samples); err != nil {
+		return err
+	}
+	return t.backend.AppendStartups(t.startups(snapshots))
+}
+
+// startups returns the startups of deployment pods Ready in snapshots
+// that are not recorded yet
+func (t *timelineSink) startups(snapshots []ClusterData) []history.Startup {
+	var startups []history.Startup
+	for _, data := range snapshots {
+		current := make(map[string]bool, len(data.Pods))
+		for _, pod := range data.Pods {
+			if pod.Deployment == "" || pod.StartupSeconds == 0 {
+				continue
+			}
+			current[pod.UID] = true
+			if t.recorded[pod.UID] {
+				continue
+			}
+			duration := time.Duration(pod.StartupSeconds * float64(time.Second))
+			startups = append(startups, history.Startup{
+				UID:        pod.UID,
+				Namespace:  pod.Namespace,
+				Deployment: pod.Deployment,
+				Pod:        pod.Name,
+				ReadyAt:    pod.CreatedAt.Add(duration),
+				Duration:   duration,
+			})
+		}
+		t.recorded = current
+	}
+	return startups
+}
+
+// SetHistory records readiness samples into backend every interval and
+// serves them at /api/history. The recorder skips unchanged snapshots, so
+// a flat stretch of the chart may have no samples.
+func (s *Server) SetHistory(backend history.Backend, interval time.Duration) {
+	s.timeline = backend
+	s.SetHistorySink(&timelineSink{backend: backend}, interval)
+}
+
+// hoursParam parses ?hours=, how far back history goes, defaulting to
+// defaultHistoryHours
+func hoursParam(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("hours")
+	if value == "" {
+		return defaultHistoryHours, nil
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours <= 0 {
+		return 0, fmt.Errorf("invalid hours %q: expected a positive number", value)
+	}
+	return hours, nil
+}
+
+// handleHistory serves the readiness samples of the last ?hours=N hours
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if s.timeline == nil {
+		http.Error(w, "History is not enabled", http.StatusNotFound)
+		return
+	}
+
+	hours, err := hoursParam(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	samples, err := s.timeline.Since(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := HistoryResponse{Hours: hours, Samples: make([]HistorySampleData, len(samples))}
+	for i, sample := range samples {
+		response.Samples[i] = HistorySampleData{
+			Time:                sample.Time,
+			Pods:                sample.Pods,
+			TotalContainers:     sample.TotalContainers,
+			ReadyContainers:     sample.ReadyContainers,
+			ContainerPercentage: sample.ContainerPercentage,
+			TotalReplicas:       sample.TotalReplicas,
+			ReadyReplicas:       sample.ReadyReplicas,
+			ReplicaPercentage:   sample.ReplicaPercentage,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// hstsHeader tells browsers to use HTTPS for a year
+	hstsHeader = "max-age=31536000"
+	// selfSignedValidity is how long a generated development certificate lasts
+	selfSignedValidity = 30 * 24 * time.Hour
+)
+
+// SetTLS serves HTTPS, and TLS on the gRPC port, with the certificate and
+// key in the given PEM files. Responses carry an HSTS header.
+func (s *Server) SetTLS(certFile, keyFile string) error {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	s.tlsConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	s.hsts = true
+	return nil
+}
+
+// SetSelfSignedTLS serves HTTPS with a certificate generated at startup
+// for localhost and the given extra hosts. It is meant for development:
+// browsers will warn about it, and no HSTS header is sent so that the
+// hosts are not pinned to a certificate that changes on every restart.
+func (s *Server) SetSelfSignedTLS(hosts ...string) error {
+	certificate, err := selfSignedCertificate(append([]string{"localhost", "127.0.0.1", "::1"}, hosts...))
+	if err != nil {
+		return fmt.Errorf("failed to generate self-signed certificate: %v", err)
+	}
+	s.tlsConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	s.hsts = false
+	return nil
+}
+
+// selfSignedCertificate creates an ECDSA certificate valid for hosts
+func selfSignedCertificate(hosts []string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"pod-visualizer development"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(selfSignedValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// withHSTS adds the Strict-Transport-Security header when TLS uses a real
+// certificate
+func (s *Server) withHSTS(next http.Handler) http.Handler {
+	if !s.hsts {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", hstsHeader)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,45 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"pod-visualizer/pkg/topology"
+)
+
+// TopologyNodeData represents an ownership tree node for JSON response
+type TopologyNodeData struct {
+	Kind      string             `json:"kind"`
+	Name      string             `json:"name"`
+	Namespace string             `json:"namespace"`
+	UID       string             `json:"uid"`
+	Status    string             `json:"status"`
+	Children  []TopologyNodeData `json:"children,omitempty"`
+}
+
+// handleTopology serves the ownership trees, optionally for ?namespace=
+func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
+	roots, err := topology.Build(r.Context(), s.client, namespaceParam(r), s.kinds)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to build topology: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTopologyData(roots))
+}
+
+// toTopologyData converts tree nodes to their response format
+func toTopologyData(nodes []*topology.Node) []TopologyNodeData {
+	nodeData := make([]TopologyNodeData, len(nodes))
+	for i, node := range nodes {
+		nodeData[i] = TopologyNodeData{
+			Kind:      node.Kind,
+			Name:      node.Name,
+			Namespace: node.Namespace,
+			UID:       node.UID,
+			Status:    node.Status,
+			Children:  toTopologyData(node.Children),
+		}
+	}
+	return nodeData
+}
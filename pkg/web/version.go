@@ -0,0 +1,79 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// APIVersion is the version of the HTTP API. It changes only with
+// breaking changes; fields and endpoints are added within a version.
+const APIVersion = "v1"
+
+// apiPrefix is the path below which the current API version is served
+const apiPrefix = "/api/" + APIVersion
+
+// APIVersions is the response body of /api, for clients to find a version
+// both they and the server speak
+type APIVersions struct {
+	Versions         []string `json:"versions"`
+	PreferredVersion string   `json:"preferredVersion"`
+	// SchemaVersion is the version of the ClusterData format
+	SchemaVersion int `json:"schemaVersion"`
+}
+
+// handleAPI registers an endpoint below apiPrefix. It stays reachable at
+// its unversioned /api path for clients written before versioning, with
+// headers announcing the deprecation and naming the successor.
+func (s *Server) handleAPI(path string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(apiPrefix+path, s.requireAuth(withAPIVersion(handler)))
+	s.mux.HandleFunc("/api"+path, s.requireAuth(withAPIVersion(deprecatedAPI(handler))))
+}
+
+// withAPIVersion reports the API version that served the response
+func withAPIVersion(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("API-Version", APIVersion)
+		next(w, r)
+	}
+}
+
+// deprecatedAPI serves an unversioned /api request as its apiPrefix
+// equivalent, marking the response deprecated (RFC 9745) with a Link to
+// the versioned path
+func deprecatedAPI(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		successor := apiPrefix + strings.TrimPrefix(r.URL.Path, "/api")
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+
+		versioned := new(http.Request)
+		*versioned = *r
+		versioned.URL = new(url.URL)
+		*versioned.URL = *r.URL
+		versioned.URL.Path = successor
+		versioned.URL.RawPath = ""
+		next(w, versioned)
+	}
+}
+
+// handleAPIVersions serves /api, the API versions of the server
+func (s *Server) handleAPIVersions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, APIVersions{
+		Versions:         []string{APIVersion},
+		PreferredVersion: APIVersion,
+		SchemaVersion:    SchemaVersion,
+	})
+}
+
+// handleAPINotFound answers /api paths no endpoint matches, naming the
+// supported versions when the path asks for another one
+func (s *Server) handleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	version, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	if version != APIVersion && len(version) > 1 && version[0] == 'v' && strings.Trim(version[1:], "0123456789") == "" {
+		http.Error(w, fmt.Sprintf("Unsupported API version %q, expected %s", version, APIVersion), http.StatusNotFound)
+		return
+	}
+	http.NotFound(w, r)
+}
@@ -0,0 +1,41 @@
+package web
+
+import (
+	"time"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// PVCData represents PersistentVolumeClaim data for JSON response
+type PVCData struct {
+	UID          string    `json:"uid"`
+	Name         string    `json:"name"`
+	Namespace    string    `json:"namespace"`
+	Phase        string    `json:"phase"`
+	Capacity     string    `json:"capacity"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	VolumeName   string    `json:"volumeName,omitempty"`
+	AccessModes  []string  `json:"accessModes"`
+	MountedBy    []string  `json:"mountedBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// toPVCData converts claims to their response format
+func toPVCData(pvcs []k8s.PVCInfo) []PVCData {
+	pvcData := make([]PVCData, len(pvcs))
+	for i, pvc := range pvcs {
+		pvcData[i] = PVCData{
+			UID:          pvc.UID,
+			Name:         pvc.Name,
+			Namespace:    pvc.Namespace,
+			Phase:        pvc.Phase,
+			Capacity:     pvc.Capacity,
+			StorageClass: pvc.StorageClass,
+			VolumeName:   pvc.VolumeName,
+			AccessModes:  pvc.AccessModes,
+			MountedBy:    append([]string{}, pvc.MountedBy...),
+			CreatedAt:    pvc.CreatedAt.UTC(),
+		}
+	}
+	return pvcData
+}
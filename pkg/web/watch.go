@@ -0,0 +1,160 @@
+package web
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// watchRetryMin is the first delay before restarting a watch
+	watchRetryMin = time.Second
+	// watchRetryMax caps the delay while the API server keeps failing
+	watchRetryMax = time.Minute
+	// watchHealthy is how long a watch must last for its restart to count
+	// as routine rather than as another failure
+	watchHealthy = 30 * time.Second
+)
+
+// backoff computes exponentially growing, jittered retry delays so that
+// watchers reconnecting to a flapping API server spread out instead of
+// retrying in lockstep
+type backoff struct {
+	min, max time.Duration
+	next     time.Duration
+}
+
+// delay returns the next delay: a random duration between half and all of
+// the current step, which doubles up to max
+func (b *backoff) delay() time.Duration {
+	step := b.next
+	if step < b.min {
+		step = b.min
+	}
+	b.next = min(2*step, b.max)
+	return step/2 + time.Duration(rand.Int63n(int64(step/2)+1))
+}
+
+// reset starts the delays over from min
+func (b *backoff) reset() {
+	b.next = 0
+}
+
+// watchFunc starts a watch on one resource
+type watchFunc func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+
+// watchPods watches for pod changes in a namespace (empty for all namespaces)
+func (s *Server) watchPods(ctx context.Context, namespace string) {
+	s.watchResource(ctx, "pods", namespace, s.client.GetClientset().CoreV1().Pods(namespace).Watch)
+}
+
+// watchDeployments watches for deployment changes in a namespace (empty for all namespaces)
+func (s *Server) watchDeployments(ctx context.Context, namespace string) {
+	s.watchResource(ctx, "deployments", namespace, s.client.GetClientset().AppsV1().Deployments(namespace).Watch)
+}
+
+// watchResource requests a refresh for every change to a resource until
+// ctx is done. A dropped watch resumes from the last resourceVersion it
+// saw, so no change is missed across the reconnect; when that version
+// has expired the watch starts over and a refresh catches up instead.
+// Failed or short-lived watches are retried with exponential backoff.
+func (s *Server) watchResource(ctx context.Context, resource, namespace string, start watchFunc) {
+	retry := backoff{min: watchRetryMin, max: watchRetryMax}
+	resourceVersion := ""
+
+	for ctx.Err() == nil {
+		watcher, err := start(ctx, metav1.ListOptions{
+			FieldSelector:       s.client.NamespaceFieldSelector(namespace),
+			ResourceVersion:     resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			if resourceVersion != "" && (apierrors.IsResourceExpired(err) || apierrors.IsGone(err)) {
+				// The version to resume from is already gone; start over
+				// and refresh in case a change since then was missed
+				slog.Info("Watch expired, restarting", "resource", resource, "resource_version", resourceVersion)
+				resourceVersion = ""
+				s.requestNamespaceRefresh("")
+				continue
+			}
+			delay := retry.delay()
+			if ctx.Err() == nil {
+				slog.Error("Failed to create watcher", "resource", resource, "namespace", namespace, "retry_in", delay.String(), "error", err)
+			}
+			sleepContext(ctx, delay)
+			continue
+		}
+
+		started := time.Now()
+		resourceVersion = s.drainWatch(ctx, watcher, resource, resourceVersion)
+		if time.Since(started) >= watchHealthy {
+			retry.reset()
+		}
+		sleepContext(ctx, retry.delay())
+	}
+}
+
+// drainWatch requests a refresh for every change the watcher reports,
+// until it closes or ctx is done, and returns the resourceVersion to
+// resume from
+func (s *Server) drainWatch(ctx context.Context, watcher watch.Interface, resource, resourceVersion string) string {
+	defer watcher.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+				s.requestNamespaceRefresh(objectNamespace(event.Object))
+				fallthrough
+			case watch.Bookmark:
+				if object, err := meta.Accessor(event.Object); err == nil {
+					resourceVersion = object.GetResourceVersion()
+				}
+			case watch.Error:
+				err := apierrors.FromObject(event.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					// Changes since resourceVersion are gone; start over
+					// and refresh in case one of them was missed
+					slog.Info("Watch expired, restarting", "resource", resource, "resource_version", resourceVersion)
+					s.requestNamespaceRefresh("")
+					return ""
+				}
+				slog.Warn("Watch failed", "resource", resource, "error", err)
+				return resourceVersion
+			}
+		case <-ctx.Done():
+			return resourceVersion
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// objectNamespace returns the namespace of a watched object, empty when
+// it has none, so that the change refreshes the whole cluster
+func objectNamespace(object runtime.Object) string {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetNamespace()
+}
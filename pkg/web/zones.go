@@ -0,0 +1,92 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+
+	"pod-visualizer/pkg/k8s"
+)
+
+// ZoneData represents a topology zone with its nodes and how many pods
+// they run for JSON response. Nodes without a zone label are in the zone
+// "(no zone)".
+type ZoneData struct {
+	Zone       string   `json:"zone"`
+	Nodes      []string `json:"nodes"`
+	ReadyNodes int      `json:"readyNodes"`
+	Pods       int      `json:"pods"`
+}
+
+// WorkloadZoneData represents how a deployment's pods spread over the
+// zones for JSON response
+type WorkloadZoneData struct {
+	Namespace  string         `json:"namespace"`
+	Name       string         `json:"name"`
+	Pods       int            `json:"pods"`
+	PodsByZone map[string]int `json:"podsByZone"`
+
+	// ConcentratedIn is the zone all of the pods run in although the
+	// cluster has several
+	ConcentratedIn string `json:"concentratedIn,omitempty"`
+}
+
+// ZonesResponse is the response body of /api/topology/zones
+type ZonesResponse struct {
+	Zones     []ZoneData         `json:"zones"`
+	Workloads []WorkloadZoneData `json:"workloads"`
+}
+
+// handleZones serves /api/topology/zones, the cluster's nodes grouped by
+// topology.kubernetes.io/zone with the pods of the cluster (or
+// ?namespace=) they run, and how each deployment spreads over the zones
+func (s *Server) handleZones(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pods, err := s.client.GetPods(ctx, namespaceParam(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get pods: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var nodes []k8s.NodeInfo
+	if s.kinds.Enabled(k8s.KindNodes) {
+		nodes, err = s.client.GetNodes(ctx)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get nodes: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	k8s.ApplyZones(pods, nodes)
+	zones, workloads := k8s.GroupZones(nodes, pods)
+	writeJSON(w, http.StatusOK, toZonesResponse(zones, workloads))
+}
+
+// toZonesResponse converts zones and workload spreads to their response
+// format
+func toZonesResponse(zones []k8s.ZoneInfo, workloads []k8s.WorkloadZones) ZonesResponse {
+	response := ZonesResponse{
+		Zones:     make([]ZoneData, len(zones)),
+		Workloads: make([]WorkloadZoneData, len(workloads)),
+	}
+	for i, zone := range zones {
+		data := ZoneData{Zone: zone.Name, Nodes: make([]string, len(zone.Nodes)), Pods: zone.Pods}
+		for j, node := range zone.Nodes {
+			data.Nodes[j] = node.Name
+			if node.Ready {
+				data.ReadyNodes++
+			}
+		}
+		response.Zones[i] = data
+	}
+	for i, workload := range workloads {
+		response.Workloads[i] = WorkloadZoneData{
+			Namespace:      workload.Namespace,
+			Name:           workload.Name,
+			Pods:           workload.Pods,
+			PodsByZone:     workload.PodsByZone,
+			ConcentratedIn: workload.ConcentratedIn,
+		}
+	}
+	return response
+}